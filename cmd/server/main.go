@@ -1,9 +1,31 @@
 package main
 
-import "nola-go/internal/app"
+import (
+	"fmt"
+	"nola-go/internal/app"
+	"os"
+)
 
 func main() {
 
+	// 数据库迁移子命令
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := app.RunMigrate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 全文搜索子命令
+	if len(os.Args) > 1 && os.Args[1] == "search" {
+		if err := app.RunSearch(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	nola, err := app.NewNola()
 	if err != nil {
 		panic(err)
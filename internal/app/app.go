@@ -1,17 +1,28 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"nola-go/internal/config"
 	"nola-go/internal/db"
+	"nola-go/internal/kv"
 	"nola-go/internal/logger"
 	"nola-go/internal/middleware"
+	"nola-go/internal/migration"
+	"nola-go/internal/password"
+	"nola-go/internal/ratelimit"
 	"nola-go/internal/repository"
 	"nola-go/internal/router"
+	"nola-go/internal/scheduler"
+	"nola-go/internal/search"
 	"nola-go/internal/service"
+	"nola-go/internal/session"
+	"nola-go/internal/spam"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
+	uberzap "go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
@@ -20,28 +31,48 @@ type Nola struct {
 	DB     *gorm.DB
 	Redis  *redis.Client
 
-	UserRepo     repository.UserRepository
-	PostRepo     repository.PostRepository
-	ConfigRepo   repository.ConfigRepository
-	TagRepo      repository.TagRepository
-	CategoryRepo repository.CategoryRepository
-	LinkRepo     repository.LinkRepository
-	MenuRepo     repository.MenuRepository
-	DiaryRepo    repository.DiaryRepository
-	FileRepo     repository.FileRepository
-	CommentRepo  repository.CommentRepository
-
-	TokenService    *service.TokenService
-	UserService     *service.UserService
-	PostService     *service.PostService
-	ConfigService   *service.ConfigService
-	TagService      *service.TagService
-	CategoryService *service.CategoryService
-	LinkService     *service.LinkService
-	MenuService     *service.MenuService
-	DiaryService    *service.DiaryService
-	FileService     *service.FileService
-	CommentService  *service.CommentService
+	UserRepo            repository.UserRepository
+	ApiTokenRepo        repository.ApiTokenRepository
+	AuditLogRepo        repository.AuditLogRepository
+	PostRepo            repository.PostRepository
+	RevisionRepo        repository.PostRevisionRepository
+	SearchRepo          repository.SearchRepository
+	ConfigRepo          repository.ConfigRepository
+	TagRepo             repository.TagRepository
+	CategoryRepo        repository.CategoryRepository
+	SeriesRepo          repository.SeriesRepository
+	LinkRepo            repository.LinkRepository
+	MenuRepo            repository.MenuRepository
+	DiaryRepo           repository.DiaryRepository
+	FileRepo            repository.FileRepository
+	CommentRepo         repository.CommentRepository
+	MailUnsubscribeRepo repository.MailUnsubscribeRepository
+	CommenterRepo       repository.CommenterRepository
+
+	TokenService        *service.TokenService
+	AttemptService      *service.AttemptService
+	AuditService        *service.AuditService
+	UserService         *service.UserService
+	RevisionService     *service.PostRevisionService
+	SearchService       *service.SearchService
+	PostService         *service.PostService
+	ConfigService       *service.ConfigService
+	TagService          *service.TagService
+	CategoryService     *service.CategoryService
+	LinkService         *service.LinkService
+	MenuService         *service.MenuService
+	DiaryService        *service.DiaryService
+	FileService         *service.FileService
+	CommentService      *service.CommentService
+	FeedService         *service.FeedService
+	SitemapService      *service.SitemapService
+	SeoService          *service.SeoService
+	SeriesService       *service.SeriesService
+	NotificationService *service.NotificationService
+	CaptchaService      *service.CaptchaService
+	CommenterService    *service.CommenterService
+
+	Scheduler *scheduler.Scheduler
 
 	Engine *gin.Engine
 }
@@ -63,13 +94,22 @@ func NewNola() (*Nola, error) {
 	}
 	a.Config = cfg
 
-	// 初始化 MySQL
-	database, err := db.ConnectMySQL(cfg)
+	// 初始化数据库
+	database, err := db.Connect(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("连接 MySQL 失败: %w", err)
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
 	}
 	a.DB = database
 
+	// 执行数据库迁移
+	applied, err := migration.NewMigrator(a.DB).Up(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("数据库迁移失败: %w", err)
+	}
+	for _, m := range applied {
+		logger.Log.Info("已执行数据库迁移", uberzap.Uint("version", m.Version), uberzap.String("name", m.Name))
+	}
+
 	// 初始化 Redis
 	redisClient, err := db.ConnectRedis(cfg)
 	if err != nil {
@@ -77,49 +117,140 @@ func NewNola() (*Nola, error) {
 	}
 	a.Redis = redisClient
 
+	// 登录会话存储
+	var sessionStore session.Store
+	switch cfg.Session.Store {
+	case "", session.StoreRedis:
+		sessionStore = session.NewRedisStore(a.Redis)
+	case session.StoreMemory:
+		sessionStore = session.NewMemoryStore()
+	default:
+		return nil, fmt.Errorf("不支持的会话存储方式: %s", cfg.Session.Store)
+	}
+
+	// 键值存储（失败次数、锁定状态等临时数据），Redis 出错时改用内存存储
+	kvStore := kv.NewFallbackStore(kv.NewRedisStore(a.Redis), kv.NewMemoryStore())
+
+	// 接口限流计数存储
+	var rateLimitStore kv.Store
+	switch cfg.RateLimit.Store {
+	case "", ratelimit.StoreRedis:
+		rateLimitStore = kvStore
+	case ratelimit.StoreMemory:
+		rateLimitStore = kv.NewMemoryStore()
+	default:
+		return nil, fmt.Errorf("不支持的限流计数存储方式: %s", cfg.RateLimit.Store)
+	}
+	limiter, err := ratelimit.New(cfg.RateLimit, rateLimitStore)
+	if err != nil {
+		return nil, fmt.Errorf("接口限流配置错误: %w", err)
+	}
+
+	// 密码哈希器
+	hasher, err := password.New(cfg.Password)
+	if err != nil {
+		return nil, err
+	}
+
 	// Repository
 	a.UserRepo = repository.NewUserRepository(a.DB)
+	a.ApiTokenRepo = repository.NewApiTokenRepository(a.DB)
+	a.AuditLogRepo = repository.NewAuditLogRepository(a.DB)
 	a.ConfigRepo = repository.NewConfigRepository(a.DB)
 	a.TagRepo = repository.NewTagRepository(a.DB)
 	a.CategoryRepo = repository.NewCategoryRepository(a.DB)
-	a.PostRepo = repository.NewPostRepository(a.DB, a.TagRepo, a.CategoryRepo)
+	a.SeriesRepo = repository.NewSeriesRepository(a.DB)
+	a.PostRepo = repository.NewPostRepository(a.DB, a.TagRepo, a.CategoryRepo, a.UserRepo, hasher)
+	a.RevisionRepo = repository.NewPostRevisionRepository(a.DB)
+	a.SearchRepo = repository.NewSearchRepository(a.DB)
 	a.LinkRepo = repository.NewLinkRepository(a.DB)
 	a.MenuRepo = repository.NewMenuRepository(a.DB)
 	a.DiaryRepo = repository.NewDiaryRepository(a.DB)
 	a.FileRepo = repository.NewFileRepo(a.DB)
 	a.CommentRepo = repository.NewCommentRepository(a.DB)
+	a.MailUnsubscribeRepo = repository.NewMailUnsubscribeRepository(a.DB)
+	a.CommenterRepo = repository.NewCommenterRepository(a.DB)
+
+	// 垃圾评论过滤器
+	spamFilter, err := spam.New(cfg.Spam, a.CommentRepo, cfg.JWT.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("垃圾评论过滤配置错误: %w", err)
+	}
 
 	// Service
-	a.TokenService = service.NewTokenService(a.Config.JWT)
-	a.UserService = service.NewUserService(a.UserRepo, a.TokenService)
-	a.ConfigService = service.NewConfigService(a.ConfigRepo)
+	a.TokenService = service.NewTokenService(a.Config.JWT, sessionStore, a.ApiTokenRepo, a.UserRepo)
+	a.AuditService = service.NewAuditService(a.AuditLogRepo)
+	a.AttemptService = service.NewAttemptService(a.Config.BruteForce, kvStore, a.AuditService)
+	a.UserService = service.NewUserService(a.UserRepo, a.TokenService, a.AttemptService, hasher, a.AuditService)
+	a.ConfigService = service.NewConfigService(a.ConfigRepo, a.AuditService)
 	a.TagService = service.NewTagService(a.TagRepo)
 	a.CategoryService = service.NewCategoryService(a.CategoryRepo)
-	a.PostService = service.NewPostService(a.PostRepo, a.TagService, a.CategoryService)
+	a.RevisionService = service.NewPostRevisionService(a.RevisionRepo, a.UserRepo)
+	a.SearchService = service.NewSearchService(a.PostRepo, search.NewIndexEngine(a.SearchRepo))
+	a.PostService = service.NewPostService(a.PostRepo, a.TagService, a.CategoryService, a.AttemptService, a.AuditService, a.RevisionService, a.SearchService, limiter, a.Config.RecycleBin)
 	a.LinkService = service.NewLinkService(a.LinkRepo)
 	a.MenuService = service.NewMenuService(a.MenuRepo)
 	a.DiaryService = service.NewDiaryService(a.DiaryRepo)
-	a.FileService = service.NewFileService(a.FileRepo)
-	a.CommentService = service.NewCommentService(a.CommentRepo, a.PostRepo)
+	a.FileService = service.NewFileService(a.FileRepo, a.AuditService)
+	a.NotificationService = service.NewNotificationService(a.ConfigService, a.PostRepo, a.CommentRepo, a.MailUnsubscribeRepo, a.Config.Mail, a.Config.JWT.Secret)
+	a.CaptchaService = service.NewCaptchaService(a.ConfigService, kvStore)
+	a.CommenterService = service.NewCommenterService(a.CommenterRepo)
+	a.CommentService = service.NewCommentService(a.CommentRepo, a.PostRepo, a.AuditService, a.NotificationService, spamFilter, a.CommenterService, a.Config.Comment)
+	a.FeedService = service.NewFeedService(a.PostService, a.ConfigService, a.TagService, a.CategoryService, a.Config.Feed)
+	a.SitemapService = service.NewSitemapService(a.PostRepo, a.TagService, a.CategoryService, a.ConfigService)
+	a.SeoService = service.NewSeoService(a.PostService, a.ConfigService)
+	a.SeriesService = service.NewSeriesService(a.SeriesRepo, a.PostRepo)
+
+	// 全文搜索索引为空时（首次升级或手动清空）重建索引
+	a.SearchService.RebuildIfEmpty(context.Background())
+
+	// 后台定时任务
+	a.Scheduler = scheduler.New(kvStore)
+	a.Scheduler.Register(&scheduler.Job{
+		Name:     "publish_scheduled_posts",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			_, err := a.PostService.PublishScheduledPosts(ctx)
+			return err
+		},
+	}, &scheduler.Job{
+		Name:     "purge_recycle_bin",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			_, err := a.PostService.PurgeRecycleBin(ctx)
+			return err
+		},
+	})
 
 	r := gin.New()
 
 	// 替换 Gin 默认日志组件
 	r.Use(middleware.ZapLogger(zap), middleware.ZapRecovery(zap, true))
 
+	// 接口限流，需要在设置路由之前使用
+	r.Use(middleware.RateLimitMiddleware(limiter))
+
 	// 设置路由
 	router.SetupRouters(r, &router.Deps{
-		TokenService:    a.TokenService,
-		UserService:     a.UserService,
-		PostService:     a.PostService,
-		ConfigService:   a.ConfigService,
-		TagService:      a.TagService,
-		CategoryService: a.CategoryService,
-		LinkService:     a.LinkService,
-		MenuService:     a.MenuService,
-		DiaryService:    a.DiaryService,
-		FileService:     a.FileService,
-		CommentService:  a.CommentService,
+		TokenService:        a.TokenService,
+		UserService:         a.UserService,
+		PostService:         a.PostService,
+		ConfigService:       a.ConfigService,
+		TagService:          a.TagService,
+		CategoryService:     a.CategoryService,
+		LinkService:         a.LinkService,
+		MenuService:         a.MenuService,
+		DiaryService:        a.DiaryService,
+		FileService:         a.FileService,
+		CommentService:      a.CommentService,
+		AuditService:        a.AuditService,
+		FeedService:         a.FeedService,
+		SitemapService:      a.SitemapService,
+		SeoService:          a.SeoService,
+		SeriesService:       a.SeriesService,
+		NotificationService: a.NotificationService,
+		CaptchaService:      a.CaptchaService,
+		CommenterService:    a.CommenterService,
 	})
 
 	// 只信任 本机代理
@@ -135,6 +266,11 @@ func NewNola() (*Nola, error) {
 
 // Run 启动 Nola 服务器
 func (n *Nola) Run() error {
+	n.Scheduler.Start()
+	defer n.Scheduler.Stop()
+	// 等待后台发送的邮件完成
+	defer n.NotificationService.Wait()
+
 	err := n.Engine.Run(n.Config.Server.Address())
 	if err != nil {
 		return fmt.Errorf("服务器启动失败: %w", err)
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nola-go/internal/config"
+	"nola-go/internal/db"
+	"nola-go/internal/migration"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// migrateUsage 迁移子命令用法
+const migrateUsage = `用法:
+  server migrate up        执行所有待执行的迁移
+  server migrate down N    回滚最近执行的 N 个迁移
+  server migrate status    查看迁移状态`
+
+// RunMigrate 执行数据库迁移子命令
+//   - args: 子命令参数（不包含 migrate 本身）
+func RunMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("配置文件 config.yaml 读取失败: %w", err)
+	}
+
+	database, err := db.Connect(cfg)
+	if err != nil {
+		return fmt.Errorf("连接数据库失败: %w", err)
+	}
+
+	ctx := context.Background()
+	m := migration.NewMigrator(database)
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, item := range applied {
+			fmt.Printf("已执行 %d_%s\n", item.Version, item.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("没有待执行的迁移")
+		}
+		return nil
+	case "down":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		steps, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("回滚数量 [%s] 不是有效的数字", args[1])
+		}
+		rolledBack, err := m.Down(ctx, steps)
+		for _, item := range rolledBack {
+			fmt.Printf("已回滚 %d_%s\n", item.Version, item.Name)
+		}
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "-"
+			if s.AppliedAt != nil {
+				appliedAt = time.UnixMilli(*s.AppliedAt).Format(time.DateTime)
+			}
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, s.State, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nola-go/internal/config"
+	"nola-go/internal/db"
+	"nola-go/internal/logger"
+	"nola-go/internal/password"
+	"nola-go/internal/repository"
+	"nola-go/internal/search"
+	"nola-go/internal/service"
+)
+
+// searchUsage 搜索子命令用法
+const searchUsage = `用法:
+  server search rebuild    重建文章全文搜索索引`
+
+// RunSearch 执行全文搜索子命令
+//   - args: 子命令参数（不包含 search 本身）
+func RunSearch(args []string) error {
+	if len(args) == 0 || args[0] != "rebuild" {
+		return errors.New(searchUsage)
+	}
+
+	logger.InitLogger()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("配置文件 config.yaml 读取失败: %w", err)
+	}
+
+	database, err := db.Connect(cfg)
+	if err != nil {
+		return fmt.Errorf("连接数据库失败: %w", err)
+	}
+
+	hasher, err := password.New(cfg.Password)
+	if err != nil {
+		return err
+	}
+
+	tagRepo := repository.NewTagRepository(database)
+	categoryRepo := repository.NewCategoryRepository(database)
+	userRepo := repository.NewUserRepository(database)
+	postRepo := repository.NewPostRepository(database, tagRepo, categoryRepo, userRepo, hasher)
+	searchService := service.NewSearchService(postRepo, search.NewIndexEngine(repository.NewSearchRepository(database)))
+
+	count, err := searchService.Rebuild(context.Background())
+	if err != nil {
+		return fmt.Errorf("重建文章索引失败: %w", err)
+	}
+	fmt.Printf("已重建 %d 篇文章的索引\n", count)
+	return nil
+}
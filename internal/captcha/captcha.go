@@ -0,0 +1,37 @@
+package captcha
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+)
+
+const (
+	// TypeImage 图片验证码，访客输入图片中的数字
+	TypeImage = "image"
+	// TypePoW 工作量证明，博客前端在浏览器中计算，访客不需要操作
+	TypePoW = "pow"
+)
+
+// NewId 生成随机验证码 ID，工作量证明同时将其作为挑战字符串
+func NewId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// NewCode 生成随机数字验证码
+//   - length: 位数
+func NewCode(length int) (string, error) {
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+	return string(code), nil
+}
@@ -0,0 +1,80 @@
+package captcha
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestPoW(t *testing.T) {
+	nonce := SolvePoW("challenge", 12)
+	if !VerifyPoW("challenge", nonce, 12) {
+		t.Fatalf("VerifyPoW(%s) = false", nonce)
+	}
+
+	tests := []struct {
+		name      string
+		challenge string
+		nonce     string
+		want      bool
+	}{
+		{"其他挑战", "other", nonce, false},
+		{"随机数为空", "challenge", "", false},
+		{"随机数过长", "challenge", string(make([]byte, maxNonceLength+1)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 其他挑战偶然满足难度的概率为 1/4096，输入固定时结果也固定
+			if got := VerifyPoW(tt.challenge, tt.nonce, 12); got != tt.want {
+				t.Errorf("VerifyPoW = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 难度为 0 时任意非空随机数都通过
+	if !VerifyPoW("challenge", "x", 0) {
+		t.Error("VerifyPoW(difficulty 0) = false")
+	}
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	tests := []struct {
+		b    []byte
+		want int
+	}{
+		{[]byte{0x80}, 0},
+		{[]byte{0x01}, 7},
+		{[]byte{0x00, 0x10}, 11},
+		{[]byte{0x00, 0x00}, 16},
+	}
+	for _, tt := range tests {
+		if got := leadingZeroBits(tt.b); got != tt.want {
+			t.Errorf("leadingZeroBits(%x) = %d, want %d", tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRenderImage(t *testing.T) {
+	code, err := NewCode(5)
+	if err != nil || len(code) != 5 {
+		t.Fatalf("NewCode = %q, %v", code, err)
+	}
+
+	data, err := RenderImage(code)
+	if err != nil {
+		t.Fatalf("RenderImage: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("png.Decode: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != ImageWidth || b.Dy() != ImageHeight {
+		t.Errorf("image size = %v", b)
+	}
+
+	for _, invalid := range []string{"", "12a45"} {
+		if _, err := RenderImage(invalid); err == nil {
+			t.Errorf("RenderImage(%q) should fail", invalid)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package captcha
+
+import (
+	"crypto/sha256"
+	"math/bits"
+	"strconv"
+)
+
+// maxNonceLength 工作量证明随机数的最大长度
+const maxNonceLength = 64
+
+// VerifyPoW 检查工作量证明，SHA-256(challenge + ":" + nonce) 的前 difficulty 位都为 0 时通过
+//   - challenge: 挑战字符串
+//   - nonce: 博客前端计算出的随机数
+//   - difficulty: 难度（前导 0 的位数）
+func VerifyPoW(challenge, nonce string, difficulty int) bool {
+	if nonce == "" || len(nonce) > maxNonceLength {
+		return false
+	}
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	return leadingZeroBits(sum[:]) >= difficulty
+}
+
+// SolvePoW 从 0 开始逐个尝试，计算满足难度的随机数，平均需要计算 2^difficulty 次
+// 博客前端使用相同的方法计算，这里用于测试
+func SolvePoW(challenge string, difficulty int) string {
+	for i := 0; ; i++ {
+		nonce := strconv.Itoa(i)
+		if VerifyPoW(challenge, nonce, difficulty) {
+			return nonce
+		}
+	}
+}
+
+// leadingZeroBits 计算前导 0 的位数
+func leadingZeroBits(b []byte) int {
+	count := 0
+	for _, v := range b {
+		if v != 0 {
+			return count + bits.LeadingZeros8(v)
+		}
+		count += 8
+	}
+	return count
+}
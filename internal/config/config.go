@@ -16,6 +16,15 @@ func (s ServerConfig) Address() string {
 	return fmt.Sprintf("%s:%d", s.Host, s.Port)
 }
 
+// DatabaseConfig 数据库配置
+type DatabaseConfig struct {
+	// Driver 数据库驱动（mysql、sqlite、postgres）
+	Driver string `mapstructure:"driver"`
+	// DSN 数据库连接字符串
+	DSN string `mapstructure:"dsn"`
+}
+
+// MySQLConfig 旧版 MySQL 配置，仅用于兼容旧的配置文件，请使用 DatabaseConfig
 type MySQLConfig struct {
 	DSN string `mapstructure:"dsn"`
 }
@@ -26,19 +35,165 @@ type RedisConfig struct {
 	DB       int    `mapstructure:"db"`
 }
 
+// SessionConfig 登录会话配置
+type SessionConfig struct {
+	// Store 会话存储方式（redis、memory），默认 redis
+	Store string `mapstructure:"store"`
+}
+
 type JWTConfig struct {
-	Secret        string        `mapstructure:"secret"`
-	Issuer        string        `mapstructure:"issuer"`
-	Audience      string        `mapstructure:"audience"`
+	// Secret 签名密钥
+	Secret string `mapstructure:"secret"`
+	// Issuer 签发者
+	Issuer string `mapstructure:"issuer"`
+	// Audience 接收者
+	Audience string `mapstructure:"audience"`
+	// ExpireMinutes 访问令牌有效期（分钟）
 	ExpireMinutes time.Duration `mapstructure:"expire_minutes"`
+	// RefreshExpireDays 刷新令牌有效期（天），每次刷新后顺延，默认 30 天
+	RefreshExpireDays time.Duration `mapstructure:"refresh_expire_days"`
+}
+
+// PasswordConfig 密码哈希配置
+type PasswordConfig struct {
+	// Algorithm 哈希算法（argon2id、bcrypt），默认 argon2id
+	// 修改后旧算法生成的哈希仍然可以验证，并在下次验证成功时自动使用新算法重新生成
+	Algorithm string `mapstructure:"algorithm"`
+	// Argon2 Argon2id 参数
+	Argon2 Argon2Config `mapstructure:"argon2"`
+	// BcryptCost bcrypt 计算成本（4 - 31），默认 12
+	BcryptCost int `mapstructure:"bcrypt_cost"`
+}
+
+// Argon2Config Argon2id 参数
+type Argon2Config struct {
+	// Memory 内存开销（KiB），默认 65536（64 MiB）
+	Memory uint32 `mapstructure:"memory"`
+	// Iterations 迭代次数，默认 3
+	Iterations uint32 `mapstructure:"iterations"`
+	// Parallelism 并行度，默认 2
+	Parallelism uint8 `mapstructure:"parallelism"`
+}
+
+// BruteForceConfig 暴力破解防护配置（登录、两步验证和加密文章密码）
+// 连续失败超过允许次数后开始锁定，锁定时长从 LockSeconds 开始每次失败翻倍，最长 MaxLockMinutes
+type BruteForceConfig struct {
+	// MaxIPFailures 同一 IP 允许的连续失败次数，默认 10
+	MaxIPFailures int64 `mapstructure:"max_ip_failures"`
+	// MaxAccountFailures 同一账户（或同一篇加密文章）允许的连续失败次数，默认 5
+	MaxAccountFailures int64 `mapstructure:"max_account_failures"`
+	// LockSeconds 首次锁定时长（秒），默认 30
+	LockSeconds int `mapstructure:"lock_seconds"`
+	// MaxLockMinutes 最长锁定时长（分钟），默认 60
+	MaxLockMinutes int `mapstructure:"max_lock_minutes"`
+	// ResetHours 最后一次失败多久后清零失败次数（小时），默认 24
+	ResetHours int `mapstructure:"reset_hours"`
+}
+
+// RecycleBinConfig 文章回收站配置
+type RecycleBinConfig struct {
+	// RetentionDays 文章在回收站中保留的天数，到期后自动彻底删除，默认 30；小于 0 表示不自动删除
+	RetentionDays int `mapstructure:"retention_days"`
+}
+
+// FeedConfig RSS / Atom 订阅配置
+type FeedConfig struct {
+	// FullContent 是否输出文章全文（HTML），默认只输出摘要；加密文章始终不输出内容
+	FullContent bool `mapstructure:"full_content"`
+	// Limit 订阅中最新文章的数量，默认 20
+	Limit int `mapstructure:"limit"`
+}
+
+// MailConfig 邮件发送配置，SMTP 服务器在后台的邮件设置中配置
+type MailConfig struct {
+	// Driver 发送方式（smtp、file、log），默认 smtp；file 将邮件写入 Dir 目录，log 输出到日志，均不实际发送
+	Driver string `mapstructure:"driver"`
+	// Dir Driver 为 file 时邮件的保存目录，默认 data/mail
+	Dir string `mapstructure:"dir"`
+}
+
+// SpamConfig 垃圾评论过滤配置，只检查博客前端提交的评论
+// 每个检查器命中时加分，总分达到 SpamThreshold 时标记为垃圾评论，达到 RejectThreshold 时直接拒绝
+type SpamConfig struct {
+	// Enabled 是否启用
+	Enabled bool `mapstructure:"enabled"`
+	// SpamThreshold 标记为垃圾评论的分数，默认 50
+	SpamThreshold int `mapstructure:"spam_threshold"`
+	// RejectThreshold 直接拒绝的分数，默认 100
+	RejectThreshold int `mapstructure:"reject_threshold"`
+	// Keywords 关键词黑名单，不区分大小写
+	Keywords []string `mapstructure:"keywords"`
+	// Patterns 正则表达式黑名单
+	Patterns []string `mapstructure:"patterns"`
+	// MaxLinks 评论内容中最多允许的链接数量，默认 3；小于 0 不限制
+	MaxLinks int `mapstructure:"max_links"`
+	// DuplicateMinutes 检查多少分钟内的重复评论，默认 60；小于 0 不检查
+	DuplicateMinutes int `mapstructure:"duplicate_minutes"`
+	// MinSubmitSeconds 博客前端获取表单令牌后最短多少秒可以提交评论，默认 0 不检查
+	MinSubmitSeconds int `mapstructure:"min_submit_seconds"`
+	// Akismet Akismet 兼容服务，Key 为空时不使用
+	Akismet AkismetConfig `mapstructure:"akismet"`
+}
+
+// AkismetConfig Akismet 兼容服务配置
+type AkismetConfig struct {
+	// Key API Key
+	Key string `mapstructure:"key"`
+	// Blog 博客地址（如 https://example.com）
+	Blog string `mapstructure:"blog"`
+	// Endpoint 接口地址，默认 https://rest.akismet.com
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+// CommentConfig 评论配置
+type CommentConfig struct {
+	// AvatarURL 评论人头像地址，{hash} 会被替换为小写邮箱的 MD5，默认 https://www.gravatar.com/avatar/{hash}?d=mp，
+	// 可以改为 Cravatar（https://cravatar.cn/avatar/{hash}?d=mp）等镜像
+	AvatarURL string `mapstructure:"avatar_url"`
+	// TrustThreshold 同一邮箱已通过审核的评论达到此数量后，新评论自动通过审核，默认 0 不自动通过；
+	// 后台标记为信任的评论人不受此限制
+	TrustThreshold int64 `mapstructure:"trust_threshold"`
+}
+
+// RateLimitConfig 接口限流配置，按客户端 IP 分别计数
+type RateLimitConfig struct {
+	// Enabled 是否启用
+	Enabled bool `mapstructure:"enabled"`
+	// Store 计数存储方式（redis、memory），默认 redis，Redis 出错时改用内存
+	Store string `mapstructure:"store"`
+	// Policies 限流策略
+	Policies []RateLimitPolicy `mapstructure:"policies"`
+}
+
+// RateLimitPolicy 限流策略，同一 IP 在 WindowSeconds 秒内对 Routes 中的路由最多请求 Limit 次
+type RateLimitPolicy struct {
+	// Name 策略名称，不同策略分别计数；post_visit 用于限制增加文章访问量
+	Name string `mapstructure:"name"`
+	// Routes 适用的路由，格式为 "方法 路径"，路径与路由注册时相同，如 "POST /api/comment"、"GET /api/post/:id"
+	// 为空时不限制路由，只用于业务代码中的限流（如 post_visit）
+	Routes []string `mapstructure:"routes"`
+	// Limit 时间窗口内允许的请求数量
+	Limit int64 `mapstructure:"limit"`
+	// WindowSeconds 时间窗口（秒）
+	WindowSeconds int `mapstructure:"window_seconds"`
 }
 
 type Config struct {
-	Env    string       `mapstructure:"env"`
-	Server ServerConfig `mapstructure:"server"`
-	MySQL  MySQLConfig  `mapstructure:"mysql"`
-	Redis  RedisConfig  `mapstructure:"redis"`
-	JWT    JWTConfig    `mapstructure:"jwt"`
+	Env        string           `mapstructure:"env"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	MySQL      MySQLConfig      `mapstructure:"mysql"`
+	Redis      RedisConfig      `mapstructure:"redis"`
+	JWT        JWTConfig        `mapstructure:"jwt"`
+	Session    SessionConfig    `mapstructure:"session"`
+	Password   PasswordConfig   `mapstructure:"password"`
+	BruteForce BruteForceConfig `mapstructure:"brute_force"`
+	RecycleBin RecycleBinConfig `mapstructure:"recycle_bin"`
+	Feed       FeedConfig       `mapstructure:"feed"`
+	Mail       MailConfig       `mapstructure:"mail"`
+	Spam       SpamConfig       `mapstructure:"spam"`
+	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
+	Comment    CommentConfig    `mapstructure:"comment"`
 }
 
 // Load 读取配置文件
@@ -58,5 +213,11 @@ func Load() (*Config, error) {
 	if err := v.Unmarshal(&c); err != nil {
 		return nil, err
 	}
+
+	// 兼容只配置了 mysql 的旧配置文件
+	if c.Database.Driver == "" && c.Database.DSN == "" && c.MySQL.DSN != "" {
+		c.Database.Driver = "mysql"
+		c.Database.DSN = c.MySQL.DSN
+	}
 	return &c, nil
 }
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"fmt"
+	"nola-go/internal/config"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	// DriverMySQL MySQL 数据库驱动
+	DriverMySQL = "mysql"
+	// DriverSQLite SQLite 数据库驱动
+	DriverSQLite = "sqlite"
+	// DriverPostgres PostgreSQL 数据库驱动
+	DriverPostgres = "postgres"
+)
+
+// Connect 根据配置文件中的数据库驱动连接数据库
+func Connect(cfg *config.Config) (*gorm.DB, error) {
+	switch cfg.Database.Driver {
+	case DriverMySQL:
+		return ConnectMySQL(cfg)
+	case DriverSQLite:
+		return ConnectSQLite(cfg)
+	case DriverPostgres:
+		return ConnectPostgres(cfg)
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动 [%s]", cfg.Database.Driver)
+	}
+}
+
+// gormConfig 获取 GORM 配置
+func gormConfig(cfg *config.Config) *gorm.Config {
+	gCfg := &gorm.Config{}
+	if cfg.Env == "dev" {
+		gCfg.Logger = logger.Default.LogMode(logger.Info)
+	}
+	return gCfg
+}
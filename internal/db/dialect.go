@@ -0,0 +1,100 @@
+package db
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeEscape LIKE 转义字符。
+// 不使用反斜杠，因为 MySQL 字符串字面量中的反斜杠本身也需要转义
+const likeEscape = '!'
+
+// likeEscaper 转义 LIKE 通配符
+var likeEscaper = strings.NewReplacer(
+	string(likeEscape), string(likeEscape)+string(likeEscape),
+	"%", string(likeEscape)+"%",
+	"_", string(likeEscape)+"_",
+)
+
+// containsExpr 模糊查询条件，构建 SQL 时根据当前数据库方言选择 LIKE 或 ILIKE
+type containsExpr struct {
+	pattern string
+	columns []clause.Column
+}
+
+// Build 构建 SQL
+func (e containsExpr) Build(builder clause.Builder) {
+	// MySQL（*_ci 排序规则）和 SQLite 的 LIKE 本身不区分大小写（ASCII），PostgreSQL 需要使用 ILIKE
+	op := " LIKE "
+	if stmt, ok := builder.(*gorm.Statement); ok && stmt.Dialector.Name() == DriverPostgres {
+		op = " ILIKE "
+	}
+
+	if len(e.columns) > 1 {
+		_ = builder.WriteByte('(')
+	}
+	for i, column := range e.columns {
+		if i > 0 {
+			_, _ = builder.WriteString(" OR ")
+		}
+		builder.WriteQuoted(column)
+		_, _ = builder.WriteString(op)
+		builder.AddVar(builder, e.pattern)
+		_, _ = builder.WriteString(" ESCAPE '" + string(likeEscape) + "'")
+	}
+	if len(e.columns) > 1 {
+		_ = builder.WriteByte(')')
+	}
+}
+
+// Contains 构建不区分大小写的模糊查询条件，任意一列包含关键字即匹配。
+// 关键字中的 % 和 _ 会被转义，列名由当前数据库方言负责引用
+//   - key: 关键字
+//   - columns: 列名，可以带表别名，如 p.title
+func Contains(key string, columns ...string) clause.Expression {
+	expr := containsExpr{
+		pattern: "%" + likeEscaper.Replace(key) + "%",
+		columns: make([]clause.Column, 0, len(columns)),
+	}
+	for _, column := range columns {
+		expr.columns = append(expr.columns, Column(column))
+	}
+	return expr
+}
+
+// HasPrefix 构建不区分大小写的前缀查询条件，关键字中的 % 和 _ 会被转义
+//   - key: 前缀
+//   - column: 列名，可以带表别名，如 st.term
+func HasPrefix(key string, column string) clause.Expression {
+	return containsExpr{
+		pattern: likeEscaper.Replace(key) + "%",
+		columns: []clause.Column{Column(column)},
+	}
+}
+
+// Column 将列名（可以带表别名，如 p.title）转为 clause.Column，由当前数据库方言负责引用。
+// 用于 index、key 等保留字列名，以及 parent_menuId 等大小写敏感的列名
+func Column(name string) clause.Column {
+	if table, column, ok := strings.Cut(name, "."); ok {
+		return clause.Column{Table: table, Name: column}
+	}
+	return clause.Column{Name: name}
+}
+
+// OrderBy 构建排序条件，列名由当前数据库方言负责引用
+//   - column: 列名，可以带表别名，如 mt.index
+//   - desc: 是否降序
+func OrderBy(column string, desc bool) clause.OrderByColumn {
+	return clause.OrderByColumn{Column: Column(column), Desc: desc}
+}
+
+// ReorderBy 构建排序条件，并替换查询中已有的排序条件
+//   - column: 列名，可以带表别名，如 mt.index
+//   - desc: 是否降序
+func ReorderBy(column string, desc bool) clause.OrderByColumn {
+	order := OrderBy(column, desc)
+	order.Reorder = true
+	return order
+}
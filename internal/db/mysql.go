@@ -9,15 +9,11 @@ import (
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
+// ConnectMySQL 连接 MySQL 数据库
 func ConnectMySQL(cfg *config.Config) (*gorm.DB, error) {
-	gCfg := &gorm.Config{}
-	if cfg.Env == "dev" {
-		gCfg.Logger = logger.Default.LogMode(logger.Info)
-	}
-	db, err := gorm.Open(mysql.Open(cfg.MySQL.DSN), gCfg)
+	db, err := gorm.Open(mysql.Open(cfg.Database.DSN), gormConfig(cfg))
 	if err != nil {
 		return nil, err
 	}
@@ -42,10 +38,10 @@ func PagerBuilder[T any](
 	// 构建查询条件
 	queryDB := queryBuilder(db)
 
-	// 查询总条数
+	// 查询总条数（覆盖查询条件中的 Select，如 p.* 无法直接用于 COUNT）
 	var totalData int64
 	countDB := queryBuilder(db.Session(&gorm.Session{}))
-	if err := countDB.WithContext(ctx).Model((*T)(nil)).Count(&totalData).Error; err != nil {
+	if err := countDB.WithContext(ctx).Model((*T)(nil)).Select("COUNT(*)").Count(&totalData).Error; err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,17 @@
+package db
+
+import (
+	"nola-go/internal/config"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// ConnectPostgres 连接 PostgreSQL 数据库
+func ConnectPostgres(cfg *config.Config) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(cfg.Database.DSN), gormConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"nola-go/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// ConnectSQLite 连接 SQLite 数据库，数据库文件所在目录不存在时自动创建
+func ConnectSQLite(cfg *config.Config) (*gorm.DB, error) {
+	if path := sqliteFilePath(cfg.Database.DSN); path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := gorm.Open(sqlite.Open(cfg.Database.DSN), gormConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// sqliteFilePath 从 SQLite DSN 中解析数据库文件路径，内存数据库返回空字符串
+func sqliteFilePath(dsn string) string {
+	path := strings.TrimPrefix(dsn, "file:")
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	if path == "" || path == ":memory:" || strings.Contains(dsn, "mode=memory") {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,101 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// atomNamespace Atom 命名空间
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+// atomDocument Atom 1.0 文档
+type atomDocument struct {
+	XMLName   xml.Name     `xml:"feed"`
+	Namespace string       `xml:"xmlns,attr"`
+	Title     string       `xml:"title"`
+	Subtitle  string       `xml:"subtitle,omitempty"`
+	Id        string       `xml:"id"`
+	Links     []*atomLink  `xml:"link"`
+	Updated   string       `xml:"updated"`
+	Author    *atomPerson  `xml:"author,omitempty"`
+	Generator string       `xml:"generator"`
+	Entries   []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title      string          `xml:"title"`
+	Id         string          `xml:"id"`
+	Link       atomLink        `xml:"link"`
+	Published  string          `xml:"published"`
+	Updated    string          `xml:"updated"`
+	Author     *atomPerson     `xml:"author,omitempty"`
+	Categories []*atomCategory `xml:"category"`
+	Summary    *atomText       `xml:"summary,omitempty"`
+	Content    *atomText       `xml:"content,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// atom 转为 Atom 1.0 文档
+func (f *Feed) atom() *atomDocument {
+	doc := &atomDocument{
+		Namespace: atomNamespace,
+		Title:     f.Title,
+		Subtitle:  f.Description,
+		Id:        f.Link,
+		Links:     []*atomLink{{Href: f.Link, Rel: "alternate", Type: "text/html"}},
+		Updated:   f.Updated.Format(time.RFC3339),
+		Generator: generator,
+	}
+	if f.SelfLink != "" {
+		doc.Links = append(doc.Links, &atomLink{Href: f.SelfLink, Rel: "self", Type: FormatAtom.mimeType()})
+	}
+	if f.Author != "" {
+		doc.Author = &atomPerson{Name: f.Author}
+	}
+
+	for _, item := range f.Items {
+		updated := item.Updated
+		if updated.IsZero() {
+			updated = item.Published
+		}
+		entry := &atomEntry{
+			Title:     item.Title,
+			Id:        item.Link,
+			Link:      atomLink{Href: item.Link, Rel: "alternate", Type: "text/html"},
+			Published: item.Published.Format(time.RFC3339),
+			Updated:   updated.Format(time.RFC3339),
+		}
+		if item.Author != "" {
+			entry.Author = &atomPerson{Name: item.Author}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, &atomCategory{Term: category})
+		}
+		if item.Description != "" {
+			entry.Summary = &atomText{Type: "text", Value: item.Description}
+		}
+		if item.Content != "" {
+			entry.Content = &atomText{Type: "html", Value: item.Content}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return doc
+}
@@ -0,0 +1,87 @@
+// Package feed 生成 RSS 2.0 和 Atom 1.0 订阅
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// generator 订阅生成器名称
+const generator = "Nola"
+
+// Format 订阅格式
+type Format string
+
+const (
+	// FormatRSS RSS 2.0
+	FormatRSS Format = "rss"
+	// FormatAtom Atom 1.0
+	FormatAtom Format = "atom"
+)
+
+// ContentType 订阅格式的响应类型
+func (f Format) ContentType() string {
+	return f.mimeType() + "; charset=utf-8"
+}
+
+// mimeType 订阅格式的 MIME 类型
+func (f Format) mimeType() string {
+	if f == FormatAtom {
+		return "application/atom+xml"
+	}
+	return "application/rss+xml"
+}
+
+// Feed 订阅
+type Feed struct {
+	// Title 标题
+	Title string
+	// Description 描述
+	Description string
+	// Link 博客页面地址
+	Link string
+	// SelfLink 订阅自身的地址
+	SelfLink string
+	// Author 作者
+	Author string
+	// Updated 最后更新时间（所有文章中最新的修改时间）
+	Updated time.Time
+	// Items 文章
+	Items []*Item
+}
+
+// Item 订阅中的文章
+type Item struct {
+	// Title 标题
+	Title string
+	// Link 文章页面地址，同时作为文章的唯一标识
+	Link string
+	// Description 摘要（纯文本）
+	Description string
+	// Content 全文（HTML），为空时只输出摘要
+	Content string
+	// Author 作者
+	Author string
+	// Categories 分类和标签
+	Categories []string
+	// Published 发布时间
+	Published time.Time
+	// Updated 最后修改时间
+	Updated time.Time
+}
+
+// Render 按指定格式生成订阅 XML
+func (f *Feed) Render(format Format) ([]byte, error) {
+	var doc any
+	if format == FormatAtom {
+		doc = f.atom()
+	} else {
+		doc = f.rss()
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,118 @@
+package feed
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &Feed{
+		Title:       "Nola & Go",
+		Description: "博客",
+		Link:        "https://example.com/",
+		SelfLink:    "https://example.com/feed.xml",
+		Author:      "博主",
+		Updated:     published.Add(time.Hour),
+		Items: []*Item{
+			{
+				Title:       "全文",
+				Link:        "https://example.com/post/full",
+				Description: "摘要",
+				Content:     "<p>正文 ]]> 结束</p>",
+				Author:      "作者",
+				Categories:  []string{"分类", "标签"},
+				Published:   published,
+				Updated:     published.Add(time.Hour),
+			},
+			{
+				Title:       "摘要",
+				Link:        "https://example.com/post/excerpt",
+				Description: "<只有摘要>",
+				Published:   published,
+			},
+		},
+	}
+}
+
+func TestFeed_RSS(t *testing.T) {
+	body, err := testFeed().Render(FormatRSS)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	var doc struct {
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Guid       string   `xml:"guid"`
+				Content    string   `xml:"encoded"`
+				Categories []string `xml:"category"`
+				PubDate    string   `xml:"pubDate"`
+				Updated    string   `xml:"updated"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, body)
+	}
+
+	channel := doc.Channel
+	if channel.Title != "Nola & Go" || channel.LastBuildDate != "Fri, 02 Jan 2026 04:04:05 +0000" || len(channel.Items) != 2 {
+		t.Fatalf("channel = %+v", channel)
+	}
+	first := channel.Items[0]
+	if first.Guid != "https://example.com/post/full" || first.Content != "<p>正文 ]]> 结束</p>" ||
+		len(first.Categories) != 2 || first.Updated != "2026-01-02T04:04:05Z" {
+		t.Errorf("item = %+v", first)
+	}
+	if channel.Items[1].Content != "" || channel.Items[1].Updated != "" {
+		t.Errorf("excerpt item = %+v", channel.Items[1])
+	}
+	if !strings.Contains(string(body), `<atom:link href="https://example.com/feed.xml" rel="self" type="application/rss+xml">`) {
+		t.Errorf("missing self link:\n%s", body)
+	}
+}
+
+func TestFeed_Atom(t *testing.T) {
+	body, err := testFeed().Render(FormatAtom)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			Id      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Summary string `xml:"summary"`
+			Content *struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, body)
+	}
+
+	if doc.Updated != "2026-01-02T04:04:05Z" || len(doc.Entries) != 2 {
+		t.Fatalf("feed = %+v", doc)
+	}
+	first := doc.Entries[0]
+	if first.Content == nil || first.Content.Type != "html" || first.Content.Value != "<p>正文 ]]> 结束</p>" || len(first.Categories) != 2 {
+		t.Errorf("entry = %+v", first)
+	}
+	// 没有修改时间时使用发布时间
+	second := doc.Entries[1]
+	if second.Content != nil || second.Updated != "2026-01-02T03:04:05Z" || second.Summary != "<只有摘要>" {
+		t.Errorf("entry = %+v", second)
+	}
+}
@@ -0,0 +1,91 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// rssDocument RSS 2.0 文档，使用 Atom 扩展声明订阅地址和文章修改时间，content 扩展输出全文
+type rssDocument struct {
+	XMLName       xml.Name   `xml:"rss"`
+	Version       string     `xml:"version,attr"`
+	AtomNamespace string     `xml:"xmlns:atom,attr"`
+	ContentNS     string     `xml:"xmlns:content,attr"`
+	DublinCoreNS  string     `xml:"xmlns:dc,attr"`
+	Channel       rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	SelfLink      *atomLink  `xml:"atom:link"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Generator     string     `xml:"generator"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Guid        rssGuid  `xml:"guid"`
+	Description string   `xml:"description"`
+	Content     *cdata   `xml:"content:encoded"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+	Updated     string   `xml:"atom:updated,omitempty"`
+}
+
+type rssGuid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// cdata 使用 CDATA 输出的 HTML 内容
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+// rss 转为 RSS 2.0 文档
+func (f *Feed) rss() *rssDocument {
+	channel := rssChannel{
+		Title:       f.Title,
+		Link:        f.Link,
+		Description: f.Description,
+		Generator:   generator,
+	}
+	if f.SelfLink != "" {
+		channel.SelfLink = &atomLink{Href: f.SelfLink, Rel: "self", Type: FormatRSS.mimeType()}
+	}
+	if !f.Updated.IsZero() {
+		channel.LastBuildDate = f.Updated.Format(time.RFC1123Z)
+	}
+
+	for _, item := range f.Items {
+		rssItem := &rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Guid:        rssGuid{IsPermaLink: true, Value: item.Link},
+			Description: item.Description,
+			Creator:     item.Author,
+			Categories:  item.Categories,
+			PubDate:     item.Published.Format(time.RFC1123Z),
+		}
+		if item.Content != "" {
+			rssItem.Content = &cdata{Value: item.Content}
+		}
+		if !item.Updated.IsZero() {
+			rssItem.Updated = item.Updated.Format(time.RFC3339)
+		}
+		channel.Items = append(channel.Items, rssItem)
+	}
+
+	return &rssDocument{
+		Version:       "2.0",
+		AtomNamespace: atomNamespace,
+		ContentNS:     "http://purl.org/rss/1.0/modules/content/",
+		DublinCoreNS:  "http://purl.org/dc/elements/1.1/",
+		Channel:       channel,
+	}
+}
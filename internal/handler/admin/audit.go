@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"nola-go/internal/middleware"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/service"
+	"nola-go/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditAdminHandler 审计日志后端接口
+type AuditAdminHandler struct {
+	auditService *service.AuditService
+	tokenService *service.TokenService
+}
+
+func NewAuditAdminHandler(asv *service.AuditService, tsv *service.TokenService) *AuditAdminHandler {
+	return &AuditAdminHandler{
+		auditService: asv,
+		tokenService: tsv,
+	}
+}
+
+// RegisterAdmin 注册审计日志后端路由
+func (h *AuditAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
+
+	// 鉴权接口
+	privateGroup := r.Group("/audit")
+	privateGroup.Use(middleware.AuthMiddleware(h.tokenService), middleware.PermissionMiddleware(enum.PermissionViewAuditLog))
+	{
+		// 分页获取审计日志
+		privateGroup.GET("", h.getAuditLogs)
+	}
+}
+
+// getAuditLogs 分页获取审计日志
+func (h *AuditAdminHandler) getAuditLogs(c *gin.Context) {
+	page, size, err := util.ShouldBindPager(c)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	var req struct {
+		// ActorId 可空操作用户 ID
+		ActorId *uint `form:"actorId"`
+		// Action 可空操作类型
+		Action *string `form:"action"`
+		// StartTime 可空开始时间戳毫秒（包含）
+		StartTime *int64 `form:"startTime"`
+		// EndTime 可空结束时间戳毫秒（不包含）
+		EndTime *int64 `form:"endTime"`
+	}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	// 操作类型
+	var action *enum.AuditAction
+	if req.Action != nil {
+		action = enum.AuditActionValueOf(*req.Action)
+		if action == nil {
+			response.ParamMismatch(c)
+			return
+		}
+	}
+
+	ret, err := h.auditService.AuditLogsPager(c, page, size, req.ActorId, action, req.StartTime, req.EndTime)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
@@ -6,6 +6,7 @@ import (
 	"mime/multipart"
 	"nola-go/internal/logger"
 	"nola-go/internal/middleware"
+	"nola-go/internal/models/enum"
 	"nola-go/internal/models/response"
 	"nola-go/internal/service"
 	"path/filepath"
@@ -31,7 +32,7 @@ func NewBackupAdminHandler(psv *service.PostService, tsc *service.TokenService)
 func (h *BackupAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 
 	privateGroup := r.Group("/backup")
-	privateGroup.Use(middleware.AuthMiddleware(h.tokenService))
+	privateGroup.Use(middleware.AuthMiddleware(h.tokenService), middleware.PermissionMiddleware(enum.PermissionManageSettings))
 	{
 		// 导入文章
 		privateGroup.POST("/post", h.importPost)
@@ -105,7 +106,7 @@ func (h *BackupAdminHandler) importPost(c *gin.Context) {
 	}
 
 	// 添加文章
-	ret, err := h.postService.AddPostByNamesAndContents(c, fileNameList, fileContentList)
+	ret, err := h.postService.AddPostByNamesAndContents(c, middleware.CurrentOperator(c), fileNameList, fileContentList)
 
 	if err != nil {
 		response.FailAndResponse(c, err.Error())
@@ -3,6 +3,7 @@ package admin
 import (
 	"nola-go/internal/middleware"
 	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
 	"nola-go/internal/models/response"
 	"nola-go/internal/service"
 	"nola-go/internal/util"
@@ -30,15 +31,17 @@ func (h *CategoryAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 	// 鉴权接口
 	privateGroup := r.Group("/category")
 	privateGroup.Use(middleware.AuthMiddleware(h.tokenService))
+	// 修改需要管理内容权限，所有登录用户都可以获取（撰写文章时使用）
+	manage := middleware.PermissionMiddleware(enum.PermissionManageContent)
 	{
 		// 添加分类
-		privateGroup.POST("", h.addCategory)
+		privateGroup.POST("", manage, h.addCategory)
 		// 根据分类 ID 数组删除分类
-		privateGroup.DELETE("", h.deleteCategoryByIds)
+		privateGroup.DELETE("", manage, h.deleteCategoryByIds)
 		// 根据分类别名数组删除分类
-		privateGroup.DELETE("/slug", h.deleteCategoryBySlugs)
+		privateGroup.DELETE("/slug", manage, h.deleteCategoryBySlugs)
 		// 修改分类
-		privateGroup.PUT("", h.updateCategory)
+		privateGroup.PUT("", manage, h.updateCategory)
 		// 根据分类 ID 获取分类
 		privateGroup.GET("/:id", h.categoryById)
 		// 分页获取分类
@@ -30,7 +30,7 @@ func (h *CommentAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 
 	// 鉴权接口
 	privateGroup := r.Group("/comment")
-	privateGroup.Use(middleware.AuthMiddleware(h.tokenService))
+	privateGroup.Use(middleware.AuthMiddleware(h.tokenService), middleware.PermissionMiddleware(enum.PermissionModerateComment))
 	{
 		// 添加评论
 		privateGroup.POST("", h.addComment)
@@ -40,6 +40,8 @@ func (h *CommentAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 		privateGroup.PUT("", h.updateComment)
 		// 修改评论是否通过审核
 		privateGroup.PUT("/pass", h.updateCommentPass)
+		// 修改评论是否为垃圾评论
+		privateGroup.PUT("/spam", h.updateCommentSpam)
 		// 获取评论
 		privateGroup.GET("", h.getComments)
 	}
@@ -62,7 +64,7 @@ func (h *CommentAdminHandler) addComment(c *gin.Context) {
 		DisplayName:     newComment.DisplayName,
 		Email:           newComment.Email,
 		IsPass:          newComment.IsPass,
-	}, false)
+	}, false, nil)
 
 	if err != nil {
 		response.FailAndResponse(c, err.Error())
@@ -80,7 +82,7 @@ func (h *CommentAdminHandler) deleteComment(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.commentService.DeleteCommentByIds(c, ids)
+	ret, err := h.commentService.DeleteCommentByIds(c, middleware.CurrentOperator(c), ids)
 	if err != nil {
 		response.FailAndResponse(c, err.Error())
 		return
@@ -129,6 +131,22 @@ func (h *CommentAdminHandler) updateCommentPass(c *gin.Context) {
 	response.OkAndResponse(c, ret)
 }
 
+// updateCommentSpam 修改评论是否为垃圾评论
+func (h *CommentAdminHandler) updateCommentSpam(c *gin.Context) {
+	var req *request.CommentSpamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.commentService.SetCommentSpam(c, req.Ids, *req.IsSpam)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+	response.OkAndResponse(c, ret)
+}
+
 // getComments 获取评论
 func (h *CommentAdminHandler) getComments(c *gin.Context) {
 
@@ -147,6 +165,8 @@ func (h *CommentAdminHandler) getComments(c *gin.Context) {
 		ParentId *string `form:"parentCommentId"`
 		// IsPass 可空是否通过审核
 		IsPass *bool `form:"isPass"`
+		// IsSpam 可空是否为垃圾评论
+		IsSpam *bool `form:"isSpam"`
 		// Key 可空关键词
 		Key *string `form:"key"`
 		// Sort 可空的排序方式
@@ -213,7 +233,7 @@ func (h *CommentAdminHandler) getComments(c *gin.Context) {
 	}
 
 	ret, err := h.commentService.Comments(
-		c, page, size, postId, nil, commentId, parentId, req.IsPass, req.Key, sort, *req.Tree,
+		c, page, size, postId, nil, commentId, parentId, req.IsPass, req.IsSpam, req.Key, sort, *req.Tree,
 	)
 
 	if err != nil {
@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"nola-go/internal/middleware"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/models/response"
+	"nola-go/internal/service"
+	"nola-go/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CommenterAdminHandler 评论人后端接口
+type CommenterAdminHandler struct {
+	commenterService *service.CommenterService
+	tokenService     *service.TokenService
+}
+
+func NewCommenterAdminHandler(csv *service.CommenterService, tsv *service.TokenService) *CommenterAdminHandler {
+	return &CommenterAdminHandler{
+		commenterService: csv,
+		tokenService:     tsv,
+	}
+}
+
+// RegisterAdmin 注册评论人后端路由
+func (h *CommenterAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
+
+	// 鉴权接口
+	privateGroup := r.Group("/commenter")
+	privateGroup.Use(middleware.AuthMiddleware(h.tokenService), middleware.PermissionMiddleware(enum.PermissionModerateComment))
+	{
+		// 标记评论人（信任或禁止评论）
+		privateGroup.PUT("", h.saveCommenter)
+		// 取消标记评论人
+		privateGroup.DELETE("", h.deleteCommenters)
+		// 获取标记的评论人
+		privateGroup.GET("", h.getCommenters)
+	}
+}
+
+// saveCommenter 标记评论人，邮箱已标记时修改状态和备注
+func (h *CommenterAdminHandler) saveCommenter(c *gin.Context) {
+	var req *request.CommenterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.commenterService.SaveCommenter(c, req.Email, req.Status, req.Remark)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+	response.OkAndResponse(c, ret)
+}
+
+// deleteCommenters 取消标记评论人
+func (h *CommenterAdminHandler) deleteCommenters(c *gin.Context) {
+	var ids []uint
+	if err := c.ShouldBindJSON(&ids); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.commenterService.DeleteCommenters(c, ids)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+	response.OkAndResponse(c, ret)
+}
+
+// getCommenters 获取标记的评论人
+func (h *CommenterAdminHandler) getCommenters(c *gin.Context) {
+	page, size, err := util.ShouldBindPager(c)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	var req struct {
+		// Status 可空状态
+		Status *string `form:"status"`
+		// Key 可空关键词（邮箱、备注）
+		Key *string `form:"key"`
+	}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	var status *enum.CommenterStatus
+	if req.Status != nil {
+		status = enum.CommenterStatusValueOf(*req.Status)
+		if status == nil {
+			response.ParamMismatch(c)
+			return
+		}
+	}
+
+	ret, err := h.commenterService.Commenters(c, page, size, status, req.Key)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+	response.OkAndResponse(c, ret)
+}
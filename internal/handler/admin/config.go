@@ -3,6 +3,7 @@ package admin
 import (
 	"nola-go/internal/middleware"
 	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
 	"nola-go/internal/models/response"
 	"nola-go/internal/service"
 	"nola-go/internal/util"
@@ -13,17 +14,19 @@ import (
 
 // ConfigAdminHandler 配置后端接口
 type ConfigAdminHandler struct {
-	configService *service.ConfigService
-	userService   *service.UserService
-	tokenService  *service.TokenService
+	configService       *service.ConfigService
+	userService         *service.UserService
+	tokenService        *service.TokenService
+	notificationService *service.NotificationService
 }
 
 // NewConfigAdminHandler 新建配置后端 Handler
-func NewConfigAdminHandler(s *service.ConfigService, usv *service.UserService, tsv *service.TokenService) *ConfigAdminHandler {
+func NewConfigAdminHandler(s *service.ConfigService, usv *service.UserService, tsv *service.TokenService, nsv *service.NotificationService) *ConfigAdminHandler {
 	return &ConfigAdminHandler{
-		configService: s,
-		userService:   usv,
-		tokenService:  tsv,
+		configService:       s,
+		userService:         usv,
+		tokenService:        tsv,
+		notificationService: nsv,
 	}
 }
 
@@ -36,12 +39,29 @@ func (h *ConfigAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 		// 获取博客信息
 		privateGroup.GET("/blog", h.getBlogInfo)
 		// 修改博客信息
-		privateGroup.PUT("/blog", h.updateBlogInfo)
+		privateGroup.PUT("/blog", middleware.PermissionMiddleware(enum.PermissionManageSettings), h.updateBlogInfo)
 
 		// 修改备案信息
-		privateGroup.PUT("/icp", h.updateIcp)
+		privateGroup.PUT("/icp", middleware.PermissionMiddleware(enum.PermissionManageSettings), h.updateIcp)
 		// 获取备案信息
 		privateGroup.GET("/icp", h.getIcp)
+
+		// 修改 robots.txt
+		privateGroup.PUT("/robots", middleware.PermissionMiddleware(enum.PermissionManageSettings), h.updateRobots)
+		// 获取 robots.txt
+		privateGroup.GET("/robots", h.getRobots)
+
+		// 修改邮件通知设置
+		privateGroup.PUT("/smtp", middleware.PermissionMiddleware(enum.PermissionManageSettings), h.updateSMTP)
+		// 获取邮件通知设置
+		privateGroup.GET("/smtp", middleware.PermissionMiddleware(enum.PermissionManageSettings), h.getSMTP)
+		// 发送测试邮件
+		privateGroup.POST("/smtp/test", middleware.PermissionMiddleware(enum.PermissionManageSettings), h.testSMTP)
+
+		// 修改评论验证码设置
+		privateGroup.PUT("/captcha", middleware.PermissionMiddleware(enum.PermissionManageSettings), h.updateCaptcha)
+		// 获取评论验证码设置
+		privateGroup.GET("/captcha", h.getCaptcha)
 	}
 
 	// 无鉴权接口
@@ -79,7 +99,7 @@ func (h *ConfigAdminHandler) initBlogInfo(c *gin.Context) {
 	}
 
 	// 初始化博客
-	ret, err := h.configService.SetBlogInfo(c, &models.BlogInfo{
+	ret, err := h.configService.SetBlogInfo(c, middleware.CurrentOperator(c), &models.BlogInfo{
 		Title:      &req.Title,
 		Subtitle:   &req.Subtitle,
 		CreateDate: util.Int64Ptr(time.Now().UnixMilli()),
@@ -148,11 +168,11 @@ func (h *ConfigAdminHandler) getBlogInfo(c *gin.Context) {
 	}
 
 	// 获取博主信息
-	users, err := h.userService.AllUsers(c)
+	blogger, err := h.userService.Blogger(c)
 
-	if len(users) != 0 && blogInfo != nil {
-		// 博主数量不为空，填充博主名称
-		blogInfo.Blogger = &users[0].DisplayName
+	if blogger != nil && blogInfo != nil {
+		// 博主不为空，填充博主名称
+		blogInfo.Blogger = &blogger.DisplayName
 	}
 
 	response.OkAndResponse(c, blogInfo)
@@ -162,10 +182,14 @@ func (h *ConfigAdminHandler) getBlogInfo(c *gin.Context) {
 func (h *ConfigAdminHandler) updateBlogInfo(c *gin.Context) {
 
 	var req struct {
-		Title    string  `json:"title" binding:"required"`
-		Subtitle *string `json:"subtitle"`
-		Logo     *string `json:"logo"`
-		Favicon  *string `json:"favicon"`
+		Title              string  `json:"title" binding:"required"`
+		Subtitle           *string `json:"subtitle"`
+		Logo               *string `json:"logo"`
+		Favicon            *string `json:"favicon"`
+		SiteURL            *string `json:"siteUrl"`
+		PostURLPattern     *string `json:"postUrlPattern"`
+		TagURLPattern      *string `json:"tagUrlPattern"`
+		CategoryURLPattern *string `json:"categoryUrlPattern"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -188,8 +212,12 @@ func (h *ConfigAdminHandler) updateBlogInfo(c *gin.Context) {
 	blogInfo.Subtitle = req.Subtitle
 	blogInfo.Logo = req.Logo
 	blogInfo.Favicon = req.Favicon
+	blogInfo.SiteURL = req.SiteURL
+	blogInfo.PostURLPattern = req.PostURLPattern
+	blogInfo.TagURLPattern = req.TagURLPattern
+	blogInfo.CategoryURLPattern = req.CategoryURLPattern
 
-	ret, err := h.configService.SetBlogInfo(c, blogInfo)
+	ret, err := h.configService.SetBlogInfo(c, middleware.CurrentOperator(c), blogInfo)
 
 	if err != nil {
 		response.FailAndResponse(c, err.Error())
@@ -211,7 +239,7 @@ func (h *ConfigAdminHandler) updateIcp(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.configService.SetICPFiling(c, &models.ICPFiling{
+	ret, err := h.configService.SetICPFiling(c, middleware.CurrentOperator(c), &models.ICPFiling{
 		ICP:    req.Icp,
 		Police: req.Police,
 	})
@@ -233,3 +261,115 @@ func (h *ConfigAdminHandler) getIcp(c *gin.Context) {
 	}
 	response.OkAndResponse(c, icp)
 }
+
+// updateRobots 修改 robots.txt
+func (h *ConfigAdminHandler) updateRobots(c *gin.Context) {
+	var req struct {
+		Content string `json:"content"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.configService.SetRobotsTxt(c, middleware.CurrentOperator(c), req.Content)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
+
+// getRobots 获取 robots.txt，没有设置时返回 null
+func (h *ConfigAdminHandler) getRobots(c *gin.Context) {
+	ret, err := h.configService.RobotsTxt(c)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
+
+// updateSMTP 修改邮件通知设置，密码为空时不修改密码
+func (h *ConfigAdminHandler) updateSMTP(c *gin.Context) {
+	var req models.SMTPConfig
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.configService.SetSMTP(c, middleware.CurrentOperator(c), &req)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
+
+// getSMTP 获取邮件通知设置（不返回密码），没有设置时返回 null
+func (h *ConfigAdminHandler) getSMTP(c *gin.Context) {
+	ret, err := h.configService.SMTP(c)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	if ret != nil {
+		ret.Password = ""
+	}
+
+	response.OkAndResponse(c, ret)
+}
+
+// testSMTP 使用已保存的邮件设置发送测试邮件
+func (h *ConfigAdminHandler) testSMTP(c *gin.Context) {
+	var req struct {
+		To string `json:"to" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	if err := h.notificationService.SendTestMail(c, req.To); err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, true)
+}
+
+// updateCaptcha 修改评论验证码设置
+func (h *ConfigAdminHandler) updateCaptcha(c *gin.Context) {
+	var req models.CaptchaConfig
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.configService.SetCaptcha(c, middleware.CurrentOperator(c), &req)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
+
+// getCaptcha 获取评论验证码设置
+func (h *ConfigAdminHandler) getCaptcha(c *gin.Context) {
+	ret, err := h.configService.Captcha(c)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
@@ -26,7 +26,7 @@ func NewDiaryAdminHandler(dsv *service.DiaryService, tsv *service.TokenService)
 
 func (h *DiaryAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 	privateGroup := r.Group("/diary")
-	privateGroup.Use(middleware.AuthMiddleware(h.tokenService))
+	privateGroup.Use(middleware.AuthMiddleware(h.tokenService), middleware.PermissionMiddleware(enum.PermissionManageContent))
 	{
 		// 添加日记
 		privateGroup.POST("", h.addDiary)
@@ -32,10 +32,11 @@ func NewFileAdminHandler(fsv *service.FileService, tsv *service.TokenService) *F
 // RegisterAdmin 注册文件后端路由
 func (h *FileAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 	privateGroup := r.Group("/file")
-	privateGroup.Use(middleware.AuthMiddleware(h.tokenService))
+	privateGroup.Use(middleware.AuthMiddleware(h.tokenService), middleware.PermissionMiddleware(enum.PermissionUploadFile))
 
 	// 文件存储方式相关路由
 	fileStorageModeRouting := privateGroup.Group("/mode")
+	fileStorageModeRouting.Use(middleware.PermissionMiddleware(enum.PermissionManageSettings))
 	{
 		// 获取已经设置的所有存储方式
 		fileStorageModeRouting.GET("", h.getModes)
@@ -212,7 +213,7 @@ func (h *FileAdminHandler) deleteFilesByIds(c *gin.Context) {
 		response.ParamMismatch(c)
 		return
 	}
-	ret, err := h.fileService.DeleteFiles(c, ids)
+	ret, err := h.fileService.DeleteFiles(c, middleware.CurrentOperator(c), ids)
 	if err != nil {
 		response.FailAndResponse(c, err.Error())
 		return
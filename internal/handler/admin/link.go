@@ -27,7 +27,7 @@ func NewLinkAdminHandler(linkService *service.LinkService, tsv *service.TokenSer
 // RegisterAdmin 注册友情链接后端路由
 func (h *LinkAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 	privateGroup := r.Group("/link")
-	privateGroup.Use(middleware.AuthMiddleware(h.tokenService))
+	privateGroup.Use(middleware.AuthMiddleware(h.tokenService), middleware.PermissionMiddleware(enum.PermissionManageContent))
 	{
 		// 添加友联
 		privateGroup.POST("", h.addLink)
@@ -2,6 +2,7 @@ package admin
 
 import (
 	"nola-go/internal/middleware"
+	"nola-go/internal/models/enum"
 	"nola-go/internal/models/request"
 	"nola-go/internal/models/response"
 	"nola-go/internal/service"
@@ -25,7 +26,7 @@ func NewMenuAdminHandler(msv *service.MenuService, tsv *service.TokenService) *M
 
 func (h *MenuAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 	privateGroup := r.Group("/menu")
-	privateGroup.Use(middleware.AuthMiddleware(h.tokenService))
+	privateGroup.Use(middleware.AuthMiddleware(h.tokenService), middleware.PermissionMiddleware(enum.PermissionManageContent))
 	{
 		// 添加菜单
 		privateGroup.POST("", h.addMenu)
@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"errors"
 	"nola-go/internal/logger"
 	"nola-go/internal/middleware"
 	"nola-go/internal/models/enum"
@@ -28,7 +29,7 @@ func NewPostAdminHandler(psv *service.PostService, tsv *service.TokenService) *P
 
 func (h *PostAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 	privateGroup := r.Group("/post")
-	privateGroup.Use(middleware.AuthMiddleware(h.tokenService))
+	privateGroup.Use(middleware.AuthMiddleware(h.tokenService), middleware.PermissionMiddleware(enum.PermissionWritePost))
 	{
 		// 添加文章
 		privateGroup.POST("", h.addPost)
@@ -48,6 +49,8 @@ func (h *PostAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 		privateGroup.GET("/content/:id", h.getAllContent)
 		// 获取文章 - 根据文章 ID
 		privateGroup.GET("/:id", h.getPostById)
+		// 获取所有定时发布的文章
+		privateGroup.GET("/scheduled", h.getScheduledPosts)
 		// 获取文章 - 根据文章别名
 		privateGroup.GET("/slug/:slug", h.getPostBySlug)
 		// 修改文章正文
@@ -66,6 +69,14 @@ func (h *PostAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 		privateGroup.PUT("/draft/publish", h.updatePostDraftToPublish)
 		// 获取文章草稿
 		privateGroup.GET("/:id/draft/:draftName", h.getPostDraft)
+		// 获取文章所有修订版本
+		privateGroup.GET("/:id/revision", h.getPostRevisions)
+		// 比较文章的两个修订版本
+		privateGroup.GET("/:id/revision/diff", h.getPostRevisionDiff)
+		// 获取文章修订版本
+		privateGroup.GET("/:id/revision/:revisionId", h.getPostRevision)
+		// 恢复文章修订版本为正文或新草稿
+		privateGroup.PUT("/:id/revision/:revisionId/restore", h.restorePostRevision)
 	}
 }
 
@@ -84,9 +95,9 @@ func (h *PostAdminHandler) addPost(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.postService.AddPost(c, req)
+	ret, err := h.postService.AddPost(c, middleware.CurrentOperator(c), req)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 
@@ -101,9 +112,9 @@ func (h *PostAdminHandler) recyclePost(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.postService.UpdatePostStatusToDeleted(c, ids)
+	ret, err := h.postService.UpdatePostStatusToDeleted(c, middleware.CurrentOperator(c), ids)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -132,16 +143,16 @@ func (h *PostAdminHandler) restorePost(c *gin.Context) {
 		return
 	}
 
-	// 状态不能为已删除
-	if *statusEnum == enum.PostStatusDeleted {
+	// 状态不能为已删除，恢复时没有定时发布时间，也不能设为定时发布
+	if *statusEnum == enum.PostStatusDeleted || *statusEnum == enum.PostStatusScheduled {
 		response.ParamMismatch(c)
 		return
 	}
 
-	ret, err := h.postService.UpdatePostStatusTo(c, ids, *statusEnum)
+	ret, err := h.postService.UpdatePostStatusTo(c, middleware.CurrentOperator(c), ids, *statusEnum)
 
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -155,9 +166,9 @@ func (h *PostAdminHandler) deletePost(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.postService.DeletePosts(c, ids)
+	ret, err := h.postService.DeletePosts(c, middleware.CurrentOperator(c), ids)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -183,9 +194,9 @@ func (h *PostAdminHandler) updatePost(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.postService.UpdatePost(c, req)
+	ret, err := h.postService.UpdatePost(c, middleware.CurrentOperator(c), req)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -199,9 +210,9 @@ func (h *PostAdminHandler) updatePostStatus(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.postService.UpdatePostStatus(c, req)
+	ret, err := h.postService.UpdatePostStatus(c, middleware.CurrentOperator(c), req)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -301,9 +312,9 @@ func (h *PostAdminHandler) getAllContent(c *gin.Context) {
 		response.ParamMismatch(c)
 		return
 	}
-	ret, err := h.postService.PostContents(c, req.Id)
+	ret, err := h.postService.PostContents(c, middleware.CurrentOperator(c), req.Id)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -326,6 +337,16 @@ func (h *PostAdminHandler) getPostById(c *gin.Context) {
 	response.OkAndResponse(c, ret)
 }
 
+// getScheduledPosts 获取所有定时发布的文章（按发布时间升序）
+func (h *PostAdminHandler) getScheduledPosts(c *gin.Context) {
+	ret, err := h.postService.ScheduledPosts(c)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+	response.OkAndResponse(c, ret)
+}
+
 // getPostBySlug 获取文章 - 根据文章别名
 func (h *PostAdminHandler) getPostBySlug(c *gin.Context) {
 	var req struct {
@@ -350,9 +371,9 @@ func (h *PostAdminHandler) updatePostContent(c *gin.Context) {
 		response.ParamMismatch(c)
 		return
 	}
-	ret, err := h.postService.UpdatePostContent(c, *req, enum.PostContentStatusPublished, nil)
+	ret, err := h.postService.UpdatePostContent(c, middleware.CurrentOperator(c), *req, enum.PostContentStatusPublished, nil)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -369,9 +390,9 @@ func (h *PostAdminHandler) getPostContent(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.postService.PostContent(c, req.Id, enum.PostContentStatusPublished, nil)
+	ret, err := h.postService.AdminPostContent(c, middleware.CurrentOperator(c), req.Id, enum.PostContentStatusPublished, nil)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -385,9 +406,9 @@ func (h *PostAdminHandler) addPostDraft(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.postService.AddPostDraft(c, req)
+	ret, err := h.postService.AddPostDraft(c, middleware.CurrentOperator(c), req)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -407,9 +428,9 @@ func (h *PostAdminHandler) deletePostDraft(c *gin.Context) {
 		response.ParamMismatch(c)
 		return
 	}
-	ret, err := h.postService.DeletePostContent(c, uri.Id, enum.PostContentStatusDraft, draftNames)
+	ret, err := h.postService.DeletePostContent(c, middleware.CurrentOperator(c), uri.Id, enum.PostContentStatusDraft, draftNames)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -422,9 +443,9 @@ func (h *PostAdminHandler) updatePostDraft(c *gin.Context) {
 		response.ParamMismatch(c)
 		return
 	}
-	ret, err := h.postService.UpdatePostContent(c, request.PostContentRequest{PostId: req.PostId, Content: req.Content}, enum.PostContentStatusDraft, &req.DraftName)
+	ret, err := h.postService.UpdatePostContent(c, middleware.CurrentOperator(c), request.PostContentRequest{PostId: req.PostId, Content: req.Content}, enum.PostContentStatusDraft, &req.DraftName)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -445,9 +466,9 @@ func (h *PostAdminHandler) updatePostDraftName(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.postService.UpdatePostDraftName(c, req.PostId, req.OldName, req.NewName)
+	ret, err := h.postService.UpdatePostDraftName(c, middleware.CurrentOperator(c), req.PostId, req.OldName, req.NewName)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -469,9 +490,9 @@ func (h *PostAdminHandler) updatePostDraftToPublish(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.postService.UpdatePostDraftToContent(c, req.PostId, req.DraftName, req.DeleteContent, req.ContentName)
+	ret, err := h.postService.UpdatePostDraftToContent(c, middleware.CurrentOperator(c), req.PostId, req.DraftName, req.DeleteContent, req.ContentName)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
@@ -489,10 +510,118 @@ func (h *PostAdminHandler) getPostDraft(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.postService.PostContent(c, req.Id, enum.PostContentStatusDraft, &req.DraftName)
+	ret, err := h.postService.AdminPostContent(c, middleware.CurrentOperator(c), req.Id, enum.PostContentStatusDraft, &req.DraftName)
 	if err != nil {
-		response.FailAndResponse(c, err.Error())
+		postFailAndResponse(c, err)
+		return
+	}
+	response.OkAndResponse(c, ret)
+}
+
+// getPostRevisions 获取文章所有修订版本
+func (h *PostAdminHandler) getPostRevisions(c *gin.Context) {
+	var req struct {
+		Id uint `uri:"id"`
+	}
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.postService.PostRevisions(c, middleware.CurrentOperator(c), req.Id)
+	if err != nil {
+		postFailAndResponse(c, err)
+		return
+	}
+	response.OkAndResponse(c, ret)
+}
+
+// getPostRevisionDiff 比较文章的两个修订版本
+func (h *PostAdminHandler) getPostRevisionDiff(c *gin.Context) {
+	var uri struct {
+		Id uint `uri:"id"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	var req struct {
+		// From 旧修订版本 ID
+		From uint `form:"from" binding:"required"`
+		// To 新修订版本 ID
+		To uint `form:"to" binding:"required"`
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.postService.PostRevisionDiff(c, middleware.CurrentOperator(c), uri.Id, req.From, req.To)
+	if err != nil {
+		postFailAndResponse(c, err)
 		return
 	}
 	response.OkAndResponse(c, ret)
 }
+
+// getPostRevision 获取文章修订版本
+func (h *PostAdminHandler) getPostRevision(c *gin.Context) {
+	var req struct {
+		Id         uint `uri:"id"`
+		RevisionId uint `uri:"revisionId"`
+	}
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.postService.PostRevision(c, middleware.CurrentOperator(c), req.Id, req.RevisionId)
+	if err != nil {
+		postFailAndResponse(c, err)
+		return
+	}
+	response.OkAndResponse(c, ret)
+}
+
+// restorePostRevision 恢复文章修订版本，不传草稿名时恢复为文章正文
+func (h *PostAdminHandler) restorePostRevision(c *gin.Context) {
+	var uri struct {
+		Id         uint `uri:"id"`
+		RevisionId uint `uri:"revisionId"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	var req struct {
+		// DraftName 恢复为新草稿时的草稿名
+		DraftName *string `json:"draftName"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	if req.DraftName != nil && util.StringIsBlank(*req.DraftName) {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.postService.RestorePostRevision(c, middleware.CurrentOperator(c), uri.Id, uri.RevisionId, req.DraftName)
+	if err != nil {
+		postFailAndResponse(c, err)
+		return
+	}
+	response.OkAndResponse(c, ret)
+}
+
+// postFailAndResponse 获取或修改文章失败并直接返回失败响应体，没有权限时返回 403
+func postFailAndResponse(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrPermissionDenied) {
+		response.ForbiddenAndResponse(c)
+		return
+	}
+	response.FailAndResponse(c, err.Error())
+}
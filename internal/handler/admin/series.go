@@ -0,0 +1,214 @@
+package admin
+
+import (
+	"nola-go/internal/middleware"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/service"
+	"nola-go/internal/util"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeriesAdminHandler 系列后端接口
+type SeriesAdminHandler struct {
+	seriesService *service.SeriesService
+	tokenService  *service.TokenService
+}
+
+func NewSeriesAdminHandler(seriesService *service.SeriesService, tsv *service.TokenService) *SeriesAdminHandler {
+	return &SeriesAdminHandler{
+		seriesService: seriesService,
+		tokenService:  tsv,
+	}
+}
+
+// RegisterAdmin 注册系列后端路由
+func (h *SeriesAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
+
+	// 鉴权接口
+	privateGroup := r.Group("/series")
+	privateGroup.Use(middleware.AuthMiddleware(h.tokenService))
+	// 修改需要管理内容权限，所有登录用户都可以获取（撰写文章时使用）
+	manage := middleware.PermissionMiddleware(enum.PermissionManageContent)
+	{
+		// 添加系列
+		privateGroup.POST("", manage, h.addSeries)
+		// 根据系列 ID 数组删除系列
+		privateGroup.DELETE("", manage, h.deleteSeriesByIds)
+		// 根据系列别名数组删除系列
+		privateGroup.DELETE("/slug", manage, h.deleteSeriesBySlugs)
+		// 修改系列
+		privateGroup.PUT("", manage, h.updateSeries)
+		// 设置系列中的文章及顺序
+		privateGroup.PUT("/:id/posts", manage, h.updateSeriesPosts)
+		// 根据系列 ID 获取系列
+		privateGroup.GET("/:id", h.seriesById)
+		// 获取系列中的文章
+		privateGroup.GET("/:id/posts", h.seriesPosts)
+		// 分页获取系列
+		privateGroup.GET("", h.series)
+	}
+}
+
+// addSeries 添加系列
+func (h *SeriesAdminHandler) addSeries(c *gin.Context) {
+	var req struct {
+		DisplayName string  `json:"displayName" binding:"required"`
+		Slug        string  `json:"slug" binding:"required"`
+		Description *string `json:"description"`
+		Cover       *string `json:"cover"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	if util.StringIsBlank(req.DisplayName) || util.StringIsBlank(req.Slug) {
+		// 系列名或别名为空
+		response.ParamMismatch(c)
+		return
+	}
+
+	series, err := h.seriesService.AddSeries(c, req.DisplayName, req.Slug, req.Description, req.Cover)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+	response.OkAndResponse(c, series)
+}
+
+// deleteSeriesByIds 根据系列 ID 数组删除系列
+func (h *SeriesAdminHandler) deleteSeriesByIds(c *gin.Context) {
+	var seriesIds []uint
+	if err := c.ShouldBindJSON(&seriesIds); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	if len(seriesIds) == 0 {
+		response.OkAndResponse(c, false)
+		return
+	}
+
+	ret, err := h.seriesService.DeleteSeries(c, seriesIds)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
+
+// deleteSeriesBySlugs 根据系列别名数组删除系列
+func (h *SeriesAdminHandler) deleteSeriesBySlugs(c *gin.Context) {
+	var slugs []string
+
+	if err := c.ShouldBindJSON(&slugs); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.seriesService.DeleteSeriesBySlugs(c, slugs)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
+
+// updateSeries 修改系列
+func (h *SeriesAdminHandler) updateSeries(c *gin.Context) {
+	var series *models.Series
+
+	if err := c.ShouldBindJSON(&series); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.seriesService.UpdateSeries(c, series)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
+
+// updateSeriesPosts 设置系列中的文章及顺序，请求体为按顺序排列的文章 ID 数组
+func (h *SeriesAdminHandler) updateSeriesPosts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	var postIds []uint
+	if err := c.ShouldBindJSON(&postIds); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.seriesService.UpdateSeriesPosts(c, uint(id), postIds)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
+
+// seriesById 根据系列 ID 获取系列
+func (h *SeriesAdminHandler) seriesById(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	series, err := h.seriesService.SeriesById(c, uint(id))
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, series)
+}
+
+// seriesPosts 获取系列中的文章，按文章在系列中的顺序
+func (h *SeriesAdminHandler) seriesPosts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	posts, err := h.seriesService.SeriesPosts(c, uint(id))
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, posts)
+}
+
+// series 分页获取系列
+func (h *SeriesAdminHandler) series(c *gin.Context) {
+	page, size, err := util.ShouldBindPager(c)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	series, err := h.seriesService.SeriesPager(c, page, size)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, series)
+}
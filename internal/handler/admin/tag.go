@@ -3,6 +3,7 @@ package admin
 import (
 	"nola-go/internal/middleware"
 	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
 	"nola-go/internal/models/response"
 	"nola-go/internal/service"
 	"nola-go/internal/util"
@@ -30,15 +31,17 @@ func (h *TagAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 	// 鉴权接口
 	privateGroup := r.Group("/tag")
 	privateGroup.Use(middleware.AuthMiddleware(h.tokenService))
+	// 修改需要管理内容权限，所有登录用户都可以获取（撰写文章时使用）
+	manage := middleware.PermissionMiddleware(enum.PermissionManageContent)
 	{
 		// 添加标签
-		privateGroup.POST("", h.addTag)
+		privateGroup.POST("", manage, h.addTag)
 		// 根据标签 ID 数组删除标签
-		privateGroup.DELETE("", h.deleteTagByIds)
+		privateGroup.DELETE("", manage, h.deleteTagByIds)
 		// 根据标签别名数组删除标签
-		privateGroup.DELETE("/slug", h.deleteTagBySlugs)
+		privateGroup.DELETE("/slug", manage, h.deleteTagBySlugs)
 		// 修改标签
-		privateGroup.PUT("", h.updateTag)
+		privateGroup.PUT("", manage, h.updateTag)
 		// 根据标签 ID 获取标签
 		privateGroup.GET("/:id", h.tagById)
 		// 分页获取标签
@@ -1,7 +1,9 @@
 package admin
 
 import (
+	"errors"
 	"nola-go/internal/middleware"
+	"nola-go/internal/models/enum"
 	"nola-go/internal/models/request"
 	"nola-go/internal/models/response"
 	"nola-go/internal/service"
@@ -22,9 +24,9 @@ func NewUserAdminHandler(s *service.UserService, tsv *service.TokenService) *Use
 
 // RegisterAdmin 注册用户后端路由
 func (h *UserAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
-	// 需要鉴权接口
+	// 需要鉴权接口（账户安全相关，不能使用 API 令牌访问）
 	privateGroup := r.Group("/user")
-	privateGroup.Use(middleware.AuthMiddleware(h.tokenService))
+	privateGroup.Use(middleware.AuthMiddleware(h.tokenService), middleware.SessionOnlyMiddleware())
 	{
 		// 验证登录是否过期
 		privateGroup.GET("/validate", func(c *gin.Context) {
@@ -36,7 +38,40 @@ func (h *UserAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 		privateGroup.PUT("", h.updateUser)
 		// 修改密码
 		privateGroup.PUT("/password", h.updatePassword)
+		// 获取登录用户的所有会话
+		privateGroup.GET("/sessions", h.getSessions)
+		// 注销登录用户的会话
+		privateGroup.DELETE("/sessions/:sessionId", h.revokeSession)
+		// 获取两步验证状态
+		privateGroup.GET("/2fa", h.getTotpStatus)
+		// 设置两步验证（生成密钥和二维码地址）
+		privateGroup.POST("/2fa/setup", h.setupTotp)
+		// 开启两步验证
+		privateGroup.POST("/2fa/enable", h.enableTotp)
+		// 关闭两步验证
+		privateGroup.POST("/2fa/disable", h.disableTotp)
+		// 重新生成恢复码
+		privateGroup.POST("/2fa/recovery-codes", h.regenerateRecoveryCodes)
+		// 获取登录用户的所有 API 令牌
+		privateGroup.GET("/tokens", h.getApiTokens)
+		// 创建 API 令牌
+		privateGroup.POST("/tokens", h.addApiToken)
+		// 删除 API 令牌
+		privateGroup.DELETE("/tokens/:id", h.revokeApiToken)
+	}
 
+	// 用户管理接口（需要管理用户权限）
+	manageGroup := r.Group("/users")
+	manageGroup.Use(middleware.AuthMiddleware(h.tokenService), middleware.PermissionMiddleware(enum.PermissionManageUsers))
+	{
+		// 获取所有用户
+		manageGroup.GET("", h.getUsers)
+		// 添加用户
+		manageGroup.POST("", h.addUser)
+		// 修改用户角色
+		manageGroup.PUT("/:id/role", h.updateUserRole)
+		// 删除用户
+		manageGroup.DELETE("/:id", h.deleteUser)
 	}
 
 	// 无需鉴权接口
@@ -44,6 +79,10 @@ func (h *UserAdminHandler) RegisterAdmin(r *gin.RouterGroup) {
 	{
 		// 用户登录
 		publicGroup.POST("/login", h.loginUser)
+		// 两步验证登录
+		publicGroup.POST("/login/2fa", h.loginTwoFactor)
+		// 刷新令牌（访问令牌可能已经过期，所以无需鉴权）
+		publicGroup.POST("/refresh", h.refreshToken)
 	}
 }
 
@@ -65,6 +104,80 @@ func (h *UserAdminHandler) getLoginUser(c *gin.Context) {
 	response.OkAndResponse(c, user)
 }
 
+// getUsers 获取所有用户
+func (h *UserAdminHandler) getUsers(c *gin.Context) {
+	res, err := h.userService.AllUsers(c)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// addUser 添加用户
+func (h *UserAdminHandler) addUser(c *gin.Context) {
+	var req request.UserCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	res, err := h.userService.CreateUser(c, middleware.CurrentOperator(c), &req)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// updateUserRole 修改用户角色
+func (h *UserAdminHandler) updateUserRole(c *gin.Context) {
+	var uri struct {
+		Id uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	var req struct {
+		Role enum.UserRole `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	res, err := h.userService.UpdateUserRole(c, middleware.CurrentOperator(c), uri.Id, req.Role)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// deleteUser 删除用户，用户的文章转移给当前登录用户
+func (h *UserAdminHandler) deleteUser(c *gin.Context) {
+	var uri struct {
+		Id uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	res, err := h.userService.DeleteUser(c, middleware.CurrentOperator(c), uri.Id)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
 // updateUser 修改登录用户信息
 func (h *UserAdminHandler) updateUser(c *gin.Context) {
 	userId := c.GetUint("uid")
@@ -128,8 +241,291 @@ func (h *UserAdminHandler) loginUser(c *gin.Context) {
 		return
 	}
 
-	res, err := h.userService.Login(c, req.Username, req.Password)
+	res, challenge, err := h.userService.Login(c, req.Username, req.Password, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		var tooMany *response.TooManyRequestsError
+		if errors.As(err, &tooMany) {
+			response.TooManyRequestsAndResponse(c, tooMany)
+			return
+		}
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	// 用户开启了两步验证，返回挑战
+	if challenge != nil {
+		response.OkAndResponse(c, challenge)
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// loginTwoFactor 使用两步验证挑战令牌和验证码（或恢复码）完成登录
+func (h *UserAdminHandler) loginTwoFactor(c *gin.Context) {
+	var req struct {
+		Challenge string `json:"challenge" binding:"required"`
+		Code      string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	res, err := h.userService.LoginTwoFactor(c, req.Challenge, req.Code, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		if errors.Is(err, service.ErrChallengeInvalid) {
+			response.UnauthorizedAndResponse(c)
+			return
+		}
+		var tooMany *response.TooManyRequestsError
+		if errors.As(err, &tooMany) {
+			response.TooManyRequestsAndResponse(c, tooMany)
+			return
+		}
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// getTotpStatus 获取登录用户的两步验证状态
+func (h *UserAdminHandler) getTotpStatus(c *gin.Context) {
+	userId := c.GetUint("uid")
+
+	if userId == 0 {
+		response.UnauthorizedAndResponse(c)
+		return
+	}
+
+	res, err := h.userService.TotpStatus(c, userId)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// setupTotp 为登录用户生成 TOTP 密钥
+func (h *UserAdminHandler) setupTotp(c *gin.Context) {
+	userId := c.GetUint("uid")
+
+	if userId == 0 {
+		response.UnauthorizedAndResponse(c)
+		return
+	}
+
+	res, err := h.userService.SetupTotp(c, userId)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// enableTotp 验证验证码并开启两步验证，返回恢复码
+func (h *UserAdminHandler) enableTotp(c *gin.Context) {
+	userId := c.GetUint("uid")
+
+	if userId == 0 {
+		response.UnauthorizedAndResponse(c)
+		return
+	}
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	codes, err := h.userService.EnableTotp(c, userId, req.Code)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, &response.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// disableTotp 关闭两步验证
+func (h *UserAdminHandler) disableTotp(c *gin.Context) {
+	userId := c.GetUint("uid")
+
+	if userId == 0 {
+		response.UnauthorizedAndResponse(c)
+		return
+	}
+
+	var req struct {
+		Password string `json:"password" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	res, err := h.userService.DisableTotp(c, userId, req.Password, req.Code)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// regenerateRecoveryCodes 重新生成恢复码
+func (h *UserAdminHandler) regenerateRecoveryCodes(c *gin.Context) {
+	userId := c.GetUint("uid")
+
+	if userId == 0 {
+		response.UnauthorizedAndResponse(c)
+		return
+	}
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	codes, err := h.userService.RegenerateRecoveryCodes(c, userId, req.Code)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, &response.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// getSessions 获取登录用户的所有会话
+func (h *UserAdminHandler) getSessions(c *gin.Context) {
+	userId := c.GetUint("uid")
+
+	if userId == 0 {
+		response.UnauthorizedAndResponse(c)
+		return
+	}
+
+	res, err := h.tokenService.Sessions(c, userId, c.GetString("sid"))
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// revokeSession 注销登录用户的会话
+func (h *UserAdminHandler) revokeSession(c *gin.Context) {
+	userId := c.GetUint("uid")
+
+	if userId == 0 {
+		response.UnauthorizedAndResponse(c)
+		return
+	}
+
+	sessionId := c.Param("sessionId")
+	if sessionId == "" {
+		response.ParamMismatch(c)
+		return
+	}
+
+	res, err := h.tokenService.RevokeSession(c, userId, sessionId)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// getApiTokens 获取登录用户的所有 API 令牌
+func (h *UserAdminHandler) getApiTokens(c *gin.Context) {
+	userId := c.GetUint("uid")
+
+	if userId == 0 {
+		response.UnauthorizedAndResponse(c)
+		return
+	}
+
+	res, err := h.tokenService.ApiTokens(c, userId)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// addApiToken 为登录用户创建 API 令牌，令牌明文只在创建时返回一次
+func (h *UserAdminHandler) addApiToken(c *gin.Context) {
+	var req request.ApiTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	res, err := h.tokenService.CreateApiToken(c, middleware.CurrentOperator(c), &req)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// revokeApiToken 删除登录用户的 API 令牌
+func (h *UserAdminHandler) revokeApiToken(c *gin.Context) {
+	userId := c.GetUint("uid")
+
+	if userId == 0 {
+		response.UnauthorizedAndResponse(c)
+		return
+	}
+
+	var uri struct {
+		Id uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	res, err := h.tokenService.RevokeApiToken(c, userId, uri.Id)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, res)
+}
+
+// refreshToken 使用刷新令牌换取新的访问令牌和刷新令牌
+func (h *UserAdminHandler) refreshToken(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refreshToken" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	res, err := h.tokenService.Refresh(c, req.RefreshToken)
 	if err != nil {
+		if errors.Is(err, service.ErrRefreshTokenInvalid) {
+			response.UnauthorizedAndResponse(c)
+			return
+		}
 		response.FailAndResponse(c, err.Error())
 		return
 	}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"nola-go/internal/models/response"
+	"nola-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CaptchaApiHandler 验证码博客接口
+type CaptchaApiHandler struct {
+	captchaService *service.CaptchaService
+}
+
+func NewCaptchaApiHandler(captchaService *service.CaptchaService) *CaptchaApiHandler {
+	return &CaptchaApiHandler{
+		captchaService: captchaService,
+	}
+}
+
+// RegisterApi 注册验证码博客路由
+func (h *CaptchaApiHandler) RegisterApi(r *gin.RouterGroup) {
+	// 获取评论验证码
+	r.GET("/captcha", h.getCaptcha)
+}
+
+// getCaptcha 获取评论验证码，未启用时返回 null，提交评论时不需要验证码
+func (h *CaptchaApiHandler) getCaptcha(c *gin.Context) {
+	ret, err := h.captchaService.Challenge(c)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
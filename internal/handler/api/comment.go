@@ -5,6 +5,7 @@ import (
 	"nola-go/internal/models/request"
 	"nola-go/internal/models/response"
 	"nola-go/internal/service"
+	"nola-go/internal/spam"
 	"nola-go/internal/util"
 
 	"github.com/gin-gonic/gin"
@@ -13,11 +14,13 @@ import (
 // CommentApiHandler 评论博客接口
 type CommentApiHandler struct {
 	commentService *service.CommentService
+	captchaService *service.CaptchaService
 }
 
-func NewCommentApiHandler(csv *service.CommentService) *CommentApiHandler {
+func NewCommentApiHandler(csv *service.CommentService, capsv *service.CaptchaService) *CommentApiHandler {
 	return &CommentApiHandler{
 		commentService: csv,
+		captchaService: capsv,
 	}
 }
 
@@ -30,6 +33,8 @@ func (h *CommentApiHandler) RegisterApi(r *gin.RouterGroup) {
 		publicGroup.POST("", h.addComment)
 		// 根据文章 ID 或别名获取评论
 		publicGroup.GET("", h.getComments)
+		// 获取评论表单令牌
+		publicGroup.GET("/token", h.getFormToken)
 	}
 }
 
@@ -41,7 +46,7 @@ func (h *CommentApiHandler) addComment(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.commentService.AddComment(c, models.Comment{
+	comment := models.Comment{
 		PostId:          req.PostId,
 		ParentCommentId: req.ParentCommentId,
 		ReplyCommentId:  req.ReplyCommentId,
@@ -50,14 +55,34 @@ func (h *CommentApiHandler) addComment(c *gin.Context) {
 		DisplayName:     req.DisplayName,
 		Email:           req.Email,
 		IsPass:          false,
-	}, true)
+	}
+
+	// 先检查填写的内容，填写错误时不消耗验证码
+	if err := service.ValidateComment(&comment); err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	// 启用评论验证码时验证
+	if err := h.captchaService.Verify(c, req.CaptchaId, req.Captcha); err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	ret, err := h.commentService.AddComment(c, comment, true, &spam.Client{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Referrer:  c.Request.Referer(),
+		Honeypot:  req.Homepage,
+		FormToken: req.Token,
+	})
 
 	if err != nil {
 		response.FailAndResponse(c, err.Error())
 		return
 	}
 
-	response.OkAndResponse(c, ret)
+	response.OkAndResponse(c, h.commentService.ApiComment(ret))
 
 }
 
@@ -101,7 +126,7 @@ func (h *CommentApiHandler) getComments(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.commentService.Comments(c, page, size, postId, req.Slug, nil, nil, util.BoolPtr(true), nil, nil, true)
+	ret, err := h.commentService.ApiComments(c, page, size, postId, req.Slug)
 
 	if err != nil {
 		response.FailAndResponse(c, err.Error())
@@ -109,3 +134,8 @@ func (h *CommentApiHandler) getComments(c *gin.Context) {
 	}
 	response.OkAndResponse(c, ret)
 }
+
+// getFormToken 获取评论表单令牌，博客前端打开评论框时获取，提交评论时放在 token 字段中
+func (h *CommentApiHandler) getFormToken(c *gin.Context) {
+	response.OkAndResponse(c, h.commentService.CommentFormToken())
+}
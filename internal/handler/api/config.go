@@ -41,11 +41,11 @@ func (h *ConfigApiHandler) getBlogInfo(c *gin.Context) {
 	}
 
 	// 获取博主信息
-	users, err := h.userService.AllUsers(c)
+	blogger, err := h.userService.Blogger(c)
 
-	if len(users) != 0 && blogInfo != nil {
-		// 博主数量不为空，填充博主名称
-		blogInfo.Blogger = &users[0].DisplayName
+	if blogger != nil && blogInfo != nil {
+		// 博主不为空，填充博主名称
+		blogInfo.Blogger = &blogger.DisplayName
 	}
 
 	response.OkAndResponse(c, blogInfo)
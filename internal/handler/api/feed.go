@@ -0,0 +1,66 @@
+package api
+
+import (
+	"nola-go/internal/feed"
+	"nola-go/internal/models/response"
+	"nola-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedApiHandler RSS / Atom 订阅 Handler
+type FeedApiHandler struct {
+	feedService *service.FeedService
+}
+
+// NewFeedApiHandler 新建订阅 Handler
+func NewFeedApiHandler(feedService *service.FeedService) *FeedApiHandler {
+	return &FeedApiHandler{
+		feedService: feedService,
+	}
+}
+
+// RegisterFeed 注册订阅路由，订阅地址位于站点根路径下
+func (h *FeedApiHandler) RegisterFeed(r gin.IRouter) {
+	// 所有文章
+	r.GET("/feed.xml", h.getFeed(feed.FormatRSS, service.FeedScopeAll))
+	r.GET("/atom.xml", h.getFeed(feed.FormatAtom, service.FeedScopeAll))
+	// 指定标签、分类的文章
+	r.GET("/feed/tag/:slug", h.getFeed(feed.FormatRSS, service.FeedScopeTag))
+	r.GET("/feed/category/:slug", h.getFeed(feed.FormatRSS, service.FeedScopeCategory))
+	r.GET("/atom/tag/:slug", h.getFeed(feed.FormatAtom, service.FeedScopeTag))
+	r.GET("/atom/category/:slug", h.getFeed(feed.FormatAtom, service.FeedScopeCategory))
+}
+
+// getFeed 获取订阅
+func (h *FeedApiHandler) getFeed(format feed.Format, scope service.FeedScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		f, err := h.feedService.Feed(c, requestOrigin(c), c.Request.URL.Path, scope, c.Param("slug"))
+		if err != nil {
+			response.FailAndResponse(c, err.Error())
+			return
+		}
+		if f == nil {
+			// 标签或分类不存在
+			response.NotFoundAndResponse(c)
+			return
+		}
+
+		body, err := f.Render(format)
+		if err != nil {
+			response.FailAndResponse(c, response.ServerError.Error())
+			return
+		}
+		response.CacheableAndResponse(c, format.ContentType(), body, f.Updated)
+	}
+}
+
+// requestOrigin 获取请求的地址（协议和主机），博客信息中没有设置前端地址时用于生成绝对链接
+// 只有经过受信任的代理转发（客户端 IP 来自 X-Forwarded-For）时才使用 X-Forwarded-Proto
+func requestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || (c.ClientIP() != c.RemoteIP() && c.GetHeader("X-Forwarded-Proto") == "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
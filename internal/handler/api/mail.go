@@ -0,0 +1,115 @@
+package api
+
+import (
+	"html/template"
+	"net/http"
+	"nola-go/internal/models/response"
+	"nola-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unsubscribePage 退订确认和结果页面
+// 邮箱扫描和链接预取会自动访问邮件中的链接，因此 GET 只显示确认按钮，提交表单后才退订
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><meta name="robots" content="noindex"><title>退订邮件通知</title></head>
+<body style="font-family: -apple-system, 'PingFang SC', 'Microsoft YaHei', sans-serif; color: #333; text-align: center; padding-top: 80px;">
+<p>{{.Message}}</p>
+{{- if .Confirm}}
+<form method="post" action="unsubscribe/confirm">
+<input type="hidden" name="email" value="{{.Email}}">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">确认退订</button>
+</form>
+{{- end}}
+</body>
+</html>`))
+
+// unsubscribePageData 退订页面数据
+type unsubscribePageData struct {
+	// Message 提示信息
+	Message string
+	// Confirm 是否显示确认退订的表单
+	Confirm bool
+	// Email 邮箱
+	Email string
+	// Token 退订链接中的签名
+	Token string
+}
+
+// MailApiHandler 邮件博客接口
+type MailApiHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewMailApiHandler(notificationService *service.NotificationService) *MailApiHandler {
+	return &MailApiHandler{
+		notificationService: notificationService,
+	}
+}
+
+// RegisterApi 注册邮件博客路由
+func (h *MailApiHandler) RegisterApi(r *gin.RouterGroup) {
+
+	publicGroup := r.Group("/mail")
+	{
+		// 退订确认页面（邮件中的退订链接），返回 HTML 页面，不会直接退订
+		publicGroup.GET("/unsubscribe", h.getUnsubscribe)
+		// 退订邮件通知（退订确认页面提交的表单），返回 HTML 页面
+		publicGroup.POST("/unsubscribe/confirm", h.confirmUnsubscribe)
+		// 退订邮件通知（邮件客户端的一键退订，RFC 8058）
+		publicGroup.POST("/unsubscribe", h.postUnsubscribe)
+	}
+}
+
+// getUnsubscribe 显示退订确认页面
+func (h *MailApiHandler) getUnsubscribe(c *gin.Context) {
+	email, token := c.Query("email"), c.Query("token")
+	if !h.notificationService.CheckUnsubscribeToken(email, token) {
+		renderUnsubscribePage(c, http.StatusBadRequest, unsubscribePageData{Message: "退订链接无效。"})
+		return
+	}
+
+	renderUnsubscribePage(c, http.StatusOK, unsubscribePageData{
+		Message: "确认退订后，" + email + " 将不会再收到评论回复的邮件通知。",
+		Confirm: true,
+		Email:   email,
+		Token:   token,
+	})
+}
+
+// confirmUnsubscribe 退订邮件通知，返回 HTML 页面
+func (h *MailApiHandler) confirmUnsubscribe(c *gin.Context) {
+	ok, err := h.notificationService.Unsubscribe(c, c.PostForm("email"), c.PostForm("token"))
+
+	status, message := http.StatusOK, "已退订，你将不会再收到评论回复的邮件通知。"
+	if err != nil {
+		status, message = http.StatusInternalServerError, "退订失败，请稍后重试。"
+	} else if !ok {
+		status, message = http.StatusBadRequest, "退订链接无效。"
+	}
+	renderUnsubscribePage(c, status, unsubscribePageData{Message: message})
+}
+
+// postUnsubscribe 退订邮件通知
+func (h *MailApiHandler) postUnsubscribe(c *gin.Context) {
+	ok, err := h.notificationService.Unsubscribe(c, c.Query("email"), c.Query("token"))
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+	if !ok {
+		response.FailAndResponse(c, "退订链接无效")
+		return
+	}
+
+	response.OkAndResponse(c, true)
+}
+
+// renderUnsubscribePage 返回退订页面
+func renderUnsubscribePage(c *gin.Context, status int, data unsubscribePageData) {
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	_ = unsubscribePage.Execute(c.Writer, data)
+}
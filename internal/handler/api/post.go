@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"nola-go/internal/models/enum"
 	"nola-go/internal/models/response"
 	"nola-go/internal/service"
@@ -12,12 +13,16 @@ import (
 
 // PostApiHandler 文章博客接口
 type PostApiHandler struct {
-	postService *service.PostService
+	postService   *service.PostService
+	seoService    *service.SeoService
+	seriesService *service.SeriesService
 }
 
-func NewPostApiHandler(psv *service.PostService) *PostApiHandler {
+func NewPostApiHandler(psv *service.PostService, seosv *service.SeoService, ssv *service.SeriesService) *PostApiHandler {
 	return &PostApiHandler{
-		postService: psv,
+		postService:   psv,
+		seoService:    seosv,
+		seriesService: ssv,
 	}
 }
 
@@ -28,6 +33,8 @@ func (h *PostApiHandler) RegisterApi(r *gin.RouterGroup) {
 		group.GET("", h.getPost)
 		// 获取文章 - 根据文章 ID
 		group.GET("/:id", h.getPostById)
+		// 获取文章 SEO 元数据
+		group.GET("/:id/seo", h.getPostSeo)
 		// 获取文章 - 根据别名
 		group.GET("/slug/:slug", h.getPostBySlug)
 		// 获取文章内容
@@ -117,6 +124,39 @@ func (h *PostApiHandler) getPostById(c *gin.Context) {
 		return
 	}
 
+	// 文章所属系列及系列导航
+	ret.Series, err = h.seriesService.PostSeries(c, ret.PostId)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
+
+// getPostSeo 获取文章 SEO 元数据
+func (h *PostApiHandler) getPostSeo(c *gin.Context) {
+	var req struct {
+		Id uint `uri:"id"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.seoService.PostSeo(c, req.Id, requestOrigin(c))
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	if ret == nil {
+		// 文章不存在，或者文章未发布，则返回 404
+		response.NotFoundAndResponse(c)
+		return
+	}
+
 	response.OkAndResponse(c, ret)
 }
 
@@ -141,6 +181,13 @@ func (h *PostApiHandler) getPostBySlug(c *gin.Context) {
 		response.NotFoundAndResponse(c)
 		return
 	}
+
+	// 文章所属系列及系列导航
+	ret.Series, err = h.seriesService.PostSeries(c, ret.PostId)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
 	response.OkAndResponse(c, ret)
 }
 
@@ -163,8 +210,13 @@ func (h *PostApiHandler) getPostContent(c *gin.Context) {
 		return
 	}
 
-	ret, err := h.postService.ApiPostContent(c, req.ID, req.Slug, req.Password)
+	ret, err := h.postService.ApiPostContent(c, req.ID, req.Slug, req.Password, c.ClientIP())
 	if err != nil {
+		var tooMany *response.TooManyRequestsError
+		if errors.As(err, &tooMany) {
+			response.TooManyRequestsAndResponse(c, tooMany)
+			return
+		}
 		response.FailAndResponse(c, err.Error())
 		return
 	}
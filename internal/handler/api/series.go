@@ -0,0 +1,75 @@
+package api
+
+import (
+	"nola-go/internal/models/response"
+	"nola-go/internal/service"
+	"nola-go/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeriesApiHandler 系列博客接口
+type SeriesApiHandler struct {
+	seriesService *service.SeriesService
+}
+
+func NewSeriesApiHandler(seriesService *service.SeriesService) *SeriesApiHandler {
+	return &SeriesApiHandler{
+		seriesService: seriesService,
+	}
+}
+
+// RegisterApi 注册系列博客路由
+func (h *SeriesApiHandler) RegisterApi(r *gin.RouterGroup) {
+
+	publicGroup := r.Group("/series")
+	{
+		// 分页获取系列
+		publicGroup.GET("", h.getSeries)
+		// 获取系列和系列中的文章 - 根据别名
+		publicGroup.GET("/:slug", h.getSeriesBySlug)
+	}
+}
+
+// getSeries 分页获取系列
+func (h *SeriesApiHandler) getSeries(c *gin.Context) {
+	page, size, err := util.ShouldBindPager(c)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	series, err := h.seriesService.SeriesPager(c, page, size)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	response.OkAndResponse(c, series)
+}
+
+// getSeriesBySlug 获取系列和系列中的文章 - 根据别名
+func (h *SeriesApiHandler) getSeriesBySlug(c *gin.Context) {
+	var req struct {
+		Slug string `uri:"slug"`
+	}
+
+	if err := c.ShouldBindUri(&req); err != nil {
+		response.ParamMismatch(c)
+		return
+	}
+
+	ret, err := h.seriesService.ApiSeries(c, req.Slug)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+
+	if ret == nil {
+		// 系列不存在
+		response.NotFoundAndResponse(c)
+		return
+	}
+
+	response.OkAndResponse(c, ret)
+}
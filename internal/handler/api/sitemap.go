@@ -0,0 +1,80 @@
+package api
+
+import (
+	"nola-go/internal/models/response"
+	"nola-go/internal/service"
+	"nola-go/internal/sitemap"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SitemapApiHandler 站点地图和 robots.txt Handler
+type SitemapApiHandler struct {
+	sitemapService *service.SitemapService
+}
+
+// NewSitemapApiHandler 新建站点地图 Handler
+func NewSitemapApiHandler(sitemapService *service.SitemapService) *SitemapApiHandler {
+	return &SitemapApiHandler{
+		sitemapService: sitemapService,
+	}
+}
+
+// RegisterSitemap 注册站点地图路由，位于站点根路径下
+func (h *SitemapApiHandler) RegisterSitemap(r gin.IRouter) {
+	// 站点地图（网址过多时为站点地图索引）
+	r.GET("/sitemap.xml", h.getSitemap)
+	// 拆分后的站点地图，如 /sitemap/1.xml
+	r.GET("/sitemap/:page", h.getSitemapPage)
+	// robots.txt
+	r.GET("/robots.txt", h.getRobots)
+}
+
+// getSitemap 获取站点地图
+func (h *SitemapApiHandler) getSitemap(c *gin.Context) {
+	h.sitemap(c, 0)
+}
+
+// getSitemapPage 获取拆分后的站点地图
+func (h *SitemapApiHandler) getSitemapPage(c *gin.Context) {
+	name, ok := strings.CutSuffix(c.Param("page"), ".xml")
+	page, err := strconv.Atoi(name)
+	if !ok || err != nil || page <= 0 {
+		response.NotFoundAndResponse(c)
+		return
+	}
+	h.sitemap(c, page)
+}
+
+// sitemap 返回站点地图
+func (h *SitemapApiHandler) sitemap(c *gin.Context, page int) {
+	doc, err := h.sitemapService.Sitemap(c, requestOrigin(c), page)
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+	if doc == nil {
+		response.NotFoundAndResponse(c)
+		return
+	}
+
+	body, err := doc.Render()
+	if err != nil {
+		response.FailAndResponse(c, response.ServerError.Error())
+		return
+	}
+	response.CacheableAndResponse(c, sitemap.ContentType, body, doc.LastModified())
+}
+
+// getRobots 获取 robots.txt
+func (h *SitemapApiHandler) getRobots(c *gin.Context) {
+	robots, err := h.sitemapService.RobotsTxt(c, requestOrigin(c))
+	if err != nil {
+		response.FailAndResponse(c, err.Error())
+		return
+	}
+	response.CacheableAndResponse(c, "text/plain; charset=utf-8", []byte(robots), time.Time{})
+}
@@ -25,22 +25,22 @@ func (h *UserApiHandler) RegisterApi(r *gin.RouterGroup) {
 
 // getBloggerInfo 获取博主信息
 func (h *UserApiHandler) getBloggerInfo(c *gin.Context) {
-	users, err := h.userService.AllUsers(c)
+	blogger, err := h.userService.Blogger(c)
 
 	if err != nil {
 		response.FailAndResponse(c, err.Error())
 		return
 	}
 
-	if len(users) == 0 {
+	if blogger == nil {
 		response.OkAndResponse(c, nil)
 		return
 	}
 
 	response.OkAndResponse(c, map[string]any{
-		"email":       users[0].Email,
-		"displayName": users[0].DisplayName,
-		"description": users[0].Description,
-		"avatar":      users[0].Avatar,
+		"email":       blogger.Email,
+		"displayName": blogger.DisplayName,
+		"description": blogger.Description,
+		"avatar":      blogger.Avatar,
 	})
 }
@@ -0,0 +1,102 @@
+package kv
+
+import (
+	"context"
+	"nola-go/internal/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fallbackStore 主存储出错时改用备用存储的键值存储
+// 例如 Redis 暂时不可用时改用内存存储，保证依赖计数器的安全限制不会因此失效；
+// 两个存储的数据互不同步，切换后计数会从备用存储中的值继续
+type fallbackStore struct {
+	primary  Store
+	fallback Store
+}
+
+// NewFallbackStore 创建主存储出错时改用备用存储的键值存储
+//   - primary: 主存储
+//   - fallback: 备用存储
+func NewFallbackStore(primary, fallback Store) Store {
+	return &fallbackStore{primary: primary, fallback: fallback}
+}
+
+// Get 获取键的值
+func (f *fallbackStore) Get(ctx context.Context, key string) (string, bool, error) {
+	value, ok, err := f.primary.Get(ctx, key)
+	if err != nil {
+		f.warn("Get", err)
+		return f.fallback.Get(ctx, key)
+	}
+	return value, ok, nil
+}
+
+// Set 设置键的值和过期时间
+func (f *fallbackStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	if err := f.primary.Set(ctx, key, value, ttl); err != nil {
+		f.warn("Set", err)
+		return f.fallback.Set(ctx, key, value, ttl)
+	}
+	return nil
+}
+
+// SetNX 键不存在时才设置键的值和过期时间
+func (f *fallbackStore) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	ok, err := f.primary.SetNX(ctx, key, value, ttl)
+	if err != nil {
+		f.warn("SetNX", err)
+		return f.fallback.SetNX(ctx, key, value, ttl)
+	}
+	return ok, nil
+}
+
+// CompareAndExpire 键的值等于 value 时才重置过期时间
+func (f *fallbackStore) CompareAndExpire(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	ok, err := f.primary.CompareAndExpire(ctx, key, value, ttl)
+	if err != nil {
+		f.warn("CompareAndExpire", err)
+		return f.fallback.CompareAndExpire(ctx, key, value, ttl)
+	}
+	return ok, nil
+}
+
+// Incr 将键的值加 1 并重置过期时间
+func (f *fallbackStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	n, err := f.primary.Incr(ctx, key, ttl)
+	if err != nil {
+		f.warn("Incr", err)
+		return f.fallback.Incr(ctx, key, ttl)
+	}
+	return n, nil
+}
+
+// TTL 获取键的剩余过期时间
+func (f *fallbackStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := f.primary.TTL(ctx, key)
+	if err != nil {
+		f.warn("TTL", err)
+		return f.fallback.TTL(ctx, key)
+	}
+	return ttl, nil
+}
+
+// Delete 删除键，两个存储中的键都会删除，只有两个存储都出错时才返回错误
+func (f *fallbackStore) Delete(ctx context.Context, keys ...string) error {
+	err := f.primary.Delete(ctx, keys...)
+	if err != nil {
+		f.warn("Delete", err)
+	}
+
+	fallbackErr := f.fallback.Delete(ctx, keys...)
+	if err != nil {
+		return fallbackErr
+	}
+	return nil
+}
+
+// warn 记录主存储出错
+func (f *fallbackStore) warn(op string, err error) {
+	logger.Log.Warn("键值存储出错，改用备用存储", zap.String("op", op), zap.Error(err))
+}
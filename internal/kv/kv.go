@@ -0,0 +1,25 @@
+package kv
+
+import (
+	"context"
+	"time"
+)
+
+// Store 带过期时间的键值存储接口，用于保存计数器、锁等临时数据
+// 过期的键由存储自行清理，读取时视为不存在
+type Store interface {
+	// Get 获取键的值，键不存在或已过期时 ok 为 false
+	Get(ctx context.Context, key string) (value string, ok bool, err error)
+	// Set 设置键的值和过期时间，ttl 必须大于 0
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	// SetNX 键不存在时才设置键的值和过期时间，返回是否设置成功
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// CompareAndExpire 键的值等于 value 时才把过期时间重置为 ttl，返回是否重置成功；比较和重置是原子的
+	CompareAndExpire(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// Incr 将键的值加 1 并返回加 1 后的值，键不存在时从 0 开始；每次调用都会把过期时间重置为 ttl
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// TTL 获取键的剩余过期时间，键不存在时返回 0
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// Delete 删除键，键不存在时忽略
+	Delete(ctx context.Context, keys ...string) error
+}
@@ -0,0 +1,139 @@
+package kv
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// cleanupInterval 内存存储清理过期键的最小间隔
+const cleanupInterval = time.Minute
+
+// memoryEntry 内存存储的值
+type memoryEntry struct {
+	value    string
+	expireAt time.Time
+}
+
+// memoryStore 进程内存键值存储
+type memoryStore struct {
+	lock        sync.Mutex
+	entries     map[string]memoryEntry
+	lastCleanup time.Time
+	// now 获取当前时间，测试时可以替换
+	now func() time.Time
+}
+
+// NewMemoryStore 创建内存键值存储，服务重启后所有数据丢失，多实例部署时数据不共享
+func NewMemoryStore() Store {
+	return &memoryStore{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// Get 获取键的值
+func (m *memoryStore) Get(_ context.Context, key string) (string, bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	e, ok := m.get(key)
+	return e.value, ok, nil
+}
+
+// Set 设置键的值和过期时间
+func (m *memoryStore) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.set(key, value, ttl)
+	return nil
+}
+
+// SetNX 键不存在时才设置键的值和过期时间
+func (m *memoryStore) SetNX(_ context.Context, key, value string, ttl time.Duration) (bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.get(key); ok {
+		return false, nil
+	}
+	m.set(key, value, ttl)
+	return true, nil
+}
+
+// CompareAndExpire 键的值等于 value 时才重置过期时间
+func (m *memoryStore) CompareAndExpire(_ context.Context, key, value string, ttl time.Duration) (bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if e, ok := m.get(key); !ok || e.value != value {
+		return false, nil
+	}
+	m.set(key, value, ttl)
+	return true, nil
+}
+
+// Incr 将键的值加 1 并重置过期时间
+func (m *memoryStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var n int64
+	if e, ok := m.get(key); ok {
+		var err error
+		if n, err = strconv.ParseInt(e.value, 10, 64); err != nil {
+			return 0, err
+		}
+	}
+	n++
+	m.set(key, strconv.FormatInt(n, 10), ttl)
+	return n, nil
+}
+
+// TTL 获取键的剩余过期时间
+func (m *memoryStore) TTL(_ context.Context, key string) (time.Duration, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	e, ok := m.get(key)
+	if !ok {
+		return 0, nil
+	}
+	return e.expireAt.Sub(m.now()), nil
+}
+
+// Delete 删除键
+func (m *memoryStore) Delete(_ context.Context, keys ...string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+// get 获取未过期的值，调用方需要持有锁
+func (m *memoryStore) get(key string) (memoryEntry, bool) {
+	e, ok := m.entries[key]
+	if !ok || !e.expireAt.After(m.now()) {
+		return memoryEntry{}, false
+	}
+	return e, true
+}
+
+// set 设置值并按需清理过期的键，调用方需要持有锁
+func (m *memoryStore) set(key, value string, ttl time.Duration) {
+	now := m.now()
+	if now.Sub(m.lastCleanup) >= cleanupInterval {
+		for k, e := range m.entries {
+			if !e.expireAt.After(now) {
+				delete(m.entries, k)
+			}
+		}
+		m.lastCleanup = now
+	}
+	m.entries[key] = memoryEntry{value: value, expireAt: now.Add(ttl)}
+}
@@ -0,0 +1,96 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// incrScript 加 1 并重置过期时间
+//   - KEYS[1]: 键
+//   - ARGV[1]: 过期时间（毫秒）
+var incrScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+return n
+`)
+
+// compareAndExpireScript 值相等时才重置过期时间
+//   - KEYS[1]: 键
+//   - ARGV[1]: 期望的值
+//   - ARGV[2]: 过期时间（毫秒）
+var compareAndExpireScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// redisStore Redis 键值存储
+type redisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore 创建 Redis 键值存储，多实例部署时数据共享
+func NewRedisStore(client *redis.Client) Store {
+	return &redisStore{client: client}
+}
+
+// Get 获取键的值
+func (r *redisStore) Get(ctx context.Context, key string) (string, bool, error) {
+	value, err := r.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// Set 设置键的值和过期时间
+func (r *redisStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+// SetNX 键不存在时才设置键的值和过期时间
+func (r *redisStore) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// CompareAndExpire 键的值等于 value 时才重置过期时间
+func (r *redisStore) CompareAndExpire(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	n, err := compareAndExpireScript.Run(ctx, r.client, []string{key}, value, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// Incr 将键的值加 1 并重置过期时间
+func (r *redisStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, r.client, []string{key}, ttl.Milliseconds()).Int64()
+}
+
+// TTL 获取键的剩余过期时间
+func (r *redisStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// 键不存在时为 -2，没有过期时间时为 -1
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Delete 删除键
+func (r *redisStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,165 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/logger"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// testStore 键值存储的通用测试
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	if _, ok, err := store.Get(ctx, "missing"); ok || err != nil {
+		t.Fatalf("Get(missing) = %v, %v", ok, err)
+	}
+
+	if err := store.Set(ctx, "a", "1", time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if value, ok, err := store.Get(ctx, "a"); value != "1" || !ok || err != nil {
+		t.Fatalf("Get = %q, %v, %v", value, ok, err)
+	}
+	if ttl, err := store.TTL(ctx, "a"); err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("TTL = %v, %v", ttl, err)
+	}
+	if ttl, err := store.TTL(ctx, "missing"); err != nil || ttl != 0 {
+		t.Errorf("TTL(missing) = %v, %v", ttl, err)
+	}
+
+	setNXTests := []struct {
+		key  string
+		want bool
+	}{
+		{"a", false},
+		{"b", true},
+		{"b", false},
+	}
+	for _, tt := range setNXTests {
+		if ok, err := store.SetNX(ctx, tt.key, "x", time.Minute); err != nil || ok != tt.want {
+			t.Errorf("SetNX(%s) = %v, %v, want %v", tt.key, ok, err, tt.want)
+		}
+	}
+
+	compareAndExpireTests := []struct {
+		key   string
+		value string
+		want  bool
+	}{
+		{"b", "x", true},
+		{"b", "y", false},
+		{"missing", "x", false},
+	}
+	for _, tt := range compareAndExpireTests {
+		if ok, err := store.CompareAndExpire(ctx, tt.key, tt.value, 2*time.Minute); err != nil || ok != tt.want {
+			t.Errorf("CompareAndExpire(%s, %s) = %v, %v, want %v", tt.key, tt.value, ok, err, tt.want)
+		}
+	}
+	if ttl, err := store.TTL(ctx, "b"); err != nil || ttl <= time.Minute {
+		t.Errorf("TTL after CompareAndExpire = %v, %v", ttl, err)
+	}
+	if _, ok, _ := store.Get(ctx, "missing"); ok {
+		t.Error("CompareAndExpire should not create missing key")
+	}
+
+	for want := int64(1); want <= 3; want++ {
+		if n, err := store.Incr(ctx, "counter", time.Minute); err != nil || n != want {
+			t.Fatalf("Incr = %d, %v, want %d", n, err, want)
+		}
+	}
+	if _, err := store.Incr(ctx, "b", time.Minute); err == nil {
+		t.Error("Incr on non-integer value should fail")
+	}
+
+	// 过期后视为不存在
+	if err := store.Set(ctx, "short", "1", 50*time.Millisecond); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, ok, _ := store.Get(ctx, "short"); ok {
+		t.Error("expired key should not exist")
+	}
+	if n, _ := store.Incr(ctx, "short", time.Minute); n != 1 {
+		t.Errorf("Incr(expired) = %d, want 1", n)
+	}
+
+	if err := store.Delete(ctx, "a", "counter", "missing"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok, _ := store.Get(ctx, "a"); ok {
+		t.Error("deleted key should not exist")
+	}
+	if n, _ := store.Incr(ctx, "counter", time.Minute); n != 1 {
+		t.Errorf("Incr(deleted) = %d, want 1", n)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+// TestRedisStore 需要设置 NOLA_TEST_REDIS_ADDR 环境变量，使用 15 号数据库并在测试前后清空
+func TestRedisStore(t *testing.T) {
+	addr := os.Getenv("NOLA_TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("NOLA_TEST_REDIS_ADDR 未设置，跳过 Redis 测试")
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr, DB: 15})
+	ctx := context.Background()
+	if err := client.FlushDB(ctx).Err(); err != nil {
+		t.Fatalf("FlushDB: %v", err)
+	}
+	t.Cleanup(func() {
+		client.FlushDB(ctx)
+		client.Close()
+	})
+
+	testStore(t, NewRedisStore(client))
+}
+
+// failingStore 所有操作都失败的存储
+type failingStore struct{ Store }
+
+var errUnavailable = errors.New("unavailable")
+
+func (failingStore) Get(context.Context, string) (string, bool, error) {
+	return "", false, errUnavailable
+}
+func (failingStore) Set(context.Context, string, string, time.Duration) error {
+	return errUnavailable
+}
+func (failingStore) SetNX(context.Context, string, string, time.Duration) (bool, error) {
+	return false, errUnavailable
+}
+func (failingStore) CompareAndExpire(context.Context, string, string, time.Duration) (bool, error) {
+	return false, errUnavailable
+}
+func (failingStore) Incr(context.Context, string, time.Duration) (int64, error) {
+	return 0, errUnavailable
+}
+func (failingStore) TTL(context.Context, string) (time.Duration, error) {
+	return 0, errUnavailable
+}
+func (failingStore) Delete(context.Context, ...string) error {
+	return errUnavailable
+}
+
+func TestFallbackStore(t *testing.T) {
+	if logger.Log == nil {
+		logger.Log = zap.NewNop()
+	}
+
+	t.Run("主存储可用", func(t *testing.T) {
+		testStore(t, NewFallbackStore(NewMemoryStore(), failingStore{}))
+	})
+	t.Run("主存储不可用", func(t *testing.T) {
+		testStore(t, NewFallbackStore(failingStore{}, NewMemoryStore()))
+	})
+}
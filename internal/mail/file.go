@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"nola-go/internal/logger"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fileMailer 将邮件写入文件，不实际发送
+type fileMailer struct {
+	dir string
+	seq atomic.Uint64
+}
+
+// NewFileMailer 创建写入文件的邮件发送器，每封邮件保存为 dir 目录下的一个 .eml 文件
+func NewFileMailer(dir string) Mailer {
+	return &fileMailer{dir: dir}
+}
+
+// Send 将邮件写入文件
+func (m *fileMailer) Send(_ context.Context, msg *Message) error {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%04d.eml", time.Now().Format("20060102-150405.000"), m.seq.Add(1))
+	return os.WriteFile(filepath.Join(m.dir, name), msg.Bytes(), 0644)
+}
+
+// logMailer 将邮件输出到日志，不实际发送
+type logMailer struct{}
+
+// NewLogMailer 创建输出到日志的邮件发送器
+func NewLogMailer() Mailer {
+	return logMailer{}
+}
+
+// Send 将邮件输出到日志
+func (logMailer) Send(_ context.Context, msg *Message) error {
+	logger.Log.Info("发送邮件",
+		zap.String("to", strings.Join(msg.To, ", ")),
+		zap.String("subject", msg.Subject),
+		zap.String("text", msg.Text),
+	)
+	return nil
+}
@@ -0,0 +1,114 @@
+// Package mail 发送邮件，提供 SMTP 实现以及写入文件、输出日志的替代实现（开发和测试时使用）
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DriverSMTP 通过 SMTP 服务器发送邮件
+	DriverSMTP = "smtp"
+	// DriverFile 将邮件写入文件（.eml），不实际发送
+	DriverFile = "file"
+	// DriverLog 将邮件输出到日志，不实际发送
+	DriverLog = "log"
+)
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	// Send 发送邮件
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Message 邮件
+type Message struct {
+	// From 发件人地址
+	From string
+	// FromName 发件人名称
+	FromName string
+	// To 收件人地址
+	To []string
+	// Subject 主题
+	Subject string
+	// Text 纯文本正文
+	Text string
+	// HTML HTML 正文，为空时只发送纯文本
+	HTML string
+	// Headers 额外的邮件头（如 List-Unsubscribe）
+	Headers map[string]string
+}
+
+// Bytes 生成 RFC 5322 格式的邮件内容，同时有纯文本和 HTML 正文时使用 multipart/alternative
+func (m *Message) Bytes() []byte {
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+
+	header("From", (&mail.Address{Name: m.FromName, Address: m.From}).String())
+	header("To", strings.Join(m.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+randomId()+"@"+domainOf(m.From)+">")
+	header("MIME-Version", "1.0")
+	keys := make([]string, 0, len(m.Headers))
+	for key := range m.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		header(key, m.Headers[key])
+	}
+
+	if m.HTML == "" {
+		writePart(&buf, "text/plain", m.Text)
+		return buf.Bytes()
+	}
+
+	boundary := randomId()
+	header("Content-Type", `multipart/alternative; boundary="`+boundary+`"`)
+	buf.WriteString("\r\n")
+	buf.WriteString("--" + boundary + "\r\n")
+	writePart(&buf, "text/plain", m.Text)
+	buf.WriteString("\r\n--" + boundary + "\r\n")
+	writePart(&buf, "text/html", m.HTML)
+	buf.WriteString("\r\n--" + boundary + "--\r\n")
+	return buf.Bytes()
+}
+
+// writePart 写入使用 quoted-printable 编码的正文
+func writePart(buf *bytes.Buffer, contentType string, body string) {
+	buf.WriteString("Content-Type: " + contentType + "; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	w := quotedprintable.NewWriter(buf)
+	_, _ = w.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n")))
+	_ = w.Close()
+}
+
+// randomId 生成随机标识，用于 Message-ID 和 multipart 分隔符
+func randomId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprint(time.Now().UnixNano())))
+	}
+	return hex.EncodeToString(b)
+}
+
+// domainOf 获取邮件地址的域名
+func domainOf(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
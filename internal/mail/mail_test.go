@@ -0,0 +1,146 @@
+package mail
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// parseMessage 解析邮件，返回邮件头和各部分正文（按 Content-Type）
+func parseMessage(t *testing.T, raw []byte) (mail.Header, map[string]string) {
+	t.Helper()
+	m, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+
+	parts := map[string]string{}
+	readPart := func(contentType string, r io.Reader) {
+		body, err := io.ReadAll(quotedprintable.NewReader(r))
+		if err != nil {
+			t.Fatalf("read %s: %v", contentType, err)
+		}
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		parts[mediaType] = strings.ReplaceAll(string(body), "\r\n", "\n")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("ParseMediaType: %v", err)
+	}
+	if mediaType != "multipart/alternative" {
+		readPart(m.Header.Get("Content-Type"), m.Body)
+		return m.Header, parts
+	}
+
+	r := multipart.NewReader(m.Body, params["boundary"])
+	for {
+		p, err := r.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		readPart(p.Header.Get("Content-Type"), p)
+	}
+	return m.Header, parts
+}
+
+func TestMessage_Bytes(t *testing.T) {
+	msg := &Message{
+		From:     "blog@example.com",
+		FromName: "我的博客",
+		To:       []string{"reader@example.com"},
+		Subject:  "新评论：你好",
+		Text:     "第一行\n第二行 = 等号",
+		HTML:     "<p>第一行</p>",
+		Headers:  map[string]string{"List-Unsubscribe": "<https://example.com/unsubscribe>"},
+	}
+
+	header, parts := parseMessage(t, msg.Bytes())
+	from, err := mail.ParseAddress(header.Get("From"))
+	if err != nil || from.Name != "我的博客" || from.Address != "blog@example.com" {
+		t.Errorf("From = %q (%v)", header.Get("From"), err)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(header.Get("Subject")); subject != msg.Subject {
+		t.Errorf("Subject = %q", subject)
+	}
+	if header.Get("To") != "reader@example.com" || header.Get("List-Unsubscribe") != "<https://example.com/unsubscribe>" {
+		t.Errorf("header = %v", header)
+	}
+	if !strings.HasSuffix(header.Get("Message-ID"), "@example.com>") {
+		t.Errorf("Message-ID = %q", header.Get("Message-ID"))
+	}
+	if parts["text/plain"] != msg.Text || parts["text/html"] != msg.HTML {
+		t.Errorf("parts = %q", parts)
+	}
+
+	// 没有 HTML 正文时只有纯文本
+	msg.HTML = ""
+	header, parts = parseMessage(t, msg.Bytes())
+	if !strings.HasPrefix(header.Get("Content-Type"), "text/plain") || parts["text/plain"] != msg.Text {
+		t.Errorf("text only: %v %q", header, parts)
+	}
+}
+
+func TestRender(t *testing.T) {
+	content, err := Render("comment_reply", map[string]any{
+		"BlogTitle":      "我的博客",
+		"DisplayName":    "<script>",
+		"PostTitle":      "Go 入门",
+		"ReplyToName":    "读者",
+		"ReplyToContent": "原评论",
+		"Content":        "回复 & 内容",
+		"PostURL":        "https://example.com/post/go",
+		"UnsubscribeURL": "https://example.com/api/mail/unsubscribe?email=a%40b.com&token=t",
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if content.Subject != "[我的博客] <script> 回复了你在《Go 入门》中的评论" {
+		t.Errorf("Subject = %q", content.Subject)
+	}
+	if !strings.Contains(content.Text, "回复 & 内容") || !strings.Contains(content.Text, "token=t") {
+		t.Errorf("Text = %q", content.Text)
+	}
+	// HTML 正文需要转义
+	if strings.Contains(content.HTML, "<script>") || !strings.Contains(content.HTML, "&lt;script&gt;") || !strings.Contains(content.HTML, "回复 &amp; 内容") {
+		t.Errorf("HTML = %q", content.HTML)
+	}
+
+	if _, err := Render("not_exist", nil); err == nil {
+		t.Error("Render(not_exist) should fail")
+	}
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer := NewFileMailer(dir)
+	for _, subject := range []string{"第一封", "第二封"} {
+		err := mailer.Send(context.Background(), &Message{From: "blog@example.com", To: []string{"a@example.com"}, Subject: subject, Text: subject})
+		if err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("ReadDir = %v, %v", entries, err)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, entries[1].Name()))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	header, _ := parseMessage(t, raw)
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(header.Get("Subject")); subject != "第二封" {
+		t.Errorf("Subject = %q", subject)
+	}
+}
@@ -0,0 +1,115 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+const (
+	// EncryptionNone 不加密
+	EncryptionNone = "none"
+	// EncryptionSTARTTLS 连接后使用 STARTTLS 升级为加密连接（通常为 587 端口）
+	EncryptionSTARTTLS = "starttls"
+	// EncryptionTLS 直接建立 TLS 连接（通常为 465 端口）
+	EncryptionTLS = "tls"
+)
+
+// smtpTimeout 没有设置截止时间时，发送一封邮件的最长时间
+const smtpTimeout = 30 * time.Second
+
+// SMTPSettings SMTP 服务器设置
+type SMTPSettings struct {
+	// Host 服务器地址
+	Host string
+	// Port 端口
+	Port int
+	// Username 用户名，为空时不进行身份验证
+	Username string
+	// Password 密码
+	Password string
+	// Encryption 加密方式（none、starttls、tls）
+	Encryption string
+}
+
+// smtpMailer 通过 SMTP 服务器发送邮件
+type smtpMailer struct {
+	settings SMTPSettings
+}
+
+// NewSMTPMailer 创建 SMTP 邮件发送器
+func NewSMTPMailer(settings SMTPSettings) Mailer {
+	return &smtpMailer{settings: settings}
+}
+
+// Send 发送邮件
+func (m *smtpMailer) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return errors.New("收件人不能为空")
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, smtpTimeout)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(m.settings.Host, strconv.Itoa(m.settings.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	tlsConfig := &tls.Config{ServerName: m.settings.Host}
+	if m.settings.Encryption == EncryptionTLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, m.settings.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if m.settings.Encryption == EncryptionSTARTTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if m.settings.Username != "" {
+		auth := smtp.PlainAuth("", m.settings.Username, m.settings.Password, m.settings.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(msg.From); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg.Bytes()); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
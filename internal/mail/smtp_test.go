@@ -0,0 +1,139 @@
+package mail
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// fakeSMTPServer 只支持不加密、不验证身份的最简 SMTP 服务器，记录收到的命令和邮件内容
+type fakeSMTPServer struct {
+	listener net.Listener
+	commands chan string
+	data     chan string
+	// rejectRcpt 拒绝的收件人
+	rejectRcpt string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	s := &fakeSMTPServer{listener: l, commands: make(chan string, 100), data: make(chan string, 10)}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		s.commands <- line
+		cmd := strings.ToUpper(strings.Fields(line + " ")[0])
+		switch {
+		case cmd == "EHLO" || cmd == "HELO":
+			_ = tp.PrintfLine("250-localhost")
+			_ = tp.PrintfLine("250 8BITMIME")
+		case cmd == "RCPT" && s.rejectRcpt != "" && strings.Contains(line, s.rejectRcpt):
+			_ = tp.PrintfLine("550 no such user")
+		case cmd == "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				return
+			}
+			s.data <- strings.Join(lines, "\n")
+			_ = tp.PrintfLine("250 ok")
+		case cmd == "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("250 ok")
+		}
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	mailer := NewSMTPMailer(SMTPSettings{Host: "127.0.0.1", Port: server.port(), Encryption: EncryptionNone})
+
+	err := mailer.Send(context.Background(), &Message{
+		From:    "blog@example.com",
+		To:      []string{"a@example.com", "b@example.com"},
+		Subject: "测试",
+		Text:    "正文",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var commands []string
+	for len(server.commands) > 0 {
+		commands = append(commands, <-server.commands)
+	}
+	joined := strings.Join(commands, "\n")
+	for _, want := range []string{"MAIL FROM:<blog@example.com>", "RCPT TO:<a@example.com>", "RCPT TO:<b@example.com>", "DATA", "QUIT"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("commands %q missing %q", commands, want)
+		}
+	}
+
+	header, parts := parseMessage(t, []byte(<-server.data))
+	if header.Get("To") != "a@example.com, b@example.com" || parts["text/plain"] != "正文" {
+		t.Errorf("message = %v %q", header, parts)
+	}
+}
+
+func TestSMTPMailer_SendError(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	server.rejectRcpt = "bad@example.com"
+	mailer := NewSMTPMailer(SMTPSettings{Host: "127.0.0.1", Port: server.port(), Encryption: EncryptionNone})
+
+	// 收件人被拒绝
+	err := mailer.Send(context.Background(), &Message{From: "blog@example.com", To: []string{"bad@example.com"}, Text: "正文"})
+	if err == nil || !strings.Contains(err.Error(), "no such user") {
+		t.Errorf("Send(rejected) = %v", err)
+	}
+
+	// 设置了 STARTTLS，但服务器不支持
+	mailer = NewSMTPMailer(SMTPSettings{Host: "127.0.0.1", Port: server.port(), Encryption: EncryptionSTARTTLS})
+	if err := mailer.Send(context.Background(), &Message{From: "blog@example.com", To: []string{"a@example.com"}, Text: "正文"}); err == nil {
+		t.Error("Send(starttls) should fail")
+	}
+
+	// 没有收件人
+	if err := mailer.Send(context.Background(), &Message{From: "blog@example.com"}); err == nil {
+		t.Error("Send(no recipient) should fail")
+	}
+
+	// 连接失败
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+	mailer = NewSMTPMailer(SMTPSettings{Host: "127.0.0.1", Port: port, Encryption: EncryptionNone})
+	if err := mailer.Send(context.Background(), &Message{From: "blog@example.com", To: []string{"a@example.com"}}); err == nil {
+		t.Error("Send(closed port) should fail")
+	}
+}
@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+)
+
+// Content 模板生成的邮件内容
+type Content struct {
+	// Subject 主题
+	Subject string
+	// Text 纯文本正文
+	Text string
+	// HTML HTML 正文
+	HTML string
+}
+
+// Render 使用邮件模板生成邮件内容
+// 模板 name.txt 中定义 name.subject（主题）和 name.text（纯文本正文），模板 name.html 为 HTML 正文
+//   - name: 模板名称（如 comment_new）
+//   - data: 模板数据
+func Render(name string, data any) (*Content, error) {
+	var subject, text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&subject, name+".subject", data); err != nil {
+		return nil, err
+	}
+	if err := textTemplates.ExecuteTemplate(&text, name+".text", data); err != nil {
+		return nil, err
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return nil, err
+	}
+	return &Content{
+		// 主题只能有一行
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
@@ -3,14 +3,16 @@ package middleware
 import (
 	"log"
 	"net/http"
+	"nola-go/internal/models/enum"
 	"nola-go/internal/models/response"
 	"nola-go/internal/service"
+	"nola-go/internal/util"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware 管理员身份验证中间件
+// AuthMiddleware 管理员身份验证中间件，支持登录访问令牌（JWT）和个人 API 令牌
 func AuthMiddleware(tokenSvc *service.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		auth := c.GetHeader("Authorization")
@@ -21,6 +23,22 @@ func AuthMiddleware(tokenSvc *service.TokenService) gin.HandlerFunc {
 
 		tokenStr := strings.TrimPrefix(auth, "Bearer ")
 
+		// API 令牌不绑定会话，权限同时受用户角色和令牌权限范围限制
+		if util.IsApiToken(tokenStr) {
+			operator := tokenSvc.VerifyApiToken(c, tokenStr, c.ClientIP())
+			if operator == nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, response.Unauthorized())
+				return
+			}
+
+			c.Set("uid", operator.UserId)
+			c.Set("username", operator.Username)
+			c.Set("role", string(operator.Role))
+			c.Set("scopes", operator.Scopes)
+			c.Next()
+			return
+		}
+
 		// 解析并验证
 		claims, err := tokenSvc.ParseAndValidate(tokenStr)
 		if err != nil {
@@ -45,8 +63,17 @@ func AuthMiddleware(tokenSvc *service.TokenService) gin.HandlerFunc {
 			return
 		}
 
-		// 检查 token 是否和 userId 对应
-		if !tokenSvc.Verify(userId, tokenStr) {
+		// 用户角色，升级前签发的令牌没有角色，需要重新登录
+		roleStr, _ := claims["role"].(string)
+		role := enum.UserRoleValueOf(roleStr)
+		if role == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Unauthorized())
+			return
+		}
+
+		// 检查 Token 所属的会话是否存在（未过期、未被注销）
+		sessionId, _ := claims["sid"].(string)
+		if !tokenSvc.Verify(c, userId, sessionId) {
 			// 验证失败
 			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Unauthorized())
 			return
@@ -54,6 +81,8 @@ func AuthMiddleware(tokenSvc *service.TokenService) gin.HandlerFunc {
 
 		// 将用户信息放到上下文，供后续 handler 使用
 		c.Set("uid", userId)
+		c.Set("sid", sessionId)
+		c.Set("role", string(*role))
 		if uname, ok := claims["username"].(string); ok {
 			c.Set("username", uname)
 		}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionMiddleware 权限验证中间件，需要在 AuthMiddleware 之后使用
+//   - permission: 访问接口需要的权限
+func PermissionMiddleware(permission enum.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !CurrentOperator(c).Can(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, response.Forbidden())
+			return
+		}
+		c.Next()
+	}
+}
+
+// SessionOnlyMiddleware 只允许使用登录会话访问的中间件，需要在 AuthMiddleware 之后使用
+// 用于修改密码、管理会话和 API 令牌等账户安全相关的接口，API 令牌不能访问
+func SessionOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("sid") == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, response.Forbidden())
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentOperator 获取当前登录用户，由 AuthMiddleware 放到上下文中，未登录时用户 ID 为 0
+func CurrentOperator(c *gin.Context) *service.Operator {
+	operator := &service.Operator{
+		UserId:   c.GetUint("uid"),
+		Username: c.GetString("username"),
+		Role:     enum.UserRole(c.GetString("role")),
+		Ip:       c.ClientIP(),
+	}
+	if scopes, ok := c.Get("scopes"); ok {
+		operator.Scopes, _ = scopes.([]enum.ApiTokenScope)
+	}
+	return operator
+}
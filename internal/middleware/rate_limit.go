@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"nola-go/internal/logger"
+	"nola-go/internal/models/response"
+	"nola-go/internal/ratelimit"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RateLimitMiddleware 接口限流中间件，按配置中路由对应的策略限制同一 IP 的请求次数
+// 需要在注册路由之前通过 Engine.Use 使用，没有配置策略的路由不限制；计数存储出错时不限制
+func RateLimitMiddleware(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy := limiter.RoutePolicy(c.Request.Method, c.FullPath())
+		if policy == nil {
+			c.Next()
+			return
+		}
+
+		ret, err := limiter.Allow(c, policy, c.ClientIP())
+		if err != nil {
+			logger.Log.Warn("接口限流计数失败", zap.String("policy", policy.Name), zap.Error(err))
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.FormatInt(ret.Limit, 10))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(ret.Remaining, 10))
+		if !ret.Allowed {
+			response.TooManyRequestsAndResponse(c, &response.TooManyRequestsError{RetryAfter: ret.RetryAfter})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"nola-go/internal/config"
+	"nola-go/internal/kv"
+	"nola-go/internal/logger"
+	"nola-go/internal/models/response"
+	"nola-go/internal/ratelimit"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	logger.Log = zap.NewNop()
+	gin.SetMode(gin.TestMode)
+
+	limiter, err := ratelimit.New(config.RateLimitConfig{Enabled: true, Policies: []config.RateLimitPolicy{
+		{Name: "comment", Routes: []string{"POST /api/comment"}, Limit: 2, WindowSeconds: 60},
+	}}, kv.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("ratelimit.New: %v", err)
+	}
+
+	r := gin.New()
+	r.Use(RateLimitMiddleware(limiter))
+	r.POST("/api/comment", func(c *gin.Context) {
+		response.OkAndResponse(c, true)
+	})
+	r.GET("/api/comment", func(c *gin.Context) {
+		response.OkAndResponse(c, true)
+	})
+
+	request := func(method string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, "/api/comment", nil)
+		req.RemoteAddr = "1.1.1.1:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 1; i <= 2; i++ {
+		w := request(http.MethodPost)
+		if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Remaining") != strconv.Itoa(2-i) {
+			t.Fatalf("request #%d = %d, remaining %q", i, w.Code, w.Header().Get("X-RateLimit-Remaining"))
+		}
+	}
+
+	// 超过限制返回 429、Retry-After 响应头和统一的响应体
+	w := request(http.MethodPost)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("limited request = %d, want 429", w.Code)
+	}
+	if retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || retryAfter <= 0 {
+		t.Errorf("Retry-After = %q", w.Header().Get("Retry-After"))
+	}
+	var body response.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Unmarshal: %v, body = %s", err, w.Body.String())
+	}
+	if body.Code != http.StatusTooManyRequests || body.ErrMsg == nil || *body.ErrMsg == "" || body.Data != nil {
+		t.Errorf("limited body = %s", w.Body.String())
+	}
+
+	// 没有配置策略的路由不限制
+	if w := request(http.MethodGet); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "" {
+		t.Errorf("unlimited request = %d, headers = %v", w.Code, w.Header())
+	}
+}
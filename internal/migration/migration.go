@@ -0,0 +1,135 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Migration 单个数据库迁移
+type Migration struct {
+	// Version 版本号（严格递增，发布后不可修改）
+	Version uint
+	// Name 迁移名称
+	Name string
+	// Models 本次迁移涉及的表结构快照，参与校验和计算。
+	// 快照结构体需要在迁移文件中单独定义，不能直接引用 models 包中的结构体，
+	// 否则 models 变化后会导致已执行迁移的校验和不一致。
+	Models []any
+	// Up 升级
+	Up func(tx *gorm.DB) error
+	// Down 回滚
+	Down func(tx *gorm.DB) error
+}
+
+// registry 所有已注册的迁移
+var registry []*Migration
+
+// register 注册迁移，由各迁移文件的 init 调用
+func register(m *Migration) {
+	registry = append(registry, m)
+}
+
+// Migrations 获取所有已注册的迁移（按版本号升序）
+func Migrations() []*Migration {
+	ret := make([]*Migration, len(registry))
+	copy(ret, registry)
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Version < ret[j].Version
+	})
+	return ret
+}
+
+// Checksum 计算迁移校验和
+// 校验和由版本号、名称和表结构快照（表名、字段名、字段类型、GORM 标签）计算得来
+func (m *Migration) Checksum() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d:%s\n", m.Version, m.Name))
+
+	for _, model := range m.Models {
+		t := reflect.TypeOf(model)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		tableName := t.Name()
+		if tabler, ok := reflect.New(t).Interface().(schema.Tabler); ok {
+			tableName = tabler.TableName()
+		}
+		b.WriteString("table " + tableName + "\n")
+
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			b.WriteString(fmt.Sprintf("  %s %s `%s`\n", f.Name, f.Type.String(), f.Tag.Get("gorm")))
+		}
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// createTables 创建不存在的表（用于兼容此前手动建表的数据库）
+func createTables(tx *gorm.DB, tables ...any) error {
+	m := tx.Migrator()
+	for _, table := range tables {
+		if m.HasTable(table) {
+			continue
+		}
+		if err := m.CreateTable(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropTables 删除存在的表
+func dropTables(tx *gorm.DB, tables ...any) error {
+	m := tx.Migrator()
+	for _, table := range tables {
+		if !m.HasTable(table) {
+			continue
+		}
+		if err := m.DropTable(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumns 给表添加不存在的字段
+//   - model: 表结构快照，需要包含要添加的字段
+//   - fields: 字段名（结构体字段名）
+func addColumns(tx *gorm.DB, model any, fields ...string) error {
+	m := tx.Migrator()
+	for _, field := range fields {
+		if m.HasColumn(model, field) {
+			continue
+		}
+		if err := m.AddColumn(model, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropColumns 删除表中存在的字段
+//   - model: 表结构快照，需要包含要删除的字段
+//   - fields: 字段名（结构体字段名）
+func dropColumns(tx *gorm.DB, model any, fields ...string) error {
+	m := tx.Migrator()
+	for _, field := range fields {
+		if !m.HasColumn(model, field) {
+			continue
+		}
+		if err := m.DropColumn(model, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nola-go/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// State 迁移状态
+type State string
+
+const (
+	// StateApplied 已执行
+	StateApplied State = "APPLIED"
+	// StatePending 待执行
+	StatePending State = "PENDING"
+	// StateModified 已执行，但迁移内容在执行后被修改（校验和不一致）
+	StateModified State = "MODIFIED"
+	// StateUnknown 数据库中存在，但程序中不存在的迁移（数据库版本高于程序版本）
+	StateUnknown State = "UNKNOWN"
+)
+
+// Status 迁移状态
+type Status struct {
+	// Version 版本号
+	Version uint `json:"version"`
+	// Name 迁移名称
+	Name string `json:"name"`
+	// State 迁移状态
+	State State `json:"state"`
+	// Checksum 程序中的迁移校验和
+	Checksum string `json:"checksum"`
+	// AppliedAt 迁移执行时间
+	AppliedAt *int64 `json:"appliedAt"`
+}
+
+// Migrator 数据库迁移执行器
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+}
+
+// NewMigrator 创建数据库迁移执行器
+func NewMigrator(db *gorm.DB) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: Migrations(),
+	}
+}
+
+// Up 执行所有待执行的迁移
+// Returns: 本次执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.verify(applied); err != nil {
+		return nil, err
+	}
+
+	var ret []*Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&models.SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.Checksum(),
+				AppliedAt: time.Now().UnixMilli(),
+			}).Error
+		})
+
+		if err != nil {
+			return ret, fmt.Errorf("执行迁移 [%d_%s] 失败: %w", migration.Version, migration.Name, err)
+		}
+		ret = append(ret, migration)
+	}
+
+	return ret, nil
+}
+
+// Down 回滚最近执行的 steps 个迁移
+// Returns: 本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	if steps <= 0 {
+		return nil, errors.New("回滚数量必须大于 0")
+	}
+
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.verify(applied); err != nil {
+		return nil, err
+	}
+
+	var ret []*Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(ret) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		if migration.Down == nil {
+			return ret, fmt.Errorf("迁移 [%d_%s] 不支持回滚", migration.Version, migration.Name)
+		}
+
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Where("version = ?", migration.Version).Delete(&models.SchemaMigration{}).Error
+		})
+
+		if err != nil {
+			return ret, fmt.Errorf("回滚迁移 [%d_%s] 失败: %w", migration.Version, migration.Name, err)
+		}
+		ret = append(ret, migration)
+	}
+
+	return ret, nil
+}
+
+// Status 获取所有迁移的状态
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*Status
+	for _, migration := range m.migrations {
+		status := &Status{
+			Version:  migration.Version,
+			Name:     migration.Name,
+			State:    StatePending,
+			Checksum: migration.Checksum(),
+		}
+
+		if record, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &record.AppliedAt
+			if record.Checksum == status.Checksum {
+				status.State = StateApplied
+			} else {
+				status.State = StateModified
+			}
+			delete(applied, migration.Version)
+		}
+
+		ret = append(ret, status)
+	}
+
+	// 剩下的是程序中不存在的迁移
+	for _, record := range applied {
+		ret = append(ret, &Status{
+			Version:   record.Version,
+			Name:      record.Name,
+			State:     StateUnknown,
+			Checksum:  record.Checksum,
+			AppliedAt: &record.AppliedAt,
+		})
+	}
+
+	return ret, nil
+}
+
+// verify 校验已执行的迁移是否与程序中的迁移一致
+func (m *Migrator) verify(applied map[uint]*models.SchemaMigration) error {
+	known := make(map[uint]*Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	for version, record := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("数据库中存在未知的迁移 [%d_%s]，请升级程序版本", record.Version, record.Name)
+		}
+		if migration.Checksum() != record.Checksum {
+			return fmt.Errorf("迁移 [%d_%s] 的校验和不一致，已执行的迁移不能被修改", migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+// appliedMigrations 获取数据库中已执行的迁移记录，迁移记录表不存在时自动创建
+func (m *Migrator) appliedMigrations(ctx context.Context) (map[uint]*models.SchemaMigration, error) {
+	db := m.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&models.SchemaMigration{}) {
+		if err := db.Migrator().CreateTable(&models.SchemaMigration{}); err != nil {
+			return nil, fmt.Errorf("创建迁移记录表失败: %w", err)
+		}
+	}
+
+	var records []*models.SchemaMigration
+	if err := db.Order("version ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	ret := make(map[uint]*models.SchemaMigration, len(records))
+	for _, record := range records {
+		ret[record.Version] = record
+	}
+	return ret, nil
+}
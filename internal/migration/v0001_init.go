@@ -0,0 +1,209 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 1 的表结构快照，已发布，请勿修改。
+// 内容字段的排序规则由数据库默认字符集决定（MySQL DSN 中 charset=utf8mb4）。
+
+type v1User struct {
+	UserId        uint    `gorm:"column:user_id;primaryKey;autoIncrement"`
+	Username      string  `gorm:"column:username;size:64;uniqueIndex;not null"`
+	Email         string  `gorm:"column:email;size:128;not null"`
+	DisplayName   string  `gorm:"column:display_name;size:128;not null"`
+	Password      string  `gorm:"column:password;size:128;not null"`
+	Salt          string  `gorm:"column:salt;size:128;not null"`
+	Description   *string `gorm:"column:description;size:1024"`
+	CreateDate    int64   `gorm:"column:create_date;not null"`
+	LastLoginDate *int64  `gorm:"column:last_login_time"` // 初始化管理员时为空，不能为 not null
+	Avatar        *string `gorm:"column:avatar;size:512"`
+}
+
+func (v1User) TableName() string { return "user" }
+
+type v1Post struct {
+	PostId              uint    `gorm:"column:post_id;primaryKey;autoIncrement"`
+	Title               string  `gorm:"column:title;size:256;not null"`
+	AutoGenerateExcerpt bool    `gorm:"column:auto_generate_excerpt;not null"`
+	Excerpt             string  `gorm:"column:excerpt;size:1024"`
+	Slug                string  `gorm:"column:slug;size:128;uniqueIndex;not null"`
+	Cover               *string `gorm:"column:cover;size:512"`
+	AllowComment        bool    `gorm:"column:allow_comment;not null"`
+	Pinned              bool    `gorm:"column:pinned;not null"`
+	Status              string  `gorm:"column:status;type:varchar(24);not null"`
+	Visible             string  `gorm:"column:visible;type:varchar(24);not null"`
+	Password            *string `gorm:"column:password;size:64"`
+	Visit               uint    `gorm:"column:visit;default:0;not null"`
+	CreateTime          int64   `gorm:"column:create_time;not null"`
+	LastModifyTime      *int64  `gorm:"column:last_modify_time"`
+}
+
+func (v1Post) TableName() string { return "post" }
+
+type v1PostContent struct {
+	PostContentId  uint    `gorm:"column:post_content_id;primaryKey;autoIncrement"`
+	PostId         uint    `gorm:"column:post_id;index;not null"`
+	Content        string  `gorm:"column:content;type:text;not null"`
+	HTML           string  `gorm:"column:html;type:text;not null"`
+	Status         string  `gorm:"column:status;type:varchar(24);not null"`
+	DraftName      *string `gorm:"column:draft_name;size:256"`
+	LastModifyTime *int64  `gorm:"column:last_modify_time"`
+}
+
+func (v1PostContent) TableName() string { return "post_content" }
+
+type v1PostTag struct {
+	PostTagId uint `gorm:"column:post_tag_id;primaryKey;autoIncrement"`
+	PostId    uint `gorm:"column:post_id;index;not null"`
+	TagId     uint `gorm:"column:tag_id;index;not null"`
+}
+
+func (v1PostTag) TableName() string { return "post_tag" }
+
+type v1PostCategory struct {
+	PostCategoryId uint `gorm:"column:post_category_id;primaryKey;autoIncrement"`
+	PostId         uint `gorm:"column:post_id;index;not null"`
+	CategoryId     uint `gorm:"column:category_id;index;not null"`
+}
+
+func (v1PostCategory) TableName() string { return "post_category" }
+
+type v1Tag struct {
+	TagId       uint    `gorm:"column:tag_id;primaryKey;autoIncrement"`
+	DisplayName string  `gorm:"column:display_name;size:256;not null"`
+	Slug        string  `gorm:"column:slug;size:128;uniqueIndex;not null"`
+	Color       *string `gorm:"column:color;size:24"`
+}
+
+func (v1Tag) TableName() string { return "tag" }
+
+type v1Category struct {
+	CategoryId   uint    `gorm:"column:category_id;primaryKey;autoIncrement"`
+	DisplayName  string  `gorm:"column:display_name;size:256;not null"`
+	Slug         string  `gorm:"column:slug;size:128;uniqueIndex;not null"`
+	Cover        *string `gorm:"column:cover;size:128"`
+	UnifiedCover bool    `gorm:"column:unified_cover;default:false;not null"`
+}
+
+func (v1Category) TableName() string { return "category" }
+
+type v1Comment struct {
+	CommentId        uint    `gorm:"column:comment_id;primaryKey;autoIncrement"`
+	PostId           uint    `gorm:"column:post_id;index;not null"`
+	ParentCommentId  *uint   `gorm:"column:parent_comment_id"`
+	ReplyCommentId   *uint   `gorm:"column:reply_comment_id"`
+	ReplyDisplayName *string `gorm:"column:reply_display_name;size:128"`
+	Content          string  `gorm:"column:content;type:text;not null"`
+	Site             *string `gorm:"column:site;size:512"`
+	DisplayName      string  `gorm:"column:display_name;size:128;not null"`
+	Email            string  `gorm:"column:email;size:128;not null"`
+	CreateTime       int64   `gorm:"column:create_time;not null"`
+	IsPass           bool    `gorm:"column:is_pass;not null"`
+}
+
+func (v1Comment) TableName() string { return "comment" }
+
+type v1Config struct {
+	ConfigId uint   `gorm:"column:config_id;primaryKey;autoIncrement"`
+	Key      string `gorm:"column:key;type:varchar(64);uniqueIndex;not null"`
+	Value    string `gorm:"column:value;type:text;not null"`
+}
+
+func (v1Config) TableName() string { return "config" }
+
+type v1Diary struct {
+	DiaryId        uint   `gorm:"column:diary_id;primaryKey;autoIncrement"`
+	Content        string `gorm:"column:content;type:text;not null"`
+	Html           string `gorm:"column:html;type:text;not null"`
+	CreateTime     int64  `gorm:"column:create_time;not null"`
+	LastModifyTime *int64 `gorm:"column:last_modify_time"`
+}
+
+func (v1Diary) TableName() string { return "diary" }
+
+type v1File struct {
+	FileId      uint   `gorm:"column:file_id;primaryKey;autoIncrement"`
+	FileGroupId *uint  `gorm:"column:file_group_id;index"`
+	DisplayName string `gorm:"column:display_name;type:varchar(512);not null"`
+	Size        int64  `gorm:"column:size;not null"`
+	StorageMode string `gorm:"column:storage_mode;type:varchar(48);not null"`
+	CreateTime  int64  `gorm:"column:create_time;not null"`
+}
+
+func (v1File) TableName() string { return "file" }
+
+type v1FileGroup struct {
+	FileGroupId uint   `gorm:"column:file_group_id;primaryKey;autoIncrement"`
+	DisplayName string `gorm:"column:display_name;type:varchar(128);not null"`
+	Path        string `gorm:"column:path;type:varchar(128);not null"`
+	StorageMode string `gorm:"column:storage_mode;type:varchar(48);not null"`
+}
+
+func (v1FileGroup) TableName() string { return "file_group" }
+
+type v1FileStorageMode struct {
+	FileStorageModeId uint   `gorm:"column:file_storage_mode_id;primaryKey;autoIncrement"`
+	StorageMode       string `gorm:"column:storage_mode;type:varchar(48);not null"`
+	Config            string `gorm:"column:config;type:text;not null"`
+}
+
+func (v1FileStorageMode) TableName() string { return "file_storage_mode" }
+
+type v1Link struct {
+	LinkId         uint    `gorm:"column:link_id;primaryKey;autoIncrement"`
+	DisplayName    string  `gorm:"column:display_name;size:128;not null"`
+	Url            string  `gorm:"column:url;size:512;not null"`
+	Logo           *string `gorm:"column:logo;size:512"`
+	Description    *string `gorm:"column:description;size:512"`
+	Priority       uint    `gorm:"column:priority;default:0;not null"`
+	Remark         *string `gorm:"column:remark;size:256"`
+	IsLost         bool    `gorm:"column:is_lost;default:false;not null"`
+	CreateTime     int64   `gorm:"column:create_time;not null"`
+	LastModifyTime *int64  `gorm:"column:last_modify_time"`
+}
+
+func (v1Link) TableName() string { return "link" }
+
+type v1Menu struct {
+	MenuId         uint   `gorm:"column:menu_id;primaryKey;autoIncrement"`
+	IsMain         bool   `gorm:"column:is_main;not null"`
+	DisplayName    string `gorm:"column:display_name;size:128;not null;uniqueIndex"`
+	CreateTime     int64  `gorm:"column:create_time;not null"`
+	LastModifyTime *int64 `gorm:"column:last_modify_time"`
+}
+
+func (v1Menu) TableName() string { return "menu" }
+
+type v1MenuItem struct {
+	MenuItemId       uint   `gorm:"column:menu_item_id;primaryKey;autoIncrement"`
+	DisplayName      string `gorm:"column:display_name;size:128;not null"`
+	Href             string `gorm:"column:href;size:512;not null"`
+	Target           string `gorm:"column:target;type:varchar(12);not null"`
+	ParentMenuId     *uint  `gorm:"column:parent_menuId;index"`
+	ParentMenuItemId *uint  `gorm:"column:parent_menu_item_id"`
+	Index            uint   `gorm:"column:index;default:0;not null"`
+	CreateTime       int64  `gorm:"column:create_time;not null"`
+	LastModifyTime   *int64 `gorm:"column:last_modify_time"`
+}
+
+func (v1MenuItem) TableName() string { return "menu_item" }
+
+func init() {
+	tables := []any{
+		&v1User{}, &v1Post{}, &v1PostContent{}, &v1PostTag{}, &v1PostCategory{},
+		&v1Tag{}, &v1Category{}, &v1Comment{}, &v1Config{}, &v1Diary{},
+		&v1File{}, &v1FileGroup{}, &v1FileStorageMode{}, &v1Link{}, &v1Menu{}, &v1MenuItem{},
+	}
+
+	register(&Migration{
+		Version: 1,
+		Name:    "init",
+		Models:  tables,
+		Up: func(tx *gorm.DB) error {
+			// 已经手动建表的数据库只补齐缺失的表
+			return createTables(tx, tables...)
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, tables...)
+		},
+	})
+}
@@ -0,0 +1,48 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 2 的表结构快照，已发布，请勿修改。
+
+// v2User 用户表新增的两步验证字段
+type v2User struct {
+	UserId       uint    `gorm:"column:user_id;primaryKey;autoIncrement"`
+	TotpSecret   *string `gorm:"column:totp_secret;size:64"`
+	TotpEnabled  bool    `gorm:"column:totp_enabled;default:false;not null"`
+	TotpLastStep *int64  `gorm:"column:totp_last_step"`
+}
+
+func (v2User) TableName() string { return "user" }
+
+// v2UserRecoveryCode 两步验证恢复码表
+type v2UserRecoveryCode struct {
+	RecoveryCodeId uint   `gorm:"column:recovery_code_id;primaryKey;autoIncrement"`
+	UserId         uint   `gorm:"column:user_id;index;not null"`
+	CodeHash       string `gorm:"column:code_hash;size:128;not null"`
+	UsedTime       *int64 `gorm:"column:used_time"`
+	CreateTime     int64  `gorm:"column:create_time;not null"`
+}
+
+func (v2UserRecoveryCode) TableName() string { return "user_recovery_code" }
+
+func init() {
+	userFields := []string{"TotpSecret", "TotpEnabled", "TotpLastStep"}
+
+	register(&Migration{
+		Version: 2,
+		Name:    "user_totp",
+		Models:  []any{&v2User{}, &v2UserRecoveryCode{}},
+		Up: func(tx *gorm.DB) error {
+			if err := addColumns(tx, &v2User{}, userFields...); err != nil {
+				return err
+			}
+			return createTables(tx, &v2UserRecoveryCode{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropTables(tx, &v2UserRecoveryCode{}); err != nil {
+				return err
+			}
+			return dropColumns(tx, &v2User{}, userFields...)
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"nola-go/internal/db"
+
+	"gorm.io/gorm"
+)
+
+// 以下为版本 3 的表结构快照，已发布，请勿修改。
+
+// v3Post 文章密码字段加长，用于保存 Argon2id / bcrypt 哈希
+type v3Post struct {
+	PostId   uint    `gorm:"column:post_id;primaryKey;autoIncrement"`
+	Password *string `gorm:"column:password;size:255"`
+}
+
+func (v3Post) TableName() string { return "post" }
+
+func init() {
+	register(&Migration{
+		Version: 3,
+		Name:    "password_hash",
+		Models:  []any{&v3Post{}},
+		Up: func(tx *gorm.DB) error {
+			// SQLite 不限制字符串长度，并且修改字段需要重建表（会丢失索引），无需修改
+			if tx.Dialector.Name() == db.DriverSQLite {
+				return nil
+			}
+			return tx.Migrator().AlterColumn(&v3Post{}, "Password")
+		},
+		Down: func(tx *gorm.DB) error {
+			// 字段缩短后会截断已有的 Argon2id / bcrypt 哈希，回滚时保持字段长度不变
+			return nil
+		},
+	})
+}
@@ -0,0 +1,66 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 4 的表结构快照，已发布，请勿修改。
+
+// v4User 用户表新增角色字段
+type v4User struct {
+	UserId uint   `gorm:"column:user_id;primaryKey;autoIncrement"`
+	Role   string `gorm:"column:role;type:varchar(24);default:'CONTRIBUTOR';not null"`
+}
+
+func (v4User) TableName() string { return "user" }
+
+// v4Post 文章表新增作者字段
+type v4Post struct {
+	PostId   uint  `gorm:"column:post_id;primaryKey;autoIncrement"`
+	AuthorId *uint `gorm:"column:author_id;index"`
+}
+
+func (v4Post) TableName() string { return "post" }
+
+func init() {
+	register(&Migration{
+		Version: 4,
+		Name:    "user_role",
+		Models:  []any{&v4User{}, &v4Post{}},
+		Up: func(tx *gorm.DB) error {
+			if err := addColumns(tx, &v4User{}, "Role"); err != nil {
+				return err
+			}
+			if err := addColumns(tx, &v4Post{}, "AuthorId"); err != nil {
+				return err
+			}
+			if !tx.Migrator().HasIndex(&v4Post{}, "AuthorId") {
+				if err := tx.Migrator().CreateIndex(&v4Post{}, "AuthorId"); err != nil {
+					return err
+				}
+			}
+
+			// 此前只能创建一个用户（博主），将其设为所有者，并作为已有文章的作者
+			var ownerIds []uint
+			if err := tx.Model(&v4User{}).Order("user_id ASC").Limit(1).Pluck("user_id", &ownerIds).Error; err != nil {
+				return err
+			}
+			if len(ownerIds) == 0 {
+				return nil
+			}
+			if err := tx.Model(&v4User{}).Where("user_id = ?", ownerIds[0]).Update("role", "OWNER").Error; err != nil {
+				return err
+			}
+			return tx.Model(&v4Post{}).Where("author_id IS NULL").Update("author_id", ownerIds[0]).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Migrator().HasIndex(&v4Post{}, "AuthorId") {
+				if err := tx.Migrator().DropIndex(&v4Post{}, "AuthorId"); err != nil {
+					return err
+				}
+			}
+			if err := dropColumns(tx, &v4Post{}, "AuthorId"); err != nil {
+				return err
+			}
+			return dropColumns(tx, &v4User{}, "Role")
+		},
+	})
+}
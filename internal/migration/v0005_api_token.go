@@ -0,0 +1,35 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 5 的表结构快照，已发布，请勿修改。
+
+// v5ApiToken 个人 API 令牌表
+type v5ApiToken struct {
+	TokenId      uint    `gorm:"column:token_id;primaryKey;autoIncrement"`
+	UserId       uint    `gorm:"column:user_id;index;not null"`
+	Name         string  `gorm:"column:name;size:64;not null"`
+	TokenHash    string  `gorm:"column:token_hash;size:64;uniqueIndex;not null"`
+	TokenPrefix  string  `gorm:"column:token_prefix;size:32;not null"`
+	Scopes       string  `gorm:"column:scopes;size:256;not null"`
+	ExpireTime   *int64  `gorm:"column:expire_time"`
+	LastUsedTime *int64  `gorm:"column:last_used_time"`
+	LastUsedIp   *string `gorm:"column:last_used_ip;size:64"`
+	CreateTime   int64   `gorm:"column:create_time;not null"`
+}
+
+func (v5ApiToken) TableName() string { return "api_token" }
+
+func init() {
+	register(&Migration{
+		Version: 5,
+		Name:    "api_token",
+		Models:  []any{&v5ApiToken{}},
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &v5ApiToken{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &v5ApiToken{})
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 6 的表结构快照，已发布，请勿修改。
+
+// v6AuditLog 审计日志表
+type v6AuditLog struct {
+	AuditLogId uint    `gorm:"column:audit_log_id;primaryKey;autoIncrement"`
+	ActorId    *uint   `gorm:"column:actor_id;index"`
+	ActorName  *string `gorm:"column:actor_name;size:64"`
+	Action     string  `gorm:"column:action;type:varchar(32);index;not null"`
+	TargetType string  `gorm:"column:target_type;type:varchar(24);not null"`
+	TargetId   *string `gorm:"column:target_id;size:128"`
+	Diff       *string `gorm:"column:diff;type:text"`
+	Ip         *string `gorm:"column:ip;size:64"`
+	CreateTime int64   `gorm:"column:create_time;index;not null"`
+}
+
+func (v6AuditLog) TableName() string { return "audit_log" }
+
+func init() {
+	register(&Migration{
+		Version: 6,
+		Name:    "audit_log",
+		Models:  []any{&v6AuditLog{}},
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &v6AuditLog{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &v6AuditLog{})
+		},
+	})
+}
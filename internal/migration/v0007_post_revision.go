@@ -0,0 +1,33 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 7 的表结构快照，已发布，请勿修改。
+
+// v7PostRevision 文章修订版本表
+type v7PostRevision struct {
+	RevisionId uint    `gorm:"column:revision_id;primaryKey;autoIncrement"`
+	PostId     uint    `gorm:"column:post_id;index;not null"`
+	AuthorId   *uint   `gorm:"column:author_id"`
+	Status     string  `gorm:"column:status;type:varchar(24);not null"`
+	DraftName  *string `gorm:"column:draft_name;size:256"`
+	Content    string  `gorm:"column:content;type:text;not null"`
+	WordCount  int     `gorm:"column:word_count;not null"`
+	CreateTime int64   `gorm:"column:create_time;not null"`
+}
+
+func (v7PostRevision) TableName() string { return "post_revision" }
+
+func init() {
+	register(&Migration{
+		Version: 7,
+		Name:    "post_revision",
+		Models:  []any{&v7PostRevision{}},
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &v7PostRevision{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &v7PostRevision{})
+		},
+	})
+}
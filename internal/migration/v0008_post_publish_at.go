@@ -0,0 +1,38 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 8 的表结构快照，已发布，请勿修改。
+
+// v8Post 文章表新增定时发布时间字段
+type v8Post struct {
+	PostId    uint   `gorm:"column:post_id;primaryKey;autoIncrement"`
+	PublishAt *int64 `gorm:"column:publish_at;index"`
+}
+
+func (v8Post) TableName() string { return "post" }
+
+func init() {
+	register(&Migration{
+		Version: 8,
+		Name:    "post_publish_at",
+		Models:  []any{&v8Post{}},
+		Up: func(tx *gorm.DB) error {
+			if err := addColumns(tx, &v8Post{}, "PublishAt"); err != nil {
+				return err
+			}
+			if !tx.Migrator().HasIndex(&v8Post{}, "PublishAt") {
+				return tx.Migrator().CreateIndex(&v8Post{}, "PublishAt")
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Migrator().HasIndex(&v8Post{}, "PublishAt") {
+				if err := tx.Migrator().DropIndex(&v8Post{}, "PublishAt"); err != nil {
+					return err
+				}
+			}
+			return dropColumns(tx, &v8Post{}, "PublishAt")
+		},
+	})
+}
@@ -0,0 +1,49 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 以下为版本 9 的表结构快照，已发布，请勿修改。
+
+// v9Post 文章表新增移入回收站时间字段
+type v9Post struct {
+	PostId    uint   `gorm:"column:post_id;primaryKey;autoIncrement"`
+	Status    string `gorm:"column:status;type:varchar(24);not null"`
+	DeletedAt *int64 `gorm:"column:deleted_at;index"`
+}
+
+func (v9Post) TableName() string { return "post" }
+
+func init() {
+	register(&Migration{
+		Version: 9,
+		Name:    "post_deleted_at",
+		Models:  []any{&v9Post{}},
+		Up: func(tx *gorm.DB) error {
+			if err := addColumns(tx, &v9Post{}, "DeletedAt"); err != nil {
+				return err
+			}
+			if !tx.Migrator().HasIndex(&v9Post{}, "DeletedAt") {
+				if err := tx.Migrator().CreateIndex(&v9Post{}, "DeletedAt"); err != nil {
+					return err
+				}
+			}
+
+			// 已在回收站中的文章不知道移入时间，从升级时开始计算保留期限
+			return tx.Model(&v9Post{}).
+				Where("status = ? AND deleted_at IS NULL", "DELETED").
+				Update("deleted_at", time.Now().UnixMilli()).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Migrator().HasIndex(&v9Post{}, "DeletedAt") {
+				if err := tx.Migrator().DropIndex(&v9Post{}, "DeletedAt"); err != nil {
+					return err
+				}
+			}
+			return dropColumns(tx, &v9Post{}, "DeletedAt")
+		},
+	})
+}
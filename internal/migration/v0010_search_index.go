@@ -0,0 +1,39 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 10 的表结构快照，已发布，请勿修改。
+
+// v10SearchDocument 全文搜索文档表
+type v10SearchDocument struct {
+	DocId      uint  `gorm:"column:doc_id;primaryKey;autoIncrement:false"`
+	Length     int   `gorm:"column:length;not null"`
+	UpdateTime int64 `gorm:"column:update_time;not null"`
+}
+
+func (v10SearchDocument) TableName() string { return "search_document" }
+
+// v10SearchTerm 全文搜索倒排索引表
+type v10SearchTerm struct {
+	SearchTermId uint   `gorm:"column:search_term_id;primaryKey;autoIncrement"`
+	Term         string `gorm:"column:term;size:64;index;not null"`
+	DocId        uint   `gorm:"column:doc_id;index;not null"`
+	Frequency    int    `gorm:"column:frequency;not null"`
+}
+
+func (v10SearchTerm) TableName() string { return "search_term" }
+
+func init() {
+	register(&Migration{
+		Version: 10,
+		Name:    "search_index",
+		Models:  []any{&v10SearchDocument{}, &v10SearchTerm{}},
+		Up: func(tx *gorm.DB) error {
+			// 索引在服务启动时发现为空会自动重建，也可以使用 search rebuild 子命令手动重建
+			return createTables(tx, &v10SearchDocument{}, &v10SearchTerm{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &v10SearchTerm{}, &v10SearchDocument{})
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 11 的表结构快照，已发布，请勿修改。
+
+// v11Post 文章表新增 SEO 字段
+type v11Post struct {
+	PostId         uint    `gorm:"column:post_id;primaryKey;autoIncrement"`
+	SeoTitle       *string `gorm:"column:seo_title;size:256"`
+	SeoDescription *string `gorm:"column:seo_description;size:512"`
+	CanonicalURL   *string `gorm:"column:canonical_url;size:512"`
+	NoIndex        bool    `gorm:"column:no_index;default:false;not null"`
+	OgImage        *string `gorm:"column:og_image;size:512"`
+}
+
+func (v11Post) TableName() string { return "post" }
+
+// v11PostSeoFields 新增的 SEO 字段
+var v11PostSeoFields = []string{"SeoTitle", "SeoDescription", "CanonicalURL", "NoIndex", "OgImage"}
+
+func init() {
+	register(&Migration{
+		Version: 11,
+		Name:    "post_seo",
+		Models:  []any{&v11Post{}},
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &v11Post{}, v11PostSeoFields...)
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, &v11Post{}, v11PostSeoFields...)
+		},
+	})
+}
@@ -0,0 +1,41 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 12 的表结构快照，已发布，请勿修改。
+
+// v12Series 系列表
+type v12Series struct {
+	SeriesId    uint    `gorm:"column:series_id;primaryKey;autoIncrement"`
+	DisplayName string  `gorm:"column:display_name;size:256;not null"`
+	Slug        string  `gorm:"column:slug;size:128;uniqueIndex;not null"`
+	Description *string `gorm:"column:description;size:1024"`
+	Cover       *string `gorm:"column:cover;size:512"`
+	CreateTime  int64   `gorm:"column:create_time;not null"`
+}
+
+func (v12Series) TableName() string { return "series" }
+
+// v12PostSeries 文章系列表
+type v12PostSeries struct {
+	PostSeriesId uint `gorm:"column:post_series_id;primaryKey;autoIncrement"`
+	SeriesId     uint `gorm:"column:series_id;index;not null"`
+	PostId       uint `gorm:"column:post_id;uniqueIndex;not null"`
+	Sort         int  `gorm:"column:sort;not null"`
+}
+
+func (v12PostSeries) TableName() string { return "post_series" }
+
+func init() {
+	register(&Migration{
+		Version: 12,
+		Name:    "series",
+		Models:  []any{&v12Series{}, &v12PostSeries{}},
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &v12Series{}, &v12PostSeries{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &v12PostSeries{}, &v12Series{})
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 13 的表结构快照，已发布，请勿修改。
+
+// v13MailUnsubscribe 退订邮件通知表
+type v13MailUnsubscribe struct {
+	MailUnsubscribeId uint   `gorm:"column:mail_unsubscribe_id;primaryKey;autoIncrement"`
+	Email             string `gorm:"column:email;size:128;uniqueIndex;not null"`
+	CreateTime        int64  `gorm:"column:create_time;not null"`
+}
+
+func (v13MailUnsubscribe) TableName() string { return "mail_unsubscribe" }
+
+func init() {
+	register(&Migration{
+		Version: 13,
+		Name:    "mail_unsubscribe",
+		Models:  []any{&v13MailUnsubscribe{}},
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &v13MailUnsubscribe{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &v13MailUnsubscribe{})
+		},
+	})
+}
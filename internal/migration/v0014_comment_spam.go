@@ -0,0 +1,32 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 14 的表结构快照，已发布，请勿修改。
+
+// v14Comment 评论表新增垃圾评论字段
+type v14Comment struct {
+	CommentId  uint    `gorm:"column:comment_id;primaryKey;autoIncrement"`
+	IsSpam     bool    `gorm:"column:is_spam;default:false;not null"`
+	SpamScore  int     `gorm:"column:spam_score;default:0;not null"`
+	SpamReason *string `gorm:"column:spam_reason;size:512"`
+}
+
+func (v14Comment) TableName() string { return "comment" }
+
+// v14CommentSpamFields 新增的垃圾评论字段
+var v14CommentSpamFields = []string{"IsSpam", "SpamScore", "SpamReason"}
+
+func init() {
+	register(&Migration{
+		Version: 14,
+		Name:    "comment_spam",
+		Models:  []any{&v14Comment{}},
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &v14Comment{}, v14CommentSpamFields...)
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, &v14Comment{}, v14CommentSpamFields...)
+		},
+	})
+}
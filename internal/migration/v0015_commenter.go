@@ -0,0 +1,31 @@
+package migration
+
+import "gorm.io/gorm"
+
+// 以下为版本 15 的表结构快照，已发布，请勿修改。
+
+// v15Commenter 管理员标记的评论人表
+type v15Commenter struct {
+	CommenterId    uint    `gorm:"column:commenter_id;primaryKey;autoIncrement"`
+	Email          string  `gorm:"column:email;size:128;uniqueIndex;not null"`
+	Status         string  `gorm:"column:status;size:16;not null"`
+	Remark         *string `gorm:"column:remark;size:256"`
+	CreateTime     int64   `gorm:"column:create_time;not null"`
+	LastModifyTime *int64  `gorm:"column:last_modify_time"`
+}
+
+func (v15Commenter) TableName() string { return "commenter" }
+
+func init() {
+	register(&Migration{
+		Version: 15,
+		Name:    "commenter",
+		Models:  []any{&v15Commenter{}},
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &v15Commenter{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &v15Commenter{})
+		},
+	})
+}
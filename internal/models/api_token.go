@@ -0,0 +1,38 @@
+package models
+
+// ApiToken 个人 API 令牌，用于脚本和持续集成等自动化场景
+type ApiToken struct {
+	// TokenId 令牌 ID
+	TokenId uint `gorm:"column:token_id;primaryKey;autoIncrement" json:"tokenId"`
+
+	// UserId 所属用户 ID
+	UserId uint `gorm:"column:user_id;index;not null" json:"userId"`
+
+	// Name 令牌名称
+	Name string `gorm:"column:name;size:64;not null" json:"name"`
+
+	// TokenHash 令牌哈希，令牌明文只在创建时返回一次
+	TokenHash string `gorm:"column:token_hash;size:64;uniqueIndex;not null" json:"-"`
+
+	// TokenPrefix 令牌前几位，用于辨认令牌
+	TokenPrefix string `gorm:"column:token_prefix;size:32;not null" json:"tokenPrefix"`
+
+	// Scopes 权限范围，多个权限范围以英文逗号分隔
+	Scopes string `gorm:"column:scopes;size:256;not null" json:"scopes"`
+
+	// ExpireTime 过期时间戳毫秒，为空表示永不过期
+	ExpireTime *int64 `gorm:"column:expire_time" json:"expireTime"`
+
+	// LastUsedTime 最后使用时间戳毫秒
+	LastUsedTime *int64 `gorm:"column:last_used_time" json:"lastUsedTime"`
+
+	// LastUsedIp 最后使用的 IP 地址
+	LastUsedIp *string `gorm:"column:last_used_ip;size:64" json:"lastUsedIp"`
+
+	// CreateTime 创建时间戳毫秒
+	CreateTime int64 `gorm:"column:create_time;not null" json:"createTime"`
+}
+
+func (ApiToken) TableName() string {
+	return "api_token"
+}
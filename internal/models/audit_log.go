@@ -0,0 +1,37 @@
+package models
+
+import "nola-go/internal/models/enum"
+
+// AuditLog 审计日志，只允许添加，不允许修改和删除
+type AuditLog struct {
+	// AuditLogId 审计日志 ID
+	AuditLogId uint `gorm:"column:audit_log_id;primaryKey;autoIncrement" json:"auditLogId"`
+
+	// ActorId 操作用户 ID，未登录的操作（例如初始化博客）为空
+	ActorId *uint `gorm:"column:actor_id;index" json:"actorId"`
+
+	// ActorName 操作用户名，用户删除后仍可辨认操作人
+	ActorName *string `gorm:"column:actor_name;size:64" json:"actorName"`
+
+	// Action 操作类型
+	Action enum.AuditAction `gorm:"column:action;type:varchar(32);index;not null" json:"action"`
+
+	// TargetType 操作对象类型
+	TargetType enum.AuditTargetType `gorm:"column:target_type;type:varchar(24);not null" json:"targetType"`
+
+	// TargetId 操作对象 ID
+	TargetId *string `gorm:"column:target_id;size:128" json:"targetId"`
+
+	// Diff 操作前后的字段变化（JSON）
+	Diff *string `gorm:"column:diff;type:text" json:"diff"`
+
+	// Ip 客户端 IP 地址
+	Ip *string `gorm:"column:ip;size:64" json:"ip"`
+
+	// CreateTime 操作时间戳毫秒
+	CreateTime int64 `gorm:"column:create_time;index;not null" json:"createTime"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
@@ -14,4 +14,12 @@ type BlogInfo struct {
 	Favicon *string `json:"favicon"`
 	// CreateDate 博客创建时间
 	CreateDate *int64 `json:"createDate"`
+	// SiteURL 博客前端地址（如 https://example.com），用于生成订阅、站点地图中的绝对链接，为空时使用请求的地址
+	SiteURL *string `json:"siteUrl"`
+	// PostURLPattern 文章页面路径，{slug} 会被替换为文章别名，为空时默认 /post/{slug}
+	PostURLPattern *string `json:"postUrlPattern"`
+	// TagURLPattern 标签页面路径，{slug} 会被替换为标签别名，为空时默认 /tag/{slug}
+	TagURLPattern *string `json:"tagUrlPattern"`
+	// CategoryURLPattern 分类页面路径，{slug} 会被替换为分类别名，为空时默认 /category/{slug}
+	CategoryURLPattern *string `json:"categoryUrlPattern"`
 }
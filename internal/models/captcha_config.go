@@ -0,0 +1,11 @@
+package models
+
+// CaptchaConfig 评论验证码设置
+type CaptchaConfig struct {
+	// Enabled 是否启用，启用后博客前端提交评论前需要获取并完成验证码
+	Enabled bool `json:"enabled"`
+	// Type 验证码类型（image 图片验证码、pow 工作量证明）
+	Type string `json:"type"`
+	// Difficulty 工作量证明难度（前导 0 的位数，8 - 28），难度每加 1 计算量翻倍
+	Difficulty int `json:"difficulty"`
+}
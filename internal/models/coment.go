@@ -24,6 +24,12 @@ type Comment struct {
 	CreateTime int64 `gorm:"column:create_time;not null" json:"createTime"`
 	// IsPass 是否通过审核
 	IsPass bool `gorm:"column:is_pass;not null" json:"isPass"`
+	// IsSpam 是否为垃圾评论，垃圾评论不会通过审核
+	IsSpam bool `gorm:"column:is_spam;default:false;not null" json:"isSpam"`
+	// SpamScore 垃圾评论过滤的分数
+	SpamScore int `gorm:"column:spam_score;default:0;not null" json:"spamScore"`
+	// SpamReason 垃圾评论过滤命中的原因
+	SpamReason *string `gorm:"column:spam_reason;size:512" json:"spamReason"`
 	// Children 子评论
 	Children []Comment `gorm:"-" json:"children"`
 	// PostTitle 文章标题
@@ -0,0 +1,23 @@
+package models
+
+import "nola-go/internal/models/enum"
+
+// Commenter 管理员标记的评论人，按邮箱信任或禁止评论
+type Commenter struct {
+	// CommenterId 评论人 ID
+	CommenterId uint `gorm:"column:commenter_id;primaryKey;autoIncrement" json:"commenterId"`
+	// Email 邮箱（小写）
+	Email string `gorm:"column:email;size:128;uniqueIndex;not null" json:"email"`
+	// Status 状态
+	Status enum.CommenterStatus `gorm:"column:status;size:16;not null" json:"status"`
+	// Remark 备注
+	Remark *string `gorm:"column:remark;size:256" json:"remark"`
+	// CreateTime 标记时间
+	CreateTime int64 `gorm:"column:create_time;autoCreateTime:milli;not null" json:"createTime"`
+	// LastModifyTime 最后修改时间
+	LastModifyTime *int64 `gorm:"column:last_modify_time" json:"lastModifyTime"`
+}
+
+func (Commenter) TableName() string {
+	return "commenter"
+}
@@ -26,4 +26,13 @@ const (
 
 	// ConfigKeyICPFiling ICP 备案信息
 	ConfigKeyICPFiling ConfigKey = "ICP_FILING"
+
+	// ConfigKeyRobotsTxt robots.txt 内容
+	ConfigKeyRobotsTxt ConfigKey = "ROBOTS_TXT"
+
+	// ConfigKeySMTP 邮件通知设置
+	ConfigKeySMTP ConfigKey = "SMTP"
+
+	// ConfigKeyCaptcha 评论验证码设置
+	ConfigKeyCaptcha ConfigKey = "CAPTCHA"
 )
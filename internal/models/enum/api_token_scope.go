@@ -0,0 +1,105 @@
+package enum
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ApiTokenScope API 令牌权限范围
+// 使用 API 令牌访问接口时，需要用户角色和令牌权限范围同时拥有接口需要的权限
+type ApiTokenScope string
+
+const (
+	// ApiTokenScopePostWrite 撰写、修改和发布文章
+	ApiTokenScopePostWrite ApiTokenScope = "post:write"
+
+	// ApiTokenScopeFileUpload 上传和管理文件
+	ApiTokenScopeFileUpload ApiTokenScope = "file:upload"
+
+	// ApiTokenScopeCommentModerate 审核、回复和删除评论
+	ApiTokenScopeCommentModerate ApiTokenScope = "comment:moderate"
+
+	// ApiTokenScopeContentManage 管理标签、分类、菜单、友链和日记
+	ApiTokenScopeContentManage ApiTokenScope = "content:manage"
+)
+
+// scopePermissions 各权限范围包含的权限，用户管理和博客设置不能通过 API 令牌访问
+var scopePermissions = map[ApiTokenScope][]Permission{
+	ApiTokenScopePostWrite: {
+		PermissionWritePost,
+		PermissionPublishPost,
+		PermissionEditOthersPosts,
+	},
+	ApiTokenScopeFileUpload: {
+		PermissionUploadFile,
+	},
+	ApiTokenScopeCommentModerate: {
+		PermissionModerateComment,
+	},
+	ApiTokenScopeContentManage: {
+		PermissionManageContent,
+	},
+}
+
+// ApiTokenScopePtr 获取 API 令牌权限范围指针
+func ApiTokenScopePtr(s ApiTokenScope) *ApiTokenScope {
+	return &s
+}
+
+// ApiTokenScopeValueOf 尝试将字符串转为 API 令牌权限范围枚举
+func ApiTokenScopeValueOf(s string) *ApiTokenScope {
+	switch s {
+	case "post:write":
+		return ApiTokenScopePtr(ApiTokenScopePostWrite)
+	case "file:upload":
+		return ApiTokenScopePtr(ApiTokenScopeFileUpload)
+	case "comment:moderate":
+		return ApiTokenScopePtr(ApiTokenScopeCommentModerate)
+	case "content:manage":
+		return ApiTokenScopePtr(ApiTokenScopeContentManage)
+	default:
+		return nil
+	}
+}
+
+// ParseApiTokenScopes 解析以英文逗号分隔的权限范围，忽略无效的权限范围
+func ParseApiTokenScopes(s string) []ApiTokenScope {
+	ret := make([]ApiTokenScope, 0)
+	for _, item := range strings.Split(s, ",") {
+		if scope := ApiTokenScopeValueOf(item); scope != nil {
+			ret = append(ret, *scope)
+		}
+	}
+	return ret
+}
+
+// Permissions 获取权限范围包含的权限
+func (s ApiTokenScope) Permissions() []Permission {
+	return scopePermissions[s]
+}
+
+// Allows 判断权限范围是否包含指定权限
+func (s ApiTokenScope) Allows(p Permission) bool {
+	for _, permission := range scopePermissions[s] {
+		if permission == p {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON 自定义反序列化，验证枚举值
+func (s *ApiTokenScope) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	// 验证是否为有效枚举值
+	if enum := ApiTokenScopeValueOf(str); enum == nil {
+		return fmt.Errorf("invalid ApiTokenScope: %s", str)
+	}
+	*s = ApiTokenScope(str)
+	return nil
+}
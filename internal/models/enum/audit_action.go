@@ -0,0 +1,84 @@
+package enum
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AuditAction 审计日志操作类型
+type AuditAction string
+
+const (
+	// AuditActionPostDelete 彻底删除文章
+	AuditActionPostDelete AuditAction = "POST_DELETE"
+
+	// AuditActionFileDelete 删除文件
+	AuditActionFileDelete AuditAction = "FILE_DELETE"
+
+	// AuditActionCommentDelete 删除评论
+	AuditActionCommentDelete AuditAction = "COMMENT_DELETE"
+
+	// AuditActionConfigUpdate 修改博客配置（博客信息、备案信息）
+	AuditActionConfigUpdate AuditAction = "CONFIG_UPDATE"
+
+	// AuditActionUserCreate 添加用户
+	AuditActionUserCreate AuditAction = "USER_CREATE"
+
+	// AuditActionUserRoleUpdate 修改用户角色
+	AuditActionUserRoleUpdate AuditAction = "USER_ROLE_UPDATE"
+
+	// AuditActionUserDelete 删除用户
+	AuditActionUserDelete AuditAction = "USER_DELETE"
+
+	// AuditActionLoginFailed 验证失败（登录密码、两步验证码、加密文章密码）
+	AuditActionLoginFailed AuditAction = "LOGIN_FAILED"
+
+	// AuditActionLoginLocked 验证失败次数过多，IP 或账户被临时锁定
+	AuditActionLoginLocked AuditAction = "LOGIN_LOCKED"
+)
+
+// AuditActionPtr 获取审计日志操作类型指针
+func AuditActionPtr(a AuditAction) *AuditAction {
+	return &a
+}
+
+// AuditActionValueOf 尝试将字符串转为审计日志操作类型枚举
+func AuditActionValueOf(s string) *AuditAction {
+	switch s {
+	case string(AuditActionPostDelete):
+		return AuditActionPtr(AuditActionPostDelete)
+	case string(AuditActionFileDelete):
+		return AuditActionPtr(AuditActionFileDelete)
+	case string(AuditActionCommentDelete):
+		return AuditActionPtr(AuditActionCommentDelete)
+	case string(AuditActionConfigUpdate):
+		return AuditActionPtr(AuditActionConfigUpdate)
+	case string(AuditActionUserCreate):
+		return AuditActionPtr(AuditActionUserCreate)
+	case string(AuditActionUserRoleUpdate):
+		return AuditActionPtr(AuditActionUserRoleUpdate)
+	case string(AuditActionUserDelete):
+		return AuditActionPtr(AuditActionUserDelete)
+	case string(AuditActionLoginFailed):
+		return AuditActionPtr(AuditActionLoginFailed)
+	case string(AuditActionLoginLocked):
+		return AuditActionPtr(AuditActionLoginLocked)
+	default:
+		return nil
+	}
+}
+
+// UnmarshalJSON 自定义反序列化，验证枚举值
+func (a *AuditAction) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	// 验证是否为有效枚举值
+	if enum := AuditActionValueOf(s); enum == nil {
+		return fmt.Errorf("invalid AuditAction: %s", s)
+	}
+	*a = AuditAction(s)
+	return nil
+}
@@ -0,0 +1,21 @@
+package enum
+
+// AuditTargetType 审计日志操作对象类型
+type AuditTargetType string
+
+const (
+	// AuditTargetTypePost 文章
+	AuditTargetTypePost AuditTargetType = "POST"
+
+	// AuditTargetTypeFile 文件
+	AuditTargetTypeFile AuditTargetType = "FILE"
+
+	// AuditTargetTypeComment 评论
+	AuditTargetTypeComment AuditTargetType = "COMMENT"
+
+	// AuditTargetTypeConfig 配置
+	AuditTargetTypeConfig AuditTargetType = "CONFIG"
+
+	// AuditTargetTypeUser 用户
+	AuditTargetTypeUser AuditTargetType = "USER"
+)
@@ -0,0 +1,49 @@
+package enum
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CommenterStatus 评论人状态
+type CommenterStatus string
+
+const (
+	// CommenterStatusTrusted 信任，评论不需要审核
+	CommenterStatusTrusted CommenterStatus = "TRUSTED"
+
+	// CommenterStatusBlocked 禁止评论
+	CommenterStatusBlocked CommenterStatus = "BLOCKED"
+)
+
+// CommenterStatusPtr 获取评论人状态指针
+func CommenterStatusPtr(s CommenterStatus) *CommenterStatus {
+	return &s
+}
+
+// CommenterStatusValueOf 尝试将字符串转为评论人状态枚举
+func CommenterStatusValueOf(s string) *CommenterStatus {
+	switch s {
+	case "TRUSTED":
+		return CommenterStatusPtr(CommenterStatusTrusted)
+	case "BLOCKED":
+		return CommenterStatusPtr(CommenterStatusBlocked)
+	default:
+		return nil
+	}
+}
+
+// UnmarshalJSON 自定义反序列化，验证枚举值
+func (cs *CommenterStatus) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	// 验证是否为有效枚举值
+	if enum := CommenterStatusValueOf(s); enum == nil {
+		return fmt.Errorf("invalid CommenterStatus: %s", s)
+	}
+	*cs = CommenterStatus(s)
+	return nil
+}
@@ -17,6 +17,9 @@ const (
 
 	// PostStatusDeleted 已删除（回收站）
 	PostStatusDeleted PostStatus = "DELETED"
+
+	// PostStatusScheduled 定时发布，到达发布时间后自动改为已发布
+	PostStatusScheduled PostStatus = "SCHEDULED"
 )
 
 // PostStatusPtr 获取文章状态指针
@@ -33,6 +36,8 @@ func PostStatusValueOf(s string) *PostStatus {
 		return PostStatusPtr(PostStatusDraft)
 	case "DELETED":
 		return PostStatusPtr(PostStatusDeleted)
+	case "SCHEDULED":
+		return PostStatusPtr(PostStatusScheduled)
 	default:
 		return nil
 	}
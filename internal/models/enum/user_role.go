@@ -0,0 +1,132 @@
+package enum
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// UserRole 用户角色
+type UserRole string
+
+const (
+	// UserRoleOwner 所有者，拥有所有权限，可以管理用户和博客设置
+	UserRoleOwner UserRole = "OWNER"
+
+	// UserRoleEditor 编辑，可以发布和修改所有文章，管理标签、分类、评论等内容
+	UserRoleEditor UserRole = "EDITOR"
+
+	// UserRoleAuthor 作者，可以发布和修改自己的文章
+	UserRoleAuthor UserRole = "AUTHOR"
+
+	// UserRoleContributor 投稿者，只能提交和修改自己的草稿，不能发布文章
+	UserRoleContributor UserRole = "CONTRIBUTOR"
+)
+
+// Permission 权限
+type Permission string
+
+const (
+	// PermissionManageUsers 管理用户
+	PermissionManageUsers Permission = "MANAGE_USERS"
+
+	// PermissionManageSettings 管理博客设置、备份
+	PermissionManageSettings Permission = "MANAGE_SETTINGS"
+
+	// PermissionViewAuditLog 查看审计日志
+	PermissionViewAuditLog Permission = "VIEW_AUDIT_LOG"
+
+	// PermissionManageContent 管理标签、分类、菜单、友链和日记
+	PermissionManageContent Permission = "MANAGE_CONTENT"
+
+	// PermissionModerateComment 审核、回复和删除评论
+	PermissionModerateComment Permission = "MODERATE_COMMENT"
+
+	// PermissionEditOthersPosts 修改其他用户的文章
+	PermissionEditOthersPosts Permission = "EDIT_OTHERS_POSTS"
+
+	// PermissionPublishPost 发布文章
+	PermissionPublishPost Permission = "PUBLISH_POST"
+
+	// PermissionUploadFile 上传和管理文件
+	PermissionUploadFile Permission = "UPLOAD_FILE"
+
+	// PermissionWritePost 撰写文章（草稿）
+	PermissionWritePost Permission = "WRITE_POST"
+)
+
+// rolePermissions 各角色拥有的权限
+var rolePermissions = map[UserRole][]Permission{
+	UserRoleOwner: {
+		PermissionManageUsers,
+		PermissionManageSettings,
+		PermissionViewAuditLog,
+		PermissionManageContent,
+		PermissionModerateComment,
+		PermissionEditOthersPosts,
+		PermissionPublishPost,
+		PermissionUploadFile,
+		PermissionWritePost,
+	},
+	UserRoleEditor: {
+		PermissionManageContent,
+		PermissionModerateComment,
+		PermissionEditOthersPosts,
+		PermissionPublishPost,
+		PermissionUploadFile,
+		PermissionWritePost,
+	},
+	UserRoleAuthor: {
+		PermissionPublishPost,
+		PermissionUploadFile,
+		PermissionWritePost,
+	},
+	UserRoleContributor: {
+		PermissionWritePost,
+	},
+}
+
+// UserRolePtr 获取用户角色指针
+func UserRolePtr(r UserRole) *UserRole {
+	return &r
+}
+
+// UserRoleValueOf 尝试将字符串转为用户角色枚举
+func UserRoleValueOf(s string) *UserRole {
+	switch s {
+	case "OWNER":
+		return UserRolePtr(UserRoleOwner)
+	case "EDITOR":
+		return UserRolePtr(UserRoleEditor)
+	case "AUTHOR":
+		return UserRolePtr(UserRoleAuthor)
+	case "CONTRIBUTOR":
+		return UserRolePtr(UserRoleContributor)
+	default:
+		return nil
+	}
+}
+
+// Can 判断角色是否拥有指定权限，未知角色没有任何权限
+func (r UserRole) Can(p Permission) bool {
+	for _, permission := range rolePermissions[r] {
+		if permission == p {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON 自定义反序列化，验证枚举值
+func (r *UserRole) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	// 验证是否为有效枚举值
+	if enum := UserRoleValueOf(s); enum == nil {
+		return fmt.Errorf("invalid UserRole: %s", s)
+	}
+	*r = UserRole(s)
+	return nil
+}
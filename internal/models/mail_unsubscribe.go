@@ -0,0 +1,15 @@
+package models
+
+// MailUnsubscribe 退订邮件通知的邮箱
+type MailUnsubscribe struct {
+	// MailUnsubscribeId 退订 ID
+	MailUnsubscribeId uint `gorm:"column:mail_unsubscribe_id;primaryKey;autoIncrement" json:"mailUnsubscribeId"`
+	// Email 邮箱（小写）
+	Email string `gorm:"column:email;size:128;uniqueIndex;not null" json:"email"`
+	// CreateTime 退订时间
+	CreateTime int64 `gorm:"column:create_time;autoCreateTime:milli;not null" json:"createTime"`
+}
+
+func (MailUnsubscribe) TableName() string {
+	return "mail_unsubscribe"
+}
@@ -7,6 +7,9 @@ type Post struct {
 	// PostId 文章 ID
 	PostId uint `gorm:"column:post_id;primaryKey;autoIncrement" json:"postId"`
 
+	// AuthorId 作者用户 ID
+	AuthorId *uint `gorm:"column:author_id;index" json:"authorId"`
+
 	// Title 标题
 	Title string `gorm:"column:title;size:256;not null" json:"title"`
 
@@ -34,8 +37,29 @@ type Post struct {
 	// Visible 可见性
 	Visible enum.PostVisible `gorm:"column:visible;type:varchar(24);not null" json:"visible"`
 
-	// Password 密码
-	Password *string `gorm:"column:password;size:64" json:"password"`
+	// Password 密码哈希（带算法前缀），没有前缀的为旧版 SHA-256 哈希
+	Password *string `gorm:"column:password;size:255" json:"password"`
+
+	// PublishAt 定时发布时间戳毫秒，只对定时发布的文章有效
+	PublishAt *int64 `gorm:"column:publish_at;index" json:"publishAt"`
+
+	// DeletedAt 移入回收站的时间戳毫秒，只对回收站中的文章有效
+	DeletedAt *int64 `gorm:"column:deleted_at;index" json:"deletedAt"`
+
+	// SeoTitle SEO 标题，为空时使用文章标题
+	SeoTitle *string `gorm:"column:seo_title;size:256" json:"seoTitle"`
+
+	// SeoDescription SEO 描述，为空时使用摘要
+	SeoDescription *string `gorm:"column:seo_description;size:512" json:"seoDescription"`
+
+	// CanonicalURL 规范链接，为空时使用文章页面地址
+	CanonicalURL *string `gorm:"column:canonical_url;size:512" json:"canonicalUrl"`
+
+	// NoIndex 是否禁止搜索引擎索引
+	NoIndex bool `gorm:"column:no_index;default:false;not null" json:"noIndex"`
+
+	// OgImage 分享图片，为空时使用封面
+	OgImage *string `gorm:"column:og_image;size:512" json:"ogImage"`
 
 	// Visit 访问量
 	Visit uint `gorm:"column:visit;default:0;not null" json:"visit"`
@@ -9,9 +9,9 @@ type PostContent struct {
 	// PostId 文章 ID
 	PostId uint `gorm:"column:post_id;not null" json:"postId"`
 	// Content 内容
-	Content string `gorm:"column:content;type:text COLLATE utf8mb4_general_ci;not null" json:"content"`
+	Content string `gorm:"column:content;type:text;not null" json:"content"`
 	// HTML （由 content 解析得来）
-	HTML string `gorm:"column:html;type:text COLLATE utf8mb4_general_ci;not null" json:"html"`
+	HTML string `gorm:"column:html;type:text;not null" json:"html"`
 	// Status 状态
 	Status enum.PostContentStatus `gorm:"column:status;type:varchar(24);not null" json:"status"`
 	// DraftName 草稿名
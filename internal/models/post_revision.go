@@ -0,0 +1,34 @@
+package models
+
+import "nola-go/internal/models/enum"
+
+// PostRevision 文章修订版本，每次发布和保存草稿时添加，添加后不再修改
+type PostRevision struct {
+	// RevisionId 修订版本 ID
+	RevisionId uint `gorm:"column:revision_id;primaryKey;autoIncrement" json:"revisionId"`
+
+	// PostId 文章 ID
+	PostId uint `gorm:"column:post_id;index;not null" json:"postId"`
+
+	// AuthorId 保存该版本的用户 ID
+	AuthorId *uint `gorm:"column:author_id" json:"authorId"`
+
+	// Status 保存时的文章内容状态
+	Status enum.PostContentStatus `gorm:"column:status;type:varchar(24);not null" json:"status"`
+
+	// DraftName 保存时的草稿名，正文为空
+	DraftName *string `gorm:"column:draft_name;size:256" json:"draftName"`
+
+	// Content 内容
+	Content string `gorm:"column:content;type:text;not null" json:"content"`
+
+	// WordCount 字数
+	WordCount int `gorm:"column:word_count;not null" json:"wordCount"`
+
+	// CreateTime 保存时间戳毫秒
+	CreateTime int64 `gorm:"column:create_time;not null" json:"createTime"`
+}
+
+func (PostRevision) TableName() string {
+	return "post_revision"
+}
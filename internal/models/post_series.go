@@ -0,0 +1,17 @@
+package models
+
+// PostSeries 文章系列表，用于记录文章和系列的关联关系及文章在系列中的顺序，一篇文章最多属于一个系列
+type PostSeries struct {
+	// PostSeriesId 文章系列 ID
+	PostSeriesId uint `gorm:"column:post_series_id;primaryKey;autoIncrement" json:"postSeriesId"`
+	// SeriesId 系列 ID
+	SeriesId uint `gorm:"column:series_id;index;not null" json:"seriesId"`
+	// PostId 文章 ID
+	PostId uint `gorm:"column:post_id;uniqueIndex;not null" json:"postId"`
+	// Sort 文章在系列中的顺序（从 0 开始）
+	Sort int `gorm:"column:sort;not null" json:"sort"`
+}
+
+func (PostSeries) TableName() string {
+	return "post_series"
+}
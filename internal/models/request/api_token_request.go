@@ -0,0 +1,15 @@
+package request
+
+import "nola-go/internal/models/enum"
+
+// ApiTokenRequest 创建 API 令牌请求结构体
+type ApiTokenRequest struct {
+	// Name 令牌名称
+	Name string `json:"name" binding:"required,max=64"`
+
+	// Scopes 权限范围
+	Scopes []enum.ApiTokenScope `json:"scopes" binding:"required,min=1"`
+
+	// ExpireDays 有效天数，为空表示永不过期
+	ExpireDays *int `json:"expireDays" binding:"omitempty,min=1"`
+}
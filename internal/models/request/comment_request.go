@@ -20,4 +20,12 @@ type CommentRequest struct {
 	Email string `json:"email" binding:"required"`
 	// IsPass 是否通过审核
 	IsPass bool `json:"isPass"`
+	// Homepage 蜜罐字段，博客前端需要隐藏此字段，正常访客提交时为空
+	Homepage string `json:"homepage"`
+	// Token 评论表单令牌，博客前端打开评论框时获取
+	Token string `json:"token"`
+	// CaptchaId 验证码 ID，启用评论验证码时需要
+	CaptchaId string `json:"captchaId"`
+	// Captcha 验证码答案，图片验证码为图片中的数字，工作量证明为计算出的随机数
+	Captcha string `json:"captcha"`
 }
@@ -0,0 +1,9 @@
+package request
+
+// CommentSpamRequest 标记垃圾评论结构体
+type CommentSpamRequest struct {
+	// Ids 评论 ID 数组
+	Ids []uint `json:"ids" binding:"required"`
+	// IsSpam 是否为垃圾评论
+	IsSpam *bool `json:"isSpam" binding:"required"`
+}
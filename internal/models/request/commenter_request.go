@@ -0,0 +1,13 @@
+package request
+
+import "nola-go/internal/models/enum"
+
+// CommenterRequest 标记评论人请求结构体
+type CommenterRequest struct {
+	// Email 评论人邮箱
+	Email string `json:"email" binding:"required"`
+	// Status 状态（TRUSTED 信任、BLOCKED 禁止评论）
+	Status enum.CommenterStatus `json:"status" binding:"required"`
+	// Remark 备注
+	Remark *string `json:"remark"`
+}
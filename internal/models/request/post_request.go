@@ -10,6 +10,8 @@ import (
 type PostRequest struct {
 	// PostId 文章 ID
 	PostId *uint `json:"postId"`
+	// AuthorId 作者用户 ID（添加文章时由当前登录用户决定，不从请求中读取）
+	AuthorId *uint `json:"-"`
 	// Title 标题
 	Title string `json:"title" binding:"required"`
 	// AutoGenerateExcerpt 是否自动生成摘要
@@ -24,6 +26,8 @@ type PostRequest struct {
 	Status enum.PostStatus `json:"status" binding:"required"`
 	// Visible 文章可见性
 	Visible enum.PostVisible `json:"visible" binding:"required"`
+	// PublishAt 定时发布时间戳毫秒（文章状态为定时发布时必填）
+	PublishAt *int64 `json:"publishAt"`
 	// Content 文章内容（Markdown 或普通文本）
 	Content *string `json:"content"`
 	// CategoryId 分类 ID
@@ -34,6 +38,16 @@ type PostRequest struct {
 	Cover *string `json:"cover"`
 	// Pinned 是否置顶
 	Pinned bool `json:"pinned"`
+	// SeoTitle SEO 标题
+	SeoTitle *string `json:"seoTitle"`
+	// SeoDescription SEO 描述
+	SeoDescription *string `json:"seoDescription"`
+	// CanonicalURL 规范链接（http(s) 绝对地址）
+	CanonicalURL *string `json:"canonicalUrl"`
+	// NoIndex 是否禁止搜索引擎索引
+	NoIndex bool `json:"noIndex"`
+	// OgImage 分享图片
+	OgImage *string `json:"ogImage"`
 	// Encrypted 文章是否加密（为 true 时需提供 password，为 nil 保持不变，为 false 删除密码）
 	Encrypted *bool `json:"encrypted"`
 	// Password 文章密码
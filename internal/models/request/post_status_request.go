@@ -10,6 +10,8 @@ type PostStatusRequest struct {
 	PostId uint `json:"postId" binding:"required"`
 	// Status 状态
 	Status *enum.PostStatus `json:"status"`
+	// PublishAt 定时发布时间戳毫秒（状态为定时发布时必填）
+	PublishAt *int64 `json:"publishAt"`
 	// Visible 可见性
 	Visible *enum.PostVisible `json:"visible"`
 	// Pinned 置顶
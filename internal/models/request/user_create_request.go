@@ -0,0 +1,12 @@
+package request
+
+import "nola-go/internal/models/enum"
+
+// UserCreateRequest 添加用户请求结构体
+type UserCreateRequest struct {
+	Username    string        `json:"username" binding:"required"`
+	Email       string        `json:"email" binding:"required"`
+	DisplayName string        `json:"displayName" binding:"required"`
+	Password    string        `json:"password" binding:"required"`
+	Role        enum.UserRole `json:"role" binding:"required"`
+}
@@ -0,0 +1,50 @@
+package response
+
+import (
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+)
+
+// ApiTokenResponse API 令牌响应结构体
+type ApiTokenResponse struct {
+	// TokenId 令牌 ID
+	TokenId uint `json:"tokenId"`
+
+	// Name 令牌名称
+	Name string `json:"name"`
+
+	// TokenPrefix 令牌前几位，用于辨认令牌
+	TokenPrefix string `json:"tokenPrefix"`
+
+	// Scopes 权限范围
+	Scopes []enum.ApiTokenScope `json:"scopes"`
+
+	// ExpireTime 过期时间戳毫秒，为空表示永不过期
+	ExpireTime *int64 `json:"expireTime"`
+
+	// LastUsedTime 最后使用时间戳毫秒
+	LastUsedTime *int64 `json:"lastUsedTime"`
+
+	// LastUsedIp 最后使用的 IP 地址
+	LastUsedIp *string `json:"lastUsedIp"`
+
+	// CreateTime 创建时间戳毫秒
+	CreateTime int64 `json:"createTime"`
+
+	// Token 令牌明文，只在创建时返回
+	Token *string `json:"token,omitempty"`
+}
+
+// NewApiTokenResponse 由 API 令牌创建响应结构体
+func NewApiTokenResponse(token *models.ApiToken) *ApiTokenResponse {
+	return &ApiTokenResponse{
+		TokenId:      token.TokenId,
+		Name:         token.Name,
+		TokenPrefix:  token.TokenPrefix,
+		Scopes:       enum.ParseApiTokenScopes(token.Scopes),
+		ExpireTime:   token.ExpireTime,
+		LastUsedTime: token.LastUsedTime,
+		LastUsedIp:   token.LastUsedIp,
+		CreateTime:   token.CreateTime,
+	}
+}
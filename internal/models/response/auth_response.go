@@ -1,5 +1,7 @@
 package response
 
+import "nola-go/internal/models/enum"
+
 // AuthResponse 用户登录成功响应结构体
 type AuthResponse struct {
 	// Username 用户名
@@ -11,6 +13,9 @@ type AuthResponse struct {
 	// DisplayName 昵称
 	DisplayName string `json:"displayName"`
 
+	// Role 角色
+	Role enum.UserRole `json:"role"`
+
 	// Description 描述
 	Description *string `json:"description"`
 
@@ -23,6 +28,12 @@ type AuthResponse struct {
 	// Avatar 头像地址
 	Avatar *string `json:"avatar"`
 
-	// Token 令牌
+	// Token 访问令牌
 	Token string `json:"token"`
+
+	// TokenExpireTime 访问令牌过期时间戳毫秒
+	TokenExpireTime int64 `json:"tokenExpireTime"`
+
+	// RefreshToken 刷新令牌，只能使用一次
+	RefreshToken string `json:"refreshToken"`
 }
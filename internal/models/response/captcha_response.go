@@ -0,0 +1,20 @@
+package response
+
+// CaptchaResponse 评论验证码响应结构体
+type CaptchaResponse struct {
+	// CaptchaId 验证码 ID，提交评论时原样返回
+	CaptchaId string `json:"captchaId"`
+
+	// Type 验证码类型（image、pow）
+	Type string `json:"type"`
+
+	// Image 图片验证码（data:image/png;base64,...），访客输入图片中的数字作为答案
+	Image string `json:"image,omitempty"`
+
+	// Difficulty 工作量证明难度，需要找到随机数 nonce，
+	// 使 SHA-256(captchaId + ":" + nonce) 的前 difficulty 位都为 0，nonce 作为答案
+	Difficulty int `json:"difficulty,omitempty"`
+
+	// ExpireTime 过期时间戳毫秒
+	ExpireTime int64 `json:"expireTime"`
+}
@@ -0,0 +1,71 @@
+package response
+
+import (
+	"nola-go/internal/models"
+	"nola-go/internal/util"
+)
+
+// CommentApiResponse 博客前端评论响应体，不返回评论人邮箱和垃圾评论信息，使用头像地址代替邮箱
+type CommentApiResponse struct {
+	CommentId        uint    `json:"commentId"`
+	PostId           uint    `json:"postId"`
+	ParentCommentId  *uint   `json:"parentCommentId"`
+	ReplyCommentId   *uint   `json:"replyCommentId"`
+	ReplyDisplayName *string `json:"replyDisplayName"`
+	Content          string  `json:"content"`
+	Site             *string `json:"site"`
+	DisplayName      string  `json:"displayName"`
+	// Avatar 评论人头像地址
+	Avatar     string `json:"avatar"`
+	CreateTime int64  `json:"createTime"`
+	// IsPass 是否通过审核，博客前端提交评论后用于提示是否需要等待审核
+	IsPass   bool                  `json:"isPass"`
+	Children []*CommentApiResponse `json:"children"`
+}
+
+// NewCommentApiResponse 新建博客前端评论响应体，子评论同样转换
+//
+// Parameters:
+//   - comment: 评论
+//   - avatarURL: 头像地址，{hash} 会被替换为邮箱的 MD5，为空时使用 Gravatar
+func NewCommentApiResponse(comment *models.Comment, avatarURL string) *CommentApiResponse {
+	children := make([]*CommentApiResponse, 0, len(comment.Children))
+	for i := range comment.Children {
+		children = append(children, NewCommentApiResponse(&comment.Children[i], avatarURL))
+	}
+
+	return &CommentApiResponse{
+		CommentId:        comment.CommentId,
+		PostId:           comment.PostId,
+		ParentCommentId:  comment.ParentCommentId,
+		ReplyCommentId:   comment.ReplyCommentId,
+		ReplyDisplayName: comment.ReplyDisplayName,
+		Content:          comment.Content,
+		Site:             comment.Site,
+		DisplayName:      comment.DisplayName,
+		Avatar:           util.AvatarURL(avatarURL, comment.Email),
+		CreateTime:       comment.CreateTime,
+		IsPass:           comment.IsPass,
+		Children:         children,
+	}
+}
+
+// NewCommentApiResponsePager 新建博客前端评论分页响应体
+//
+// Parameters:
+//   - pager: 评论分页
+//   - avatarURL: 头像地址，{hash} 会被替换为邮箱的 MD5，为空时使用 Gravatar
+func NewCommentApiResponsePager(pager *models.Pager[models.Comment], avatarURL string) *models.Pager[CommentApiResponse] {
+	data := make([]*CommentApiResponse, 0, len(pager.Data))
+	for _, comment := range pager.Data {
+		data = append(data, NewCommentApiResponse(comment, avatarURL))
+	}
+
+	return &models.Pager[CommentApiResponse]{
+		Page:       pager.Page,
+		Size:       pager.Size,
+		Data:       data,
+		TotalData:  pager.TotalData,
+		TotalPages: pager.TotalPages,
+	}
+}
@@ -3,7 +3,9 @@ package response
 import (
 	"errors"
 	"fmt"
+	"math"
 	"nola-go/internal/models/enum"
+	"time"
 )
 
 var (
@@ -14,3 +16,18 @@ var (
 func FileStorageNotConfiguredError(mode enum.FileStorageMode) error {
 	return errors.New(fmt.Sprintf("文件存储策略 [%s] 还未配置", mode))
 }
+
+// TooManyRequestsError 请求过于频繁或失败次数过多异常
+type TooManyRequestsError struct {
+	// RetryAfter 需要等待的时长
+	RetryAfter time.Duration
+}
+
+// RetryAfterSeconds 需要等待的秒数（向上取整，至少 1 秒）
+func (e *TooManyRequestsError) RetryAfterSeconds() int {
+	return int(math.Max(1, math.Ceil(e.RetryAfter.Seconds())))
+}
+
+func (e *TooManyRequestsError) Error() string {
+	return fmt.Sprintf("尝试次数过多，请 %d 秒后再试", e.RetryAfterSeconds())
+}
@@ -6,19 +6,30 @@ import (
 
 // PostApiResponse 博客前端文章响应体
 type PostApiResponse struct {
-	PostId         uint             `json:"postId"`
-	Title          string           `json:"title"`
-	Excerpt        *string          `json:"excerpt"`
-	Slug           string           `json:"slug"`
-	Cover          *string          `json:"cover"`
-	AllowComment   bool             `json:"allowComment"`
-	Pinned         *bool            `json:"pinned"`
-	Encrypted      bool             `json:"encrypted"`
-	Visit          uint             `json:"visit"`
-	Category       *models.Category `json:"category"`
-	Tags           []*models.Tag    `json:"tags"`
-	CreateTime     int64            `json:"createTime"`
-	LastModifyTime *int64           `json:"lastModifyTime"`
+	PostId         uint                `json:"postId"`
+	Author         *PostAuthorResponse `json:"author"`
+	Title          string              `json:"title"`
+	Excerpt        *string             `json:"excerpt"`
+	Slug           string              `json:"slug"`
+	Cover          *string             `json:"cover"`
+	AllowComment   bool                `json:"allowComment"`
+	Pinned         *bool               `json:"pinned"`
+	Encrypted      bool                `json:"encrypted"`
+	Visit          uint                `json:"visit"`
+	Category       *models.Category    `json:"category"`
+	Tags           []*models.Tag       `json:"tags"`
+	CreateTime     int64               `json:"createTime"`
+	LastModifyTime *int64              `json:"lastModifyTime"`
+	// Highlight 搜索高亮，只有按关键词搜索时才有
+	Highlight *PostHighlightResponse `json:"highlight,omitempty"`
+}
+
+// PostHighlightResponse 文章搜索高亮响应体，命中的关键词使用 <mark> 标记，其余内容已进行 HTML 转义
+type PostHighlightResponse struct {
+	// Title 高亮后的标题
+	Title string `json:"title"`
+	// Snippet 正文中命中关键词附近的片段，加密文章为空
+	Snippet *string `json:"snippet"`
 }
 
 // NewPostApiResponse 新建博客前端文章响应体，通过 *response.PostResponse 文章响应体
@@ -40,6 +51,7 @@ func NewPostApiResponse(post *PostResponse, hide bool) *PostApiResponse {
 
 	return &PostApiResponse{
 		PostId:         post.PostId,
+		Author:         post.Author,
 		Title:          post.Title,
 		Excerpt:        excerpt,
 		Slug:           post.Slug,
@@ -0,0 +1,25 @@
+package response
+
+import "nola-go/internal/models"
+
+// PostAuthorResponse 文章作者响应体，只包含公开信息
+type PostAuthorResponse struct {
+	// UserId 用户 ID
+	UserId uint `json:"userId"`
+	// DisplayName 显示名称
+	DisplayName string `json:"displayName"`
+	// Description 描述
+	Description *string `json:"description"`
+	// Avatar 头像地址
+	Avatar *string `json:"avatar"`
+}
+
+// NewPostAuthorResponse 新建文章作者响应体，通过 *models.User 用户
+func NewPostAuthorResponse(user *models.User) *PostAuthorResponse {
+	return &PostAuthorResponse{
+		UserId:      user.UserId,
+		DisplayName: user.DisplayName,
+		Description: user.Description,
+		Avatar:      user.Avatar,
+	}
+}
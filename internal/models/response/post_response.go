@@ -8,23 +8,35 @@ import (
 
 // PostResponse 文章响应体
 type PostResponse struct {
-	PostId              uint             `json:"postId"`
-	Title               string           `json:"title"`
-	AutoGenerateExcerpt bool             `json:"autoGenerateExcerpt"`
-	Excerpt             string           `json:"excerpt"`
-	Slug                string           `json:"slug"`
-	Cover               *string          `json:"cover"`
-	AllowComment        bool             `json:"allowComment"`
-	Pinned              *bool            `json:"pinned"`
-	Status              enum.PostStatus  `json:"status"`
-	Visible             enum.PostVisible `json:"visible"`
-	Encrypted           bool             `json:"encrypted"`
-	Password            *string          `json:"password"`
-	Visit               uint             `json:"visit"`
-	Category            *models.Category `json:"category"`
-	Tags                []*models.Tag    `json:"tags"`
-	CreateTime          int64            `json:"createTime"`
-	LastModifyTime      *int64           `json:"lastModifyTime"`
+	PostId              uint                `json:"postId"`
+	AuthorId            *uint               `json:"authorId"`
+	Author              *PostAuthorResponse `json:"author"`
+	Title               string              `json:"title"`
+	AutoGenerateExcerpt bool                `json:"autoGenerateExcerpt"`
+	Excerpt             string              `json:"excerpt"`
+	Slug                string              `json:"slug"`
+	Cover               *string             `json:"cover"`
+	AllowComment        bool                `json:"allowComment"`
+	Pinned              *bool               `json:"pinned"`
+	Status              enum.PostStatus     `json:"status"`
+	Visible             enum.PostVisible    `json:"visible"`
+	PublishAt           *int64              `json:"publishAt"`
+	DeletedAt           *int64              `json:"deletedAt"`
+	DaysUntilPurge      *int                `json:"daysUntilPurge"`
+	SeoTitle            *string             `json:"seoTitle"`
+	SeoDescription      *string             `json:"seoDescription"`
+	CanonicalURL        *string             `json:"canonicalUrl"`
+	NoIndex             bool                `json:"noIndex"`
+	OgImage             *string             `json:"ogImage"`
+	Encrypted           bool                `json:"encrypted"`
+	Password            *string             `json:"password"`
+	Visit               uint                `json:"visit"`
+	Category            *models.Category    `json:"category"`
+	Tags                []*models.Tag       `json:"tags"`
+	CreateTime          int64               `json:"createTime"`
+	LastModifyTime      *int64              `json:"lastModifyTime"`
+	// Series 文章所属系列及系列导航，只在博客前端获取单篇文章时返回
+	Series *PostSeriesResponse `json:"series,omitempty"`
 }
 
 // NewPostResponse 新建文章响应体，通过 *models.Post 文章
@@ -32,6 +44,8 @@ func NewPostResponse(post *models.Post) *PostResponse {
 	isEncrypted := !util.StringIsNilOrBlank(post.Password)
 	return &PostResponse{
 		PostId:              post.PostId,
+		AuthorId:            post.AuthorId,
+		Author:              nil,
 		Title:               post.Title,
 		AutoGenerateExcerpt: post.AutoGenerateExcerpt,
 		Excerpt:             post.Excerpt,
@@ -41,6 +55,13 @@ func NewPostResponse(post *models.Post) *PostResponse {
 		Pinned:              &post.Pinned,
 		Status:              post.Status,
 		Visible:             post.Visible,
+		PublishAt:           post.PublishAt,
+		DeletedAt:           post.DeletedAt,
+		SeoTitle:            post.SeoTitle,
+		SeoDescription:      post.SeoDescription,
+		CanonicalURL:        post.CanonicalURL,
+		NoIndex:             post.NoIndex,
+		OgImage:             post.OgImage,
 		Encrypted:           isEncrypted,
 		Password:            nil,
 		Visit:               post.Visit,
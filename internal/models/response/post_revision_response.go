@@ -0,0 +1,52 @@
+package response
+
+import (
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/util"
+)
+
+// PostRevisionResponse 文章修订版本响应体，不包含内容
+type PostRevisionResponse struct {
+	// RevisionId 修订版本 ID
+	RevisionId uint `json:"revisionId"`
+	// PostId 文章 ID
+	PostId uint `json:"postId"`
+	// AuthorId 保存该版本的用户 ID
+	AuthorId *uint `json:"authorId"`
+	// Author 保存该版本的用户，用户已删除时为空
+	Author *PostAuthorResponse `json:"author"`
+	// Status 保存时的文章内容状态
+	Status enum.PostContentStatus `json:"status"`
+	// DraftName 保存时的草稿名
+	DraftName *string `json:"draftName"`
+	// WordCount 字数
+	WordCount int `json:"wordCount"`
+	// CreateTime 保存时间戳毫秒
+	CreateTime int64 `json:"createTime"`
+}
+
+// NewPostRevisionResponse 创建文章修订版本响应体，通过 *models.PostRevision
+func NewPostRevisionResponse(revision *models.PostRevision) *PostRevisionResponse {
+	return &PostRevisionResponse{
+		RevisionId: revision.RevisionId,
+		PostId:     revision.PostId,
+		AuthorId:   revision.AuthorId,
+		Status:     revision.Status,
+		DraftName:  revision.DraftName,
+		WordCount:  revision.WordCount,
+		CreateTime: revision.CreateTime,
+	}
+}
+
+// PostRevisionDiffResponse 两个文章修订版本的差异响应体
+type PostRevisionDiffResponse struct {
+	// From 旧版本
+	From *PostRevisionResponse `json:"from"`
+	// To 新版本
+	To *PostRevisionResponse `json:"to"`
+	// Lines 逐行差异
+	Lines []*util.DiffLine `json:"lines"`
+	// Unified 统一差异格式（unified diff）文本
+	Unified string `json:"unified"`
+}
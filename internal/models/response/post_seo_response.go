@@ -0,0 +1,59 @@
+package response
+
+// PostSeoResponse 文章 SEO 元数据响应体，前端服务端渲染时直接输出
+type PostSeoResponse struct {
+	// Title 页面标题
+	Title string `json:"title"`
+	// Description 页面描述
+	Description string `json:"description"`
+	// Canonical 规范链接
+	Canonical string `json:"canonical"`
+	// Robots robots 元标签内容
+	Robots string `json:"robots"`
+	// NoIndex 是否禁止搜索引擎索引
+	NoIndex bool `json:"noIndex"`
+	// Image 分享图片绝对地址，没有时为 nil
+	Image *string `json:"image"`
+	// OpenGraph Open Graph 元标签，输出为 <meta property="..." content="...">
+	OpenGraph []*MetaTagResponse `json:"openGraph"`
+	// Twitter Twitter Card 元标签，输出为 <meta name="..." content="...">
+	Twitter []*MetaTagResponse `json:"twitter"`
+	// JsonLd schema.org BlogPosting 结构化数据，输出到 <script type="application/ld+json">
+	JsonLd *BlogPostingJsonLd `json:"jsonLd"`
+}
+
+// MetaTagResponse HTML 元标签
+type MetaTagResponse struct {
+	// Property 属性名（Open Graph 使用）
+	Property string `json:"property,omitempty"`
+	// Name 名称（Twitter Card 使用）
+	Name string `json:"name,omitempty"`
+	// Content 内容
+	Content string `json:"content"`
+}
+
+// BlogPostingJsonLd schema.org BlogPosting 结构化数据
+type BlogPostingJsonLd struct {
+	Context          string       `json:"@context"`
+	Type             string       `json:"@type"`
+	Headline         string       `json:"headline"`
+	Description      string       `json:"description,omitempty"`
+	Image            []string     `json:"image,omitempty"`
+	URL              string       `json:"url"`
+	MainEntityOfPage *JsonLdThing `json:"mainEntityOfPage"`
+	DatePublished    string       `json:"datePublished"`
+	DateModified     string       `json:"dateModified"`
+	Author           *JsonLdThing `json:"author,omitempty"`
+	Publisher        *JsonLdThing `json:"publisher,omitempty"`
+	ArticleSection   string       `json:"articleSection,omitempty"`
+	Keywords         string       `json:"keywords,omitempty"`
+}
+
+// JsonLdThing schema.org 中的嵌套对象（Person、Organization、WebPage、ImageObject）
+type JsonLdThing struct {
+	Type string       `json:"@type"`
+	Id   string       `json:"@id,omitempty"`
+	Name string       `json:"name,omitempty"`
+	URL  string       `json:"url,omitempty"`
+	Logo *JsonLdThing `json:"logo,omitempty"`
+}
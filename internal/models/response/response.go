@@ -3,6 +3,8 @@ package response
 import (
 	"net/http"
 	"nola-go/internal/util"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -74,3 +76,52 @@ func Unauthorized() Response {
 func UnauthorizedAndResponse(ctx *gin.Context) {
 	ctx.JSON(http.StatusUnauthorized, Unauthorized())
 }
+
+// Forbidden 没有权限响应体
+func Forbidden() Response {
+	return Response{
+		Code:   http.StatusForbidden,
+		Data:   nil,
+		ErrMsg: util.StringPtr("没有权限执行此操作"),
+	}
+}
+
+// ForbiddenAndResponse 没有权限并直接返回失败响应体
+func ForbiddenAndResponse(ctx *gin.Context) {
+	ctx.JSON(http.StatusForbidden, Forbidden())
+}
+
+// TooManyRequests 请求过于频繁响应体
+func TooManyRequests(errMsg string) Response {
+	return Response{
+		Code:   http.StatusTooManyRequests,
+		Data:   nil,
+		ErrMsg: &errMsg,
+	}
+}
+
+// TooManyRequestsAndResponse 请求过于频繁并直接返回失败响应体，同时设置 Retry-After 响应头
+func TooManyRequestsAndResponse(ctx *gin.Context, err *TooManyRequestsError) {
+	ctx.Header("Retry-After", strconv.Itoa(err.RetryAfterSeconds()))
+	ctx.JSON(http.StatusTooManyRequests, TooManyRequests(err.Error()))
+}
+
+// CacheableAndResponse 返回可以被客户端缓存的内容，设置 ETag 和 Last-Modified 响应头，
+// 客户端缓存的内容未修改时返回 304
+//   - contentType: 响应类型
+//   - body: 响应内容
+//   - lastModified: 内容最后修改时间，零值表示未知
+func CacheableAndResponse(ctx *gin.Context, contentType string, body []byte, lastModified time.Time) {
+	etag := `"` + util.GenerateHash(string(body))[:32] + `"`
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "public, max-age=300")
+	if !lastModified.IsZero() {
+		ctx.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if util.IsNotModified(ctx.Request.Header, etag, lastModified) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Data(http.StatusOK, contentType, body)
+}
@@ -0,0 +1,44 @@
+package response
+
+import "nola-go/internal/models"
+
+// SeriesApiResponse 博客前端系列响应体，包含系列中已发布、可见的文章
+type SeriesApiResponse struct {
+	// Series 系列
+	Series *models.Series `json:"series"`
+	// Posts 文章，按文章在系列中的顺序
+	Posts []*PostApiResponse `json:"posts"`
+}
+
+// PostSeriesResponse 文章所属系列响应体，用于在文章页面显示系列导航
+type PostSeriesResponse struct {
+	// Series 系列
+	Series *models.Series `json:"series"`
+	// Index 文章在系列中的序号（从 1 开始）
+	Index int `json:"index"`
+	// Total 系列中的文章数量
+	Total int `json:"total"`
+	// Prev 系列中的上一篇文章，没有时为 nil
+	Prev *PostLinkResponse `json:"prev"`
+	// Next 系列中的下一篇文章，没有时为 nil
+	Next *PostLinkResponse `json:"next"`
+}
+
+// PostLinkResponse 文章链接响应体
+type PostLinkResponse struct {
+	// PostId 文章 ID
+	PostId uint `json:"postId"`
+	// Title 标题
+	Title string `json:"title"`
+	// Slug 别名
+	Slug string `json:"slug"`
+}
+
+// NewPostLinkResponse 新建文章链接响应体，通过 *response.PostResponse 文章响应体
+func NewPostLinkResponse(post *PostResponse) *PostLinkResponse {
+	return &PostLinkResponse{
+		PostId: post.PostId,
+		Title:  post.Title,
+		Slug:   post.Slug,
+	}
+}
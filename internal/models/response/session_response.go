@@ -0,0 +1,25 @@
+package response
+
+// SessionResponse 登录会话响应结构体
+type SessionResponse struct {
+	// SessionId 会话 ID
+	SessionId string `json:"sessionId"`
+
+	// UserAgent 登录设备的浏览器标识
+	UserAgent string `json:"userAgent"`
+
+	// IP 登录设备的 IP 地址
+	IP string `json:"ip"`
+
+	// CreateTime 登录时间戳毫秒
+	CreateTime int64 `json:"createTime"`
+
+	// LastActiveTime 最后活跃时间戳毫秒
+	LastActiveTime int64 `json:"lastActiveTime"`
+
+	// ExpireTime 过期时间戳毫秒
+	ExpireTime int64 `json:"expireTime"`
+
+	// Current 是否为当前请求所用的会话
+	Current bool `json:"current"`
+}
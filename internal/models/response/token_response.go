@@ -0,0 +1,13 @@
+package response
+
+// TokenResponse 令牌响应结构体
+type TokenResponse struct {
+	// Token 访问令牌
+	Token string `json:"token"`
+
+	// TokenExpireTime 访问令牌过期时间戳毫秒
+	TokenExpireTime int64 `json:"tokenExpireTime"`
+
+	// RefreshToken 刷新令牌，只能使用一次
+	RefreshToken string `json:"refreshToken"`
+}
@@ -0,0 +1,37 @@
+package response
+
+// TwoFactorChallengeResponse 开启两步验证的用户密码验证通过后的响应结构体
+type TwoFactorChallengeResponse struct {
+	// TwoFactorRequired 是否需要两步验证，始终为 true
+	TwoFactorRequired bool `json:"twoFactorRequired"`
+
+	// Challenge 挑战令牌，提交验证码时需要一并提交
+	Challenge string `json:"challenge"`
+
+	// ExpireTime 挑战令牌过期时间戳毫秒
+	ExpireTime int64 `json:"expireTime"`
+}
+
+// TotpSetupResponse 设置两步验证响应结构体
+type TotpSetupResponse struct {
+	// Secret TOTP 密钥（Base32），用于手动输入到身份验证器
+	Secret string `json:"secret"`
+
+	// Uri otpauth 链接，用于生成二维码
+	Uri string `json:"uri"`
+}
+
+// TotpStatusResponse 两步验证状态响应结构体
+type TotpStatusResponse struct {
+	// Enabled 是否已开启两步验证
+	Enabled bool `json:"enabled"`
+
+	// RecoveryCodeCount 剩余可用的恢复码数量
+	RecoveryCodeCount int64 `json:"recoveryCodeCount"`
+}
+
+// RecoveryCodesResponse 恢复码响应结构体
+type RecoveryCodesResponse struct {
+	// RecoveryCodes 恢复码，只在生成时返回一次
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// SchemaMigration 数据库迁移记录
+type SchemaMigration struct {
+	// Version 迁移版本号
+	Version uint `gorm:"column:version;primaryKey;autoIncrement:false" json:"version"`
+	// Name 迁移名称
+	Name string `gorm:"column:name;size:128;not null" json:"name"`
+	// Checksum 迁移校验和
+	Checksum string `gorm:"column:checksum;size:64;not null" json:"checksum"`
+	// AppliedAt 迁移执行时间
+	AppliedAt int64 `gorm:"column:applied_at;not null" json:"appliedAt"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
@@ -0,0 +1,37 @@
+package models
+
+// SearchDocument 全文搜索索引中的文档
+type SearchDocument struct {
+	// DocId 文档 ID（文章 ID）
+	DocId uint `gorm:"column:doc_id;primaryKey;autoIncrement:false" json:"docId"`
+
+	// Length 文档加权后的词项总数，用于相关度计算
+	Length int `gorm:"column:length;not null" json:"length"`
+
+	// UpdateTime 索引时间戳毫秒
+	UpdateTime int64 `gorm:"column:update_time;not null" json:"updateTime"`
+}
+
+func (SearchDocument) TableName() string {
+	return "search_document"
+}
+
+// SearchTerm 全文搜索倒排索引，每个词项在每个文档中一行
+// 部分数据库默认的排序规则不区分大小写和重音，词项不作为唯一键，查询结果按词项精确匹配
+type SearchTerm struct {
+	// SearchTermId 倒排索引 ID
+	SearchTermId uint `gorm:"column:search_term_id;primaryKey;autoIncrement" json:"searchTermId"`
+
+	// Term 词项
+	Term string `gorm:"column:term;size:64;index;not null" json:"term"`
+
+	// DocId 文档 ID（文章 ID）
+	DocId uint `gorm:"column:doc_id;index;not null" json:"docId"`
+
+	// Frequency 词项在文档中加权后的出现次数
+	Frequency int `gorm:"column:frequency;not null" json:"frequency"`
+}
+
+func (SearchTerm) TableName() string {
+	return "search_term"
+}
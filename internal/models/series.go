@@ -0,0 +1,23 @@
+package models
+
+// Series 系列（多篇文章组成的连载或专题，文章在系列中有固定顺序）
+type Series struct {
+	// SeriesId 系列 ID
+	SeriesId uint `gorm:"column:series_id;primaryKey;autoIncrement" json:"seriesId" binding:"required"`
+	// DisplayName 系列名
+	DisplayName string `gorm:"column:display_name;size:256;not null" json:"displayName" binding:"required"`
+	// Slug 系列别名
+	Slug string `gorm:"column:slug;size:128;uniqueIndex;not null" json:"slug" binding:"required"`
+	// Description 描述
+	Description *string `gorm:"column:description;size:1024" json:"description"`
+	// Cover 封面
+	Cover *string `gorm:"column:cover;size:512" json:"cover"`
+	// CreateTime 创建时间
+	CreateTime int64 `gorm:"column:create_time;autoCreateTime:milli;not null" json:"createTime"`
+	// PostCount 文章数量（无数据库字段，只读字段）
+	PostCount int64 `gorm:"->;column:post_count" json:"postCount"`
+}
+
+func (Series) TableName() string {
+	return "series"
+}
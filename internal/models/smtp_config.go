@@ -0,0 +1,23 @@
+package models
+
+// SMTPConfig 邮件通知设置
+type SMTPConfig struct {
+	// Enabled 是否启用邮件通知
+	Enabled bool `json:"enabled"`
+	// Host SMTP 服务器地址
+	Host string `json:"host"`
+	// Port SMTP 服务器端口
+	Port int `json:"port"`
+	// Username 用户名，为空时不进行身份验证
+	Username string `json:"username"`
+	// Password 密码，获取设置时不返回，修改设置时为空表示不修改
+	Password string `json:"password,omitempty"`
+	// Encryption 加密方式（none、starttls、tls）
+	Encryption string `json:"encryption"`
+	// From 发件人地址
+	From string `json:"from"`
+	// FromName 发件人名称，为空时使用博客标题
+	FromName string `json:"fromName"`
+	// AdminEmail 接收新评论通知的邮箱，为空时不发送新评论通知
+	AdminEmail string `json:"adminEmail"`
+}
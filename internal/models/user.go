@@ -1,5 +1,7 @@
 package models
 
+import "nola-go/internal/models/enum"
+
 // User 用户
 type User struct {
 	// UserId 用户ID
@@ -14,10 +16,13 @@ type User struct {
 	// DisplayName 显示名称
 	DisplayName string `gorm:"column:display_name;size:128;not null" json:"displayName"`
 
-	// Password 密码
+	// Role 角色
+	Role enum.UserRole `gorm:"column:role;type:varchar(24);default:'CONTRIBUTOR';not null" json:"role"`
+
+	// Password 密码哈希（带算法前缀，例如 $argon2id$），没有前缀的为旧版 SHA-256 哈希
 	Password string `gorm:"column:password;size:128;not null" json:"-"`
 
-	// Salt 盐值
+	// Salt 旧版 SHA-256 哈希的盐值，新版哈希的盐值编码在 Password 中，为空
 	Salt string `gorm:"column:salt;size:128;not null" json:"-"`
 
 	// Description 描述
@@ -31,6 +36,15 @@ type User struct {
 
 	// Avatar 头像地址
 	Avatar *string `gorm:"column:avatar;size:512" json:"avatar"`
+
+	// TotpSecret 两步验证 TOTP 密钥（开启或正在开启两步验证时不为空）
+	TotpSecret *string `gorm:"column:totp_secret;size:64" json:"-"`
+
+	// TotpEnabled 是否已开启两步验证
+	TotpEnabled bool `gorm:"column:totp_enabled;default:false;not null" json:"totpEnabled"`
+
+	// TotpLastStep 最后一次使用的 TOTP 时间步，防止验证码被重复使用
+	TotpLastStep *int64 `gorm:"column:totp_last_step" json:"-"`
 }
 
 func (User) TableName() string {
@@ -0,0 +1,23 @@
+package models
+
+// UserRecoveryCode 两步验证恢复码
+type UserRecoveryCode struct {
+	// RecoveryCodeId 恢复码 ID
+	RecoveryCodeId uint `gorm:"column:recovery_code_id;primaryKey;autoIncrement" json:"recoveryCodeId"`
+
+	// UserId 用户 ID
+	UserId uint `gorm:"column:user_id;index;not null" json:"userId"`
+
+	// CodeHash 恢复码哈希
+	CodeHash string `gorm:"column:code_hash;size:128;not null" json:"-"`
+
+	// UsedTime 使用时间戳毫秒，为空表示未使用
+	UsedTime *int64 `gorm:"column:used_time" json:"usedTime"`
+
+	// CreateTime 创建时间戳毫秒
+	CreateTime int64 `gorm:"column:create_time;not null" json:"createTime"`
+}
+
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_code"
+}
@@ -0,0 +1,108 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"nola-go/internal/config"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// argon2Prefix Argon2id 哈希前缀
+	argon2Prefix = "$argon2id$"
+	// argon2SaltLength 盐值字节长度
+	argon2SaltLength = 16
+	// argon2KeyLength 哈希字节长度
+	argon2KeyLength = 32
+)
+
+// argon2Encoding Argon2id 哈希中盐值和哈希的编码（PHC 格式，Base64 无填充）
+var argon2Encoding = base64.RawStdEncoding
+
+// argon2Params Argon2id 参数
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// argon2id Argon2id 算法，哈希格式为 $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+type argon2id struct {
+	params argon2Params
+}
+
+// newArgon2id 创建 Argon2id 算法，未配置的参数使用 OWASP 推荐的默认值
+func newArgon2id(cfg config.Argon2Config) *argon2id {
+	params := argon2Params{
+		memory:      cfg.Memory,
+		iterations:  cfg.Iterations,
+		parallelism: cfg.Parallelism,
+	}
+	if params.memory == 0 {
+		params.memory = 64 * 1024
+	}
+	if params.iterations == 0 {
+		params.iterations = 3
+	}
+	if params.parallelism == 0 {
+		params.parallelism = 2
+	}
+	return &argon2id{params: params}
+}
+
+func (a *argon2id) match(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2Prefix)
+}
+
+func (a *argon2id) hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	p := a.params
+	key := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, argon2KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2Prefix, argon2.Version, p.memory, p.iterations, p.parallelism,
+		argon2Encoding.EncodeToString(salt), argon2Encoding.EncodeToString(key)), nil
+}
+
+func (a *argon2id) verify(password, encoded string) (bool, bool) {
+	// "", "argon2id", "v=19", "m=65536,t=3,p=2", "<salt>", "<hash>"
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false
+	}
+
+	var p argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return false, false
+	}
+	if p.memory == 0 || p.iterations == 0 || p.parallelism == 0 {
+		return false, false
+	}
+
+	salt, err := argon2Encoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false
+	}
+	key, err := argon2Encoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false, false
+	}
+
+	actual := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return false, false
+	}
+	return true, p != a.params || len(key) != argon2KeyLength
+}
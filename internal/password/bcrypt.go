@@ -0,0 +1,50 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// defaultBcryptCost 未配置时 bcrypt 的默认计算成本
+const defaultBcryptCost = 12
+
+// bcryptAlgorithm bcrypt 算法，哈希格式为 $2a$12$<salt+hash>
+// bcrypt 最多只使用密码的前 72 个字节，超出部分会导致生成哈希失败
+type bcryptAlgorithm struct {
+	cost int
+}
+
+// newBcrypt 创建 bcrypt 算法
+func newBcrypt(cost int) (*bcryptAlgorithm, error) {
+	if cost == 0 {
+		cost = defaultBcryptCost
+	}
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt 计算成本必须在 %d - %d 之间", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return &bcryptAlgorithm{cost: cost}, nil
+}
+
+func (b *bcryptAlgorithm) match(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+func (b *bcryptAlgorithm) hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (b *bcryptAlgorithm) verify(password, encoded string) (bool, bool) {
+	if bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return true, err != nil || cost != b.cost
+}
@@ -0,0 +1,18 @@
+package password
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// verifyLegacy 验证旧版 SHA-256 哈希（十六进制，计算方式为 sha256(salt + password)）
+// 用户密码使用单独保存的盐值，文章密码没有盐值
+//   - password: 密码明文
+//   - encoded: 保存的哈希
+//   - salt: 盐值，没有盐值时为空字符串
+func verifyLegacy(password, encoded, salt string) bool {
+	sum := sha256.Sum256([]byte(salt + password))
+	actual := hex.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(encoded)) == 1
+}
@@ -0,0 +1,93 @@
+package password
+
+import (
+	"fmt"
+	"nola-go/internal/config"
+)
+
+const (
+	// AlgorithmArgon2id Argon2id 算法（默认）
+	AlgorithmArgon2id = "argon2id"
+	// AlgorithmBcrypt bcrypt 算法
+	AlgorithmBcrypt = "bcrypt"
+)
+
+// Hasher 密码哈希器
+// 生成的哈希带有算法前缀（例如 $argon2id$、$2a$），参数和盐值都编码在哈希中；
+// 验证时根据前缀选择算法，因此切换算法后旧的哈希仍然可以验证。
+type Hasher interface {
+	// Hash 生成密码哈希
+	//   - password: 密码明文
+	Hash(password string) (string, error)
+
+	// Verify 验证密码是否正确
+	// needsRehash 表示密码正确，但哈希不是由当前算法和参数生成的（包括旧版 SHA-256 哈希），
+	// 调用方应使用 Hash 重新生成并保存
+	//   - password: 密码明文
+	//   - encoded: 保存的哈希
+	//   - salt: 旧版 SHA-256 哈希的盐值，新版哈希忽略该参数
+	Verify(password, encoded, salt string) (ok bool, needsRehash bool)
+}
+
+// algorithm 单个哈希算法
+type algorithm interface {
+	// match 判断哈希是否由该算法生成
+	match(encoded string) bool
+	// hash 生成哈希
+	hash(password string) (string, error)
+	// verify 验证密码，needsRehash 表示哈希参数与当前参数不一致
+	verify(password, encoded string) (ok bool, needsRehash bool)
+}
+
+// hasher 默认哈希器实现，使用 current 生成哈希，使用所有已知算法验证哈希
+type hasher struct {
+	current    algorithm
+	algorithms []algorithm
+}
+
+// New 根据配置创建密码哈希器
+//   - cfg: 密码哈希配置
+func New(cfg config.PasswordConfig) (Hasher, error) {
+	a2 := newArgon2id(cfg.Argon2)
+	bc, err := newBcrypt(cfg.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &hasher{algorithms: []algorithm{a2, bc}}
+	switch cfg.Algorithm {
+	case "", AlgorithmArgon2id:
+		h.current = a2
+	case AlgorithmBcrypt:
+		h.current = bc
+	default:
+		return nil, fmt.Errorf("不支持的密码哈希算法: %s", cfg.Algorithm)
+	}
+	return h, nil
+}
+
+// Hash 生成密码哈希
+func (h *hasher) Hash(password string) (string, error) {
+	return h.current.hash(password)
+}
+
+// Verify 验证密码是否正确
+func (h *hasher) Verify(password, encoded, salt string) (bool, bool) {
+	if encoded == "" {
+		return false, false
+	}
+
+	for _, a := range h.algorithms {
+		if !a.match(encoded) {
+			continue
+		}
+		ok, needsRehash := a.verify(password, encoded)
+		if !ok {
+			return false, false
+		}
+		return true, needsRehash || a != h.current
+	}
+
+	// 没有算法前缀，视为旧版 SHA-256 哈希，验证成功后总是需要重新生成
+	return verifyLegacy(password, encoded, salt), true
+}
@@ -0,0 +1,110 @@
+package password
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"nola-go/internal/config"
+	"strings"
+	"testing"
+)
+
+// testArgon2 测试使用的低开销 Argon2id 参数
+var testArgon2 = config.Argon2Config{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+// newTestHasher 创建测试使用的哈希器
+func newTestHasher(t *testing.T, cfg config.PasswordConfig) Hasher {
+	t.Helper()
+	h, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return h
+}
+
+// legacyHash 生成旧版 SHA-256 哈希
+func legacyHash(password, salt string) string {
+	sum := sha256.Sum256([]byte(salt + password))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.PasswordConfig
+		prefix  string
+		wantErr bool
+	}{
+		{"默认 Argon2id", config.PasswordConfig{Argon2: testArgon2}, "$argon2id$v=19$m=1024,t=1,p=1$", false},
+		{"bcrypt", config.PasswordConfig{Algorithm: AlgorithmBcrypt, BcryptCost: 4}, "$2a$04$", false},
+		{"不支持的算法", config.PasswordConfig{Algorithm: "md5"}, "", true},
+		{"bcrypt 计算成本过大", config.PasswordConfig{Algorithm: AlgorithmBcrypt, BcryptCost: 32}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := New(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			encoded, err := h.Hash("password")
+			if err != nil || !strings.HasPrefix(encoded, tt.prefix) {
+				t.Fatalf("Hash = %q, %v, want prefix %q", encoded, err, tt.prefix)
+			}
+			if ok, rehash := h.Verify("password", encoded, ""); !ok || rehash {
+				t.Errorf("Verify(correct) = %v, %v, want true, false", ok, rehash)
+			}
+			if ok, _ := h.Verify("wrong", encoded, ""); ok {
+				t.Error("Verify(wrong) should fail")
+			}
+
+			// 相同密码每次生成的哈希都不同
+			if again, _ := h.Hash("password"); again == encoded {
+				t.Error("Hash should use random salt")
+			}
+		})
+	}
+}
+
+func TestHasher_Verify(t *testing.T) {
+	argon := newTestHasher(t, config.PasswordConfig{Argon2: testArgon2})
+	stronger := newTestHasher(t, config.PasswordConfig{Argon2: config.Argon2Config{Memory: 2048, Iterations: 1, Parallelism: 1}})
+	bc := newTestHasher(t, config.PasswordConfig{Algorithm: AlgorithmBcrypt, BcryptCost: 4})
+	bcStronger := newTestHasher(t, config.PasswordConfig{Algorithm: AlgorithmBcrypt, BcryptCost: 5})
+
+	argonHash, _ := argon.Hash("password")
+	bcHash, _ := bc.Hash("password")
+
+	tests := []struct {
+		name       string
+		hasher     Hasher
+		password   string
+		encoded    string
+		salt       string
+		wantOk     bool
+		wantRehash bool
+	}{
+		{"旧版加盐哈希", argon, "password", legacyHash("password", "salt"), "salt", true, true},
+		{"旧版加盐哈希盐值错误", argon, "password", legacyHash("password", "salt"), "other", false, true},
+		{"旧版无盐哈希", argon, "password", legacyHash("password", ""), "", true, true},
+		{"旧版哈希密码错误", argon, "wrong", legacyHash("password", ""), "", false, true},
+		{"Argon2id 参数变化", stronger, "password", argonHash, "", true, true},
+		{"切换到 bcrypt 后验证 Argon2id", bc, "password", argonHash, "", true, true},
+		{"切换到 Argon2id 后验证 bcrypt", argon, "password", bcHash, "", true, true},
+		{"bcrypt 计算成本变化", bcStronger, "password", bcHash, "", true, true},
+		{"空哈希", argon, "", "", "", false, false},
+		{"格式错误的 Argon2id 哈希", argon, "password", "$argon2id$v=19$m=1024", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, rehash := tt.hasher.Verify(tt.password, tt.encoded, tt.salt)
+			if ok != tt.wantOk || (ok && rehash != tt.wantRehash) {
+				t.Errorf("Verify = %v, %v, want %v, %v", ok, rehash, tt.wantOk, tt.wantRehash)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"nola-go/internal/config"
+	"nola-go/internal/kv"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// StoreRedis 使用 Redis 保存计数，Redis 出错时改用内存
+	StoreRedis = "redis"
+	// StoreMemory 使用进程内存保存计数，多实例部署时各实例分别计数
+	StoreMemory = "memory"
+)
+
+// Policy 限流策略，同一客户端在 Window 内最多允许 Limit 次请求
+type Policy struct {
+	// Name 策略名称，不同策略分别计数
+	Name string
+	// Limit 时间窗口内允许的请求数量
+	Limit int64
+	// Window 时间窗口
+	Window time.Duration
+}
+
+// Result 限流结果
+type Result struct {
+	// Allowed 是否允许本次请求
+	Allowed bool
+	// Limit 时间窗口内允许的请求数量
+	Limit int64
+	// Remaining 时间窗口内剩余的请求数量
+	Remaining int64
+	// RetryAfter 不允许时需要等待的时长
+	RetryAfter time.Duration
+}
+
+// keyPrefix 计数器的键前缀
+//   - nola:ratelimit:<策略>:<客户端>:<窗口序号> 窗口内的请求数量，下一个窗口结束后过期
+const keyPrefix = "nola:ratelimit:"
+
+// Limiter 滑动窗口限流器
+//
+// 按固定窗口计数，并用上一个窗口的计数乘以它与滑动窗口重叠的比例估算滑动窗口内的请求数量，
+// 每个客户端每个策略只需要两个计数器；被拒绝的请求同样计数，持续请求的客户端会一直被限制
+type Limiter struct {
+	store kv.Store
+	// policies 名称对应的限流策略
+	policies map[string]*Policy
+	// routes 路由（"方法 路径"）对应的限流策略
+	routes map[string]*Policy
+	// now 获取当前时间，测试时可以替换
+	now func() time.Time
+}
+
+// New 根据配置创建限流器，未启用时不限制任何路由
+//   - cfg: 限流配置
+//   - store: 计数存储
+func New(cfg config.RateLimitConfig, store kv.Store) (*Limiter, error) {
+	l := NewLimiter(store)
+	if !cfg.Enabled {
+		return l, nil
+	}
+
+	names := make(map[string]bool)
+	for _, p := range cfg.Policies {
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			return nil, errors.New("限流策略名称不能为空")
+		}
+		if names[name] {
+			return nil, fmt.Errorf("限流策略 [%s] 重复", name)
+		}
+		names[name] = true
+		if p.Limit <= 0 || p.WindowSeconds <= 0 {
+			return nil, fmt.Errorf("限流策略 [%s] 的请求数量和时间窗口必须大于 0", name)
+		}
+
+		policy := &Policy{Name: name, Limit: p.Limit, Window: time.Duration(p.WindowSeconds) * time.Second}
+		l.policies[name] = policy
+		for _, route := range p.Routes {
+			method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+			path = strings.TrimSpace(path)
+			if !ok || method == "" || path == "" {
+				return nil, fmt.Errorf("限流策略 [%s] 的路由 [%s] 格式错误，应为 \"方法 路径\"", name, route)
+			}
+			key := routeKey(method, path)
+			if exist, ok := l.routes[key]; ok {
+				return nil, fmt.Errorf("路由 [%s] 同时属于限流策略 [%s] 和 [%s]", route, exist.Name, name)
+			}
+			l.routes[key] = policy
+		}
+	}
+	return l, nil
+}
+
+// NewLimiter 创建不限制任何路由的限流器，可以直接调用 Allow 使用指定的策略
+func NewLimiter(store kv.Store) *Limiter {
+	return &Limiter{
+		store:    store,
+		policies: make(map[string]*Policy),
+		routes:   make(map[string]*Policy),
+		now:      time.Now,
+	}
+}
+
+// Policy 获取指定名称的限流策略，没有配置或未启用时返回 nil
+// 没有配置路由的策略不会被中间件使用，可以在业务代码中调用 Allow 限制某个操作
+func (l *Limiter) Policy(name string) *Policy {
+	return l.policies[name]
+}
+
+// RoutePolicy 获取路由的限流策略，没有配置时返回 nil
+//   - method: 请求方法
+//   - path: 路由注册时的路径，如 /api/post/:id
+func (l *Limiter) RoutePolicy(method, path string) *Policy {
+	return l.routes[routeKey(method, path)]
+}
+
+// Allow 记录一次请求并判断是否允许
+//   - policy: 限流策略
+//   - client: 客户端标识，如 IP
+func (l *Limiter) Allow(ctx context.Context, policy *Policy, client string) (*Result, error) {
+	window := policy.Window.Milliseconds()
+	now := l.now().UnixMilli()
+	current := now / window
+	elapsed := now % window
+	prefix := keyPrefix + policy.Name + ":" + client + ":"
+
+	var previous int64
+	value, ok, err := l.store.Get(ctx, prefix+strconv.FormatInt(current-1, 10))
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		previous, _ = strconv.ParseInt(value, 10, 64)
+	}
+
+	// 当前窗口的计数需要保留到下一个窗口结束
+	count, err := l.store.Incr(ctx, prefix+strconv.FormatInt(current, 10), 2*policy.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := float64(policy.Limit)
+	estimated := float64(previous)*float64(window-elapsed)/float64(window) + float64(count)
+	if estimated <= limit {
+		return &Result{
+			Allowed:   true,
+			Limit:     policy.Limit,
+			Remaining: int64(math.Floor(limit - estimated)),
+		}, nil
+	}
+
+	// 计算不再有新请求时，最早什么时候允许下一次请求
+	var wait float64
+	if count < policy.Limit {
+		// 当前窗口内，上一个窗口的计数衰减到足够小即可
+		wait = float64(window-elapsed) - float64(window)*(limit-1-float64(count))/float64(previous)
+	} else {
+		// 当前窗口已满，需要等到下一个窗口中当前窗口的计数衰减到足够小
+		wait = float64(window-elapsed) + float64(window)*(1-(limit-1)/float64(count))
+	}
+	return &Result{
+		Allowed:    false,
+		Limit:      policy.Limit,
+		RetryAfter: time.Duration(math.Ceil(wait)) * time.Millisecond,
+	}, nil
+}
+
+// routeKey 路由的键
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
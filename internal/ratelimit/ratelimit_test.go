@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"nola-go/internal/config"
+	"nola-go/internal/kv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	l := NewLimiter(kv.NewMemoryStore())
+	// 从窗口开始计时
+	now := time.UnixMilli(1_700_000_040_000)
+	l.now = func() time.Time { return now }
+	policy := &Policy{Name: "comment", Limit: 3, Window: 10 * time.Second}
+
+	allow := func(client string) *Result {
+		t.Helper()
+		ret, err := l.Allow(ctx, policy, client)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		return ret
+	}
+
+	for i := int64(1); i <= 3; i++ {
+		if ret := allow("1.1.1.1"); !ret.Allowed || ret.Remaining != 3-i {
+			t.Fatalf("Allow #%d = %+v", i, ret)
+		}
+	}
+
+	// 超过限制，当前窗口已满，需要等到下一个窗口中当前窗口计数衰减：10s + 10s * (1 - 2/4)
+	ret := allow("1.1.1.1")
+	if ret.Allowed || ret.RetryAfter != 15*time.Second {
+		t.Fatalf("Allow(limited) = %+v", ret)
+	}
+
+	// 不同客户端分别计数
+	if ret := allow("2.2.2.2"); !ret.Allowed {
+		t.Errorf("Allow(other client) = %+v", ret)
+	}
+
+	// 到达 RetryAfter 后允许
+	now = now.Add(15 * time.Second)
+	if ret := allow("1.1.1.1"); !ret.Allowed || ret.Remaining != 0 {
+		t.Fatalf("Allow(after retry) = %+v", ret)
+	}
+
+	// 上一个窗口的计数还未衰减完，当前窗口未满：5s - 10s * (3 - 1 - 2) / 4
+	ret = allow("1.1.1.1")
+	if ret.Allowed || ret.RetryAfter != 5*time.Second {
+		t.Fatalf("Allow(sliding) = %+v", ret)
+	}
+
+	// 两个窗口后重新计数
+	now = now.Add(20 * time.Second)
+	if ret := allow("1.1.1.1"); !ret.Allowed || ret.Remaining != 2 {
+		t.Errorf("Allow(reset) = %+v", ret)
+	}
+}
+
+func TestNew(t *testing.T) {
+	store := kv.NewMemoryStore()
+	comment := config.RateLimitPolicy{Name: "comment", Routes: []string{"POST /api/comment", " get  /api/post/:id "}, Limit: 5, WindowSeconds: 60}
+
+	// 未启用时不限制
+	l, err := New(config.RateLimitConfig{Policies: []config.RateLimitPolicy{comment}}, store)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if p := l.RoutePolicy("POST", "/api/comment"); p != nil {
+		t.Errorf("disabled RoutePolicy = %+v", p)
+	}
+
+	l, err = New(config.RateLimitConfig{Enabled: true, Policies: []config.RateLimitPolicy{comment}}, store)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if p := l.RoutePolicy("POST", "/api/comment"); p == nil || p.Name != "comment" || p.Limit != 5 || p.Window != time.Minute {
+		t.Errorf("RoutePolicy = %+v", p)
+	}
+	if p := l.RoutePolicy("GET", "/api/post/:id"); p == nil {
+		t.Error("RoutePolicy(GET /api/post/:id) = nil")
+	}
+	if p := l.RoutePolicy("GET", "/api/comment"); p != nil {
+		t.Errorf("RoutePolicy(GET /api/comment) = %+v", p)
+	}
+	if p := l.Policy("comment"); p == nil || p != l.RoutePolicy("POST", "/api/comment") {
+		t.Errorf("Policy(comment) = %+v", p)
+	}
+	if p := l.Policy("missing"); p != nil {
+		t.Errorf("Policy(missing) = %+v", p)
+	}
+
+	// 配置错误
+	tests := []struct {
+		name     string
+		policies []config.RateLimitPolicy
+		wantErr  string
+	}{
+		{"名称为空", []config.RateLimitPolicy{{Limit: 1, WindowSeconds: 1}}, "名称不能为空"},
+		{"名称重复", []config.RateLimitPolicy{comment, comment}, "重复"},
+		{"请求数量为 0", []config.RateLimitPolicy{{Name: "a", WindowSeconds: 1}}, "必须大于 0"},
+		{"路由格式错误", []config.RateLimitPolicy{{Name: "a", Routes: []string{"/api/comment"}, Limit: 1, WindowSeconds: 1}}, "格式错误"},
+		{"路由重复", []config.RateLimitPolicy{comment, {Name: "b", Routes: []string{"POST /api/comment"}, Limit: 1, WindowSeconds: 1}}, "同时属于"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(config.RateLimitConfig{Enabled: true, Policies: tt.policies}, store)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ApiTokenRepository API 令牌 Repo 接口
+type ApiTokenRepository interface {
+	// Create 创建 API 令牌
+	Create(ctx context.Context, token *models.ApiToken) error
+	// GetByHash 根据令牌哈希获取 API 令牌
+	GetByHash(ctx context.Context, tokenHash string) (*models.ApiToken, error)
+	// GetByUserId 获取用户的所有 API 令牌（按创建时间降序）
+	GetByUserId(ctx context.Context, userId uint) ([]*models.ApiToken, error)
+	// UpdateLastUsed 更新 API 令牌最后使用时间和 IP 地址
+	UpdateLastUsed(ctx context.Context, tokenId uint, lastUsedTime int64, ip string) error
+	// Delete 删除用户的 API 令牌，令牌不属于该用户时返回 false
+	Delete(ctx context.Context, userId uint, tokenId uint) (bool, error)
+}
+
+type apiTokenRepo struct {
+	db *gorm.DB
+}
+
+// NewApiTokenRepository 创建 API 令牌 Repo
+func NewApiTokenRepository(db *gorm.DB) ApiTokenRepository {
+	return &apiTokenRepo{db: db}
+}
+
+// Create 创建 API 令牌
+func (r *apiTokenRepo) Create(ctx context.Context, token *models.ApiToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// GetByHash 根据令牌哈希获取 API 令牌
+func (r *apiTokenRepo) GetByHash(ctx context.Context, tokenHash string) (*models.ApiToken, error) {
+	var token models.ApiToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetByUserId 获取用户的所有 API 令牌（按创建时间降序）
+func (r *apiTokenRepo) GetByUserId(ctx context.Context, userId uint) ([]*models.ApiToken, error) {
+	var tokens []*models.ApiToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userId).
+		Order("create_time DESC, token_id DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// UpdateLastUsed 更新 API 令牌最后使用时间和 IP 地址
+func (r *apiTokenRepo) UpdateLastUsed(ctx context.Context, tokenId uint, lastUsedTime int64, ip string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ApiToken{}).
+		Where("token_id = ?", tokenId).
+		Updates(map[string]any{
+			"last_used_time": lastUsedTime,
+			"last_used_ip":   ip,
+		}).Error
+}
+
+// Delete 删除用户的 API 令牌，令牌不属于该用户时返回 false
+func (r *apiTokenRepo) Delete(ctx context.Context, userId uint, tokenId uint) (bool, error) {
+	ret := r.db.WithContext(ctx).
+		Where("token_id = ? AND user_id = ?", tokenId, userId).
+		Delete(&models.ApiToken{})
+	return ret.RowsAffected > 0, ret.Error
+}
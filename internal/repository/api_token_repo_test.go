@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/testutil"
+	"testing"
+)
+
+func TestApiTokenRepo(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	repo := NewApiTokenRepository(database)
+
+	first := &models.ApiToken{UserId: 1, Name: "ci", TokenHash: "hash1", TokenPrefix: "nola_1", Scopes: "post:write", CreateTime: 1}
+	second := &models.ApiToken{UserId: 1, Name: "backup", TokenHash: "hash2", TokenPrefix: "nola_2", Scopes: "file:upload", CreateTime: 2}
+	for _, token := range []*models.ApiToken{first, second} {
+		if err := repo.Create(ctx, token); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	if err := repo.Create(ctx, &models.ApiToken{UserId: 2, Name: "dup", TokenHash: "hash1", TokenPrefix: "nola_1", CreateTime: 3}); err == nil {
+		t.Error("Create with duplicate hash should fail")
+	}
+
+	if got, err := repo.GetByHash(ctx, "hash1"); err != nil || got == nil || got.TokenId != first.TokenId {
+		t.Errorf("GetByHash = %+v, %v", got, err)
+	}
+	if got, err := repo.GetByHash(ctx, "missing"); err != nil || got != nil {
+		t.Errorf("GetByHash(missing) = %+v, %v", got, err)
+	}
+
+	tokens, err := repo.GetByUserId(ctx, 1)
+	if err != nil || len(tokens) != 2 || tokens[0].TokenId != second.TokenId {
+		t.Fatalf("GetByUserId = %+v, %v", tokens, err)
+	}
+
+	if err := repo.UpdateLastUsed(ctx, first.TokenId, 100, "10.0.0.1"); err != nil {
+		t.Fatalf("UpdateLastUsed: %v", err)
+	}
+	got, _ := repo.GetByHash(ctx, "hash1")
+	if got.LastUsedTime == nil || *got.LastUsedTime != 100 || got.LastUsedIp == nil || *got.LastUsedIp != "10.0.0.1" {
+		t.Errorf("GetByHash after UpdateLastUsed = %+v", got)
+	}
+
+	if ok, err := repo.Delete(ctx, 2, first.TokenId); err != nil || ok {
+		t.Errorf("Delete(other user) = %v, %v", ok, err)
+	}
+	if ok, err := repo.Delete(ctx, 1, first.TokenId); err != nil || !ok {
+		t.Fatalf("Delete = %v, %v", ok, err)
+	}
+	if n := countRows(t, database, &models.ApiToken{}, "user_id = ?", 1); n != 1 {
+		t.Errorf("api_token rows = %d, want 1", n)
+	}
+}
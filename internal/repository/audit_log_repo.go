@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"nola-go/internal/db"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository 审计日志 Repo 接口
+// 审计日志只允许添加，不提供修改和删除方法
+type AuditLogRepository interface {
+	// Create 添加审计日志
+	Create(ctx context.Context, logs ...*models.AuditLog) error
+	// AuditLogsPager 分页获取审计日志（按操作时间降序）
+	AuditLogsPager(
+		ctx context.Context,
+		page, size int,
+		actorId *uint,
+		action *enum.AuditAction,
+		startTime, endTime *int64,
+	) (*models.Pager[models.AuditLog], error)
+}
+
+type auditLogRepo struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository 创建审计日志 Repo
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepo{db: db}
+}
+
+// Create 添加审计日志
+func (r *auditLogRepo) Create(ctx context.Context, logs ...*models.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(logs).Error
+}
+
+// AuditLogsPager 分页获取审计日志（按操作时间降序）
+//   - page: 当前页数
+//   - size: 每页条数
+//   - actorId: 操作用户 ID
+//   - action: 操作类型
+//   - startTime: 开始时间戳毫秒（包含）
+//   - endTime: 结束时间戳毫秒（不包含）
+func (r *auditLogRepo) AuditLogsPager(
+	ctx context.Context,
+	page, size int,
+	actorId *uint,
+	action *enum.AuditAction,
+	startTime, endTime *int64,
+) (*models.Pager[models.AuditLog], error) {
+	return db.PagerBuilder[models.AuditLog](ctx, r.db, page, size, func(query *gorm.DB) *gorm.DB {
+		query = query.Model(&models.AuditLog{})
+		if actorId != nil {
+			query = query.Where("actor_id = ?", *actorId)
+		}
+		if action != nil {
+			query = query.Where("action = ?", *action)
+		}
+		if startTime != nil {
+			query = query.Where("create_time >= ?", *startTime)
+		}
+		if endTime != nil {
+			query = query.Where("create_time < ?", *endTime)
+		}
+		return query.Order("create_time DESC, audit_log_id DESC")
+	})
+}
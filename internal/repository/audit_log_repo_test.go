@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+)
+
+func TestAuditLogRepo(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAuditLogRepository(testutil.NewDB(t))
+
+	if err := repo.Create(ctx); err != nil {
+		t.Fatalf("Create(empty): %v", err)
+	}
+	err := repo.Create(ctx,
+		&models.AuditLog{ActorId: util.UintPrt(1), Action: enum.AuditActionPostDelete, TargetType: enum.AuditTargetTypePost, CreateTime: 100},
+		&models.AuditLog{ActorId: util.UintPrt(2), Action: enum.AuditActionFileDelete, TargetType: enum.AuditTargetTypeFile, CreateTime: 200},
+		&models.AuditLog{ActorId: util.UintPrt(1), Action: enum.AuditActionFileDelete, TargetType: enum.AuditTargetTypeFile, CreateTime: 300},
+	)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		actorId   *uint
+		action    *enum.AuditAction
+		startTime *int64
+		endTime   *int64
+		wantTimes []int64
+	}{
+		{"全部按时间降序", nil, nil, nil, nil, []int64{300, 200, 100}},
+		{"操作用户", util.UintPrt(1), nil, nil, nil, []int64{300, 100}},
+		{"操作类型", nil, enum.AuditActionPtr(enum.AuditActionFileDelete), nil, nil, []int64{300, 200}},
+		{"时间范围", nil, nil, util.Int64Ptr(100), util.Int64Ptr(300), []int64{200, 100}},
+		{"组合条件", util.UintPrt(1), enum.AuditActionPtr(enum.AuditActionFileDelete), util.Int64Ptr(100), nil, []int64{300}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pager, err := repo.AuditLogsPager(ctx, 1, 10, tt.actorId, tt.action, tt.startTime, tt.endTime)
+			if err != nil || pager.TotalData != int64(len(tt.wantTimes)) {
+				t.Fatalf("AuditLogsPager = %+v, %v", pager, err)
+			}
+			for i, want := range tt.wantTimes {
+				if pager.Data[i].CreateTime != want {
+					t.Errorf("Data[%d].CreateTime = %d, want %d", i, pager.Data[i].CreateTime, want)
+				}
+			}
+		})
+	}
+
+	pager, err := repo.AuditLogsPager(ctx, 2, 2, nil, nil, nil, nil)
+	if err != nil || len(pager.Data) != 1 || pager.TotalPages != 2 {
+		t.Errorf("AuditLogsPager(page 2) = %+v, %v", pager, err)
+	}
+}
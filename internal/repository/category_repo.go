@@ -79,7 +79,7 @@ func (r *categoryRepo) DeleteCategories(ctx context.Context, ids []uint) (bool,
 	}
 
 	// 先尝试删除分类文章关联信息
-	ret := tx.Where("`category_id` IN ?", ids).Delete(&models.PostCategory{})
+	ret := tx.Where("category_id IN ?", ids).Delete(&models.PostCategory{})
 	if err := ret.Error; err != nil {
 		tx.Rollback()
 		return false, err
@@ -130,10 +130,10 @@ func (r *categoryRepo) UpdateCategory(ctx context.Context, category *models.Cate
 	updates := map[string]any{
 		"display_name":  category.DisplayName,
 		"slug":          category.Slug,
-		"cover":         *category.Cover,
+		"cover":         category.Cover,
 		"unified_cover": category.UnifiedCover,
 	}
-	ret := r.db.WithContext(ctx).Where("`category_id` = ?", category.CategoryId).Model(&models.Category{}).Updates(updates)
+	ret := r.db.WithContext(ctx).Where("category_id = ?", category.CategoryId).Model(&models.Category{}).Updates(updates)
 	return ret.RowsAffected > 0, ret.Error
 }
 
@@ -162,7 +162,7 @@ func (r *categoryRepo) CategoriesPager(ctx context.Context, page, size int) (*mo
 // TopCategories 获取文章数量最多的 6 个分类
 func (r *categoryRepo) TopCategories(ctx context.Context) ([]*models.Category, error) {
 	var categories []*models.Category
-	err := r.sqlSelectCategory().WithContext(ctx).Order("post_count DESC").Limit(6).Scan(&categories).Error
+	err := r.sqlSelectCategory().WithContext(ctx).Order(db.ReorderBy("post_count", true)).Limit(6).Scan(&categories).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/models"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// newTestCategoryRepo 创建分类 Repo 测试环境
+func newTestCategoryRepo(t *testing.T) (CategoryRepository, *testutil.Fixture, *gorm.DB) {
+	database := testutil.NewDB(t)
+	return NewCategoryRepository(database), testutil.NewFixture(t, database), database
+}
+
+// categorySlugs 获取分类别名数组
+func categorySlugs(categories []*models.Category) []string {
+	slugs := make([]string, len(categories))
+	for i, category := range categories {
+		slugs[i] = category.Slug
+	}
+	return slugs
+}
+
+func TestCategoryRepo_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		delete func(repo CategoryRepository, a *models.Category) (bool, error)
+		wantOk bool
+		want   []string
+	}{
+		{"DeleteCategories", func(repo CategoryRepository, a *models.Category) (bool, error) {
+			return repo.DeleteCategories(ctx, []uint{a.CategoryId})
+		}, true, []string{"b"}},
+		{"DeleteCategories 空数组", func(repo CategoryRepository, _ *models.Category) (bool, error) {
+			return repo.DeleteCategories(ctx, []uint{})
+		}, false, []string{"b", "a"}},
+		{"DeleteCategoryBySlugs", func(repo CategoryRepository, _ *models.Category) (bool, error) {
+			return repo.DeleteCategoryBySlugs(ctx, []string{"a"})
+		}, true, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, f, database := newTestCategoryRepo(t)
+			a, b := f.Category("a"), f.Category("b")
+			f.PostCategory(f.Post("p1", "content"), a)
+			f.PostCategory(f.Post("p2", "content"), b)
+
+			ok, err := tt.delete(repo, a)
+			if err != nil || ok != tt.wantOk {
+				t.Fatalf("delete = %v, %v, want %v", ok, err, tt.wantOk)
+			}
+			categories, _ := repo.Categories(ctx)
+			assertStrings(t, categorySlugs(categories), tt.want)
+			if n := countRows(t, database, &models.PostCategory{}, "1 = 1"); n != int64(len(tt.want)) {
+				t.Errorf("post_category rows = %d, want %d", n, len(tt.want))
+			}
+		})
+	}
+
+	t.Run("删除分类失败时回滚", func(t *testing.T) {
+		repo, f, database := newTestCategoryRepo(t)
+		a := f.Category("a")
+		f.PostCategory(f.Post("p1", "content"), a)
+		testutil.InjectError(t, database, testutil.OpDelete, "category", 0)
+
+		if _, err := repo.DeleteCategories(ctx, []uint{a.CategoryId}); !errors.Is(err, testutil.ErrInjected) {
+			t.Fatalf("DeleteCategories err = %v, want injected error", err)
+		}
+		if n := countRows(t, database, &models.PostCategory{}, "1 = 1"); n != 1 {
+			t.Errorf("post_category rows = %d, want 1", n)
+		}
+	})
+}
+
+func TestCategoryRepo_Update(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		cover *string
+	}{
+		{"设置封面", util.StringPtr("/cover.png")},
+		{"没有封面", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, _, _ := newTestCategoryRepo(t)
+			category, err := repo.AddCategory(ctx, &models.Category{DisplayName: "Go", Slug: "go", Cover: util.StringPtr("/old.png")})
+			if err != nil {
+				t.Fatalf("AddCategory: %v", err)
+			}
+
+			ok, err := repo.UpdateCategory(ctx, &models.Category{
+				CategoryId: category.CategoryId, DisplayName: "Golang", Slug: "golang", Cover: tt.cover, UnifiedCover: true,
+			})
+			if err != nil || !ok {
+				t.Fatalf("UpdateCategory = %v, %v", ok, err)
+			}
+			got, err := repo.CategoryById(ctx, category.CategoryId)
+			if err != nil || got.Slug != "golang" || !got.UnifiedCover {
+				t.Fatalf("CategoryById = %+v, %v", got, err)
+			}
+			if (got.Cover == nil) != (tt.cover == nil) || (got.Cover != nil && *got.Cover != *tt.cover) {
+				t.Errorf("Cover = %v, want %v", got.Cover, tt.cover)
+			}
+		})
+	}
+}
+
+func TestCategoryRepo_Query(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestCategoryRepo(t)
+
+	var categories []*models.Category
+	for _, slug := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		categories = append(categories, f.Category(slug))
+	}
+	// b 有 2 篇文章，c 有 1 篇
+	b, c := categories[1], categories[2]
+	post := f.Post("p1", "content")
+	f.PostCategory(post, c)
+	f.PostCategory(f.Post("p2", "content"), b)
+	f.PostCategory(f.Post("p3", "content"), b)
+
+	if count, err := repo.CategoryCount(ctx); err != nil || count != 7 {
+		t.Errorf("CategoryCount = %d, %v, want 7", count, err)
+	}
+
+	top, err := repo.TopCategories(ctx)
+	if err != nil || len(top) != 6 {
+		t.Fatalf("TopCategories = %v, %v", top, err)
+	}
+	assertStrings(t, categorySlugs(top[:2]), []string{"b", "c"})
+	if top[0].PostCount != 2 {
+		t.Errorf("PostCount = %d, want 2", top[0].PostCount)
+	}
+
+	bySlugs, err := repo.CategoryBySlugs(ctx, []string{"a", "c"})
+	if err != nil {
+		t.Fatalf("CategoryBySlugs: %v", err)
+	}
+	assertStrings(t, categorySlugs(bySlugs), []string{"c", "a"})
+
+	singleTests := []struct {
+		name string
+		get  func() (*models.Category, error)
+		want string
+	}{
+		{"CategoryByPostId", func() (*models.Category, error) { return repo.CategoryByPostId(ctx, post.PostId) }, "c"},
+		{"CategoryByPostId 没有分类", func() (*models.Category, error) { return repo.CategoryByPostId(ctx, 9999) }, ""},
+		{"CategoryById", func() (*models.Category, error) { return repo.CategoryById(ctx, b.CategoryId) }, "b"},
+		{"CategoryById 不存在", func() (*models.Category, error) { return repo.CategoryById(ctx, 9999) }, ""},
+		{"CategoryBySlug", func() (*models.Category, error) { return repo.CategoryBySlug(ctx, "d") }, "d"},
+		{"CategoryBySlug 不存在", func() (*models.Category, error) { return repo.CategoryBySlug(ctx, "missing") }, ""},
+		{"CategoryByDisplayName", func() (*models.Category, error) { return repo.CategoryByDisplayName(ctx, "e") }, "e"},
+		{"CategoryByDisplayName 不存在", func() (*models.Category, error) { return repo.CategoryByDisplayName(ctx, "missing") }, ""},
+	}
+	for _, tt := range singleTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if (got == nil && tt.want != "") || (got != nil && got.Slug != tt.want) {
+				t.Errorf("%s = %+v, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	pager, err := repo.CategoriesPager(ctx, 1, 3)
+	if err != nil {
+		t.Fatalf("CategoriesPager: %v", err)
+	}
+	if pager.TotalData != 7 || pager.TotalPages != 3 {
+		t.Errorf("CategoriesPager total = %d/%d, want 7/3", pager.TotalData, pager.TotalPages)
+	}
+	assertStrings(t, categorySlugs(pager.Data), []string{"g", "f", "e"})
+}
@@ -6,6 +6,7 @@ import (
 	"nola-go/internal/db"
 	"nola-go/internal/models"
 	"nola-go/internal/models/enum"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -25,15 +26,17 @@ type CommentRepository interface {
 	DeleteCommentByParentIds(ctx context.Context, parentIds []uint) (bool, error)
 	// UpdateComment 修改评论
 	UpdateComment(ctx context.Context, comment models.Comment) (bool, error)
-	// SetCommentPass 批量设置评论是否通过审核
+	// SetCommentPass 批量设置评论是否通过审核，通过审核的评论不再是垃圾评论
 	SetCommentPass(ctx context.Context, ids []uint, isPass bool) (bool, error)
+	// SetCommentSpam 批量设置评论是否为垃圾评论，垃圾评论不通过审核
+	SetCommentSpam(ctx context.Context, ids []uint, isSpam bool) (bool, error)
 	// CommentByIds 根据评论 ID 数组获取所有评论
 	CommentByIds(ctx context.Context, ids []uint) ([]*models.Comment, error)
 	// Comments 获取所有评论
 	Comments(
 		ctx context.Context,
 		postId, commentId, parentId *uint,
-		isPass *bool, key *string,
+		isPass, isSpam *bool, key *string,
 		sort *enum.CommentSort,
 	) ([]*models.Comment, error)
 	// CommentsPager 分页获取所有评论
@@ -41,7 +44,7 @@ type CommentRepository interface {
 		ctx context.Context,
 		page, size int,
 		postId, commentId, parentId *uint,
-		isPass *bool, key *string,
+		isPass, isSpam *bool, key *string,
 		sort *enum.CommentSort,
 	) (*models.Pager[models.Comment], error)
 	// CommentById 根据评论 ID 获取评论
@@ -50,6 +53,10 @@ type CommentRepository interface {
 	CommentByPostId(ctx context.Context, postId uint, isPass bool) ([]*models.Comment, error)
 	// CommentCount 获取评论数量
 	CommentCount(ctx context.Context) (int64, error)
+	// DuplicateCommentCount 获取创建时间在 since（毫秒时间戳）之后，内容相同的评论数量
+	DuplicateCommentCount(ctx context.Context, content string, since int64) (int64, error)
+	// PassedCommentCount 获取邮箱已通过审核的评论数量，邮箱不区分大小写
+	PassedCommentCount(ctx context.Context, email string) (int64, error)
 }
 
 type commentRepo struct {
@@ -87,16 +94,16 @@ func (r *commentRepo) DeleteCommentByIds(ctx context.Context, ids []uint) (bool,
 // DeleteCommentByPostId 根据文章 ID 删除评论
 func (r *commentRepo) DeleteCommentByPostId(ctx context.Context, postId uint) (bool, error) {
 	ret := r.db.WithContext(ctx).
-		Delete(&models.Comment{}).
-		Where("post_id = ?", postId)
+		Where("post_id = ?", postId).
+		Delete(&models.Comment{})
 	return ret.RowsAffected > 0, ret.Error
 }
 
 // DeleteCommentByParentIds 根据父评论 ID 数组删除评论
 func (r *commentRepo) DeleteCommentByParentIds(ctx context.Context, parentIds []uint) (bool, error) {
 	ret := r.db.WithContext(ctx).
-		Delete(&models.Comment{}).
-		Where("parent_comment_id IN ?", parentIds)
+		Where("parent_comment_id IN ?", parentIds).
+		Delete(&models.Comment{})
 	return ret.RowsAffected > 0, ret.Error
 }
 
@@ -109,6 +116,10 @@ func (r *commentRepo) UpdateComment(ctx context.Context, comment models.Comment)
 		"email":        comment.Email,
 		"is_pass":      comment.IsPass,
 	}
+	if comment.IsPass {
+		// 通过审核的评论不再是垃圾评论
+		updates["is_spam"] = false
+	}
 
 	ret := r.db.WithContext(ctx).
 		Model(&models.Comment{}).
@@ -117,12 +128,31 @@ func (r *commentRepo) UpdateComment(ctx context.Context, comment models.Comment)
 	return ret.RowsAffected > 0, ret.Error
 }
 
-// SetCommentPass 批量设置评论是否通过审核
+// SetCommentPass 批量设置评论是否通过审核，通过审核的评论不再是垃圾评论
 func (r *commentRepo) SetCommentPass(ctx context.Context, ids []uint, isPass bool) (bool, error) {
+	updates := map[string]any{"is_pass": isPass}
+	if isPass {
+		updates["is_spam"] = false
+	}
+
+	ret := r.db.WithContext(ctx).
+		Model(&models.Comment{}).
+		Where("comment_id IN ?", ids).
+		Updates(updates)
+	return ret.RowsAffected > 0, ret.Error
+}
+
+// SetCommentSpam 批量设置评论是否为垃圾评论，垃圾评论不通过审核
+func (r *commentRepo) SetCommentSpam(ctx context.Context, ids []uint, isSpam bool) (bool, error) {
+	updates := map[string]any{"is_spam": isSpam}
+	if isSpam {
+		updates["is_pass"] = false
+	}
+
 	ret := r.db.WithContext(ctx).
 		Model(&models.Comment{}).
 		Where("comment_id IN ?", ids).
-		Update("is_pass", isPass)
+		Updates(updates)
 	return ret.RowsAffected > 0, ret.Error
 }
 
@@ -144,15 +174,16 @@ func (r *commentRepo) CommentByIds(ctx context.Context, ids []uint) ([]*models.C
 //   - commentId: 评论 ID
 //   - parentId: 父评论 ID
 //   - isPass: 是否通过审核
+//   - isSpam: 是否为垃圾评论，为空且 isPass 为 false 时不包含垃圾评论（待审核列表）
 //   - key: 关键字
 //   - sort: 排序方式（默认时间降序）
 func (r *commentRepo) Comments(
 	ctx context.Context,
 	postId, commentId, parentId *uint,
-	isPass *bool, key *string,
+	isPass, isSpam *bool, key *string,
 	sort *enum.CommentSort,
 ) ([]*models.Comment, error) {
-	query := r.commentSQL(ctx, postId, commentId, parentId, isPass, key, sort)
+	query := r.commentSQL(ctx, postId, commentId, parentId, isPass, isSpam, key, sort)
 
 	var comments []*models.Comment
 
@@ -171,6 +202,7 @@ func (r *commentRepo) Comments(
 //   - commentId: 评论 ID
 //   - parentId: 父评论 ID
 //   - isPass: 是否通过审核
+//   - isSpam: 是否为垃圾评论，为空且 isPass 为 false 时不包含垃圾评论（待审核列表）
 //   - key: 关键字
 //   - sort: 排序方式（默认时间降序）
 func (r *commentRepo) CommentsPager(
@@ -181,11 +213,12 @@ func (r *commentRepo) CommentsPager(
 	commentId,
 	parentId *uint,
 	isPass *bool,
+	isSpam *bool,
 	key *string,
 	sort *enum.CommentSort,
 ) (*models.Pager[models.Comment], error) {
 
-	query := r.commentSQL(ctx, postId, commentId, parentId, isPass, key, sort)
+	query := r.commentSQL(ctx, postId, commentId, parentId, isPass, isSpam, key, sort)
 
 	if page == 0 {
 		// 获取所有评论
@@ -258,11 +291,33 @@ func (r *commentRepo) CommentCount(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
+// DuplicateCommentCount 获取创建时间在 since（毫秒时间戳）之后，内容相同的评论数量
+func (r *commentRepo) DuplicateCommentCount(ctx context.Context, content string, since int64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Comment{}).
+		Where("content = ?", content).
+		Where("create_time >= ?", since).
+		Count(&count).Error
+	return count, err
+}
+
+// PassedCommentCount 获取邮箱已通过审核的评论数量，邮箱不区分大小写
+func (r *commentRepo) PassedCommentCount(ctx context.Context, email string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Comment{}).
+		Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email))).
+		Where("is_pass = ?", true).
+		Count(&count).Error
+	return count, err
+}
+
 // 构建评论查询 SQL
 func (r *commentRepo) commentSQL(
 	ctx context.Context,
 	postId, commentId, parentId *uint,
-	isPass *bool, key *string,
+	isPass, isSpam *bool, key *string,
 	sort *enum.CommentSort,
 ) *gorm.DB {
 	query := r.db.WithContext(ctx).
@@ -285,8 +340,15 @@ func (r *commentRepo) commentSQL(
 		query = query.Where("c.is_pass = ?", isPass)
 	}
 
+	if isSpam != nil {
+		query = query.Where("c.is_spam = ?", isSpam)
+	} else if isPass != nil && !*isPass {
+		// 垃圾评论不进入待审核列表，需要使用 isSpam = true 单独获取
+		query = query.Where("c.is_spam = ?", false)
+	}
+
 	if key != nil {
-		query = query.Where("c.content LIKE ? OR c.email LIKE ? OR c.display_name LIKE ?", "%"+*key+"%", "%"+*key+"%", "%"+*key+"%")
+		query = query.Where(db.Contains(*key, "c.content", "c.email", "c.display_name"))
 	}
 
 	if sort == nil {
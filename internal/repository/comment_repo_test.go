@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// newTestCommentRepo 创建评论 Repo 测试环境
+func newTestCommentRepo(t *testing.T) (CommentRepository, *testutil.Fixture, *gorm.DB) {
+	database := testutil.NewDB(t)
+	return NewCommentRepository(database), testutil.NewFixture(t, database), database
+}
+
+// commentEmails 获取评论邮箱数组
+func commentEmails(comments []*models.Comment) []string {
+	emails := make([]string, len(comments))
+	for i, c := range comments {
+		emails[i] = c.Email
+	}
+	return emails
+}
+
+func TestCommentRepo_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		delete func(repo CommentRepository, a, b *models.Comment, post *models.Post) (bool, error)
+		wantOk bool
+		want   []string
+	}{
+		{
+			name: "DeleteCommentById",
+			delete: func(repo CommentRepository, a, b *models.Comment, _ *models.Post) (bool, error) {
+				return repo.DeleteCommentById(ctx, a.CommentId)
+			},
+			wantOk: true,
+			want:   []string{"a1", "b"},
+		},
+		{
+			name: "DeleteCommentByIds",
+			delete: func(repo CommentRepository, a, b *models.Comment, _ *models.Post) (bool, error) {
+				return repo.DeleteCommentByIds(ctx, []uint{a.CommentId, b.CommentId})
+			},
+			wantOk: true,
+			want:   []string{"a1"},
+		},
+		{
+			name: "DeleteCommentByPostId",
+			delete: func(repo CommentRepository, _, _ *models.Comment, post *models.Post) (bool, error) {
+				return repo.DeleteCommentByPostId(ctx, post.PostId)
+			},
+			wantOk: true,
+			want:   []string{"b"},
+		},
+		{
+			name: "DeleteCommentByParentIds",
+			delete: func(repo CommentRepository, a, _ *models.Comment, _ *models.Post) (bool, error) {
+				return repo.DeleteCommentByParentIds(ctx, []uint{a.CommentId})
+			},
+			wantOk: true,
+			want:   []string{"a", "b"},
+		},
+		{
+			name: "DeleteCommentByParentIds 没有子评论",
+			delete: func(repo CommentRepository, _, b *models.Comment, _ *models.Post) (bool, error) {
+				return repo.DeleteCommentByParentIds(ctx, []uint{b.CommentId})
+			},
+			wantOk: false,
+			want:   []string{"a", "a1", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, f, _ := newTestCommentRepo(t)
+			post, other := f.Post("post", "content"), f.Post("other", "content")
+			a := f.Comment(post, nil, "a", true)
+			f.Comment(post, a, "a1", true)
+			b := f.Comment(other, nil, "b", true)
+
+			ok, err := tt.delete(repo, a, b, post)
+			if err != nil || ok != tt.wantOk {
+				t.Fatalf("delete = %v, %v, want %v", ok, err, tt.wantOk)
+			}
+			comments, err := repo.Comments(ctx, nil, nil, nil, nil, nil, nil, enum.CommentSortPtr(enum.CommentSortCreateAsc))
+			if err != nil {
+				t.Fatalf("Comments: %v", err)
+			}
+			assertStrings(t, commentEmails(comments), tt.want)
+		})
+	}
+}
+
+func TestCommentRepo_Update(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestCommentRepo(t)
+	post := f.Post("post", "content")
+
+	added, err := repo.AddComment(ctx, &models.Comment{PostId: post.PostId, Content: "hi", DisplayName: "x", Email: "x@a.com"})
+	if err != nil || added.CommentId == 0 || added.CreateTime == 0 {
+		t.Fatalf("AddComment = %+v, %v", added, err)
+	}
+	other := f.Comment(post, nil, "y@a.com", false)
+
+	added.Content, added.Site, added.IsPass = "hello", util.StringPtr("https://x.com"), true
+	if ok, err := repo.UpdateComment(ctx, *added); err != nil || !ok {
+		t.Fatalf("UpdateComment = %v, %v", ok, err)
+	}
+	got, err := repo.CommentById(ctx, added.CommentId)
+	if err != nil || got.Content != "hello" || got.Site == nil || !got.IsPass {
+		t.Fatalf("CommentById = %+v, %v", got, err)
+	}
+	if missing, err := repo.CommentById(ctx, 9999); missing != nil || err != nil {
+		t.Errorf("CommentById(missing) = %v, %v", missing, err)
+	}
+
+	if ok, err := repo.SetCommentPass(ctx, []uint{added.CommentId, other.CommentId}, false); err != nil || !ok {
+		t.Fatalf("SetCommentPass = %v, %v", ok, err)
+	}
+	comments, err := repo.CommentByIds(ctx, []uint{added.CommentId, other.CommentId})
+	if err != nil || len(comments) != 2 {
+		t.Fatalf("CommentByIds = %v, %v", comments, err)
+	}
+	for _, c := range comments {
+		if c.IsPass {
+			t.Errorf("comment %d should not pass", c.CommentId)
+		}
+	}
+}
+
+func TestCommentRepo_Query(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestCommentRepo(t)
+	post, other := f.Post("post", "content"), f.Post("other", "content")
+	a := f.Comment(post, nil, "a@x.com", true)
+	f.Comment(post, a, "a1@x.com", false)
+	f.Comment(post, a, "a2@x.com", true)
+	f.Comment(other, nil, "b_1@y.com", true)
+	spam := f.Comment(other, nil, "spam@y.com", false)
+	if _, err := repo.SetCommentSpam(ctx, []uint{spam.CommentId}, true); err != nil {
+		t.Fatalf("SetCommentSpam: %v", err)
+	}
+
+	if count, err := repo.CommentCount(ctx); err != nil || count != 5 {
+		t.Errorf("CommentCount = %d, %v, want 5", count, err)
+	}
+
+	byPost, err := repo.CommentByPostId(ctx, post.PostId, true)
+	if err != nil {
+		t.Fatalf("CommentByPostId: %v", err)
+	}
+	assertStrings(t, commentEmails(byPost), []string{"a@x.com", "a2@x.com"})
+
+	asc := enum.CommentSortPtr(enum.CommentSortCreateAsc)
+	tests := []struct {
+		name                        string
+		postId, commentId, parentId *uint
+		isPass, isSpam              *bool
+		key                         *string
+		sort                        *enum.CommentSort
+		want                        []string
+	}{
+		{name: "默认时间降序", want: []string{"spam@y.com", "b_1@y.com", "a2@x.com", "a1@x.com", "a@x.com"}},
+		{name: "文章", postId: &other.PostId, sort: asc, want: []string{"b_1@y.com", "spam@y.com"}},
+		{name: "评论 ID", commentId: &a.CommentId, want: []string{"a@x.com"}},
+		{name: "父评论", parentId: &a.CommentId, sort: asc, want: []string{"a1@x.com", "a2@x.com"}},
+		{name: "审核状态", isPass: util.BoolPtr(false), isSpam: util.BoolPtr(false), want: []string{"a1@x.com"}},
+		{name: "垃圾评论", isSpam: util.BoolPtr(true), want: []string{"spam@y.com"}},
+		{name: "关键字", key: util.StringPtr("X.COM"), sort: asc, want: []string{"a@x.com", "a1@x.com", "a2@x.com"}},
+		{name: "关键字转义通配符", key: util.StringPtr("b_"), want: []string{"b_1@y.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("Comments/"+tt.name, func(t *testing.T) {
+			comments, err := repo.Comments(ctx, tt.postId, tt.commentId, tt.parentId, tt.isPass, tt.isSpam, tt.key, tt.sort)
+			if err != nil {
+				t.Fatalf("Comments: %v", err)
+			}
+			assertStrings(t, commentEmails(comments), tt.want)
+		})
+	}
+
+	pagerTests := []struct {
+		name       string
+		page, size int
+		want       []string
+		wantTotal  int64
+	}{
+		{"不分页", 0, 0, []string{"a@x.com", "a1@x.com", "a2@x.com", "b_1@y.com"}, 4},
+		{"第二页", 2, 3, []string{"b_1@y.com"}, 4},
+	}
+	for _, tt := range pagerTests {
+		t.Run("CommentsPager/"+tt.name, func(t *testing.T) {
+			pager, err := repo.CommentsPager(ctx, tt.page, tt.size, nil, nil, nil, nil, util.BoolPtr(false), nil, asc)
+			if err != nil {
+				t.Fatalf("CommentsPager: %v", err)
+			}
+			if pager.TotalData != tt.wantTotal {
+				t.Errorf("TotalData = %d, want %d", pager.TotalData, tt.wantTotal)
+			}
+			assertStrings(t, commentEmails(pager.Data), tt.want)
+		})
+	}
+}
+
+func TestCommentRepo_Spam(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestCommentRepo(t)
+	post := f.Post("post", "content")
+	a := f.Comment(post, nil, "a@x.com", true)
+	b := f.Comment(post, nil, "b@x.com", true)
+
+	// 标记为垃圾评论时取消通过审核
+	if ok, err := repo.SetCommentSpam(ctx, []uint{a.CommentId, b.CommentId}, true); err != nil || !ok {
+		t.Fatalf("SetCommentSpam = %v, %v", ok, err)
+	}
+	got, _ := repo.CommentById(ctx, a.CommentId)
+	if !got.IsSpam || got.IsPass {
+		t.Errorf("spam comment = %+v", got)
+	}
+
+	// 通过审核时不再是垃圾评论
+	if _, err := repo.SetCommentPass(ctx, []uint{a.CommentId}, true); err != nil {
+		t.Fatalf("SetCommentPass: %v", err)
+	}
+	got, _ = repo.CommentById(ctx, a.CommentId)
+	if got.IsSpam || !got.IsPass {
+		t.Errorf("passed comment = %+v", got)
+	}
+	got, _ = repo.CommentById(ctx, b.CommentId)
+	got.IsPass = true
+	if _, err := repo.UpdateComment(ctx, *got); err != nil {
+		t.Fatalf("UpdateComment: %v", err)
+	}
+	got, _ = repo.CommentById(ctx, b.CommentId)
+	if got.IsSpam || !got.IsPass {
+		t.Errorf("updated comment = %+v", got)
+	}
+
+	// 重复评论
+	if count, err := repo.DuplicateCommentCount(ctx, a.Content, 0); err != nil || count != 1 {
+		t.Errorf("DuplicateCommentCount = %d, %v", count, err)
+	}
+	if count, err := repo.DuplicateCommentCount(ctx, a.Content, a.CreateTime+1); err != nil || count != 0 {
+		t.Errorf("DuplicateCommentCount(since) = %d, %v", count, err)
+	}
+	if count, err := repo.DuplicateCommentCount(ctx, "other", 0); err != nil || count != 0 {
+		t.Errorf("DuplicateCommentCount(other) = %d, %v", count, err)
+	}
+}
+
+func TestCommentRepo_PendingExcludesSpam(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestCommentRepo(t)
+	post := f.Post("post", "content")
+	f.Comment(post, nil, "pending@x.com", false)
+	spammed := f.Comment(post, nil, "spam@x.com", false)
+	f.Comment(post, nil, "passed@x.com", true)
+	if _, err := repo.SetCommentSpam(ctx, []uint{spammed.CommentId}, true); err != nil {
+		t.Fatalf("SetCommentSpam: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		isPass *bool
+		isSpam *bool
+		want   []string
+	}{
+		// 待审核列表默认不包含垃圾评论
+		{"待审核", util.BoolPtr(false), nil, []string{"pending@x.com"}},
+		{"垃圾评论", util.BoolPtr(false), util.BoolPtr(true), []string{"spam@x.com"}},
+		{"仅垃圾评论", nil, util.BoolPtr(true), []string{"spam@x.com"}},
+		{"全部", nil, nil, []string{"passed@x.com", "spam@x.com", "pending@x.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comments, err := repo.Comments(ctx, &post.PostId, nil, nil, tt.isPass, tt.isSpam, nil, nil)
+			if err != nil {
+				t.Fatalf("Comments: %v", err)
+			}
+			if got := commentEmails(comments); !slices.Equal(got, tt.want) {
+				t.Errorf("Comments = %v, want %v", got, tt.want)
+			}
+			pager, err := repo.CommentsPager(ctx, 1, 10, &post.PostId, nil, nil, tt.isPass, tt.isSpam, nil, nil)
+			if err != nil || pager.TotalData != int64(len(tt.want)) {
+				t.Errorf("CommentsPager = %+v, %v, want %d", pager, err, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestCommentRepo_PassedCommentCount(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestCommentRepo(t)
+	post := f.Post("post", "content")
+	f.Comment(post, nil, "A@x.com", true)
+	f.Comment(post, nil, "a@x.com", true)
+	f.Comment(post, nil, "a@x.com", false)
+	f.Comment(post, nil, "b@x.com", true)
+
+	// 邮箱不区分大小写，只统计通过审核的评论
+	if count, err := repo.PassedCommentCount(ctx, " a@X.com "); err != nil || count != 2 {
+		t.Errorf("PassedCommentCount = %d, %v", count, err)
+	}
+}
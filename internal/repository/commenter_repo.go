@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/db"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/util"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommenterRepository 管理员标记的评论人 Repo 接口
+type CommenterRepository interface {
+	// SaveCommenter 标记评论人，邮箱已标记时修改状态和备注
+	SaveCommenter(ctx context.Context, commenter *models.Commenter) (*models.Commenter, error)
+	// DeleteCommenters 取消标记评论人
+	DeleteCommenters(ctx context.Context, ids []uint) (bool, error)
+	// CommenterByEmail 根据邮箱获取标记的评论人
+	CommenterByEmail(ctx context.Context, email string) (*models.Commenter, error)
+	// CommentersPager 分页获取标记的评论人
+	CommentersPager(ctx context.Context, page, size int, status *enum.CommenterStatus, key *string) (*models.Pager[models.Commenter], error)
+}
+
+type commenterRepo struct {
+	db *gorm.DB
+}
+
+func NewCommenterRepository(db *gorm.DB) CommenterRepository {
+	return &commenterRepo{
+		db: db,
+	}
+}
+
+// SaveCommenter 标记评论人，邮箱不区分大小写
+func (r *commenterRepo) SaveCommenter(ctx context.Context, commenter *models.Commenter) (*models.Commenter, error) {
+	commenter.Email = strings.ToLower(strings.TrimSpace(commenter.Email))
+	now := time.Now().UnixMilli()
+	commenter.LastModifyTime = &now
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "email"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "remark", "last_modify_time"}),
+		}).
+		Create(commenter).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// 修改已有记录时部分数据库不会返回已有记录的 ID，重新获取
+	return r.CommenterByEmail(ctx, commenter.Email)
+}
+
+// DeleteCommenters 取消标记评论人
+func (r *commenterRepo) DeleteCommenters(ctx context.Context, ids []uint) (bool, error) {
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	ret := r.db.WithContext(ctx).Delete(&models.Commenter{}, ids)
+	if ret.Error != nil {
+		return false, ret.Error
+	}
+	return ret.RowsAffected > 0, nil
+}
+
+// CommenterByEmail 根据邮箱获取标记的评论人，邮箱不区分大小写，没有标记时返回 nil
+func (r *commenterRepo) CommenterByEmail(ctx context.Context, email string) (*models.Commenter, error) {
+	var commenter models.Commenter
+	err := r.db.WithContext(ctx).
+		Where("email = ?", strings.ToLower(strings.TrimSpace(email))).
+		First(&commenter).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &commenter, nil
+}
+
+// CommentersPager 分页获取标记的评论人，page 为 0 时获取所有
+//   - status: 状态
+//   - key: 关键字（邮箱、备注）
+func (r *commenterRepo) CommentersPager(ctx context.Context, page, size int, status *enum.CommenterStatus, key *string) (*models.Pager[models.Commenter], error) {
+	queryBuilder := func(query *gorm.DB) *gorm.DB {
+		query = query.Model(&models.Commenter{})
+		if status != nil {
+			query = query.Where("status = ?", *status)
+		}
+		if key != nil {
+			query = query.Where(db.Contains(*key, "email", "remark"))
+		}
+		return query.Order("create_time DESC")
+	}
+
+	if page == 0 {
+		var data []*models.Commenter
+		if err := queryBuilder(r.db.WithContext(ctx)).Find(&data).Error; err != nil {
+			return nil, err
+		}
+		return &models.Pager[models.Commenter]{
+			Page:       0,
+			Size:       0,
+			Data:       util.DefaultEmptySlice(data),
+			TotalData:  int64(len(data)),
+			TotalPages: 1,
+		}, nil
+	}
+
+	return db.PagerBuilder[models.Commenter](ctx, r.db, page, size, queryBuilder)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+)
+
+func TestCommenterRepo(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCommenterRepository(testutil.NewDB(t))
+
+	// 邮箱保存为小写
+	trusted, err := repo.SaveCommenter(ctx, &models.Commenter{Email: " Friend@Example.com ", Status: enum.CommenterStatusTrusted})
+	if err != nil || trusted.CommenterId == 0 || trusted.Email != "friend@example.com" {
+		t.Fatalf("SaveCommenter = %+v, %v", trusted, err)
+	}
+	if _, err := repo.SaveCommenter(ctx, &models.Commenter{Email: "spam@example.com", Status: enum.CommenterStatusBlocked, Remark: util.StringPtr("广告")}); err != nil {
+		t.Fatalf("SaveCommenter: %v", err)
+	}
+
+	// 已标记的邮箱修改状态，ID 不变
+	blocked, err := repo.SaveCommenter(ctx, &models.Commenter{Email: "FRIEND@example.com", Status: enum.CommenterStatusBlocked})
+	if err != nil || blocked.CommenterId != trusted.CommenterId || blocked.Status != enum.CommenterStatusBlocked || blocked.LastModifyTime == nil {
+		t.Fatalf("SaveCommenter(update) = %+v, %v", blocked, err)
+	}
+
+	if got, err := repo.CommenterByEmail(ctx, "friend@EXAMPLE.com"); err != nil || got == nil || got.Status != enum.CommenterStatusBlocked {
+		t.Errorf("CommenterByEmail = %+v, %v", got, err)
+	}
+	if got, err := repo.CommenterByEmail(ctx, "nobody@example.com"); err != nil || got != nil {
+		t.Errorf("CommenterByEmail(nobody) = %+v, %v", got, err)
+	}
+
+	tests := []struct {
+		name   string
+		page   int
+		status *enum.CommenterStatus
+		key    *string
+		want   int
+	}{
+		{"全部", 0, nil, nil, 2},
+		{"分页", 1, nil, nil, 1},
+		{"按状态", 0, enum.CommenterStatusPtr(enum.CommenterStatusTrusted), nil, 0},
+		{"按备注", 0, nil, util.StringPtr("广告"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := 0
+			if tt.page > 0 {
+				size = 1
+			}
+			pager, err := repo.CommentersPager(ctx, tt.page, size, tt.status, tt.key)
+			if err != nil || len(pager.Data) != tt.want {
+				t.Errorf("CommentersPager = %+v, %v, want %d", pager, err, tt.want)
+			}
+		})
+	}
+
+	if ok, err := repo.DeleteCommenters(ctx, []uint{trusted.CommenterId}); err != nil || !ok {
+		t.Fatalf("DeleteCommenters = %v, %v", ok, err)
+	}
+	if got, _ := repo.CommenterByEmail(ctx, "friend@example.com"); got != nil {
+		t.Errorf("deleted commenter = %+v", got)
+	}
+}
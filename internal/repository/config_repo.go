@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"nola-go/internal/db"
 	"nola-go/internal/models"
 
 	"gorm.io/gorm"
@@ -42,7 +43,7 @@ func (r *configRepo) AddConfig(ctx context.Context, config *models.Config) (*mod
 
 // DeleteConfig 删除配置
 func (r *configRepo) DeleteConfig(ctx context.Context, key models.ConfigKey) (bool, error) {
-	err := r.db.WithContext(ctx).Where("`key` = ?", key).Delete(&models.Config{}).Error
+	err := r.db.WithContext(ctx).Where("? = ?", db.Column("key"), key).Delete(&models.Config{}).Error
 	if err != nil {
 		return false, err
 	}
@@ -51,7 +52,7 @@ func (r *configRepo) DeleteConfig(ctx context.Context, key models.ConfigKey) (bo
 
 // UpdateConfig 更新配置
 func (r *configRepo) UpdateConfig(ctx context.Context, config *models.Config) (bool, error) {
-	err := r.db.WithContext(ctx).Where("`key` = ?", config.Key).Updates(config).Error
+	err := r.db.WithContext(ctx).Where("? = ?", db.Column("key"), config.Key).Updates(config).Error
 	if err != nil {
 		return false, err
 	}
@@ -61,7 +62,7 @@ func (r *configRepo) UpdateConfig(ctx context.Context, config *models.Config) (b
 // Config 获取配置
 func (r *configRepo) Config(ctx context.Context, key models.ConfigKey) (*string, error) {
 	var config models.Config
-	err := r.db.WithContext(ctx).Where("`key` = ?", key).First(&config).Error
+	err := r.db.WithContext(ctx).Where("? = ?", db.Column("key"), key).First(&config).Error
 
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/testutil"
+	"testing"
+)
+
+func TestConfigRepo(t *testing.T) {
+	ctx := context.Background()
+	repo := NewConfigRepository(testutil.NewDB(t))
+
+	if value, err := repo.Config(ctx, models.ConfigKeyBlogInfo); value != nil || err != nil {
+		t.Fatalf("Config(empty) = %v, %v", value, err)
+	}
+
+	if _, err := repo.AddConfig(ctx, &models.Config{Key: models.ConfigKeyBlogInfo, Value: `{"title":"a"}`}); err != nil {
+		t.Fatalf("AddConfig: %v", err)
+	}
+	if _, err := repo.AddConfig(ctx, &models.Config{Key: models.ConfigKeyICPFiling, Value: "{}"}); err != nil {
+		t.Fatalf("AddConfig: %v", err)
+	}
+	if _, err := repo.AddConfig(ctx, &models.Config{Key: models.ConfigKeyBlogInfo, Value: "{}"}); err == nil {
+		t.Error("AddConfig with duplicate key should fail")
+	}
+
+	if ok, err := repo.UpdateConfig(ctx, &models.Config{Key: models.ConfigKeyBlogInfo, Value: `{"title":"b"}`}); err != nil || !ok {
+		t.Fatalf("UpdateConfig = %v, %v", ok, err)
+	}
+
+	tests := []struct {
+		key  models.ConfigKey
+		want string
+	}{
+		{models.ConfigKeyBlogInfo, `{"title":"b"}`},
+		{models.ConfigKeyICPFiling, "{}"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.key), func(t *testing.T) {
+			value, err := repo.Config(ctx, tt.key)
+			if err != nil || value == nil || *value != tt.want {
+				t.Errorf("Config = %v, %v, want %s", value, err, tt.want)
+			}
+		})
+	}
+
+	if ok, err := repo.DeleteConfig(ctx, models.ConfigKeyBlogInfo); err != nil || !ok {
+		t.Fatalf("DeleteConfig = %v, %v", ok, err)
+	}
+	if value, _ := repo.Config(ctx, models.ConfigKeyBlogInfo); value != nil {
+		t.Errorf("Config after delete = %s", *value)
+	}
+	if value, _ := repo.Config(ctx, models.ConfigKeyICPFiling); value == nil {
+		t.Error("DeleteConfig should only delete the given key")
+	}
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+)
+
+// diaryContents 获取日记内容数组
+func diaryContents(diaries []*models.Diary) []string {
+	contents := make([]string, len(diaries))
+	for i, diary := range diaries {
+		contents[i] = diary.Content
+	}
+	return contents
+}
+
+func TestDiaryRepo(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	repo := NewDiaryRepository(database)
+	f := testutil.NewFixture(t, database)
+
+	added, err := repo.AddDiary(ctx, &request.DiaryRequest{Content: "**a**"})
+	if err != nil || added.DiaryId == 0 || added.Html == "" || added.LastModifyTime != nil {
+		t.Fatalf("AddDiary = %+v, %v", added, err)
+	}
+	// 固定创建时间，保证排序稳定
+	b := &models.Diary{Content: "b", Html: "<p>b</p>", CreateTime: added.CreateTime + 1000}
+	c := &models.Diary{Content: "c", Html: "<p>c</p>", CreateTime: added.CreateTime + 2000, LastModifyTime: util.Int64Ptr(1)}
+	f.Create(b)
+	f.Create(c)
+
+	if ok, err := repo.UpdateDiary(ctx, &request.DiaryRequest{DiaryId: &added.DiaryId, Content: "a"}); err != nil || !ok {
+		t.Fatalf("UpdateDiary = %v, %v", ok, err)
+	}
+	if ok, _ := repo.UpdateDiary(ctx, &request.DiaryRequest{DiaryId: util.DefaultPtr[uint](nil, 9999), Content: "x"}); ok {
+		t.Error("UpdateDiary(missing) should return false")
+	}
+
+	if count, err := repo.DiaryCount(ctx); err != nil || count != 3 {
+		t.Errorf("DiaryCount = %d, %v, want 3", count, err)
+	}
+
+	tests := []struct {
+		name string
+		sort *enum.DiarySort
+		want []string
+	}{
+		{"默认创建时间降序", nil, []string{"c", "b", "a"}},
+		{"创建时间升序", enum.DiarySortPtr(enum.DiarySortCreateTimeAsc), []string{"a", "b", "c"}},
+		{"修改时间降序", enum.DiarySortPtr(enum.DiarySortModifyTimeDesc), []string{"a", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diaries, err := repo.Diaries(ctx, tt.sort)
+			if err != nil {
+				t.Fatalf("Diaries: %v", err)
+			}
+			got := diaryContents(diaries)
+			if tt.sort != nil && *tt.sort == enum.DiarySortModifyTimeDesc {
+				// 没有修改时间的日记在不同数据库中的位置不同，只比较有修改时间的日记
+				got = util.Filter(got, func(s string) bool { return s != "b" })
+			}
+			assertStrings(t, got, tt.want)
+		})
+	}
+
+	pager, err := repo.DiariesPager(ctx, 1, 2, nil)
+	if err != nil || pager.TotalData != 3 || pager.TotalPages != 2 {
+		t.Fatalf("DiariesPager = %+v, %v", pager, err)
+	}
+	assertStrings(t, diaryContents(pager.Data), []string{"c", "b"})
+
+	if ok, err := repo.DeleteDiaries(ctx, []uint{added.DiaryId, b.DiaryId}); err != nil || !ok {
+		t.Fatalf("DeleteDiaries = %v, %v", ok, err)
+	}
+	all, _ := repo.DiariesPager(ctx, 0, 0, nil)
+	assertStrings(t, diaryContents(all.Data), []string{"c"})
+}
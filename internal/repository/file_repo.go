@@ -10,6 +10,7 @@ import (
 	"nola-go/internal/util"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type FileRepository interface {
@@ -364,12 +365,17 @@ func (r *fileRepo) DeleteFileByGroupIdAndName(ctx context.Context, pairs []*mode
 	// 先删除有文件组的记录
 	if len(withGroup) > 0 {
 		for _, chunk := range util.Chunk(withGroup, batchSize) {
-			var args [][]any
+			// 不使用 (file_group_id, display_name) IN ((?, ?), ...)，SQLite 不支持行值列表
+			conditions := make([]clause.Expression, 0, len(chunk))
 			for _, p := range chunk {
-				args = append(args, []any{*p.First, p.Second})
+				conditions = append(conditions, clause.And(
+					clause.Eq{Column: clause.Column{Name: "file_group_id"}, Value: *p.First},
+					clause.Eq{Column: clause.Column{Name: "display_name"}, Value: p.Second},
+				))
 			}
 
-			ret := tx.Where("storage_mode = ? AND (file_group_id, display_name) IN ?", storageMode, args).
+			ret := tx.Where("storage_mode = ?", storageMode).
+				Where(clause.Or(conditions...)).
 				Delete(&models.File{})
 
 			if ret.Error != nil {
@@ -488,12 +494,17 @@ func (r *fileRepo) GetFile(ctx context.Context, fileName string, groupId *uint,
 	return file, nil
 }
 
+// sqlSelectFileWithGroup 查询文件和文件组的列。
+// 别名使用下划线命名，PostgreSQL 会将未加引号的别名转为小写
+const sqlSelectFileWithGroup = "f.file_id AS file_id, f.file_group_id AS file_group_id, f.display_name AS file_name, " +
+	"fg.display_name AS file_group_name, fg.path AS file_group_path, f.size AS size, f.storage_mode AS storage_mode, f.create_time AS create_time"
+
 // GetFileWithGroupByIds 根据文件 ID 数组，获取文件和文件组数据类
 func (r *fileRepo) GetFileWithGroupByIds(ctx context.Context, ids []uint) ([]*models.FileWithGroup, error) {
 	var ret []*models.FileWithGroup
 	err := r.db.WithContext(ctx).
 		Table("file f").
-		Select("f.file_id as fileId, f.file_group_id as fileGroupId, f.display_name as fileName, fg.display_name as fileGroupName, fg.path as fileGroupPath, f.size as size, f.storage_mode as storageMode, f.create_time as createTime").
+		Select(sqlSelectFileWithGroup).
 		Joins("LEFT JOIN file_group fg ON f.file_group_id = fg.file_group_id").
 		Where("f.file_id IN ?", ids).
 		Scan(&ret).Error
@@ -522,7 +533,7 @@ func (r *fileRepo) GetFileWithGroups(
 ) (*models.Pager[models.FileWithGroup], error) {
 	baseQuery := r.db.WithContext(ctx).
 		Table("file f").
-		Select("f.file_id as fileId, f.file_group_id as fileGroupId, f.display_name as fileName, fg.display_name as fileGroupName, fg.path as fileGroupPath, f.size as size, f.storage_mode as storageMode, f.create_time as createTime").
+		Select(sqlSelectFileWithGroup).
 		Joins("LEFT JOIN file_group fg ON f.file_group_id = fg.file_group_id")
 
 	if mode != nil {
@@ -534,7 +545,7 @@ func (r *fileRepo) GetFileWithGroups(
 	}
 
 	if !util.StringIsNilOrBlank(key) {
-		baseQuery = baseQuery.Where("f.display_name LIKE ?", "%"+*key+"%")
+		baseQuery = baseQuery.Where(db.Contains(*key, "f.display_name"))
 	}
 
 	if sort != nil {
@@ -0,0 +1,412 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// newTestFileRepo 创建文件 Repo 测试环境
+func newTestFileRepo(t *testing.T) (FileRepository, *testutil.Fixture, *gorm.DB) {
+	database := testutil.NewDB(t)
+	return NewFileRepo(database), testutil.NewFixture(t, database), database
+}
+
+// fileNames 获取文件表中指定存储方式的所有文件名
+func fileNames(t *testing.T, db *gorm.DB, storageMode enum.FileStorageMode) []string {
+	t.Helper()
+	var names []string
+	err := db.Model(&models.File{}).
+		Where("storage_mode = ?", storageMode).
+		Order("file_id ASC").
+		Pluck("display_name", &names).Error
+	if err != nil {
+		t.Fatalf("pluck: %v", err)
+	}
+	return names
+}
+
+func TestFileRepo_DeleteFileByGroupIdAndName(t *testing.T) {
+	ctx := context.Background()
+	local, cos := enum.FileStorageModeLocal, enum.FileStorageModeTencentCOS
+
+	// prepare 插入两个存储方式下同名的文件
+	prepare := func(t *testing.T) (FileRepository, *gorm.DB, *models.FileGroup, *models.FileGroup) {
+		repo, f, database := newTestFileRepo(t)
+		images := f.FileGroup("images", local)
+		docs := f.FileGroup("docs", local)
+		f.File("a.png", images, local)
+		f.File("a.png", docs, local)
+		f.File("b.png", images, local)
+		f.File("a.png", nil, local)
+		f.File("c.png", nil, local)
+		f.File("a.png", nil, cos)
+		return repo, database, images, docs
+	}
+
+	tests := []struct {
+		name string
+		// pairs 文件组路径（空字符串表示没有文件组）和文件名
+		pairs       [][2]string
+		storageMode enum.FileStorageMode
+		wantOk      bool
+		wantLocal   []string
+		wantCos     []string
+	}{
+		{
+			name:        "只删除指定文件组下的文件",
+			pairs:       [][2]string{{"images", "a.png"}},
+			storageMode: local,
+			wantOk:      true,
+			wantLocal:   []string{"a.png", "b.png", "a.png", "c.png"},
+			wantCos:     []string{"a.png"},
+		},
+		{
+			name:        "同时删除有文件组和没有文件组的文件",
+			pairs:       [][2]string{{"images", "b.png"}, {"docs", "a.png"}, {"", "a.png"}},
+			storageMode: local,
+			wantOk:      true,
+			wantLocal:   []string{"a.png", "c.png"},
+			wantCos:     []string{"a.png"},
+		},
+		{
+			name:        "只删除指定存储方式的文件",
+			pairs:       [][2]string{{"", "a.png"}},
+			storageMode: cos,
+			wantOk:      true,
+			wantLocal:   []string{"a.png", "a.png", "b.png", "a.png", "c.png"},
+			wantCos:     []string{},
+		},
+		{
+			name:        "文件组和文件名不匹配",
+			pairs:       [][2]string{{"docs", "b.png"}},
+			storageMode: local,
+			wantOk:      false,
+			wantLocal:   []string{"a.png", "a.png", "b.png", "a.png", "c.png"},
+			wantCos:     []string{"a.png"},
+		},
+		{
+			name:        "空数组",
+			storageMode: local,
+			wantOk:      false,
+			wantLocal:   []string{"a.png", "a.png", "b.png", "a.png", "c.png"},
+			wantCos:     []string{"a.png"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, database, images, docs := prepare(t)
+			groups := map[string]*uint{"": nil, "images": &images.FileGroupId, "docs": &docs.FileGroupId}
+
+			var pairs []*models.Pair[*uint, string]
+			for _, p := range tt.pairs {
+				pairs = append(pairs, &models.Pair[*uint, string]{First: groups[p[0]], Second: p[1]})
+			}
+
+			ok, err := repo.DeleteFileByGroupIdAndName(ctx, pairs, tt.storageMode)
+			if err != nil || ok != tt.wantOk {
+				t.Fatalf("DeleteFileByGroupIdAndName = %v, %v, want %v", ok, err, tt.wantOk)
+			}
+			assertStrings(t, util.DefaultEmptySlice(fileNames(t, database, local)), tt.wantLocal)
+			assertStrings(t, util.DefaultEmptySlice(fileNames(t, database, cos)), tt.wantCos)
+		})
+	}
+
+	failures := []struct {
+		name string
+		// withGroup 有文件组的文件数量
+		withGroup int
+		// withoutGroup 没有文件组的文件数量
+		withoutGroup int
+		skip         int
+	}{
+		{"删除有文件组的文件失败", 1, 1, 0},
+		{"删除没有文件组的文件失败", 1, 1, 1},
+		// 每批最多 500 条，第二批失败时第一批也要回滚
+		{"分批删除时第二批失败", 501, 0, 1},
+	}
+
+	for _, tt := range failures {
+		t.Run(tt.name+"时回滚", func(t *testing.T) {
+			repo, f, database := newTestFileRepo(t)
+			group := f.FileGroup("images", local)
+
+			var files []*models.File
+			var pairs []*models.Pair[*uint, string]
+			for i := 0; i < tt.withGroup; i++ {
+				name := fmt.Sprintf("g%d.png", i)
+				files = append(files, &models.File{FileGroupId: &group.FileGroupId, DisplayName: name, StorageMode: local})
+				pairs = append(pairs, &models.Pair[*uint, string]{First: &group.FileGroupId, Second: name})
+			}
+			for i := 0; i < tt.withoutGroup; i++ {
+				name := fmt.Sprintf("n%d.png", i)
+				files = append(files, &models.File{DisplayName: name, StorageMode: local})
+				pairs = append(pairs, &models.Pair[*uint, string]{First: nil, Second: name})
+			}
+			if err := database.CreateInBatches(files, 200).Error; err != nil {
+				t.Fatalf("create files: %v", err)
+			}
+
+			testutil.InjectError(t, database, testutil.OpDelete, "file", tt.skip)
+
+			if _, err := repo.DeleteFileByGroupIdAndName(ctx, pairs, local); !errors.Is(err, testutil.ErrInjected) {
+				t.Fatalf("DeleteFileByGroupIdAndName err = %v, want injected error", err)
+			}
+			if n := countRows(t, database, &models.File{}, "1 = 1"); n != int64(len(files)) {
+				t.Errorf("file rows = %d, want %d", n, len(files))
+			}
+		})
+	}
+}
+
+func TestFileRepo_StorageConfig(t *testing.T) {
+	ctx := context.Background()
+	repo, f, database := newTestFileRepo(t)
+
+	if config, err := repo.GetFileStorageConfig(ctx, enum.FileStorageModeLocal); config != nil || err != nil {
+		t.Fatalf("GetFileStorageConfig(empty) = %v, %v", config, err)
+	}
+
+	// 新增和修改配置
+	for _, value := range []string{"{}", `{"path":"/data"}`} {
+		if ok, err := repo.SetFileStorageConfig(ctx, enum.FileStorageModeLocal, value); err != nil || !ok {
+			t.Fatalf("SetFileStorageConfig = %v, %v", ok, err)
+		}
+		config, err := repo.GetFileStorageConfig(ctx, enum.FileStorageModeLocal)
+		if err != nil || config == nil || *config != value {
+			t.Fatalf("GetFileStorageConfig = %v, %v, want %s", config, err, value)
+		}
+	}
+
+	if _, err := repo.SetFileStorageConfig(ctx, enum.FileStorageModeTencentCOS, "{}"); err != nil {
+		t.Fatalf("SetFileStorageConfig: %v", err)
+	}
+	modes, err := repo.GetModes(ctx)
+	if err != nil || len(modes) != 2 {
+		t.Fatalf("GetModes = %v, %v", modes, err)
+	}
+
+	// 删除存储方式时同时删除对应的文件组
+	f.FileGroup("images", enum.FileStorageModeLocal)
+	f.FileGroup("cos", enum.FileStorageModeTencentCOS)
+	if ok, err := repo.DeleteFileStorageConfig(ctx, enum.FileStorageModeLocal); err != nil || !ok {
+		t.Fatalf("DeleteFileStorageConfig = %v, %v", ok, err)
+	}
+	if n := countRows(t, database, &models.FileGroup{}, "1 = 1"); n != 1 {
+		t.Errorf("file_group rows = %d, want 1", n)
+	}
+
+	t.Run("删除存储方式失败时回滚", func(t *testing.T) {
+		testutil.InjectError(t, database, testutil.OpDelete, "file_storage_mode", 0)
+		if _, err := repo.DeleteFileStorageConfig(ctx, enum.FileStorageModeTencentCOS); !errors.Is(err, testutil.ErrInjected) {
+			t.Fatalf("DeleteFileStorageConfig err = %v, want injected error", err)
+		}
+		if n := countRows(t, database, &models.FileGroup{}, "1 = 1"); n != 1 {
+			t.Errorf("file_group rows = %d, want 1", n)
+		}
+	})
+}
+
+func TestFileRepo_FileGroup(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestFileRepo(t)
+
+	group, err := repo.AddFileGroup(ctx, request.FileGroupAddRequest{DisplayName: "图片", Path: "/images", StorageMode: enum.FileStorageModeLocal})
+	if err != nil || group.FileGroupId == 0 {
+		t.Fatalf("AddFileGroup = %+v, %v", group, err)
+	}
+	f.FileGroup("/cos", enum.FileStorageModeTencentCOS)
+
+	if ok, err := repo.UpdateFileGroup(ctx, request.FileGroupUpdateRequest{FileGroupId: group.FileGroupId, DisplayName: "相册"}); err != nil || !ok {
+		t.Fatalf("UpdateFileGroup = %v, %v", ok, err)
+	}
+	got, err := repo.GetFileGroupById(ctx, group.FileGroupId)
+	if err != nil || got.DisplayName != "相册" || got.Path != "/images" {
+		t.Fatalf("GetFileGroupById = %+v, %v", got, err)
+	}
+	if missing, err := repo.GetFileGroupById(ctx, 9999); missing != nil || err != nil {
+		t.Errorf("GetFileGroupById(missing) = %v, %v", missing, err)
+	}
+
+	byPath, err := repo.GetFileGroupsByPath(ctx, []string{"/images", "/missing"})
+	if err != nil || len(byPath) != 1 {
+		t.Errorf("GetFileGroupsByPath = %v, %v", byPath, err)
+	}
+
+	modeTests := []struct {
+		name string
+		mode *enum.FileStorageMode
+		want int
+	}{
+		{"所有存储方式", nil, 2},
+		{"本地存储", enum.FileStorageModePtr(enum.FileStorageModeLocal), 1},
+	}
+	for _, tt := range modeTests {
+		t.Run("GetFileGroupsByMode/"+tt.name, func(t *testing.T) {
+			groups, err := repo.GetFileGroupsByMode(ctx, tt.mode)
+			if err != nil || len(groups) != tt.want {
+				t.Errorf("GetFileGroupsByMode = %d, %v, want %d", len(groups), err, tt.want)
+			}
+		})
+	}
+
+	if ok, err := repo.DeleteFileGroup(ctx, group.FileGroupId); err != nil || !ok {
+		t.Fatalf("DeleteFileGroup = %v, %v", ok, err)
+	}
+	if ok, _ := repo.DeleteFileGroup(ctx, group.FileGroupId); ok {
+		t.Error("DeleteFileGroup twice should return false")
+	}
+}
+
+func TestFileRepo_File(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestFileRepo(t)
+	local := enum.FileStorageModeLocal
+	group := f.FileGroup("images", local)
+
+	a, err := repo.AddFile(ctx, models.File{DisplayName: "a.png", FileGroupId: &group.FileGroupId, Size: 10, StorageMode: local, CreateTime: f.Now()})
+	if err != nil || a.FileId == 0 {
+		t.Fatalf("AddFile = %+v, %v", a, err)
+	}
+	b, _ := repo.AddFile(ctx, models.File{DisplayName: "b.txt", Size: 30, StorageMode: local, CreateTime: f.Now()})
+	c, _ := repo.AddFile(ctx, models.File{DisplayName: "c_1.png", Size: 20, StorageMode: enum.FileStorageModeTencentCOS, CreateTime: f.Now()})
+
+	countTests := []struct {
+		name string
+		fn   func() (int64, error)
+		want int64
+	}{
+		{"GetFileCount", func() (int64, error) { return repo.GetFileCount(ctx) }, 3},
+		{"GetFileCountByMode", func() (int64, error) { return repo.GetFileCountByMode(ctx, local) }, 2},
+		{"GetFileCountByGroup", func() (int64, error) { return repo.GetFileCountByGroup(ctx, group.FileGroupId) }, 1},
+	}
+	for _, tt := range countTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := tt.fn(); err != nil || got != tt.want {
+				t.Errorf("%s = %d, %v, want %d", tt.name, got, err, tt.want)
+			}
+		})
+	}
+
+	getTests := []struct {
+		name     string
+		fileName string
+		groupId  *uint
+		mode     enum.FileStorageMode
+		wantId   uint
+	}{
+		{"文件组和文件名匹配", "a.png", &group.FileGroupId, local, a.FileId},
+		{"不限定文件组", "b.txt", nil, local, b.FileId},
+		{"存储方式不匹配", "c_1.png", nil, local, 0},
+	}
+	for _, tt := range getTests {
+		t.Run("GetFile/"+tt.name, func(t *testing.T) {
+			got, err := repo.GetFile(ctx, tt.fileName, tt.groupId, tt.mode)
+			if err != nil {
+				t.Fatalf("GetFile: %v", err)
+			}
+			if (got == nil && tt.wantId != 0) || (got != nil && got.FileId != tt.wantId) {
+				t.Errorf("GetFile = %+v, want id %d", got, tt.wantId)
+			}
+		})
+	}
+
+	withGroup, err := repo.GetFileWithGroupByIds(ctx, []uint{a.FileId, b.FileId})
+	if err != nil || len(withGroup) != 2 {
+		t.Fatalf("GetFileWithGroupByIds = %v, %v", withGroup, err)
+	}
+	for _, fg := range withGroup {
+		if fg.FileId == a.FileId && (fg.FileName != "a.png" || fg.FileGroupPath == nil || *fg.FileGroupPath != "images") {
+			t.Errorf("unexpected file with group: %+v", fg)
+		}
+		if fg.FileId == b.FileId && (fg.FileGroupId != nil || fg.FileGroupName != nil || fg.StorageMode != local) {
+			t.Errorf("unexpected file without group: %+v", fg)
+		}
+	}
+
+	pagerTests := []struct {
+		name       string
+		page, size int
+		sort       *enum.FileSort
+		mode       *enum.FileStorageMode
+		groupId    *uint
+		key        *string
+		want       []string
+	}{
+		{name: "按大小降序", sort: enum.FileSortPtr(enum.FileSortSizeDesc), want: []string{"b.txt", "c_1.png", "a.png"}},
+		{name: "分页", page: 2, size: 2, sort: enum.FileSortPtr(enum.FileSortCreateTimeAsc), want: []string{"c_1.png"}},
+		{name: "存储方式", mode: &local, sort: enum.FileSortPtr(enum.FileSortSizeAsc), want: []string{"a.png", "b.txt"}},
+		{name: "文件组", groupId: &group.FileGroupId, want: []string{"a.png"}},
+		{name: "关键字", key: util.StringPtr(".PNG"), sort: enum.FileSortPtr(enum.FileSortSizeAsc), want: []string{"a.png", "c_1.png"}},
+		{name: "关键字转义通配符", key: util.StringPtr("c_"), want: []string{"c_1.png"}},
+	}
+	for _, tt := range pagerTests {
+		t.Run("GetFileWithGroups/"+tt.name, func(t *testing.T) {
+			pager, err := repo.GetFileWithGroups(ctx, tt.page, tt.size, tt.sort, tt.mode, tt.groupId, tt.key)
+			if err != nil {
+				t.Fatalf("GetFileWithGroups: %v", err)
+			}
+			got := make([]string, len(pager.Data))
+			for i, fg := range pager.Data {
+				got[i] = fg.FileName
+			}
+			assertStrings(t, got, tt.want)
+		})
+	}
+
+	// 移动文件
+	a.FileGroupId = nil
+	if ok, err := repo.UpdateFile(ctx, *a); err != nil || !ok {
+		t.Fatalf("UpdateFile = %v, %v", ok, err)
+	}
+	b.DisplayName, c.DisplayName = "b2.txt", "c2.png"
+	if ok, err := repo.UpdateFiles(ctx, []models.File{*b, *c}); err != nil || !ok {
+		t.Fatalf("UpdateFiles = %v, %v", ok, err)
+	}
+
+	files, err := repo.GetFileByIds(ctx, []uint{a.FileId, b.FileId, c.FileId})
+	if err != nil || len(files) != 3 {
+		t.Fatalf("GetFileByIds = %v, %v", files, err)
+	}
+	for _, file := range files {
+		if file.FileId == a.FileId && file.FileGroupId != nil {
+			t.Errorf("file a should have no group: %+v", file)
+		}
+		if file.FileId == c.FileId && file.DisplayName != "c2.png" {
+			t.Errorf("file c = %+v, want c2.png", file)
+		}
+	}
+
+	t.Run("批量修改失败时回滚", func(t *testing.T) {
+		_, _, database := newTestFileRepo(t)
+		repo := NewFileRepo(database)
+		f := testutil.NewFixture(t, database)
+		x, y := f.File("x", nil, local), f.File("y", nil, local)
+		testutil.InjectError(t, database, testutil.OpUpdate, "file", 1)
+
+		x.DisplayName, y.DisplayName = "x2", "y2"
+		if _, err := repo.UpdateFiles(ctx, []models.File{*x, *y}); !errors.Is(err, testutil.ErrInjected) {
+			t.Fatalf("UpdateFiles err = %v, want injected error", err)
+		}
+		assertStrings(t, fileNames(t, database, local), []string{"x", "y"})
+	})
+
+	if ok, err := repo.DeleteFileById(ctx, a.FileId); err != nil || !ok {
+		t.Fatalf("DeleteFileById = %v, %v", ok, err)
+	}
+	if ok, err := repo.DeleteFileByIds(ctx, []uint{b.FileId, c.FileId}); err != nil || !ok {
+		t.Fatalf("DeleteFileByIds = %v, %v", ok, err)
+	}
+	if count, _ := repo.GetFileCount(ctx); count != 0 {
+		t.Errorf("GetFileCount = %d, want 0", count)
+	}
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// countRows 获取表中满足条件的行数
+func countRows(t *testing.T, db *gorm.DB, model any, query string, args ...any) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(model).Where(query, args...).Count(&count).Error; err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	return count
+}
+
+// assertStrings 断言字符串切片内容及顺序
+func assertStrings(t *testing.T, got, want []string) {
+	t.Helper()
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+)
+
+// linkNames 获取友情链接名称数组
+func linkNames(links []*models.Link) []string {
+	names := make([]string, len(links))
+	for i, link := range links {
+		names[i] = link.DisplayName
+	}
+	return names
+}
+
+func TestLinkRepo(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	repo := NewLinkRepository(database)
+	f := testutil.NewFixture(t, database)
+
+	a, err := repo.AddLink(ctx, &models.Link{DisplayName: "a", Url: "https://a.com", Priority: 1, Logo: util.StringPtr(" "), CreateTime: f.Now()})
+	if err != nil || a.LinkId == 0 || a.Logo != nil {
+		t.Fatalf("AddLink = %+v, %v", a, err)
+	}
+	b, _ := repo.AddLink(ctx, &models.Link{DisplayName: "b", Url: "https://b.com", Priority: 3, CreateTime: f.Now()})
+	c, _ := repo.AddLink(ctx, &models.Link{DisplayName: "c", Url: "https://c.com", Priority: 2, CreateTime: f.Now()})
+
+	if count, err := repo.LinkCount(ctx); err != nil || count != 3 {
+		t.Errorf("LinkCount = %d, %v, want 3", count, err)
+	}
+
+	ok, err := repo.UpdateLink(ctx, &request.LinkRequest{
+		LinkId: &a.LinkId, DisplayName: "a2", Url: "https://a2.com", Priority: 1,
+		Description: util.StringPtr("desc"), Remark: util.StringPtr(""), IsLost: true,
+	})
+	if err != nil || !ok {
+		t.Fatalf("UpdateLink = %v, %v", ok, err)
+	}
+
+	sortTests := []struct {
+		name string
+		sort *enum.LinkSort
+		want []string
+	}{
+		{"默认优先级降序", nil, []string{"b", "c", "a2"}},
+		{"优先级升序", enum.LinkSortPtr(enum.LinkSortPriorityAsc), []string{"a2", "c", "b"}},
+		{"创建时间降序", enum.LinkSortPtr(enum.LinkSortCreateTimeDesc), []string{"c", "b", "a2"}},
+		{"创建时间升序", enum.LinkSortPtr(enum.LinkSortCreateTimeAsc), []string{"a2", "b", "c"}},
+	}
+	for _, tt := range sortTests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, err := repo.Links(ctx, tt.sort)
+			if err != nil {
+				t.Fatalf("Links: %v", err)
+			}
+			assertStrings(t, linkNames(links), tt.want)
+		})
+	}
+
+	links, _ := repo.Links(ctx, enum.LinkSortPtr(enum.LinkSortModifyTimeDesc))
+	updated := util.Find(links, func(l *models.Link) bool { return l.LinkId == a.LinkId })
+	if updated == nil || !(*updated).IsLost || (*updated).LastModifyTime == nil || (*updated).Description == nil || (*updated).Remark != nil {
+		t.Errorf("updated link = %+v", updated)
+	}
+
+	pager, err := repo.LinksPager(ctx, 2, 2, nil)
+	if err != nil || pager.TotalData != 3 {
+		t.Fatalf("LinksPager = %+v, %v", pager, err)
+	}
+	assertStrings(t, linkNames(pager.Data), []string{"a2"})
+
+	if ok, _ := repo.DeleteLinks(ctx, nil); ok {
+		t.Error("DeleteLinks(nil) should return false")
+	}
+	if ok, err := repo.DeleteLinks(ctx, []uint{b.LinkId, c.LinkId}); err != nil || !ok {
+		t.Fatalf("DeleteLinks = %v, %v", ok, err)
+	}
+	all, _ := repo.LinksPager(ctx, 0, 0, nil)
+	assertStrings(t, linkNames(all.Data), []string{"a2"})
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MailUnsubscribeRepository 退订邮件通知 Repo 接口
+type MailUnsubscribeRepository interface {
+	// AddUnsubscribe 退订邮件通知，已退订时忽略
+	AddUnsubscribe(ctx context.Context, email string) error
+	// IsUnsubscribed 邮箱是否已退订邮件通知
+	IsUnsubscribed(ctx context.Context, email string) (bool, error)
+}
+
+type mailUnsubscribeRepo struct {
+	db *gorm.DB
+}
+
+func NewMailUnsubscribeRepository(db *gorm.DB) MailUnsubscribeRepository {
+	return &mailUnsubscribeRepo{
+		db: db,
+	}
+}
+
+// AddUnsubscribe 退订邮件通知，邮箱不区分大小写
+func (r *mailUnsubscribeRepo) AddUnsubscribe(ctx context.Context, email string) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "email"}}, DoNothing: true}).
+		Create(&models.MailUnsubscribe{Email: strings.ToLower(email)}).Error
+}
+
+// IsUnsubscribed 邮箱是否已退订邮件通知，邮箱不区分大小写
+func (r *mailUnsubscribeRepo) IsUnsubscribed(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.MailUnsubscribe{}).
+		Where("email = ?", strings.ToLower(email)).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -84,7 +84,7 @@ func (r *menuRepo) AddMenu(ctx context.Context, menu *request.MenuRequest) (*mod
 
 	if menu.IsMain && ret.RowsAffected > 0 {
 		// 当前菜单被设为了主菜单，将其他所有菜单设为非主菜单
-		_, err := r.setMainMenu(ctx, add.MenuId)
+		_, err := r.setMainMenu(tx, add.MenuId)
 		if err != nil {
 			tx.Rollback()
 			return nil, err
@@ -107,10 +107,10 @@ func (r *menuRepo) DeleteMenus(ctx context.Context, menuIds []uint) (bool, error
 	}()
 
 	// 先删除被删除的菜单下的菜单项
-	err := tx.Where("parent_menuId IN ?", menuIds).Delete(&models.MenuItem{}).Error
+	err := tx.Where("? IN ?", db.Column("parent_menuId"), menuIds).Delete(&models.MenuItem{}).Error
 	if err != nil {
 		tx.Rollback()
-		return false, nil
+		return false, err
 	}
 
 	// 删除菜单
@@ -161,7 +161,7 @@ func (r *menuRepo) UpdateMenu(ctx context.Context, menu *request.MenuRequest) (b
 
 	if menu.IsMain && updateRet.RowsAffected > 0 {
 		// 当前菜单被设为了主菜单，将其他所有菜单设为非主菜单
-		_, err := r.setMainMenu(ctx, *menu.MenuId)
+		_, err := r.setMainMenu(tx, *menu.MenuId)
 		if err != nil {
 			tx.Rollback()
 			return false, err
@@ -238,14 +238,11 @@ func (r *menuRepo) AddMenuItem(ctx context.Context, menuItem *request.MenuItemRe
 		Href:             menuItem.Href,
 		ParentMenuId:     &menuItem.ParentMenuId,
 		ParentMenuItemId: menuItem.ParentMenuItemId,
+		Target:           *util.DefaultPtr(menuItem.Target, enum.MenuTargetBlank),
 		Index:            menuItem.Index,
 		CreateTime:       time.Now().UnixMilli(),
 	}
 
-	if menuItem.Target == nil {
-		add.Target = enum.MenuTargetBlank
-	}
-
 	err = r.db.WithContext(ctx).Create(add).Error
 	if err != nil {
 		return nil, err
@@ -322,8 +319,8 @@ func (r *menuRepo) MenuItemsByMenuId(ctx context.Context, menuId uint) ([]*model
 
 	err := r.db.WithContext(ctx).
 		Model(&models.MenuItem{}).
-		Where("parent_menuId = ?", menuId).
-		Order("`index` ASC").
+		Where("? = ?", db.Column("parent_menuId"), menuId).
+		Order(db.OrderBy("index", false)).
 		Find(&menuItems).Error
 
 	if err != nil {
@@ -337,7 +334,7 @@ func (r *menuRepo) MenuItems(ctx context.Context) ([]*models.MenuItem, error) {
 	var menuItems []*models.MenuItem
 	err := r.db.WithContext(ctx).
 		Model(&models.MenuItem{}).
-		Order("`index` ASC").
+		Order(db.OrderBy("index", false)).
 		Find(&menuItems).Error
 	if err != nil {
 		return nil, err
@@ -350,9 +347,10 @@ func (r *menuRepo) MainMenuItems(ctx context.Context) ([]*models.MenuItem, error
 	var menuItems []*models.MenuItem
 	err := r.db.WithContext(ctx).
 		Table("menu_item mt").
-		Joins("LEFT JOIN menu m ON m.menu_id = mt.parent_menuId").
-		Where("m.is_main = 1").
-		Order("mt.index ASC").
+		Select("mt.*").
+		Joins("LEFT JOIN menu m ON m.menu_id = ?", db.Column("mt.parent_menuId")).
+		Where("m.is_main = ?", true).
+		Order(db.OrderBy("mt.index", false)).
 		Find(&menuItems).Error
 
 	if err != nil {
@@ -376,22 +374,22 @@ func (r *menuRepo) MainMenuItemCount(ctx context.Context) (int64, error) {
 	// 先获取主菜单 ID
 	var mainMenu *models.Menu
 	err := r.db.WithContext(ctx).
-		Where("is_main = 1").
+		Where("is_main = ?", true).
 		Model(&models.Menu{}).
 		First(&mainMenu).Error
 
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 还没有设置主菜单
+			return 0, nil
+		}
 		return 0, err
 	}
 
-	if mainMenu == nil {
-		return 0, nil
-	}
-
 	// 获取主菜单的菜单项数量
 	var count int64
 	err = r.db.WithContext(ctx).
-		Where("parent_menuId = ?", mainMenu.MenuId).
+		Where("? = ?", db.Column("parent_menuId"), mainMenu.MenuId).
 		Model(&models.MenuItem{}).
 		Count(&count).Error
 
@@ -403,10 +401,10 @@ func (r *menuRepo) MainMenuItemCount(ctx context.Context) (int64, error) {
 }
 
 // setMainMenu 将非此菜单 ID 的菜单设置为非主菜单
-//   - ctx: 上下文
+//   - tx: 添加或修改菜单所在的事务
 //   - menuId: 菜单 ID（此菜单外的所有菜单都会被设为非主菜单，即 isMain = false）
-func (r *menuRepo) setMainMenu(ctx context.Context, menuId uint) (int, error) {
-	ret := r.db.WithContext(ctx).
+func (r *menuRepo) setMainMenu(tx *gorm.DB, menuId uint) (int, error) {
+	ret := tx.
 		Model(&models.Menu{}).
 		Where("menu_id != ?", menuId).
 		Update("is_main", false)
@@ -442,19 +440,29 @@ func (r *menuRepo) sqlUpdateMenuItemIndex(
 	}()
 
 	// 获取所有同级菜单
-	var sameLevelMenuItems []*models.MenuItem
-	err := tx.WithContext(ctx).
+	query := tx.WithContext(ctx).
 		Model(&models.MenuItem{}).
-		Where("parent_menuId = ? AND parent_menu_item_id = ?",
-			newMenuItem.ParentMenuId, newMenuItem.ParentMenuItemId).
-		Order("`index` ASC").
+		Where("? = ?", db.Column("parent_menuId"), newMenuItem.ParentMenuId)
+
+	if newMenuItem.ParentMenuItemId == nil {
+		// 一级菜单项，parent_menu_item_id = NULL 不会匹配任何行
+		query = query.Where("parent_menu_item_id IS NULL")
+	} else {
+		query = query.Where("parent_menu_item_id = ?", *newMenuItem.ParentMenuItemId)
+	}
+
+	var sameLevelMenuItems []*models.MenuItem
+	err := query.
+		Order(db.OrderBy("index", false)).
 		Find(&sameLevelMenuItems).Error
 
 	if err != nil {
+		tx.Rollback()
 		return err
 	}
 
 	if len(sameLevelMenuItems) == 0 {
+		tx.Rollback()
 		return nil
 	}
 
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/testutil"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// newTestMenuRepo 创建菜单 Repo 测试环境
+func newTestMenuRepo(t *testing.T) (MenuRepository, *gorm.DB) {
+	database := testutil.NewDB(t)
+	return NewMenuRepository(database), database
+}
+
+// menuItemNames 获取菜单项名称数组
+func menuItemNames(items []*models.MenuItem) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.DisplayName
+	}
+	return names
+}
+
+// mainMenuNames 获取所有主菜单名称
+func mainMenuNames(t *testing.T, repo MenuRepository) []string {
+	t.Helper()
+	menus, err := repo.Menus(context.Background())
+	if err != nil {
+		t.Fatalf("Menus: %v", err)
+	}
+	var names []string
+	for _, menu := range menus {
+		if menu.IsMain {
+			names = append(names, menu.DisplayName)
+		}
+	}
+	return names
+}
+
+func TestMenuRepo_Menu(t *testing.T) {
+	ctx := context.Background()
+	repo, database := newTestMenuRepo(t)
+
+	if count, err := repo.MainMenuItemCount(ctx); err != nil || count != 0 {
+		t.Fatalf("MainMenuItemCount(no main menu) = %d, %v", count, err)
+	}
+
+	a, err := repo.AddMenu(ctx, &request.MenuRequest{DisplayName: "a", IsMain: true})
+	if err != nil {
+		t.Fatalf("AddMenu: %v", err)
+	}
+	b, err := repo.AddMenu(ctx, &request.MenuRequest{DisplayName: "b", IsMain: true})
+	if err != nil {
+		t.Fatalf("AddMenu: %v", err)
+	}
+	// 新的主菜单会取消原来的主菜单
+	assertStrings(t, mainMenuNames(t, repo), []string{"b"})
+
+	if ok, err := repo.UpdateMenu(ctx, &request.MenuRequest{MenuId: &a.MenuId, DisplayName: "a2", IsMain: true}); err != nil || !ok {
+		t.Fatalf("UpdateMenu = %v, %v", ok, err)
+	}
+	assertStrings(t, mainMenuNames(t, repo), []string{"a2"})
+
+	if ok, _ := repo.UpdateMenu(ctx, &request.MenuRequest{DisplayName: "x"}); ok {
+		t.Error("UpdateMenu without id should return false")
+	}
+
+	t.Run("设置主菜单失败时回滚", func(t *testing.T) {
+		// 第一次修改是菜单本身，第二次是取消其他主菜单
+		testutil.InjectError(t, database, testutil.OpUpdate, "menu", 1)
+		if _, err := repo.UpdateMenu(ctx, &request.MenuRequest{MenuId: &b.MenuId, DisplayName: "b2", IsMain: true}); !errors.Is(err, testutil.ErrInjected) {
+			t.Fatalf("UpdateMenu err = %v, want injected error", err)
+		}
+		assertStrings(t, mainMenuNames(t, repo), []string{"a2"})
+		if menu, _ := repo.MenuById(ctx, b.MenuId); menu.DisplayName != "b" {
+			t.Errorf("menu b = %+v, want unchanged", menu)
+		}
+	})
+
+	if menu, err := repo.MenuByDisplayName(ctx, "a2"); err != nil || menu == nil || menu.MenuId != a.MenuId {
+		t.Errorf("MenuByDisplayName = %+v, %v", menu, err)
+	}
+	if menu, err := repo.MenuById(ctx, 9999); menu != nil || err != nil {
+		t.Errorf("MenuById(missing) = %+v, %v", menu, err)
+	}
+	if count, err := repo.MenuCount(ctx); err != nil || count != 2 {
+		t.Errorf("MenuCount = %d, %v, want 2", count, err)
+	}
+	pager, err := repo.MenusPager(ctx, 1, 1)
+	if err != nil || pager.TotalData != 2 || len(pager.Data) != 1 {
+		t.Errorf("MenusPager = %+v, %v", pager, err)
+	}
+
+	if _, err := repo.AddMenuItem(ctx, &request.MenuItemRequest{DisplayName: "item", Href: "/", ParentMenuId: a.MenuId}); err != nil {
+		t.Fatalf("AddMenuItem: %v", err)
+	}
+	if ok, err := repo.DeleteMenus(ctx, []uint{a.MenuId}); err != nil || !ok {
+		t.Fatalf("DeleteMenus = %v, %v", ok, err)
+	}
+	if items, _ := repo.MenuItems(ctx); len(items) != 0 {
+		t.Errorf("menu items of deleted menu = %v", menuItemNames(items))
+	}
+}
+
+func TestMenuRepo_MenuItem(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestMenuRepo(t)
+
+	main, _ := repo.AddMenu(ctx, &request.MenuRequest{DisplayName: "main", IsMain: true})
+	other, _ := repo.AddMenu(ctx, &request.MenuRequest{DisplayName: "other"})
+
+	add := func(name string, menuId uint, parent *uint, index uint, target *enum.MenuTarget) *models.MenuItem {
+		t.Helper()
+		item, err := repo.AddMenuItem(ctx, &request.MenuItemRequest{
+			DisplayName: name, Href: "/" + name, Target: target,
+			ParentMenuId: menuId, ParentMenuItemId: parent, Index: index,
+		})
+		if err != nil {
+			t.Fatalf("AddMenuItem(%s): %v", name, err)
+		}
+		return item
+	}
+
+	a := add("a", main.MenuId, nil, 0, nil)
+	add("b", main.MenuId, nil, 1, enum.MenuTargetPtr(enum.MenuTargetSelf))
+	// 插入到 a 前面，同级菜单项依次后移
+	add("c", main.MenuId, nil, 0, nil)
+	// 子菜单项和其他菜单的菜单项不影响一级菜单项的顺序
+	add("a1", main.MenuId, &a.MenuItemId, 0, nil)
+	add("x", other.MenuId, nil, 0, nil)
+
+	items, err := repo.MenuItemsByMenuId(ctx, main.MenuId)
+	if err != nil {
+		t.Fatalf("MenuItemsByMenuId: %v", err)
+	}
+	topLevel := make([]*models.MenuItem, 0)
+	for _, item := range items {
+		if item.ParentMenuItemId == nil {
+			topLevel = append(topLevel, item)
+		}
+	}
+	assertStrings(t, menuItemNames(topLevel), []string{"c", "a", "b"})
+
+	targetTests := []struct {
+		name string
+		want enum.MenuTarget
+	}{
+		{"a", enum.MenuTargetBlank},
+		{"b", enum.MenuTargetSelf},
+	}
+	for _, tt := range targetTests {
+		t.Run("Target/"+tt.name, func(t *testing.T) {
+			for _, item := range items {
+				if item.DisplayName == tt.name && item.Target != tt.want {
+					t.Errorf("Target = %s, want %s", item.Target, tt.want)
+				}
+			}
+		})
+	}
+
+	// 将 b 移到最前面
+	b := topLevel[2]
+	ok, err := repo.UpdateMenuItem(ctx, &request.MenuItemRequest{
+		MenuItemId: &b.MenuItemId, DisplayName: "b2", Href: "/b2", ParentMenuId: main.MenuId, Index: 0,
+	})
+	if err != nil || !ok {
+		t.Fatalf("UpdateMenuItem = %v, %v", ok, err)
+	}
+	got, err := repo.MenuItemById(ctx, b.MenuItemId)
+	if err != nil || got.DisplayName != "b2" || got.Target != enum.MenuTargetBlank {
+		t.Fatalf("MenuItemById = %+v, %v", got, err)
+	}
+
+	mainItems, err := repo.MainMenuItems(ctx)
+	if err != nil {
+		t.Fatalf("MainMenuItems: %v", err)
+	}
+	assertStrings(t, menuItemNames(mainItems), []string{"b2", "a1", "c", "a"})
+
+	if count, err := repo.MainMenuItemCount(ctx); err != nil || count != 4 {
+		t.Errorf("MainMenuItemCount = %d, %v, want 4", count, err)
+	}
+
+	if ok, _ := repo.DeleteMenuItems(ctx, nil); ok {
+		t.Error("DeleteMenuItems(nil) should return false")
+	}
+	if ok, err := repo.DeleteMenuItems(ctx, []uint{a.MenuItemId}); err != nil || !ok {
+		t.Fatalf("DeleteMenuItems = %v, %v", ok, err)
+	}
+	if item, err := repo.MenuItemById(ctx, a.MenuItemId); item != nil || err != nil {
+		t.Errorf("MenuItemById(deleted) = %+v, %v", item, err)
+	}
+	all, _ := repo.MenuItems(ctx)
+	if len(all) != 4 {
+		t.Errorf("MenuItems = %v", menuItemNames(all))
+	}
+}
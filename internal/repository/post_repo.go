@@ -9,6 +9,7 @@ import (
 	"nola-go/internal/models/enum"
 	"nola-go/internal/models/request"
 	"nola-go/internal/models/response"
+	"nola-go/internal/password"
 	"nola-go/internal/util"
 	"time"
 
@@ -67,7 +68,17 @@ type PostRepository interface {
 		categoryId *uint,
 		tag *string,
 		category *string,
+		sort *enum.PostSort,
 	) (*models.Pager[response.PostApiResponse], error)
+	// PostApiByIds 根据文章 ID 数组获取已发布、可见的文章（博客前端），用于搜索结果
+	PostApiByIds(
+		ctx context.Context,
+		ids []uint,
+		tagId *uint,
+		categoryId *uint,
+		tag *string,
+		category *string,
+	) ([]*response.PostResponse, error)
 	// PostContents 获取文章所有内容（包括正文和草稿）
 	PostContents(ctx context.Context, postId uint) ([]*response.PostContentResponse, error)
 	// PostContent 获取文章内容
@@ -104,22 +115,52 @@ type PostRepository interface {
 	MostViewedPost(ctx context.Context) (*response.PostResponse, error)
 	// PostVisitCount 文章总浏览量
 	PostVisitCount(ctx context.Context) (int64, error)
+	// ScheduledPosts 获取所有定时发布的文章（按发布时间升序）
+	ScheduledPosts(ctx context.Context) ([]*response.PostResponse, error)
+	// DueScheduledPostIds 获取已到发布时间的定时发布文章 ID
+	DueScheduledPostIds(ctx context.Context, now int64) ([]uint, error)
+	// PublishScheduledPost 发布已到发布时间的定时发布文章，文章已被发布或取消定时时返回 false
+	PublishScheduledPost(ctx context.Context, postId uint, now int64) (bool, error)
+	// ExpiredDeletedPostIds 获取在指定时间之前移入回收站的文章 ID
+	ExpiredDeletedPostIds(ctx context.Context, before int64) ([]uint, error)
+	// PublicPostLinks 获取所有公开文章（已发布、可见、未加密、允许搜索引擎索引）的别名和时间，用于站点地图
+	PublicPostLinks(ctx context.Context) ([]*models.Post, error)
 }
 
 type postRepo struct {
 	db           *gorm.DB
 	tagRepo      TagRepository
 	categoryRepo CategoryRepository
+	userRepo     UserRepository
+	hasher       password.Hasher
 }
 
-func NewPostRepository(db *gorm.DB, tagRepo TagRepository, categoryRepo CategoryRepository) PostRepository {
-	return &postRepo{db: db, tagRepo: tagRepo, categoryRepo: categoryRepo}
+func NewPostRepository(
+	db *gorm.DB,
+	tagRepo TagRepository,
+	categoryRepo CategoryRepository,
+	userRepo UserRepository,
+	hasher password.Hasher,
+) PostRepository {
+	return &postRepo{db: db, tagRepo: tagRepo, categoryRepo: categoryRepo, userRepo: userRepo, hasher: hasher}
 }
 
 // AddPost 添加文章
 func (r *postRepo) AddPost(ctx context.Context, req *request.PostRequest) (*models.Post, error) {
 	currentTime := time.Now().UnixMilli()
 
+	var pwd *string
+	if !util.StringIsNilOrBlank(req.Password) {
+		// 密码不为空
+		hash, err := r.hasher.Hash(*req.Password)
+		if err != nil {
+			return nil, err
+		}
+		pwd = &hash
+	} else {
+		pwd = nil
+	}
+
 	// 开启事务
 	tx := r.db.WithContext(ctx).Begin()
 	defer func() {
@@ -128,15 +169,8 @@ func (r *postRepo) AddPost(ctx context.Context, req *request.PostRequest) (*mode
 		}
 	}()
 
-	var pwd *string
-	if !util.StringIsNilOrBlank(req.Password) {
-		// 密码不为空
-		pwd = util.StringPtr(util.GenerateHash(*req.Password))
-	} else {
-		pwd = nil
-	}
-
 	post := models.Post{
+		AuthorId:            req.AuthorId,
 		Title:               req.Title,
 		AutoGenerateExcerpt: *req.AutoGenerateExcerpt,
 		Excerpt:             util.StringDefault(req.Excerpt, ""),
@@ -146,10 +180,19 @@ func (r *postRepo) AddPost(ctx context.Context, req *request.PostRequest) (*mode
 		Pinned:              req.Pinned,
 		Status:              req.Status,
 		Visible:             req.Visible,
+		PublishAt:           postPublishAt(req.Status, req.PublishAt),
 		Password:            pwd,
+		SeoTitle:            req.SeoTitle,
+		SeoDescription:      req.SeoDescription,
+		CanonicalURL:        req.CanonicalURL,
+		NoIndex:             req.NoIndex,
+		OgImage:             req.OgImage,
 		CreateTime:          currentTime,
 		LastModifyTime:      nil,
 	}
+	if req.Status == enum.PostStatusDeleted {
+		post.DeletedAt = util.Int64Ptr(currentTime)
+	}
 
 	// 插入文章
 	if err := tx.Model(&models.Post{}).Create(&post).Error; err != nil {
@@ -231,6 +274,20 @@ func (r *postRepo) DeletePostByIds(ctx context.Context, ids []uint) (success boo
 		return false, err
 	}
 
+	// 删除文章修订版本
+	err = tx.Where("post_id IN ?", ids).Delete(&models.PostRevision{}).Error
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// 删除文章评论
+	err = tx.Where("post_id IN ?", ids).Delete(&models.Comment{}).Error
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
 	// 删除文章分类
 	err = tx.Where("post_id IN ?", ids).Delete(&models.PostCategory{}).Error
 	if err != nil {
@@ -245,6 +302,13 @@ func (r *postRepo) DeletePostByIds(ctx context.Context, ids []uint) (success boo
 		return false, err
 	}
 
+	// 删除文章系列
+	err = tx.Where("post_id IN ?", ids).Delete(&models.PostSeries{}).Error
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
 	// 删除文章
 	ret := tx.Where("post_id IN ?", ids).Delete(&models.Post{})
 	if err := ret.Error; err != nil {
@@ -270,7 +334,11 @@ func (r *postRepo) UpdatePostStatusTo(ctx context.Context, ids []uint, status en
 		return false, nil
 	}
 
-	ret := r.db.WithContext(ctx).Model(&models.Post{}).Where("post_id IN ?", ids).Update("status", status)
+	ret := r.db.WithContext(ctx).Model(&models.Post{}).Where("post_id IN ?", ids).Updates(map[string]any{
+		"status":     status,
+		"publish_at": postPublishAt(status, nil),
+		"deleted_at": postDeletedAt(status),
+	})
 	if err := ret.Error; err != nil {
 		return false, err
 	}
@@ -285,6 +353,15 @@ func (r *postRepo) UpdatePost(ctx context.Context, req *request.PostRequest) (su
 		return false, errors.New("文章 ID 不能为 nil")
 	}
 
+	// 新密码哈希，在事务开始前生成，避免长时间占用事务
+	var pwd string
+	if !util.StringIsNilOrBlank(req.Password) && req.Encrypted != nil && *req.Encrypted == true {
+		pwd, err = r.hasher.Hash(*req.Password)
+		if err != nil {
+			return false, err
+		}
+	}
+
 	tx := r.db.WithContext(ctx).Begin()
 	defer handlePanic(tx)(&success, &err)
 
@@ -339,13 +416,20 @@ func (r *postRepo) UpdatePost(ctx context.Context, req *request.PostRequest) (su
 		"allow_comment":         req.AllowComment,
 		"status":                req.Status,
 		"visible":               req.Visible,
+		"publish_at":            postPublishAt(req.Status, req.PublishAt),
+		"deleted_at":            postDeletedAt(req.Status),
 		"cover":                 req.Cover,
 		"pinned":                req.Pinned,
+		"seo_title":             req.SeoTitle,
+		"seo_description":       req.SeoDescription,
+		"canonical_url":         req.CanonicalURL,
+		"no_index":              req.NoIndex,
+		"og_image":              req.OgImage,
 	}
 
 	if !util.StringIsNilOrBlank(req.Password) && req.Encrypted != nil && *req.Encrypted == true {
 		// 设置新密码
-		newPost["password"] = util.GenerateHash(*req.Password)
+		newPost["password"] = pwd
 	} else if req.Encrypted != nil && *req.Encrypted == false {
 		// 移除旧密码
 		newPost["password"] = nil
@@ -374,6 +458,8 @@ func (r *postRepo) UpdatePostStatus(ctx context.Context, req *request.PostStatus
 
 	if req.Status != nil {
 		updates["status"] = req.Status
+		updates["publish_at"] = postPublishAt(*req.Status, req.PublishAt)
+		updates["deleted_at"] = postDeletedAt(*req.Status)
 	}
 	if req.Visible != nil {
 		updates["visible"] = req.Visible
@@ -441,8 +527,8 @@ func (r *postRepo) Posts(ctx context.Context, includeTagAndCategory bool) ([]*re
 	postRes := response.NewPostResponses(posts)
 
 	if includeTagAndCategory {
-		// 获取标签和分类
-		if err := r.fillTagAndCategory(ctx, postRes); err != nil {
+		// 获取标签、分类和作者
+		if err := r.fillRelations(ctx, postRes); err != nil {
 			return nil, err
 		}
 	}
@@ -463,7 +549,7 @@ func (r *postRepo) PostById(ctx context.Context, id uint, includeTagAndCategory
 	postRes := response.NewPostResponse(post)
 
 	if includeTagAndCategory {
-		if err := r.fillTagAndCategory(ctx, []*response.PostResponse{postRes}); err != nil {
+		if err := r.fillRelations(ctx, []*response.PostResponse{postRes}); err != nil {
 			return nil, err
 		}
 	}
@@ -486,7 +572,7 @@ func (r *postRepo) PostByIds(ctx context.Context, ids []uint, includeTagAndCateg
 	postRes := response.NewPostResponses(posts)
 
 	if includeTagAndCategory {
-		if err := r.fillTagAndCategory(ctx, postRes); err != nil {
+		if err := r.fillRelations(ctx, postRes); err != nil {
 			return nil, err
 		}
 	}
@@ -524,8 +610,8 @@ func (r *postRepo) PostPager(
 		// 转为文章响应体
 		postRes := response.NewPostResponses(posts)
 
-		// 填充标签和分类
-		err = r.fillTagAndCategory(ctx, postRes)
+		// 填充标签、分类和作者
+		err = r.fillRelations(ctx, postRes)
 		if err != nil {
 			return nil, err
 		}
@@ -550,8 +636,8 @@ func (r *postRepo) PostPager(
 	// 转为文章响应体
 	postRes := response.NewPostResponses(pager.Data)
 
-	// 填充标签和分类s
-	err = r.fillTagAndCategory(ctx, postRes)
+	// 填充标签、分类和作者
+	err = r.fillRelations(ctx, postRes)
 	if err != nil {
 		return nil, err
 	}
@@ -579,8 +665,8 @@ func (r *postRepo) PostBySlug(ctx context.Context, slug string, includeTagAndCat
 	postRes := response.NewPostResponse(post)
 
 	if includeTagAndCategory {
-		// 填充标签和分类
-		err = r.fillTagAndCategory(ctx, []*response.PostResponse{postRes})
+		// 填充标签、分类和作者
+		err = r.fillRelations(ctx, []*response.PostResponse{postRes})
 	}
 
 	if err != nil {
@@ -596,6 +682,7 @@ func (r *postRepo) PostByKey(ctx context.Context, key string) ([]*response.PostR
 
 	query := r.db.WithContext(ctx).
 		Table("post p").
+		Select("DISTINCT p.*").
 		Joins("LEFT JOIN post_content pc ON p.post_id = pc.post_id")
 	// 关键词查询
 	err := r.sqlQueryKey(query, key).Order("p.create_time DESC").Find(&posts).Error
@@ -607,6 +694,7 @@ func (r *postRepo) PostByKey(ctx context.Context, key string) ([]*response.PostR
 }
 
 // PostApi 获取文章 Api 接口（博客前端）
+//   - sort: 排序方式（默认置顶文章在前）
 func (r *postRepo) PostApi(
 	ctx context.Context,
 	page int, size int,
@@ -615,7 +703,11 @@ func (r *postRepo) PostApi(
 	categoryId *uint,
 	tag *string,
 	category *string,
+	sort *enum.PostSort,
 ) (*models.Pager[response.PostApiResponse], error) {
+	if sort == nil {
+		sort = enum.PostSortPtr(enum.PostSortPinned)
+	}
 
 	// 构建查询语句
 	base, err := r.sqlQueryPosts(
@@ -624,7 +716,7 @@ func (r *postRepo) PostApi(
 		enum.PostVisiblePtr(enum.PostVisibleVisible),
 		key, tagId, categoryId,
 		tag, category,
-		enum.PostSortPtr(enum.PostSortPinned),
+		sort,
 	)
 
 	if err != nil {
@@ -642,8 +734,8 @@ func (r *postRepo) PostApi(
 		// 转为文章响应体
 		postRes := response.NewPostResponses(posts)
 
-		// 填充标签和分类
-		err = r.fillTagAndCategory(ctx, postRes)
+		// 填充标签、分类和作者
+		err = r.fillRelations(ctx, postRes)
 		if err != nil {
 			return nil, err
 		}
@@ -668,8 +760,8 @@ func (r *postRepo) PostApi(
 	// 转为文章响应体
 	postRes := response.NewPostResponses(pager.Data)
 
-	// 填充标签和分类
-	err = r.fillTagAndCategory(ctx, postRes)
+	// 填充标签、分类和作者
+	err = r.fillRelations(ctx, postRes)
 	if err != nil {
 		return nil, err
 	}
@@ -683,6 +775,49 @@ func (r *postRepo) PostApi(
 	}, nil
 }
 
+// PostApiByIds 根据文章 ID 数组获取已发布、可见的文章（博客前端），用于搜索结果
+// 返回的文章顺序不确定，由调用方按搜索相关度排序
+//   - ids: 文章 ID 数组
+//   - tagId: 标签 ID
+//   - categoryId: 分类 ID
+//   - tag: 标签名或别名
+//   - category: 分类名或别名
+func (r *postRepo) PostApiByIds(
+	ctx context.Context,
+	ids []uint,
+	tagId *uint,
+	categoryId *uint,
+	tag *string,
+	category *string,
+) ([]*response.PostResponse, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	base, err := r.sqlQueryPosts(
+		ctx,
+		enum.PostStatusPtr(enum.PostStatusPublished),
+		enum.PostVisiblePtr(enum.PostVisibleVisible),
+		nil, tagId, categoryId,
+		tag, category,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var posts []*models.Post
+	if err := base.Where("p.post_id IN ?", ids).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	postRes := response.NewPostResponses(posts)
+	if err := r.fillRelations(ctx, postRes); err != nil {
+		return nil, err
+	}
+	return postRes, nil
+}
+
 // PostContents 获取文章所有内容（包括正文和草稿）
 func (r *postRepo) PostContents(ctx context.Context, postId uint) ([]*response.PostContentResponse, error) {
 	var contents []*models.PostContent
@@ -899,18 +1034,46 @@ func (r *postRepo) UpdatePostDraftToContent(ctx context.Context, postId uint, dr
 }
 
 // IsPostPasswordValid 验证文章密码是否正确
+// 验证成功且哈希需要升级（旧版 SHA-256 哈希或哈希参数变化）时重新生成并保存
 func (r *postRepo) IsPostPasswordValid(ctx context.Context, postId uint, password string) (bool, error) {
-	var count int64
-	ret := r.db.WithContext(ctx).
-		Model(&models.Post{}).
-		Where("post_id = ? AND password = ?", postId, util.GenerateHash(password)).
-		Count(&count)
-
-	if err := ret.Error; err != nil {
+	var post models.Post
+	err := r.db.WithContext(ctx).
+		Select("post_id", "password").
+		Where("post_id = ?", postId).
+		First(&post).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
 		return false, err
 	}
 
-	return count > 0, nil
+	if post.Password == nil {
+		return false, nil
+	}
+
+	// 文章密码的旧版哈希没有盐值
+	ok, needsRehash := r.hasher.Verify(password, *post.Password, "")
+	if !ok {
+		return false, nil
+	}
+
+	if needsRehash {
+		hash, err := r.hasher.Hash(password)
+		if err != nil {
+			return false, err
+		}
+		// 只在密码没有被修改时更新，避免覆盖同时设置的新密码
+		err = r.db.WithContext(ctx).
+			Model(&models.Post{}).
+			Where("post_id = ? AND password = ?", postId, *post.Password).
+			Update("password", hash).Error
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
 }
 
 // MostViewedPost 浏览量最多的文章
@@ -929,8 +1092,8 @@ func (r *postRepo) MostViewedPost(ctx context.Context) (*response.PostResponse,
 	// 转为文章响应类
 	res := response.NewPostResponse(post)
 
-	// 填充标签和分类
-	err = r.fillTagAndCategory(ctx, []*response.PostResponse{res})
+	// 填充标签、分类和作者
+	err = r.fillRelations(ctx, []*response.PostResponse{res})
 
 	if err != nil {
 		return nil, err
@@ -945,7 +1108,7 @@ func (r *postRepo) PostVisitCount(ctx context.Context) (int64, error) {
 
 	err := r.db.WithContext(ctx).
 		Model(&models.Post{}).
-		Select("SUM(visit)").
+		Select("COALESCE(SUM(visit), 0)").
 		Scan(&count).Error
 
 	if err != nil {
@@ -955,12 +1118,16 @@ func (r *postRepo) PostVisitCount(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
-// fillTagAndCategory 给文章填充标签和分类
-func (r *postRepo) fillTagAndCategory(ctx context.Context, posts []*response.PostResponse) error {
+// fillRelations 给文章填充标签、分类和作者
+func (r *postRepo) fillRelations(ctx context.Context, posts []*response.PostResponse) error {
 	if len(posts) == 0 {
 		return nil
 	}
 
+	if err := r.fillAuthor(ctx, posts); err != nil {
+		return err
+	}
+
 	for _, post := range posts {
 		// 获取文章标签
 		tags, err := r.tagRepo.TagByPostId(ctx, post.PostId)
@@ -980,6 +1147,132 @@ func (r *postRepo) fillTagAndCategory(ctx context.Context, posts []*response.Pos
 	return nil
 }
 
+// fillAuthor 给文章填充作者信息
+func (r *postRepo) fillAuthor(ctx context.Context, posts []*response.PostResponse) error {
+	var authorIds []uint
+	for _, post := range posts {
+		if post.AuthorId != nil {
+			authorIds = append(authorIds, *post.AuthorId)
+		}
+	}
+
+	users, err := r.userRepo.GetByIds(ctx, authorIds)
+	if err != nil {
+		return err
+	}
+
+	authors := util.AssociateBy(users, func(user *models.User) uint { return user.UserId })
+	for _, post := range posts {
+		if post.AuthorId == nil {
+			continue
+		}
+		if author, ok := authors[*post.AuthorId]; ok {
+			post.Author = response.NewPostAuthorResponse(author)
+		}
+	}
+	return nil
+}
+
+// ScheduledPosts 获取所有定时发布的文章（按发布时间升序）
+func (r *postRepo) ScheduledPosts(ctx context.Context) ([]*response.PostResponse, error) {
+	var posts []*models.Post
+	err := r.db.WithContext(ctx).
+		Where("status = ?", enum.PostStatusScheduled).
+		Order("publish_at ASC, post_id ASC").
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	postRes := response.NewPostResponses(posts)
+	if err := r.fillRelations(ctx, postRes); err != nil {
+		return nil, err
+	}
+	return postRes, nil
+}
+
+// DueScheduledPostIds 获取已到发布时间的定时发布文章 ID
+//   - now: 当前时间戳毫秒
+func (r *postRepo) DueScheduledPostIds(ctx context.Context, now int64) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&models.Post{}).
+		Where("status = ? AND publish_at <= ?", enum.PostStatusScheduled, now).
+		Order("publish_at ASC").
+		Pluck("post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// PublishScheduledPost 发布已到发布时间的定时发布文章，文章创建时间改为定时发布时间
+// 通过带条件的更新抢占文章，多个实例同时发布同一篇文章时只有一个会成功
+//   - postId: 文章 ID
+//   - now: 当前时间戳毫秒
+func (r *postRepo) PublishScheduledPost(ctx context.Context, postId uint, now int64) (bool, error) {
+	ret := r.db.WithContext(ctx).
+		Model(&models.Post{}).
+		Where("post_id = ? AND status = ? AND publish_at <= ?", postId, enum.PostStatusScheduled, now).
+		Updates(map[string]any{
+			"status":      enum.PostStatusPublished,
+			"create_time": gorm.Expr("publish_at"),
+		})
+	if err := ret.Error; err != nil {
+		return false, err
+	}
+	return ret.RowsAffected > 0, nil
+}
+
+// ExpiredDeletedPostIds 获取在指定时间之前移入回收站的文章 ID
+//   - before: 时间戳毫秒（不包含）
+func (r *postRepo) ExpiredDeletedPostIds(ctx context.Context, before int64) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&models.Post{}).
+		Where("status = ? AND deleted_at < ?", enum.PostStatusDeleted, before).
+		Pluck("post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// PublicPostLinks 获取所有公开文章（已发布、可见、未加密、允许搜索引擎索引）的别名和时间，用于站点地图
+// 禁止索引的文章页面会输出 noindex，不放入站点地图，避免搜索引擎报告提交的地址被标记为 noindex
+// 只查询 post_id、slug、create_time 和 last_modify_time，按创建时间降序
+func (r *postRepo) PublicPostLinks(ctx context.Context) ([]*models.Post, error) {
+	var posts []*models.Post
+	err := r.db.WithContext(ctx).
+		Select("post_id, slug, create_time, last_modify_time").
+		Where("status = ? AND visible = ?", enum.PostStatusPublished, enum.PostVisibleVisible).
+		Where("password IS NULL OR password = ''").
+		Where("no_index = ?", false).
+		Order("create_time DESC, post_id DESC").
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// postDeletedAt 修改文章状态时移入回收站时间的更新值
+// 移入回收站时记录当前时间（已在回收站中的文章保持原来的时间），移出回收站时清空
+func postDeletedAt(status enum.PostStatus) any {
+	if status != enum.PostStatusDeleted {
+		return nil
+	}
+	return gorm.Expr("COALESCE(deleted_at, ?)", time.Now().UnixMilli())
+}
+
+// postPublishAt 文章的定时发布时间，不是定时发布状态的文章为空
+func postPublishAt(status enum.PostStatus, publishAt *int64) *int64 {
+	if status != enum.PostStatusScheduled {
+		return nil
+	}
+	return publishAt
+}
+
 // sqlQueryPosts 构建文章查询 SQL
 func (r *postRepo) sqlQueryPosts(
 	ctx context.Context,
@@ -992,8 +1285,10 @@ func (r *postRepo) sqlQueryPosts(
 	category *string,
 	sort *enum.PostSort,
 ) (*gorm.DB, error) {
+	// 只查询文章表的列，防止与文章内容表的同名列（post_id、status 等）冲突
 	query := r.db.WithContext(ctx).
 		Table("post p").
+		Select("p.*").
 		Joins("LEFT JOIN post_content pc ON p.post_id = pc.post_id AND pc.status = ?", enum.PostContentStatusPublished)
 
 	// 文章状态
@@ -1009,7 +1304,7 @@ func (r *postRepo) sqlQueryPosts(
 		query = query.Where("p.visible = ?", visible)
 	}
 
-	// 关键词
+	// 关键词（后台管理使用，可以匹配草稿以外的所有文章内容；博客前端的搜索使用全文搜索索引，见 SearchService）
 	if !util.StringIsNilOrBlank(key) {
 		query = r.sqlQueryKey(query, *key)
 	}
@@ -1094,7 +1389,8 @@ func (r *postRepo) sqlQueryPosts(
 		case enum.PostSortVisitAsc:
 			query = query.Order("p.visit ASC")
 		case enum.PostSortPinned:
-			query = query.Order("p.pinned DESC")
+			// 置顶文章在前，其余按创建时间降序
+			query = query.Order("p.pinned DESC").Order("p.create_time DESC")
 		}
 	} else {
 		// 默认创建时间降序
@@ -1106,7 +1402,7 @@ func (r *postRepo) sqlQueryPosts(
 
 // sqlQueryKey 给查询条件加上关键字查询
 func (r *postRepo) sqlQueryKey(base *gorm.DB, key string) *gorm.DB {
-	return base.Where("p.title LIKE %?% OR p.slug LIKE %?% OR p.excerpt LIKE %?% OR pc.content LIKE %?%", key, key, key, key)
+	return base.Where(db.Contains(key, "p.title", "p.slug", "p.excerpt", "pc.content"))
 }
 
 // handlePanic 处理 Panic
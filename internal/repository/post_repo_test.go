@@ -0,0 +1,835 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/models/response"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// newTestPostRepo 创建文章 Repo 测试环境
+func newTestPostRepo(t *testing.T) (PostRepository, *testutil.Fixture, *gorm.DB) {
+	database := testutil.NewDB(t)
+	repo := NewPostRepository(database, NewTagRepository(database), NewCategoryRepository(database), NewUserRepository(database), testutil.NewHasher(t))
+	return repo, testutil.NewFixture(t, database), database
+}
+
+// newPostRequest 创建文章请求
+func newPostRequest(slug string, content string) *request.PostRequest {
+	return &request.PostRequest{
+		Title:               slug,
+		AutoGenerateExcerpt: util.BoolPtr(false),
+		Excerpt:             util.StringPtr("excerpt of " + slug),
+		Slug:                slug,
+		AllowComment:        util.BoolPtr(true),
+		Status:              enum.PostStatusPublished,
+		Visible:             enum.PostVisibleVisible,
+		Content:             util.StringPtr(content),
+	}
+}
+
+func TestPostRepo_AddPost(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("插入文章、标签、分类和正文", func(t *testing.T) {
+		repo, f, database := newTestPostRepo(t)
+		tag1, tag2 := f.Tag("go"), f.Tag("gorm")
+		category := f.Category("code")
+
+		req := newPostRequest("hello", "# Hello")
+		req.TagIds = []uint{tag1.TagId, tag2.TagId}
+		req.CategoryId = &category.CategoryId
+		req.Password = util.StringPtr("secret")
+
+		post, err := repo.AddPost(ctx, req)
+		if err != nil {
+			t.Fatalf("AddPost: %v", err)
+		}
+		if post.PostId == 0 || post.Password == nil || *post.Password == "secret" {
+			t.Fatalf("unexpected post: %+v", post)
+		}
+
+		if n := countRows(t, database, &models.PostTag{}, "post_id = ?", post.PostId); n != 2 {
+			t.Errorf("post_tag rows = %d, want 2", n)
+		}
+		if n := countRows(t, database, &models.PostCategory{}, "post_id = ?", post.PostId); n != 1 {
+			t.Errorf("post_category rows = %d, want 1", n)
+		}
+
+		content, err := repo.PostContent(ctx, post.PostId, enum.PostContentStatusPublished, nil)
+		if err != nil || content == nil {
+			t.Fatalf("PostContent: %v, %v", content, err)
+		}
+		if content.Content != "# Hello" || content.HTML == "" {
+			t.Errorf("unexpected content: %+v", content)
+		}
+	})
+
+	failures := []struct {
+		name  string
+		op    testutil.Op
+		table string
+	}{
+		{"插入文章标签失败", testutil.OpCreate, "post_tag"},
+		{"插入文章分类失败", testutil.OpCreate, "post_category"},
+		{"插入文章正文失败", testutil.OpCreate, "post_content"},
+	}
+
+	for _, tt := range failures {
+		t.Run(tt.name+"时回滚", func(t *testing.T) {
+			repo, f, database := newTestPostRepo(t)
+			tag := f.Tag("go")
+			category := f.Category("code")
+			testutil.InjectError(t, database, tt.op, tt.table, 0)
+
+			req := newPostRequest("hello", "content")
+			req.TagIds = []uint{tag.TagId}
+			req.CategoryId = &category.CategoryId
+
+			if _, err := repo.AddPost(ctx, req); !errors.Is(err, testutil.ErrInjected) {
+				t.Fatalf("AddPost err = %v, want injected error", err)
+			}
+
+			for _, model := range []any{&models.Post{}, &models.PostTag{}, &models.PostCategory{}, &models.PostContent{}} {
+				if n := countRows(t, database, model, "1 = 1"); n != 0 {
+					t.Errorf("%T rows = %d, want 0", model, n)
+				}
+			}
+		})
+	}
+
+	t.Run("别名重复", func(t *testing.T) {
+		repo, f, database := newTestPostRepo(t)
+		f.Post("hello", "content")
+
+		if _, err := repo.AddPost(ctx, newPostRequest("hello", "other")); err == nil {
+			t.Fatal("AddPost with duplicate slug should fail")
+		}
+		if n := countRows(t, database, &models.PostContent{}, "1 = 1"); n != 1 {
+			t.Errorf("post_content rows = %d, want 1", n)
+		}
+	})
+}
+
+func TestPostRepo_UpdatePost(t *testing.T) {
+	ctx := context.Background()
+
+	// prepare 插入一篇带标签和分类的文章
+	prepare := func(t *testing.T) (PostRepository, *gorm.DB, *models.Post, *request.PostRequest) {
+		repo, f, database := newTestPostRepo(t)
+		oldTag, newTag := f.Tag("old"), f.Tag("new")
+		oldCategory, newCategory := f.Category("old"), f.Category("new")
+		post := f.Post("hello", "content", oldTag)
+		f.PostCategory(post, oldCategory)
+
+		req := newPostRequest("hello-world", "ignored")
+		req.PostId = &post.PostId
+		req.Title = "Hello World"
+		req.TagIds = []uint{newTag.TagId}
+		req.CategoryId = &newCategory.CategoryId
+		req.Encrypted = util.BoolPtr(true)
+		req.Password = util.StringPtr("secret")
+		return repo, database, post, req
+	}
+
+	t.Run("修改文章、标签和分类", func(t *testing.T) {
+		repo, _, post, req := prepare(t)
+
+		ok, err := repo.UpdatePost(ctx, req)
+		if err != nil || !ok {
+			t.Fatalf("UpdatePost = %v, %v", ok, err)
+		}
+
+		got, err := repo.PostById(ctx, post.PostId, true)
+		if err != nil {
+			t.Fatalf("PostById: %v", err)
+		}
+		if got.Title != "Hello World" || got.Slug != "hello-world" || !got.Encrypted {
+			t.Errorf("unexpected post: %+v", got)
+		}
+		if len(got.Tags) != 1 || got.Tags[0].Slug != "new" {
+			t.Errorf("tags = %+v, want [new]", got.Tags)
+		}
+		if got.Category == nil || got.Category.Slug != "new" {
+			t.Errorf("category = %+v, want new", got.Category)
+		}
+
+		valid, err := repo.IsPostPasswordValid(ctx, post.PostId, "secret")
+		if err != nil || !valid {
+			t.Errorf("IsPostPasswordValid = %v, %v", valid, err)
+		}
+	})
+
+	t.Run("移除密码", func(t *testing.T) {
+		repo, _, post, req := prepare(t)
+		if _, err := repo.UpdatePost(ctx, req); err != nil {
+			t.Fatalf("UpdatePost: %v", err)
+		}
+
+		req.Encrypted = util.BoolPtr(false)
+		if _, err := repo.UpdatePost(ctx, req); err != nil {
+			t.Fatalf("UpdatePost: %v", err)
+		}
+
+		got, _ := repo.PostById(ctx, post.PostId, false)
+		if got.Encrypted {
+			t.Error("post should not be encrypted")
+		}
+	})
+
+	t.Run("文章 ID 为空", func(t *testing.T) {
+		repo, _, _, req := prepare(t)
+		req.PostId = nil
+		if _, err := repo.UpdatePost(ctx, req); err == nil {
+			t.Fatal("UpdatePost without id should fail")
+		}
+	})
+
+	failures := []struct {
+		name  string
+		op    testutil.Op
+		table string
+	}{
+		{"删除旧分类失败", testutil.OpDelete, "post_category"},
+		{"插入新标签失败", testutil.OpCreate, "post_tag"},
+		{"插入新分类失败", testutil.OpCreate, "post_category"},
+		{"修改文章失败", testutil.OpUpdate, "post"},
+	}
+
+	for _, tt := range failures {
+		t.Run(tt.name+"时回滚", func(t *testing.T) {
+			repo, database, post, req := prepare(t)
+			testutil.InjectError(t, database, tt.op, tt.table, 0)
+
+			if _, err := repo.UpdatePost(ctx, req); !errors.Is(err, testutil.ErrInjected) {
+				t.Fatalf("UpdatePost err = %v, want injected error", err)
+			}
+
+			got, err := repo.PostById(ctx, post.PostId, true)
+			if err != nil {
+				t.Fatalf("PostById: %v", err)
+			}
+			if got.Slug != "hello" || got.Encrypted {
+				t.Errorf("post changed: %+v", got)
+			}
+			if len(got.Tags) != 1 || got.Tags[0].Slug != "old" {
+				t.Errorf("tags = %+v, want [old]", got.Tags)
+			}
+			if got.Category == nil || got.Category.Slug != "old" {
+				t.Errorf("category = %+v, want old", got.Category)
+			}
+		})
+	}
+}
+
+func TestPostRepo_UpdatePostDraftToContent(t *testing.T) {
+	ctx := context.Background()
+
+	// prepare 插入一篇带草稿的文章
+	prepare := func(t *testing.T) (PostRepository, *gorm.DB, *models.Post) {
+		repo, f, database := newTestPostRepo(t)
+		post := f.Post("hello", "published")
+		f.Draft(post, "v2", "draft v2")
+		f.Draft(post, "v3", "draft v3")
+		return repo, database, post
+	}
+
+	tests := []struct {
+		name          string
+		deleteContent bool
+		contentName   *string
+		// wantDrafts 转换后的草稿名和内容
+		wantDrafts map[string]string
+	}{
+		{
+			name:        "保留原正文，使用草稿名",
+			wantDrafts:  map[string]string{"v2": "published", "v3": "draft v3"},
+			contentName: nil,
+		},
+		{
+			name:        "保留原正文，使用指定名称",
+			contentName: util.StringPtr("v1"),
+			wantDrafts:  map[string]string{"v1": "published", "v3": "draft v3"},
+		},
+		{
+			name:          "删除原正文",
+			deleteContent: true,
+			wantDrafts:    map[string]string{"v3": "draft v3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, _, post := prepare(t)
+
+			ok, err := repo.UpdatePostDraftToContent(ctx, post.PostId, "v2", tt.deleteContent, tt.contentName)
+			if err != nil || !ok {
+				t.Fatalf("UpdatePostDraftToContent = %v, %v", ok, err)
+			}
+
+			published, _ := repo.PostContent(ctx, post.PostId, enum.PostContentStatusPublished, nil)
+			if published == nil || published.Content != "draft v2" || published.DraftName != nil {
+				t.Fatalf("published = %+v, want draft v2", published)
+			}
+
+			contents, _ := repo.PostContents(ctx, post.PostId)
+			drafts := map[string]bool{}
+			for _, c := range contents {
+				if *c.Status == enum.PostContentStatusDraft {
+					drafts[*c.DraftName] = true
+				}
+			}
+			if len(drafts) != len(tt.wantDrafts) {
+				t.Fatalf("drafts = %v, want %v", drafts, tt.wantDrafts)
+			}
+			for name, want := range tt.wantDrafts {
+				draft, _ := repo.PostContent(ctx, post.PostId, enum.PostContentStatusDraft, util.StringPtr(name))
+				if draft == nil || draft.Content != want {
+					t.Errorf("draft %s = %+v, want %q", name, draft, want)
+				}
+			}
+		})
+	}
+
+	failures := []struct {
+		name          string
+		deleteContent bool
+		op            testutil.Op
+		skip          int
+	}{
+		{"删除原正文失败", true, testutil.OpDelete, 0},
+		{"原正文转为草稿失败", false, testutil.OpUpdate, 0},
+		{"草稿转为正文失败", false, testutil.OpUpdate, 1},
+		{"删除原正文后草稿转为正文失败", true, testutil.OpUpdate, 0},
+	}
+
+	for _, tt := range failures {
+		t.Run(tt.name+"时回滚", func(t *testing.T) {
+			repo, database, post := prepare(t)
+			testutil.InjectError(t, database, tt.op, "post_content", tt.skip)
+
+			if _, err := repo.UpdatePostDraftToContent(ctx, post.PostId, "v2", tt.deleteContent, nil); !errors.Is(err, testutil.ErrInjected) {
+				t.Fatalf("UpdatePostDraftToContent err = %v, want injected error", err)
+			}
+
+			published, _ := repo.PostContent(ctx, post.PostId, enum.PostContentStatusPublished, nil)
+			if published == nil || published.Content != "published" {
+				t.Errorf("published = %+v, want published", published)
+			}
+			draft, _ := repo.PostContent(ctx, post.PostId, enum.PostContentStatusDraft, util.StringPtr("v2"))
+			if draft == nil || draft.Content != "draft v2" {
+				t.Errorf("draft v2 = %+v, want draft v2", draft)
+			}
+		})
+	}
+}
+
+func TestPostRepo_DeletePostByIds(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("删除文章及关联数据", func(t *testing.T) {
+		repo, f, database := newTestPostRepo(t)
+		tag := f.Tag("go")
+		category := f.Category("code")
+		post := f.Post("hello", "content", tag)
+		f.PostCategory(post, category)
+		f.Draft(post, "draft", "draft")
+		f.Comment(post, nil, "a@example.com", true)
+		f.Create(&models.PostSeries{SeriesId: 1, PostId: post.PostId})
+		other := f.Post("other", "content", tag)
+
+		ok, err := repo.DeletePostByIds(ctx, []uint{post.PostId})
+		if err != nil || !ok {
+			t.Fatalf("DeletePostByIds = %v, %v", ok, err)
+		}
+
+		for _, model := range []any{&models.Post{}, &models.PostTag{}, &models.PostCategory{}, &models.PostContent{}, &models.Comment{}, &models.PostSeries{}} {
+			if n := countRows(t, database, model, "post_id = ?", post.PostId); n != 0 {
+				t.Errorf("%T rows = %d, want 0", model, n)
+			}
+		}
+		if n := countRows(t, database, &models.PostTag{}, "post_id = ?", other.PostId); n != 1 {
+			t.Errorf("other post tags = %d, want 1", n)
+		}
+	})
+
+	t.Run("空数组", func(t *testing.T) {
+		repo, _, _ := newTestPostRepo(t)
+		if ok, err := repo.DeletePostByIds(ctx, nil); ok || err != nil {
+			t.Fatalf("DeletePostByIds(nil) = %v, %v", ok, err)
+		}
+	})
+
+	t.Run("删除文章失败时回滚", func(t *testing.T) {
+		repo, f, database := newTestPostRepo(t)
+		post := f.Post("hello", "content", f.Tag("go"))
+		testutil.InjectError(t, database, testutil.OpDelete, "post", 0)
+
+		if _, err := repo.DeletePostByIds(ctx, []uint{post.PostId}); !errors.Is(err, testutil.ErrInjected) {
+			t.Fatalf("DeletePostByIds err = %v, want injected error", err)
+		}
+		if n := countRows(t, database, &models.PostContent{}, "post_id = ?", post.PostId); n != 1 {
+			t.Errorf("post_content rows = %d, want 1", n)
+		}
+		if n := countRows(t, database, &models.PostTag{}, "post_id = ?", post.PostId); n != 1 {
+			t.Errorf("post_tag rows = %d, want 1", n)
+		}
+	})
+}
+
+func TestPostRepo_UpdatePostStatus(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestPostRepo(t)
+	post := f.Post("hello", "content")
+	other := f.Post("other", "content")
+
+	if ok, err := repo.UpdatePostStatusToDeleted(ctx, []uint{post.PostId}); err != nil || !ok {
+		t.Fatalf("UpdatePostStatusToDeleted = %v, %v", ok, err)
+	}
+	got, _ := repo.PostById(ctx, post.PostId, false)
+	if got.Status != enum.PostStatusDeleted || got.DeletedAt == nil {
+		t.Fatalf("status = %s, deletedAt = %v, want DELETED", got.Status, got.DeletedAt)
+	}
+
+	// 再次移入回收站时保持原来的时间
+	deletedAt := *got.DeletedAt
+	time.Sleep(5 * time.Millisecond)
+	_, _ = repo.UpdatePostStatusToDeleted(ctx, []uint{post.PostId})
+	got, _ = repo.PostById(ctx, post.PostId, false)
+	if *got.DeletedAt != deletedAt {
+		t.Errorf("deletedAt = %d, want %d", *got.DeletedAt, deletedAt)
+	}
+
+	if ids, err := repo.ExpiredDeletedPostIds(ctx, deletedAt+1); err != nil || len(ids) != 1 || ids[0] != post.PostId {
+		t.Errorf("ExpiredDeletedPostIds = %v, %v", ids, err)
+	}
+	if ids, err := repo.ExpiredDeletedPostIds(ctx, deletedAt); err != nil || len(ids) != 0 {
+		t.Errorf("ExpiredDeletedPostIds(deletedAt) = %v, %v", ids, err)
+	}
+
+	if ok, err := repo.UpdatePostStatusTo(ctx, []uint{post.PostId}, enum.PostStatusDraft); err != nil || !ok {
+		t.Fatalf("UpdatePostStatusTo = %v, %v", ok, err)
+	}
+	if got, _ = repo.PostById(ctx, post.PostId, false); got.DeletedAt != nil {
+		t.Errorf("restored post deletedAt = %d, want nil", *got.DeletedAt)
+	}
+	if ok, _ := repo.UpdatePostStatusTo(ctx, nil, enum.PostStatusDraft); ok {
+		t.Error("UpdatePostStatusTo(nil) should return false")
+	}
+
+	ok, err := repo.UpdatePostStatus(ctx, &request.PostStatusRequest{
+		PostId:  other.PostId,
+		Visible: enum.PostVisiblePtr(enum.PostVisibleHidden),
+		Pinned:  util.BoolPtr(true),
+	})
+	if err != nil || !ok {
+		t.Fatalf("UpdatePostStatus = %v, %v", ok, err)
+	}
+	got, _ = repo.PostById(ctx, other.PostId, false)
+	if got.Status != enum.PostStatusPublished || got.Visible != enum.PostVisibleHidden || !*got.Pinned {
+		t.Errorf("unexpected post: %+v", got)
+	}
+
+	if ok, err := repo.UpdatePostExcerpt(ctx, post.PostId, "new excerpt"); err != nil || !ok {
+		t.Fatalf("UpdatePostExcerpt = %v, %v", ok, err)
+	}
+	if ok, err := repo.UpdatePostLastModifyTime(ctx, post.PostId, util.Int64Ptr(42)); err != nil || !ok {
+		t.Fatalf("UpdatePostLastModifyTime = %v, %v", ok, err)
+	}
+	got, _ = repo.PostById(ctx, post.PostId, false)
+	if got.Excerpt != "new excerpt" || got.LastModifyTime == nil || *got.LastModifyTime != 42 {
+		t.Errorf("unexpected post: %+v", got)
+	}
+}
+
+func TestPostRepo_Scheduled(t *testing.T) {
+	ctx := context.Background()
+	repo, _, _ := newTestPostRepo(t)
+
+	schedule := func(slug string, publishAt int64) *models.Post {
+		t.Helper()
+		req := newPostRequest(slug, "content")
+		req.Status = enum.PostStatusScheduled
+		req.PublishAt = util.Int64Ptr(publishAt)
+		post, err := repo.AddPost(ctx, req)
+		if err != nil {
+			t.Fatalf("AddPost: %v", err)
+		}
+		return post
+	}
+	later := schedule("later", 3000)
+	due := schedule("due", 1000)
+
+	// 非定时发布的文章不保存发布时间
+	req := newPostRequest("published", "content")
+	req.PublishAt = util.Int64Ptr(1000)
+	published, _ := repo.AddPost(ctx, req)
+	if published.PublishAt != nil {
+		t.Errorf("published post PublishAt = %d, want nil", *published.PublishAt)
+	}
+
+	posts, err := repo.ScheduledPosts(ctx)
+	if err != nil || len(posts) != 2 || posts[0].PostId != due.PostId || *posts[0].PublishAt != 1000 {
+		t.Fatalf("ScheduledPosts = %+v, %v", posts, err)
+	}
+
+	ids, err := repo.DueScheduledPostIds(ctx, 2000)
+	if err != nil || len(ids) != 1 || ids[0] != due.PostId {
+		t.Fatalf("DueScheduledPostIds = %v, %v", ids, err)
+	}
+
+	// 同一篇文章只能发布一次，未到发布时间的文章不能发布
+	if ok, err := repo.PublishScheduledPost(ctx, due.PostId, 2000); err != nil || !ok {
+		t.Fatalf("PublishScheduledPost = %v, %v", ok, err)
+	}
+	if ok, _ := repo.PublishScheduledPost(ctx, due.PostId, 2000); ok {
+		t.Error("PublishScheduledPost should not publish the same post twice")
+	}
+	if ok, _ := repo.PublishScheduledPost(ctx, later.PostId, 2000); ok {
+		t.Error("PublishScheduledPost should not publish posts before publishAt")
+	}
+
+	got, _ := repo.PostById(ctx, due.PostId, false)
+	if got.Status != enum.PostStatusPublished || got.CreateTime != 1000 {
+		t.Errorf("published post = %+v", got)
+	}
+
+	// 修改为其他状态时清除发布时间
+	if _, err := repo.UpdatePostStatus(ctx, &request.PostStatusRequest{
+		PostId: later.PostId,
+		Status: enum.PostStatusPtr(enum.PostStatusDraft),
+	}); err != nil {
+		t.Fatalf("UpdatePostStatus: %v", err)
+	}
+	got, _ = repo.PostById(ctx, later.PostId, false)
+	if got.Status != enum.PostStatusDraft || got.PublishAt != nil {
+		t.Errorf("unscheduled post = %+v", got)
+	}
+}
+
+func TestPostRepo_PublicPostLinks(t *testing.T) {
+	ctx := context.Background()
+	repo, f, database := newTestPostRepo(t)
+
+	f.Post("public", "content")
+	hidden := f.Post("hidden", "content")
+	database.Model(hidden).Update("visible", enum.PostVisibleHidden)
+	encrypted := f.Post("encrypted", "content")
+	database.Model(encrypted).Update("password", "hash")
+	draft := f.Post("draft", "content")
+	database.Model(draft).Update("status", enum.PostStatusDraft)
+	emptyPassword := f.Post("empty-password", "content")
+	database.Model(emptyPassword).Update("password", "")
+
+	posts, err := repo.PublicPostLinks(ctx)
+	if err != nil {
+		t.Fatalf("PublicPostLinks: %v", err)
+	}
+	got := make([]string, len(posts))
+	for i, post := range posts {
+		got[i] = post.Slug
+	}
+	assertStrings(t, got, []string{"empty-password", "public"})
+}
+
+func TestPostRepo_Visit(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestPostRepo(t)
+
+	// 没有文章时总浏览量为 0
+	if count, err := repo.PostVisitCount(ctx); err != nil || count != 0 {
+		t.Fatalf("PostVisitCount = %d, %v", count, err)
+	}
+
+	post := f.Post("hello", "content")
+	f.Post("other", "content")
+	for i := 0; i < 3; i++ {
+		if ok, err := repo.AddPostVisit(ctx, post.PostId); err != nil || !ok {
+			t.Fatalf("AddPostVisit = %v, %v", ok, err)
+		}
+	}
+
+	if count, err := repo.PostVisitCount(ctx); err != nil || count != 3 {
+		t.Errorf("PostVisitCount = %d, %v, want 3", count, err)
+	}
+	most, err := repo.MostViewedPost(ctx)
+	if err != nil || most == nil || most.PostId != post.PostId {
+		t.Errorf("MostViewedPost = %+v, %v", most, err)
+	}
+}
+
+func TestPostRepo_Query(t *testing.T) {
+	ctx := context.Background()
+	repo, f, database := newTestPostRepo(t)
+
+	goTag, dbTag := f.Tag("go"), f.Tag("db")
+	code, life := f.Category("code"), f.Category("life")
+
+	p1 := f.Post("gorm-intro", "Gorm 入门，100% 好用", goTag, dbTag)
+	f.PostCategory(p1, code)
+	p2 := f.Post("gin-intro", "Gin 入门", goTag)
+	f.PostCategory(p2, code)
+	p3 := f.Post("travel", "游记")
+	f.PostCategory(p3, life)
+	hidden := f.Post("hidden", "hidden")
+	database.Model(hidden).Update("visible", enum.PostVisibleHidden)
+	deleted := f.Post("deleted", "Gin deleted")
+	database.Model(deleted).Update("status", enum.PostStatusDeleted)
+	f.Draft(p3, "draft", "Gin in draft")
+
+	if count, err := repo.PostCount(ctx); err != nil || count != 5 {
+		t.Errorf("PostCount = %d, %v", count, err)
+	}
+
+	posts, err := repo.Posts(ctx, true)
+	if err != nil || len(posts) != 5 {
+		t.Fatalf("Posts = %d, %v", len(posts), err)
+	}
+
+	byIds, err := repo.PostByIds(ctx, []uint{p1.PostId, p2.PostId}, true)
+	if err != nil || len(byIds) != 2 {
+		t.Fatalf("PostByIds = %d, %v", len(byIds), err)
+	}
+	if empty, _ := repo.PostByIds(ctx, nil, false); empty != nil {
+		t.Errorf("PostByIds(nil) = %v", empty)
+	}
+
+	bySlug, err := repo.PostBySlug(ctx, "gorm-intro", true)
+	if err != nil || bySlug == nil || len(bySlug.Tags) != 2 || bySlug.Category.Slug != "code" {
+		t.Errorf("PostBySlug = %+v, %v", bySlug, err)
+	}
+	if missing, err := repo.PostBySlug(ctx, "missing", false); missing != nil || err != nil {
+		t.Errorf("PostBySlug(missing) = %v, %v", missing, err)
+	}
+	if missing, err := repo.PostById(ctx, 9999, false); missing != nil || err != nil {
+		t.Errorf("PostById(missing) = %v, %v", missing, err)
+	}
+
+	byKey, err := repo.PostByKey(ctx, "GIN")
+	if err != nil {
+		t.Fatalf("PostByKey: %v", err)
+	}
+	// 包含草稿内容匹配的文章，每篇文章只出现一次
+	if slugs := postSlugs(byKey); len(slugs) != 3 || !slugs["gin-intro"] || !slugs["travel"] || !slugs["deleted"] {
+		t.Errorf("PostByKey(GIN) = %v", slugs)
+	}
+
+	pagerTests := []struct {
+		name       string
+		page, size int
+		status     *enum.PostStatus
+		visible    *enum.PostVisible
+		key        *string
+		tagId      *uint
+		categoryId *uint
+		sort       *enum.PostSort
+		want       []string
+	}{
+		{name: "默认不包含回收站", want: []string{"hidden", "travel", "gin-intro", "gorm-intro"}},
+		{name: "分页", page: 2, size: 3, sort: enum.PostSortPtr(enum.PostSortCreateAsc), want: []string{"hidden"}},
+		{name: "状态", status: enum.PostStatusPtr(enum.PostStatusDeleted), want: []string{"deleted"}},
+		{name: "可见性", visible: enum.PostVisiblePtr(enum.PostVisibleHidden), want: []string{"hidden"}},
+		{name: "关键字匹配正文", key: util.StringPtr("入门"), want: []string{"gin-intro", "gorm-intro"}},
+		{name: "关键字包含百分号", key: util.StringPtr("100%"), want: []string{"gorm-intro"}},
+		{name: "关键字转义通配符", key: util.StringPtr("n_i"), want: []string{}},
+		{name: "关键字不匹配草稿", key: util.StringPtr("draft"), want: []string{}},
+		{name: "标签", tagId: &dbTag.TagId, want: []string{"gorm-intro"}},
+		{name: "分类", categoryId: &code.CategoryId, want: []string{"gin-intro", "gorm-intro"}},
+	}
+
+	for _, tt := range pagerTests {
+		t.Run("PostPager/"+tt.name, func(t *testing.T) {
+			pager, err := repo.PostPager(ctx, tt.page, tt.size, tt.status, tt.visible, tt.key, tt.tagId, tt.categoryId, tt.sort)
+			if err != nil {
+				t.Fatalf("PostPager: %v", err)
+			}
+			assertSlugs(t, pager.Data, tt.want)
+			if tt.page > 0 && pager.TotalData != 4 {
+				t.Errorf("TotalData = %d, want 4", pager.TotalData)
+			}
+		})
+	}
+
+	apiTests := []struct {
+		name       string
+		page, size int
+		key        *string
+		tagId      *uint
+		categoryId *uint
+		tag        *string
+		category   *string
+		want       []string
+	}{
+		{name: "只包含已发布且可见的文章", want: []string{"travel", "gin-intro", "gorm-intro"}},
+		{name: "分页", page: 1, size: 2, want: []string{"travel", "gin-intro"}},
+		{name: "标签 ID", tagId: &goTag.TagId, want: []string{"gin-intro", "gorm-intro"}},
+		{name: "分类 ID", categoryId: &life.CategoryId, want: []string{"travel"}},
+		{name: "标签名", tag: util.StringPtr("db"), want: []string{"gorm-intro"}},
+		{name: "分类别名", category: util.StringPtr("code"), want: []string{"gin-intro", "gorm-intro"}},
+		{name: "关键字", key: util.StringPtr("gin"), want: []string{"gin-intro"}},
+	}
+
+	for _, tt := range apiTests {
+		t.Run("PostApi/"+tt.name, func(t *testing.T) {
+			pager, err := repo.PostApi(ctx, tt.page, tt.size, tt.key, tt.tagId, tt.categoryId, tt.tag, tt.category, nil)
+			if err != nil {
+				t.Fatalf("PostApi: %v", err)
+			}
+			got := make([]string, len(pager.Data))
+			for i, p := range pager.Data {
+				got[i] = p.Slug
+			}
+			assertStrings(t, got, tt.want)
+		})
+	}
+}
+
+func TestPostRepo_Content(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestPostRepo(t)
+	post := f.Post("hello", "published")
+
+	draft, err := repo.AddPostDraft(ctx, post.PostId, "draft", "v2")
+	if err != nil || draft.PostContentId == 0 {
+		t.Fatalf("AddPostDraft = %+v, %v", draft, err)
+	}
+	if _, err := repo.AddPostDraft(ctx, post.PostId, "draft", "v3"); err != nil {
+		t.Fatalf("AddPostDraft: %v", err)
+	}
+
+	contents, err := repo.PostContents(ctx, post.PostId)
+	if err != nil || len(contents) != 3 {
+		t.Fatalf("PostContents = %d, %v", len(contents), err)
+	}
+
+	ok, err := repo.UpdatePostContent(ctx, request.PostContentRequest{PostId: post.PostId, Content: "updated"}, enum.PostContentStatusPublished, nil)
+	if err != nil || !ok {
+		t.Fatalf("UpdatePostContent = %v, %v", ok, err)
+	}
+	published, _ := repo.PostContent(ctx, post.PostId, enum.PostContentStatusPublished, nil)
+	if published.Content != "updated" {
+		t.Errorf("published = %q, want updated", published.Content)
+	}
+	got, _ := repo.PostById(ctx, post.PostId, false)
+	if got.LastModifyTime == nil {
+		t.Error("post last modify time should be updated")
+	}
+
+	ok, err = repo.UpdatePostContent(ctx, request.PostContentRequest{PostId: post.PostId, Content: "v2 updated"}, enum.PostContentStatusDraft, util.StringPtr("v2"))
+	if err != nil || !ok {
+		t.Fatalf("UpdatePostContent(draft) = %v, %v", ok, err)
+	}
+	v3, _ := repo.PostContent(ctx, post.PostId, enum.PostContentStatusDraft, util.StringPtr("v3"))
+	if v3.Content != "draft" {
+		t.Errorf("draft v3 = %q, want unchanged", v3.Content)
+	}
+
+	if ok, err := repo.UpdatePostDraftName(ctx, post.PostId, "v3", "v4"); err != nil || !ok {
+		t.Fatalf("UpdatePostDraftName = %v, %v", ok, err)
+	}
+	if v3, _ := repo.PostContent(ctx, post.PostId, enum.PostContentStatusDraft, util.StringPtr("v3")); v3 != nil {
+		t.Error("draft v3 should be renamed")
+	}
+
+	if ok, err := repo.DeletePostContent(ctx, post.PostId, enum.PostContentStatusDraft, []string{"v4"}); err != nil || !ok {
+		t.Fatalf("DeletePostContent = %v, %v", ok, err)
+	}
+	contents, _ = repo.PostContents(ctx, post.PostId)
+	if len(contents) != 2 {
+		t.Errorf("PostContents = %d, want 2", len(contents))
+	}
+}
+
+func TestPostRepo_IsPostPasswordValid(t *testing.T) {
+	ctx := context.Background()
+	repo, _, database := newTestPostRepo(t)
+
+	req := newPostRequest("hello", "content")
+	req.Password = util.StringPtr("secret")
+	post, err := repo.AddPost(ctx, req)
+	if err != nil {
+		t.Fatalf("AddPost: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		postId   uint
+		password string
+		want     bool
+	}{
+		{"密码正确", post.PostId, "secret", true},
+		{"密码错误", post.PostId, "wrong", false},
+		{"文章不存在", 9999, "secret", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.IsPostPasswordValid(ctx, tt.postId, tt.password)
+			if err != nil || got != tt.want {
+				t.Errorf("IsPostPasswordValid = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	t.Run("旧版哈希验证成功后升级", func(t *testing.T) {
+		legacy := util.GenerateHash("secret")
+		if err := database.Model(&models.Post{}).Where("post_id = ?", post.PostId).Update("password", legacy).Error; err != nil {
+			t.Fatalf("set legacy password: %v", err)
+		}
+
+		if valid, _ := repo.IsPostPasswordValid(ctx, post.PostId, "wrong"); valid {
+			t.Fatal("IsPostPasswordValid(wrong) = true")
+		}
+		if got := postPassword(t, database, post.PostId); got != legacy {
+			t.Fatalf("password = %q, should not be rehashed after failed validation", got)
+		}
+
+		if valid, err := repo.IsPostPasswordValid(ctx, post.PostId, "secret"); err != nil || !valid {
+			t.Fatalf("IsPostPasswordValid = %v, %v", valid, err)
+		}
+		if got := postPassword(t, database, post.PostId); !strings.HasPrefix(got, "$argon2id$") {
+			t.Errorf("password = %q, want argon2id hash", got)
+		}
+		if valid, err := repo.IsPostPasswordValid(ctx, post.PostId, "secret"); err != nil || !valid {
+			t.Errorf("IsPostPasswordValid after rehash = %v, %v", valid, err)
+		}
+	})
+}
+
+// postPassword 获取文章保存的密码哈希
+func postPassword(t *testing.T, database *gorm.DB, postId uint) string {
+	t.Helper()
+	var post models.Post
+	if err := database.Select("password").Where("post_id = ?", postId).First(&post).Error; err != nil {
+		t.Fatalf("get post password: %v", err)
+	}
+	return util.StringDefault(post.Password, "")
+}
+
+// postSlugs 获取文章别名集合
+func postSlugs(posts []*response.PostResponse) map[string]bool {
+	ret := make(map[string]bool, len(posts))
+	for _, p := range posts {
+		ret[p.Slug] = true
+	}
+	return ret
+}
+
+// assertSlugs 断言文章别名及顺序
+func assertSlugs(t *testing.T, posts []*response.PostResponse, want []string) {
+	t.Helper()
+	got := make([]string, len(posts))
+	for i, p := range posts {
+		got[i] = p.Slug
+	}
+	assertStrings(t, got, want)
+}
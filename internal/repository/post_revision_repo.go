@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+
+	"gorm.io/gorm"
+)
+
+// PostRevisionRepository 文章修订版本 Repo 接口
+// 修订版本添加后不再修改，只在彻底删除文章时一并删除（见 PostRepository.DeletePostByIds）
+type PostRevisionRepository interface {
+	// Create 添加修订版本
+	Create(ctx context.Context, revision *models.PostRevision) error
+	// GetById 根据修订版本 ID 获取修订版本
+	GetById(ctx context.Context, revisionId uint) (*models.PostRevision, error)
+	// GetByPostId 获取文章所有修订版本（不包含内容，按保存时间降序）
+	GetByPostId(ctx context.Context, postId uint) ([]*models.PostRevision, error)
+	// Latest 获取文章指定内容最新的修订版本
+	Latest(ctx context.Context, postId uint, status enum.PostContentStatus, draftName *string) (*models.PostRevision, error)
+}
+
+type postRevisionRepo struct {
+	db *gorm.DB
+}
+
+// NewPostRevisionRepository 创建文章修订版本 Repo
+func NewPostRevisionRepository(db *gorm.DB) PostRevisionRepository {
+	return &postRevisionRepo{db: db}
+}
+
+// Create 添加修订版本
+func (r *postRevisionRepo) Create(ctx context.Context, revision *models.PostRevision) error {
+	return r.db.WithContext(ctx).Create(revision).Error
+}
+
+// GetById 根据修订版本 ID 获取修订版本
+func (r *postRevisionRepo) GetById(ctx context.Context, revisionId uint) (*models.PostRevision, error) {
+	var revision models.PostRevision
+	err := r.db.WithContext(ctx).Where("revision_id = ?", revisionId).First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetByPostId 获取文章所有修订版本（不包含内容，按保存时间降序）
+func (r *postRevisionRepo) GetByPostId(ctx context.Context, postId uint) ([]*models.PostRevision, error) {
+	var revisions []*models.PostRevision
+	err := r.db.WithContext(ctx).
+		Omit("content").
+		Where("post_id = ?", postId).
+		Order("create_time DESC, revision_id DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// Latest 获取文章指定内容最新的修订版本
+//   - postId: 文章 ID
+//   - status: 文章内容状态
+//   - draftName: 草稿名，正文传空
+func (r *postRevisionRepo) Latest(
+	ctx context.Context,
+	postId uint,
+	status enum.PostContentStatus,
+	draftName *string,
+) (*models.PostRevision, error) {
+	query := r.db.WithContext(ctx).Where("post_id = ? AND status = ?", postId, status)
+	if draftName != nil {
+		query = query.Where("draft_name = ?", *draftName)
+	} else {
+		query = query.Where("draft_name IS NULL")
+	}
+
+	var revision models.PostRevision
+	err := query.Order("revision_id DESC").First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
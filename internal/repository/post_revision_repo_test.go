@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+)
+
+func TestPostRevisionRepo(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	f := testutil.NewFixture(t, database)
+	repo := NewPostRevisionRepository(database)
+
+	post := f.Post("post", "v1")
+	revisions := []*models.PostRevision{
+		{PostId: post.PostId, Status: enum.PostContentStatusPublished, Content: "v1", CreateTime: f.Now()},
+		{PostId: post.PostId, Status: enum.PostContentStatusDraft, DraftName: util.StringPtr("draft"), Content: "d1", CreateTime: f.Now()},
+		{PostId: post.PostId, Status: enum.PostContentStatusPublished, Content: "v2", CreateTime: f.Now()},
+		{PostId: post.PostId + 1, Status: enum.PostContentStatusPublished, Content: "other", CreateTime: f.Now()},
+	}
+	for _, revision := range revisions {
+		if err := repo.Create(ctx, revision); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	got, err := repo.GetByPostId(ctx, post.PostId)
+	if err != nil || len(got) != 3 || got[0].RevisionId != revisions[2].RevisionId || got[0].Content != "" {
+		t.Fatalf("GetByPostId = %+v, %v", got, err)
+	}
+
+	if got, err := repo.GetById(ctx, revisions[1].RevisionId); err != nil || got == nil || got.Content != "d1" {
+		t.Errorf("GetById = %+v, %v", got, err)
+	}
+	if got, err := repo.GetById(ctx, 1000); err != nil || got != nil {
+		t.Errorf("GetById(missing) = %+v, %v", got, err)
+	}
+
+	tests := []struct {
+		name      string
+		status    enum.PostContentStatus
+		draftName *string
+		want      string
+	}{
+		{"正文", enum.PostContentStatusPublished, nil, "v2"},
+		{"草稿", enum.PostContentStatusDraft, util.StringPtr("draft"), "d1"},
+		{"不存在的草稿", enum.PostContentStatusDraft, util.StringPtr("missing"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.Latest(ctx, post.PostId, tt.status, tt.draftName)
+			if err != nil {
+				t.Fatalf("Latest: %v", err)
+			}
+			if (got == nil && tt.want != "") || (got != nil && got.Content != tt.want) {
+				t.Errorf("Latest = %+v, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// 彻底删除文章时一并删除修订版本
+	if _, err := NewPostRepository(database, NewTagRepository(database), NewCategoryRepository(database), NewUserRepository(database), testutil.NewHasher(t)).DeletePostByIds(ctx, []uint{post.PostId}); err != nil {
+		t.Fatalf("DeletePostByIds: %v", err)
+	}
+	if n := countRows(t, database, &models.PostRevision{}, "1 = 1"); n != 1 {
+		t.Errorf("post_revision rows = %d, want 1", n)
+	}
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/db"
+	"nola-go/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// searchTermBatchSize 批量插入倒排索引时每批的条数
+const searchTermBatchSize = 500
+
+// SearchIndexStats 全文搜索索引统计信息
+type SearchIndexStats struct {
+	// Count 文档总数
+	Count int64
+	// AverageLength 文档平均长度
+	AverageLength float64
+}
+
+// SearchRepository 全文搜索索引 Repo 接口
+type SearchRepository interface {
+	// ReplaceDocuments 添加或替换文档及其倒排索引
+	ReplaceDocuments(ctx context.Context, docs []*models.SearchDocument, terms []*models.SearchTerm) error
+	// DeleteDocuments 删除文档及其倒排索引
+	DeleteDocuments(ctx context.Context, ids []uint) error
+	// Rebuild 清空索引后添加所有文档
+	Rebuild(ctx context.Context, docs []*models.SearchDocument, terms []*models.SearchTerm) error
+	// Postings 获取词项的倒排索引（词项精确匹配）
+	Postings(ctx context.Context, terms []string) ([]*models.SearchTerm, error)
+	// PrefixPostings 获取以指定前缀开头的词项的倒排索引
+	PrefixPostings(ctx context.Context, prefix string) ([]*models.SearchTerm, error)
+	// Documents 根据文档 ID 获取文档
+	Documents(ctx context.Context, ids []uint) ([]*models.SearchDocument, error)
+	// Stats 获取索引统计信息
+	Stats(ctx context.Context) (*SearchIndexStats, error)
+}
+
+type searchRepo struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository 创建全文搜索索引 Repo
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepo{db: db}
+}
+
+// ReplaceDocuments 添加或替换文档及其倒排索引
+//   - docs: 文档
+//   - terms: 文档的所有倒排索引
+func (r *searchRepo) ReplaceDocuments(ctx context.Context, docs []*models.SearchDocument, terms []*models.SearchTerm) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.DocId
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteSearchDocuments(tx, ids); err != nil {
+			return err
+		}
+		return createSearchDocuments(tx, docs, terms)
+	})
+}
+
+// DeleteDocuments 删除文档及其倒排索引
+func (r *searchRepo) DeleteDocuments(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteSearchDocuments(tx, ids)
+	})
+}
+
+// Rebuild 清空索引后添加所有文档
+func (r *searchRepo) Rebuild(ctx context.Context, docs []*models.SearchDocument, terms []*models.SearchTerm) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.SearchTerm{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1 = 1").Delete(&models.SearchDocument{}).Error; err != nil {
+			return err
+		}
+		return createSearchDocuments(tx, docs, terms)
+	})
+}
+
+// Postings 获取词项的倒排索引
+// 数据库排序规则可能不区分大小写和重音，查询结果再按词项精确过滤
+func (r *searchRepo) Postings(ctx context.Context, terms []string) ([]*models.SearchTerm, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var postings []*models.SearchTerm
+	err := r.db.WithContext(ctx).Where("term IN ?", terms).Find(&postings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		wanted[term] = true
+	}
+
+	result := postings[:0]
+	for _, posting := range postings {
+		if wanted[posting.Term] {
+			result = append(result, posting)
+		}
+	}
+	return result, nil
+}
+
+// PrefixPostings 获取以指定前缀开头的词项的倒排索引
+// 数据库排序规则可能不区分大小写和重音，查询结果再按前缀精确过滤
+func (r *searchRepo) PrefixPostings(ctx context.Context, prefix string) ([]*models.SearchTerm, error) {
+	if prefix == "" {
+		return nil, nil
+	}
+
+	var postings []*models.SearchTerm
+	err := r.db.WithContext(ctx).Where(db.HasPrefix(prefix, "term")).Find(&postings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := postings[:0]
+	for _, posting := range postings {
+		if strings.HasPrefix(posting.Term, prefix) {
+			result = append(result, posting)
+		}
+	}
+	return result, nil
+}
+
+// Documents 根据文档 ID 获取文档
+func (r *searchRepo) Documents(ctx context.Context, ids []uint) ([]*models.SearchDocument, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var docs []*models.SearchDocument
+	err := r.db.WithContext(ctx).Where("doc_id IN ?", ids).Find(&docs).Error
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// Stats 获取索引统计信息
+func (r *searchRepo) Stats(ctx context.Context) (*SearchIndexStats, error) {
+	var row struct {
+		Count       int64
+		TotalLength *int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.SearchDocument{}).
+		Select("COUNT(*) AS count, SUM(length) AS total_length").
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &SearchIndexStats{Count: row.Count}
+	if row.Count > 0 && row.TotalLength != nil {
+		stats.AverageLength = float64(*row.TotalLength) / float64(row.Count)
+	}
+	return stats, nil
+}
+
+// deleteSearchDocuments 在事务中删除文档及其倒排索引
+func deleteSearchDocuments(tx *gorm.DB, ids []uint) error {
+	if err := tx.Where("doc_id IN ?", ids).Delete(&models.SearchTerm{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("doc_id IN ?", ids).Delete(&models.SearchDocument{}).Error
+}
+
+// createSearchDocuments 在事务中添加文档及其倒排索引
+func createSearchDocuments(tx *gorm.DB, docs []*models.SearchDocument, terms []*models.SearchTerm) error {
+	if len(docs) == 0 {
+		if len(terms) > 0 {
+			return errors.New("倒排索引缺少对应的文档")
+		}
+		return nil
+	}
+	if err := tx.CreateInBatches(docs, searchTermBatchSize).Error; err != nil {
+		return err
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(terms, searchTermBatchSize).Error
+}
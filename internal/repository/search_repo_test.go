@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/testutil"
+	"testing"
+)
+
+func TestSearchRepo(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	repo := NewSearchRepository(database)
+
+	err := repo.Rebuild(ctx,
+		[]*models.SearchDocument{{DocId: 1, Length: 3}, {DocId: 2, Length: 5}},
+		[]*models.SearchTerm{
+			{Term: "go", DocId: 1, Frequency: 2},
+			{Term: "gin", DocId: 1, Frequency: 1},
+			{Term: "go", DocId: 2, Frequency: 5},
+			{Term: "café", DocId: 2, Frequency: 1},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+
+	stats, err := repo.Stats(ctx)
+	if err != nil || stats.Count != 2 || stats.AverageLength != 4 {
+		t.Fatalf("Stats = %+v, %v", stats, err)
+	}
+
+	postings, err := repo.Postings(ctx, []string{"go", "cafe"})
+	if err != nil || len(postings) != 2 {
+		t.Fatalf("Postings = %+v, %v", postings, err)
+	}
+	for _, posting := range postings {
+		if posting.Term != "go" {
+			t.Errorf("unexpected posting %+v", posting)
+		}
+	}
+
+	// 前缀匹配，前缀中的通配符按普通字符处理
+	postings, err = repo.PrefixPostings(ctx, "g")
+	if err != nil || len(postings) != 3 {
+		t.Errorf("PrefixPostings(g) = %+v, %v", postings, err)
+	}
+	if postings, err := repo.PrefixPostings(ctx, "g_"); err != nil || len(postings) != 0 {
+		t.Errorf("PrefixPostings(g_) = %+v, %v", postings, err)
+	}
+
+	// 替换文档时删除旧的倒排索引
+	err = repo.ReplaceDocuments(ctx,
+		[]*models.SearchDocument{{DocId: 1, Length: 1}},
+		[]*models.SearchTerm{{Term: "rust", DocId: 1, Frequency: 1}},
+	)
+	if err != nil {
+		t.Fatalf("ReplaceDocuments: %v", err)
+	}
+	if n := countRows(t, database, &models.SearchTerm{}, "doc_id = ?", 1); n != 1 {
+		t.Errorf("doc 1 has %d terms, want 1", n)
+	}
+	if docs, err := repo.Documents(ctx, []uint{1}); err != nil || len(docs) != 1 || docs[0].Length != 1 {
+		t.Errorf("Documents = %+v, %v", docs, err)
+	}
+
+	if err := repo.DeleteDocuments(ctx, []uint{2}); err != nil {
+		t.Fatalf("DeleteDocuments: %v", err)
+	}
+	if n := countRows(t, database, &models.SearchTerm{}, "doc_id = ?", 2); n != 0 {
+		t.Errorf("doc 2 still has %d terms", n)
+	}
+
+	// 重建时清空旧索引
+	if err := repo.Rebuild(ctx, nil, nil); err != nil {
+		t.Fatalf("Rebuild(empty): %v", err)
+	}
+	if stats, err := repo.Stats(ctx); err != nil || stats.Count != 0 || stats.AverageLength != 0 {
+		t.Errorf("Stats after rebuild = %+v, %v", stats, err)
+	}
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/db"
+	"nola-go/internal/models"
+	"nola-go/internal/util"
+
+	"gorm.io/gorm"
+)
+
+// SeriesRepository 系列 Repo 接口
+type SeriesRepository interface {
+	// AddSeries 添加系列
+	AddSeries(ctx context.Context, series *models.Series) (*models.Series, error)
+	// DeleteSeries 删除系列 - ID 数组
+	DeleteSeries(ctx context.Context, ids []uint) (bool, error)
+	// DeleteSeriesBySlugs 删除系列 - Slug 数组
+	DeleteSeriesBySlugs(ctx context.Context, slugs []string) (bool, error)
+	// UpdateSeries 修改系列
+	UpdateSeries(ctx context.Context, series *models.Series) (bool, error)
+	// SeriesList 获取所有系列
+	SeriesList(ctx context.Context) ([]*models.Series, error)
+	// SeriesPager 分页获取所有系列
+	SeriesPager(ctx context.Context, page, size int) (*models.Pager[models.Series], error)
+	// SeriesById 获取系列 - ID
+	SeriesById(ctx context.Context, id uint) (*models.Series, error)
+	// SeriesBySlug 获取系列 - 别名
+	SeriesBySlug(ctx context.Context, slug string) (*models.Series, error)
+	// SeriesBySlugs 获取系列 - 别名数组
+	SeriesBySlugs(ctx context.Context, slugs []string) ([]*models.Series, error)
+	// SeriesByPostId 获取系列 - 文章 ID
+	SeriesByPostId(ctx context.Context, postId uint) (*models.Series, error)
+	// SeriesPostIds 获取系列中的文章 ID，按文章在系列中的顺序
+	SeriesPostIds(ctx context.Context, seriesId uint) ([]uint, error)
+	// UpdateSeriesPosts 设置系列中的文章及顺序，文章原来所属的其他系列会被移除
+	UpdateSeriesPosts(ctx context.Context, seriesId uint, postIds []uint) (bool, error)
+}
+
+type seriesRepo struct {
+	db *gorm.DB
+}
+
+func NewSeriesRepository(db *gorm.DB) SeriesRepository {
+	return &seriesRepo{
+		db: db,
+	}
+}
+
+// AddSeries 添加系列
+func (r *seriesRepo) AddSeries(ctx context.Context, series *models.Series) (*models.Series, error) {
+	err := r.db.WithContext(ctx).Create(series).Error
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// DeleteSeries 删除系列 - ID 数组
+func (r *seriesRepo) DeleteSeries(ctx context.Context, ids []uint) (success bool, err error) {
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	tx := r.db.WithContext(ctx).Begin()
+	defer handlePanic(tx)(&success, &err)
+
+	// 先删除系列文章关联信息
+	err = tx.Where("series_id IN ?", ids).Delete(&models.PostSeries{}).Error
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// 删除系列
+	ret := tx.Delete(&models.Series{}, ids)
+	if err := ret.Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+
+	return ret.RowsAffected > 0, nil
+}
+
+// DeleteSeriesBySlugs 删除系列 - Slug 数组
+func (r *seriesRepo) DeleteSeriesBySlugs(ctx context.Context, slugs []string) (bool, error) {
+	if len(slugs) == 0 {
+		return false, nil
+	}
+
+	// 先根据别名获取到对应的系列
+	seriesList, err := r.SeriesBySlugs(ctx, slugs)
+	if err != nil {
+		return false, err
+	}
+
+	ids := util.Map(seriesList, func(series *models.Series) uint {
+		return series.SeriesId
+	})
+
+	return r.DeleteSeries(ctx, ids)
+}
+
+// UpdateSeries 修改系列
+func (r *seriesRepo) UpdateSeries(ctx context.Context, series *models.Series) (bool, error) {
+	updates := map[string]any{
+		"display_name": series.DisplayName,
+		"slug":         series.Slug,
+		"description":  series.Description,
+		"cover":        series.Cover,
+	}
+	ret := r.db.WithContext(ctx).Where("series_id = ?", series.SeriesId).Model(&models.Series{}).Updates(updates)
+	return ret.RowsAffected > 0, ret.Error
+}
+
+// SeriesList 获取所有系列
+func (r *seriesRepo) SeriesList(ctx context.Context) ([]*models.Series, error) {
+	var seriesList []*models.Series
+	if err := r.sqlSelectSeries().WithContext(ctx).Scan(&seriesList).Error; err != nil {
+		return nil, err
+	}
+	return seriesList, nil
+}
+
+// SeriesPager 分页获取所有系列
+func (r *seriesRepo) SeriesPager(ctx context.Context, page, size int) (*models.Pager[models.Series], error) {
+	return db.PagerBuilder[models.Series](ctx, r.db, page, size, func(query *gorm.DB) *gorm.DB {
+		return r.sqlSelectSeries()
+	})
+}
+
+// SeriesById 获取系列 - ID
+func (r *seriesRepo) SeriesById(ctx context.Context, id uint) (*models.Series, error) {
+	return r.first(r.sqlSelectSeries().WithContext(ctx).Where("s.series_id = ?", id))
+}
+
+// SeriesBySlug 获取系列 - 别名
+func (r *seriesRepo) SeriesBySlug(ctx context.Context, slug string) (*models.Series, error) {
+	return r.first(r.sqlSelectSeries().WithContext(ctx).Where("s.slug = ?", slug))
+}
+
+// SeriesBySlugs 获取系列 - 别名数组
+func (r *seriesRepo) SeriesBySlugs(ctx context.Context, slugs []string) ([]*models.Series, error) {
+	var seriesList []*models.Series
+	err := r.sqlSelectSeries().WithContext(ctx).Where("s.slug IN ?", slugs).Scan(&seriesList).Error
+	if err != nil {
+		return nil, err
+	}
+	return seriesList, nil
+}
+
+// SeriesByPostId 获取系列 - 文章 ID
+func (r *seriesRepo) SeriesByPostId(ctx context.Context, postId uint) (*models.Series, error) {
+	var seriesId uint
+	err := r.db.WithContext(ctx).Model(&models.PostSeries{}).
+		Select("series_id").
+		Where("post_id = ?", postId).
+		Limit(1).
+		Scan(&seriesId).Error
+	if err != nil || seriesId == 0 {
+		return nil, err
+	}
+	return r.SeriesById(ctx, seriesId)
+}
+
+// SeriesPostIds 获取系列中的文章 ID，按文章在系列中的顺序
+func (r *seriesRepo) SeriesPostIds(ctx context.Context, seriesId uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.PostSeries{}).
+		Where("series_id = ?", seriesId).
+		Order("sort ASC").
+		Order("post_series_id ASC").
+		Pluck("post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpdateSeriesPosts 设置系列中的文章及顺序，文章原来所属的其他系列会被移除
+//   - seriesId: 系列 ID
+//   - postIds: 文章 ID 数组，数组顺序即文章在系列中的顺序，为空时清空系列
+func (r *seriesRepo) UpdateSeriesPosts(ctx context.Context, seriesId uint, postIds []uint) (success bool, err error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer handlePanic(tx)(&success, &err)
+
+	// 删除系列原有的文章
+	query := tx.Where("series_id = ?", seriesId)
+	if len(postIds) > 0 {
+		// 一篇文章最多属于一个系列，从其他系列中移除
+		query = query.Or("post_id IN ?", postIds)
+	}
+	if err = query.Delete(&models.PostSeries{}).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if len(postIds) > 0 {
+		postSeries := make([]*models.PostSeries, 0, len(postIds))
+		for i, postId := range postIds {
+			postSeries = append(postSeries, &models.PostSeries{
+				SeriesId: seriesId,
+				PostId:   postId,
+				Sort:     i,
+			})
+		}
+		if err = tx.Create(&postSeries).Error; err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// first 获取查询结果中的第一个系列，不存在时返回 nil
+func (r *seriesRepo) first(query *gorm.DB) (*models.Series, error) {
+	var series models.Series
+	if err := query.First(&series).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// sqlSelectSeries 获取系列和对应的文章数量
+func (r *seriesRepo) sqlSelectSeries() *gorm.DB {
+	return r.db.Table("series s").
+		Joins("LEFT JOIN post_series ps ON s.series_id = ps.series_id").
+		Select("s.series_id, s.display_name, s.slug, s.description, s.cover, s.create_time, COUNT(ps.post_series_id) as post_count").
+		Group("s.series_id").
+		Order("s.series_id DESC")
+}
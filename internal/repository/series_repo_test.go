@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/models"
+	"nola-go/internal/testutil"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// newTestSeriesRepo 创建系列 Repo 测试环境
+func newTestSeriesRepo(t *testing.T) (SeriesRepository, *testutil.Fixture, *gorm.DB) {
+	database := testutil.NewDB(t)
+	return NewSeriesRepository(database), testutil.NewFixture(t, database), database
+}
+
+// addTestSeries 添加测试系列
+func addTestSeries(t *testing.T, repo SeriesRepository, slug string) *models.Series {
+	t.Helper()
+	series, err := repo.AddSeries(context.Background(), &models.Series{DisplayName: slug, Slug: slug})
+	if err != nil {
+		t.Fatalf("AddSeries: %v", err)
+	}
+	return series
+}
+
+func TestSeriesRepo_UpdateSeriesPosts(t *testing.T) {
+	ctx := context.Background()
+	repo, f, database := newTestSeriesRepo(t)
+	a, b := addTestSeries(t, repo, "a"), addTestSeries(t, repo, "b")
+	p1, p2, p3 := f.Post("p1", "content"), f.Post("p2", "content"), f.Post("p3", "content")
+
+	if ok, err := repo.UpdateSeriesPosts(ctx, a.SeriesId, []uint{p3.PostId, p1.PostId, p2.PostId}); err != nil || !ok {
+		t.Fatalf("UpdateSeriesPosts = %v, %v", ok, err)
+	}
+	ids, err := repo.SeriesPostIds(ctx, a.SeriesId)
+	if err != nil || !slices.Equal(ids, []uint{p3.PostId, p1.PostId, p2.PostId}) {
+		t.Errorf("SeriesPostIds = %v, %v", ids, err)
+	}
+
+	// 文章移到另一个系列后从原系列中移除
+	if _, err := repo.UpdateSeriesPosts(ctx, b.SeriesId, []uint{p1.PostId}); err != nil {
+		t.Fatalf("UpdateSeriesPosts(b): %v", err)
+	}
+	ids, _ = repo.SeriesPostIds(ctx, a.SeriesId)
+	if !slices.Equal(ids, []uint{p3.PostId, p2.PostId}) {
+		t.Errorf("SeriesPostIds(a) = %v", ids)
+	}
+	series, err := repo.SeriesByPostId(ctx, p1.PostId)
+	if err != nil || series == nil || series.Slug != "b" || series.PostCount != 1 {
+		t.Errorf("SeriesByPostId = %+v, %v", series, err)
+	}
+	if series, err := repo.SeriesByPostId(ctx, 999); series != nil || err != nil {
+		t.Errorf("SeriesByPostId(999) = %+v, %v", series, err)
+	}
+
+	// 清空系列
+	if _, err := repo.UpdateSeriesPosts(ctx, a.SeriesId, nil); err != nil {
+		t.Fatalf("UpdateSeriesPosts(nil): %v", err)
+	}
+	if n := countRows(t, database, &models.PostSeries{}, "series_id = ?", a.SeriesId); n != 0 {
+		t.Errorf("post_series rows = %d, want 0", n)
+	}
+
+	t.Run("插入失败时回滚", func(t *testing.T) {
+		if _, err := repo.UpdateSeriesPosts(ctx, b.SeriesId, []uint{p1.PostId, p2.PostId}); err != nil {
+			t.Fatalf("UpdateSeriesPosts: %v", err)
+		}
+		testutil.InjectError(t, database, testutil.OpCreate, "post_series", 0)
+		if _, err := repo.UpdateSeriesPosts(ctx, b.SeriesId, []uint{p3.PostId}); !errors.Is(err, testutil.ErrInjected) {
+			t.Fatalf("UpdateSeriesPosts err = %v, want injected error", err)
+		}
+		ids, _ := repo.SeriesPostIds(ctx, b.SeriesId)
+		if !slices.Equal(ids, []uint{p1.PostId, p2.PostId}) {
+			t.Errorf("SeriesPostIds = %v, want unchanged", ids)
+		}
+	})
+}
+
+func TestSeriesRepo_Delete(t *testing.T) {
+	ctx := context.Background()
+	repo, f, database := newTestSeriesRepo(t)
+	a, b := addTestSeries(t, repo, "a"), addTestSeries(t, repo, "b")
+	p1, p2 := f.Post("p1", "content"), f.Post("p2", "content")
+	_, _ = repo.UpdateSeriesPosts(ctx, a.SeriesId, []uint{p1.PostId})
+	_, _ = repo.UpdateSeriesPosts(ctx, b.SeriesId, []uint{p2.PostId})
+
+	if ok, err := repo.DeleteSeriesBySlugs(ctx, []string{"a"}); err != nil || !ok {
+		t.Fatalf("DeleteSeriesBySlugs = %v, %v", ok, err)
+	}
+	list, _ := repo.SeriesList(ctx)
+	if len(list) != 1 || list[0].Slug != "b" {
+		t.Errorf("SeriesList = %+v", list)
+	}
+	if n := countRows(t, database, &models.PostSeries{}, "1 = 1"); n != 1 {
+		t.Errorf("post_series rows = %d, want 1", n)
+	}
+	// 文章不会被删除
+	if n := countRows(t, database, &models.Post{}, "post_id = ?", p1.PostId); n != 1 {
+		t.Errorf("post rows = %d, want 1", n)
+	}
+}
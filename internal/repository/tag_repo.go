@@ -81,7 +81,7 @@ func (r *tagRepo) DeleteTags(ctx context.Context, tagIds []uint) (bool, error) {
 	}
 
 	// 先尝试删除标签文章关联信息
-	ret := tx.Where("`tag_id` IN ?", tagIds).Delete(&models.PostTag{})
+	ret := tx.Where("tag_id IN ?", tagIds).Delete(&models.PostTag{})
 	if err := ret.Error; err != nil {
 		tx.Rollback()
 		return false, err
@@ -136,10 +136,10 @@ func (r *tagRepo) UpdateTag(ctx context.Context, tag *models.Tag) (bool, error)
 	updates := map[string]any{
 		"display_name": tag.DisplayName,
 		"slug":         tag.Slug,
-		"color":        *tag.Color,
+		"color":        tag.Color,
 	}
 
-	err := r.db.WithContext(ctx).Where("`tag_id` = ?", tag.TagId).Model(&models.Tag{}).Updates(updates).Error
+	err := r.db.WithContext(ctx).Where("tag_id = ?", tag.TagId).Model(&models.Tag{}).Updates(updates).Error
 	if err != nil {
 		return false, err
 	}
@@ -211,7 +211,7 @@ func (r *tagRepo) TagByIds(ctx context.Context, ids []uint) ([]*models.Tag, erro
 // TopTags 获取文章数量最多的 6 个标签
 func (r *tagRepo) TopTags(ctx context.Context) ([]*models.Tag, error) {
 	var tags []*models.Tag
-	err := r.sqlSelectTag().WithContext(ctx).Order("post_count DESC").Limit(6).Scan(&tags).Error
+	err := r.sqlSelectTag().WithContext(ctx).Order(db.ReorderBy("post_count", true)).Limit(6).Scan(&tags).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/models"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// newTestTagRepo 创建标签 Repo 测试环境
+func newTestTagRepo(t *testing.T) (TagRepository, *testutil.Fixture, *gorm.DB) {
+	database := testutil.NewDB(t)
+	return NewTagRepository(database), testutil.NewFixture(t, database), database
+}
+
+// tagSlugs 获取标签别名数组
+func tagSlugs(tags []*models.Tag) []string {
+	slugs := make([]string, len(tags))
+	for i, tag := range tags {
+		slugs[i] = tag.Slug
+	}
+	return slugs
+}
+
+func TestTagRepo_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		delete func(repo TagRepository, a *models.Tag) (bool, error)
+		wantOk bool
+		want   []string
+	}{
+		{"DeleteTags", func(repo TagRepository, a *models.Tag) (bool, error) {
+			return repo.DeleteTags(ctx, []uint{a.TagId})
+		}, true, []string{"b"}},
+		{"DeleteTags 空数组", func(repo TagRepository, _ *models.Tag) (bool, error) {
+			return repo.DeleteTags(ctx, nil)
+		}, false, []string{"b", "a"}},
+		{"DeleteTagBySlugs", func(repo TagRepository, _ *models.Tag) (bool, error) {
+			return repo.DeleteTagBySlugs(ctx, []string{"a", "missing"})
+		}, true, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, f, database := newTestTagRepo(t)
+			a, b := f.Tag("a"), f.Tag("b")
+			f.Post("post", "content", a, b)
+
+			ok, err := tt.delete(repo, a)
+			if err != nil || ok != tt.wantOk {
+				t.Fatalf("delete = %v, %v, want %v", ok, err, tt.wantOk)
+			}
+			tags, _ := repo.Tags(ctx)
+			assertStrings(t, tagSlugs(tags), tt.want)
+			if n := countRows(t, database, &models.PostTag{}, "1 = 1"); n != int64(len(tt.want)) {
+				t.Errorf("post_tag rows = %d, want %d", n, len(tt.want))
+			}
+		})
+	}
+
+	t.Run("删除标签失败时回滚", func(t *testing.T) {
+		repo, f, database := newTestTagRepo(t)
+		a := f.Tag("a")
+		f.Post("post", "content", a)
+		testutil.InjectError(t, database, testutil.OpDelete, "tag", 0)
+
+		if _, err := repo.DeleteTags(ctx, []uint{a.TagId}); !errors.Is(err, testutil.ErrInjected) {
+			t.Fatalf("DeleteTags err = %v, want injected error", err)
+		}
+		if n := countRows(t, database, &models.PostTag{}, "1 = 1"); n != 1 {
+			t.Errorf("post_tag rows = %d, want 1", n)
+		}
+	})
+}
+
+func TestTagRepo_Update(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		color     *string
+		wantColor *string
+	}{
+		{"设置颜色", util.StringPtr("#fff"), util.StringPtr("#fff")},
+		{"空白颜色置空", util.StringPtr(" "), nil},
+		{"没有颜色", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, _, _ := newTestTagRepo(t)
+			tag, err := repo.AddTag(ctx, &models.Tag{DisplayName: "Go", Slug: "go", Color: util.StringPtr("#000")})
+			if err != nil {
+				t.Fatalf("AddTag: %v", err)
+			}
+
+			ok, err := repo.UpdateTag(ctx, &models.Tag{TagId: tag.TagId, DisplayName: "Golang", Slug: "golang", Color: tt.color})
+			if err != nil || !ok {
+				t.Fatalf("UpdateTag = %v, %v", ok, err)
+			}
+			got, err := repo.TagById(ctx, tag.TagId)
+			if err != nil || got.Slug != "golang" || got.DisplayName != "Golang" {
+				t.Fatalf("TagById = %+v, %v", got, err)
+			}
+			if (got.Color == nil) != (tt.wantColor == nil) || (got.Color != nil && *got.Color != *tt.wantColor) {
+				t.Errorf("Color = %v, want %v", got.Color, tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestTagRepo_Query(t *testing.T) {
+	ctx := context.Background()
+	repo, f, _ := newTestTagRepo(t)
+
+	var tags []*models.Tag
+	for _, slug := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		tags = append(tags, f.Tag(slug))
+	}
+	// b 有 3 篇文章，c 有 2 篇，a 有 1 篇
+	a, b, c := tags[0], tags[1], tags[2]
+	post := f.Post("p1", "content", a, b, c)
+	f.Post("p2", "content", b, c)
+	f.Post("p3", "content", b)
+
+	if count, err := repo.TagCount(ctx); err != nil || count != 7 {
+		t.Errorf("TagCount = %d, %v, want 7", count, err)
+	}
+
+	top, err := repo.TopTags(ctx)
+	if err != nil || len(top) != 6 {
+		t.Fatalf("TopTags = %v, %v", top, err)
+	}
+	assertStrings(t, tagSlugs(top[:3]), []string{"b", "c", "a"})
+	if top[0].PostCount != 3 {
+		t.Errorf("PostCount = %d, want 3", top[0].PostCount)
+	}
+
+	byPost, err := repo.TagByPostId(ctx, post.PostId)
+	if err != nil {
+		t.Fatalf("TagByPostId: %v", err)
+	}
+	assertStrings(t, tagSlugs(byPost), []string{"c", "b", "a"})
+	if empty, err := repo.TagByPostId(ctx, 9999); err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("TagByPostId(missing) = %v, %v", empty, err)
+	}
+
+	byIds, err := repo.TagByIds(ctx, []uint{a.TagId, c.TagId})
+	if err != nil {
+		t.Fatalf("TagByIds: %v", err)
+	}
+	assertStrings(t, tagSlugs(byIds), []string{"c", "a"})
+
+	bySlugs, err := repo.TagBySlugs(ctx, []string{"a", "b", "missing"})
+	if err != nil {
+		t.Fatalf("TagBySlugs: %v", err)
+	}
+	assertStrings(t, tagSlugs(bySlugs), []string{"b", "a"})
+
+	singleTests := []struct {
+		name string
+		get  func() (*models.Tag, error)
+		want string
+	}{
+		{"TagById", func() (*models.Tag, error) { return repo.TagById(ctx, b.TagId) }, "b"},
+		{"TagById 不存在", func() (*models.Tag, error) { return repo.TagById(ctx, 9999) }, ""},
+		{"TagBySlug", func() (*models.Tag, error) { return repo.TagBySlug(ctx, "c") }, "c"},
+		{"TagBySlug 不存在", func() (*models.Tag, error) { return repo.TagBySlug(ctx, "missing") }, ""},
+		{"TagByDisplayName", func() (*models.Tag, error) { return repo.TagByDisplayName(ctx, "a") }, "a"},
+		{"TagByDisplayName 不存在", func() (*models.Tag, error) { return repo.TagByDisplayName(ctx, "missing") }, ""},
+	}
+	for _, tt := range singleTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if (got == nil && tt.want != "") || (got != nil && got.Slug != tt.want) {
+				t.Errorf("%s = %+v, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	pager, err := repo.TagsPager(ctx, 2, 5)
+	if err != nil {
+		t.Fatalf("TagsPager: %v", err)
+	}
+	if pager.TotalData != 7 || pager.TotalPages != 2 {
+		t.Errorf("TagsPager total = %d/%d, want 7/2", pager.TotalData, pager.TotalPages)
+	}
+	assertStrings(t, tagSlugs(pager.Data), []string{"b", "a"})
+}
@@ -4,8 +4,9 @@ import (
 	"context"
 	"errors"
 	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
 	"nola-go/internal/models/request"
-	"nola-go/internal/util"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -16,14 +17,36 @@ type UserRepository interface {
 	Create(ctx context.Context, u *models.User) error
 	// UpdateUser 更新用户信息
 	UpdateUser(ctx context.Context, userId uint, userInfo *request.UserInfoRequest) (bool, error)
-	// UpdatePassword 更新用户密码
-	UpdatePassword(ctx context.Context, userId uint, hash *util.SaltedHash) (bool, error)
+	// UpdatePassword 更新用户密码哈希，并清空旧版哈希使用的盐值
+	UpdatePassword(ctx context.Context, userId uint, hash string) (bool, error)
 	// GetByUsername 根据用户名获取用户
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
 	// GetById 根据用户 ID 获取用户
 	GetById(ctx context.Context, userId uint) (*models.User, error)
-	// GetAllUsers 获取所有用户
+	// GetByIds 根据用户 ID 数组获取用户
+	GetByIds(ctx context.Context, userIds []uint) ([]*models.User, error)
+	// GetAllUsers 获取所有用户（按用户 ID 升序）
 	GetAllUsers(ctx context.Context) ([]*models.User, error)
+	// UpdateRole 修改用户角色
+	UpdateRole(ctx context.Context, userId uint, role enum.UserRole) (bool, error)
+	// CountByRole 获取指定角色的用户数量
+	CountByRole(ctx context.Context, role enum.UserRole) (int64, error)
+	// Delete 删除用户及其恢复码和 API 令牌，并将用户的文章转移给另一个用户
+	Delete(ctx context.Context, userId uint, transferTo uint) (bool, error)
+	// SetTotpSecret 设置待验证的 TOTP 密钥（两步验证尚未开启）
+	SetTotpSecret(ctx context.Context, userId uint, secret string) (bool, error)
+	// EnableTotp 开启两步验证，并替换所有恢复码
+	EnableTotp(ctx context.Context, userId uint, codeHashes []string) (bool, error)
+	// DisableTotp 关闭两步验证，并删除 TOTP 密钥和所有恢复码
+	DisableTotp(ctx context.Context, userId uint) (bool, error)
+	// UseTotpStep 记录已使用的 TOTP 时间步，时间步不大于上次使用的时间步时返回 false
+	UseTotpStep(ctx context.Context, userId uint, step int64) (bool, error)
+	// ReplaceRecoveryCodes 删除用户所有恢复码并添加新的恢复码
+	ReplaceRecoveryCodes(ctx context.Context, userId uint, codeHashes []string) error
+	// UseRecoveryCode 使用恢复码，恢复码不存在或已使用时返回 false
+	UseRecoveryCode(ctx context.Context, userId uint, codeHash string) (bool, error)
+	// RecoveryCodeCount 获取用户未使用的恢复码数量
+	RecoveryCodeCount(ctx context.Context, userId uint) (int64, error)
 }
 
 type userRepo struct {
@@ -54,11 +77,11 @@ func (r *userRepo) UpdateUser(ctx context.Context, userId uint, userInfo *reques
 	return ret.RowsAffected > 0, ret.Error
 }
 
-// UpdatePassword 更新用户密码
-func (r *userRepo) UpdatePassword(ctx context.Context, userId uint, hash *util.SaltedHash) (bool, error) {
-	ret := r.db.WithContext(ctx).Model(&models.User{}).Where("user_id = ?", userId).Updates(models.User{
-		Password: hash.Hash,
-		Salt:     hash.Salt,
+// UpdatePassword 更新用户密码哈希，并清空旧版哈希使用的盐值
+func (r *userRepo) UpdatePassword(ctx context.Context, userId uint, hash string) (bool, error) {
+	ret := r.db.WithContext(ctx).Model(&models.User{}).Where("user_id = ?", userId).Updates(map[string]any{
+		"password": hash,
+		"salt":     "",
 	})
 	return ret.RowsAffected > 0, ret.Error
 }
@@ -87,11 +110,202 @@ func (r *userRepo) GetById(ctx context.Context, userId uint) (*models.User, erro
 	return &u, nil
 }
 
-// GetAllUsers 获取所有用户
+// GetByIds 根据用户 ID 数组获取用户
+func (r *userRepo) GetByIds(ctx context.Context, userIds []uint) ([]*models.User, error) {
+	if len(userIds) == 0 {
+		return []*models.User{}, nil
+	}
+
+	var users []*models.User
+	if err := r.db.WithContext(ctx).Where("user_id IN ?", userIds).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetAllUsers 获取所有用户（按用户 ID 升序）
 func (r *userRepo) GetAllUsers(ctx context.Context) ([]*models.User, error) {
 	var users []*models.User
-	if err := r.db.WithContext(ctx).Find(&users).Error; err != nil {
+	if err := r.db.WithContext(ctx).Order("user_id ASC").Find(&users).Error; err != nil {
 		return nil, err
 	}
 	return users, nil
 }
+
+// UpdateRole 修改用户角色
+func (r *userRepo) UpdateRole(ctx context.Context, userId uint, role enum.UserRole) (bool, error) {
+	ret := r.db.WithContext(ctx).Model(&models.User{}).Where("user_id = ?", userId).Update("role", role)
+	return ret.RowsAffected > 0, ret.Error
+}
+
+// CountByRole 获取指定角色的用户数量
+func (r *userRepo) CountByRole(ctx context.Context, role enum.UserRole) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Where("role = ?", role).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Delete 删除用户及其恢复码和 API 令牌，并将用户的文章转移给另一个用户
+func (r *userRepo) Delete(ctx context.Context, userId uint, transferTo uint) (bool, error) {
+	var deleted bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Post{}).Where("author_id = ?", userId).Update("author_id", transferTo).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userId).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userId).Delete(&models.ApiToken{}).Error; err != nil {
+			return err
+		}
+
+		ret := tx.Where("user_id = ?", userId).Delete(&models.User{})
+		if ret.Error != nil {
+			return ret.Error
+		}
+		deleted = ret.RowsAffected > 0
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return deleted, nil
+}
+
+// SetTotpSecret 设置待验证的 TOTP 密钥（两步验证尚未开启）
+func (r *userRepo) SetTotpSecret(ctx context.Context, userId uint, secret string) (bool, error) {
+	ret := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("user_id = ?", userId).
+		Updates(map[string]any{
+			"totp_secret":    secret,
+			"totp_enabled":   false,
+			"totp_last_step": nil,
+		})
+	return ret.RowsAffected > 0, ret.Error
+}
+
+// EnableTotp 开启两步验证，并替换所有恢复码
+func (r *userRepo) EnableTotp(ctx context.Context, userId uint, codeHashes []string) (bool, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	ret := tx.Model(&models.User{}).
+		Where("user_id = ? AND totp_secret IS NOT NULL", userId).
+		Update("totp_enabled", true)
+	if ret.Error != nil {
+		tx.Rollback()
+		return false, ret.Error
+	}
+	if ret.RowsAffected == 0 {
+		tx.Rollback()
+		return false, nil
+	}
+
+	if err := r.replaceRecoveryCodes(tx, userId, codeHashes); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DisableTotp 关闭两步验证，并删除 TOTP 密钥和所有恢复码
+func (r *userRepo) DisableTotp(ctx context.Context, userId uint) (bool, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	ret := tx.Model(&models.User{}).
+		Where("user_id = ?", userId).
+		Updates(map[string]any{
+			"totp_secret":    nil,
+			"totp_enabled":   false,
+			"totp_last_step": nil,
+		})
+	if ret.Error != nil {
+		tx.Rollback()
+		return false, ret.Error
+	}
+
+	if err := tx.Where("user_id = ?", userId).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return ret.RowsAffected > 0, nil
+}
+
+// UseTotpStep 记录已使用的 TOTP 时间步，时间步不大于上次使用的时间步时返回 false
+func (r *userRepo) UseTotpStep(ctx context.Context, userId uint, step int64) (bool, error) {
+	ret := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("user_id = ? AND (totp_last_step IS NULL OR totp_last_step < ?)", userId, step).
+		Update("totp_last_step", step)
+	return ret.RowsAffected > 0, ret.Error
+}
+
+// ReplaceRecoveryCodes 删除用户所有恢复码并添加新的恢复码
+func (r *userRepo) ReplaceRecoveryCodes(ctx context.Context, userId uint, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.replaceRecoveryCodes(tx, userId, codeHashes)
+	})
+}
+
+// UseRecoveryCode 使用恢复码，恢复码不存在或已使用时返回 false
+func (r *userRepo) UseRecoveryCode(ctx context.Context, userId uint, codeHash string) (bool, error) {
+	ret := r.db.WithContext(ctx).
+		Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_time IS NULL", userId, codeHash).
+		Update("used_time", time.Now().UnixMilli())
+	return ret.RowsAffected > 0, ret.Error
+}
+
+// RecoveryCodeCount 获取用户未使用的恢复码数量
+func (r *userRepo) RecoveryCodeCount(ctx context.Context, userId uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND used_time IS NULL", userId).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// replaceRecoveryCodes 在事务中删除用户所有恢复码并添加新的恢复码
+func (r *userRepo) replaceRecoveryCodes(tx *gorm.DB, userId uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userId).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	now := time.Now().UnixMilli()
+	codes := make([]*models.UserRecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = &models.UserRecoveryCode{UserId: userId, CodeHash: hash, CreateTime: now}
+	}
+	return tx.Create(&codes).Error
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+)
+
+func TestUserRepo(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(testutil.NewDB(t))
+
+	user := &models.User{
+		Username: "admin", Email: "admin@a.com", DisplayName: "Admin",
+		Password: "legacy-hash", Salt: "salt", CreateDate: 1,
+	}
+	if err := repo.Create(ctx, user); err != nil || user.UserId == 0 {
+		t.Fatalf("Create = %d, %v", user.UserId, err)
+	}
+	if err := repo.Create(ctx, &models.User{Username: "admin", Password: "x", Salt: "x"}); err == nil {
+		t.Error("Create with duplicate username should fail")
+	}
+
+	ok, err := repo.UpdateUser(ctx, user.UserId, &request.UserInfoRequest{
+		Username: "root", Email: "root@a.com", DisplayName: "Root", Description: util.StringPtr("desc"),
+	})
+	if err != nil || !ok {
+		t.Fatalf("UpdateUser = %v, %v", ok, err)
+	}
+
+	if ok, err := repo.UpdatePassword(ctx, user.UserId, "$argon2id$new-hash"); err != nil || !ok {
+		t.Fatalf("UpdatePassword = %v, %v", ok, err)
+	}
+
+	tests := []struct {
+		name string
+		get  func() (*models.User, error)
+		want string
+	}{
+		{"GetById", func() (*models.User, error) { return repo.GetById(ctx, user.UserId) }, "root"},
+		{"GetById 不存在", func() (*models.User, error) { return repo.GetById(ctx, 9999) }, ""},
+		{"GetByUsername", func() (*models.User, error) { return repo.GetByUsername(ctx, "root") }, "root"},
+		{"GetByUsername 旧用户名", func() (*models.User, error) { return repo.GetByUsername(ctx, "admin") }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if (got == nil && tt.want != "") || (got != nil && got.Username != tt.want) {
+				t.Errorf("%s = %+v, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	got, _ := repo.GetById(ctx, user.UserId)
+	if got.Email != "root@a.com" || got.Description == nil || got.LastLoginDate != nil {
+		t.Errorf("GetById = %+v", got)
+	}
+	if got.Password != "$argon2id$new-hash" || got.Salt != "" {
+		t.Errorf("Password, Salt = %q, %q, want new hash and empty salt", got.Password, got.Salt)
+	}
+
+	users, err := repo.GetAllUsers(ctx)
+	if err != nil || len(users) != 1 {
+		t.Errorf("GetAllUsers = %v, %v", users, err)
+	}
+}
+
+func TestUserRepo_Totp(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	repo := NewUserRepository(database)
+
+	user := &models.User{Username: "admin", Email: "admin@a.com", DisplayName: "Admin", Password: "x", Salt: "x", CreateDate: 1}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// 没有密钥时不能开启
+	if ok, err := repo.EnableTotp(ctx, user.UserId, []string{"a"}); err != nil || ok {
+		t.Fatalf("EnableTotp(no secret) = %v, %v", ok, err)
+	}
+
+	if ok, err := repo.SetTotpSecret(ctx, user.UserId, "SECRET"); err != nil || !ok {
+		t.Fatalf("SetTotpSecret = %v, %v", ok, err)
+	}
+	if ok, err := repo.EnableTotp(ctx, user.UserId, []string{"a", "b"}); err != nil || !ok {
+		t.Fatalf("EnableTotp = %v, %v", ok, err)
+	}
+	got, _ := repo.GetById(ctx, user.UserId)
+	if !got.TotpEnabled || got.TotpSecret == nil || *got.TotpSecret != "SECRET" {
+		t.Fatalf("GetById = %+v", got)
+	}
+
+	stepTests := []struct {
+		name string
+		step int64
+		want bool
+	}{
+		{"首次使用", 10, true},
+		{"重复使用", 10, false},
+		{"旧时间步", 9, false},
+		{"新时间步", 11, true},
+	}
+	for _, tt := range stepTests {
+		t.Run("UseTotpStep/"+tt.name, func(t *testing.T) {
+			if ok, err := repo.UseTotpStep(ctx, user.UserId, tt.step); err != nil || ok != tt.want {
+				t.Errorf("UseTotpStep(%d) = %v, %v, want %v", tt.step, ok, err, tt.want)
+			}
+		})
+	}
+
+	if ok, err := repo.UseRecoveryCode(ctx, user.UserId, "a"); err != nil || !ok {
+		t.Fatalf("UseRecoveryCode = %v, %v", ok, err)
+	}
+	if ok, _ := repo.UseRecoveryCode(ctx, user.UserId, "a"); ok {
+		t.Error("UseRecoveryCode should not reuse code")
+	}
+	if ok, _ := repo.UseRecoveryCode(ctx, user.UserId, "missing"); ok {
+		t.Error("UseRecoveryCode(missing) should return false")
+	}
+	if count, err := repo.RecoveryCodeCount(ctx, user.UserId); err != nil || count != 1 {
+		t.Errorf("RecoveryCodeCount = %d, %v, want 1", count, err)
+	}
+
+	if err := repo.ReplaceRecoveryCodes(ctx, user.UserId, []string{"c", "d", "e"}); err != nil {
+		t.Fatalf("ReplaceRecoveryCodes: %v", err)
+	}
+	if count, _ := repo.RecoveryCodeCount(ctx, user.UserId); count != 3 {
+		t.Errorf("RecoveryCodeCount = %d, want 3", count)
+	}
+
+	t.Run("替换恢复码失败时回滚", func(t *testing.T) {
+		testutil.InjectError(t, database, testutil.OpCreate, "user_recovery_code", 0)
+		if err := repo.ReplaceRecoveryCodes(ctx, user.UserId, []string{"f"}); !errors.Is(err, testutil.ErrInjected) {
+			t.Fatalf("ReplaceRecoveryCodes err = %v, want injected error", err)
+		}
+		if count, _ := repo.RecoveryCodeCount(ctx, user.UserId); count != 3 {
+			t.Errorf("RecoveryCodeCount = %d, want 3", count)
+		}
+	})
+
+	if ok, err := repo.DisableTotp(ctx, user.UserId); err != nil || !ok {
+		t.Fatalf("DisableTotp = %v, %v", ok, err)
+	}
+	got, _ = repo.GetById(ctx, user.UserId)
+	if got.TotpEnabled || got.TotpSecret != nil || got.TotpLastStep != nil {
+		t.Errorf("GetById after DisableTotp = %+v", got)
+	}
+	if n := countRows(t, database, &models.UserRecoveryCode{}, "1 = 1"); n != 0 {
+		t.Errorf("user_recovery_code rows = %d, want 0", n)
+	}
+}
+
+func TestUserRepo_Role(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	repo := NewUserRepository(database)
+
+	owner := &models.User{Username: "owner", Email: "o@a.com", DisplayName: "Owner", Password: "x", Role: enum.UserRoleOwner, CreateDate: 1}
+	author := &models.User{Username: "author", Email: "a@a.com", DisplayName: "Author", Password: "x", Role: enum.UserRoleAuthor, CreateDate: 2}
+	for _, u := range []*models.User{owner, author} {
+		if err := repo.Create(ctx, u); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	if count, err := repo.CountByRole(ctx, enum.UserRoleOwner); err != nil || count != 1 {
+		t.Errorf("CountByRole = %d, %v, want 1", count, err)
+	}
+	if ok, err := repo.UpdateRole(ctx, author.UserId, enum.UserRoleEditor); err != nil || !ok {
+		t.Fatalf("UpdateRole = %v, %v", ok, err)
+	}
+	users, err := repo.GetByIds(ctx, []uint{author.UserId, 9999})
+	if err != nil || len(users) != 1 || users[0].Role != enum.UserRoleEditor {
+		t.Fatalf("GetByIds = %+v, %v", users, err)
+	}
+
+	post := &models.Post{Title: "t", Slug: "t", AuthorId: &author.UserId, Status: enum.PostStatusPublished, Visible: enum.PostVisibleVisible}
+	if err := database.Create(post).Error; err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+	if ok, err := repo.Delete(ctx, author.UserId, owner.UserId); err != nil || !ok {
+		t.Fatalf("Delete = %v, %v", ok, err)
+	}
+	if got, _ := repo.GetById(ctx, author.UserId); got != nil {
+		t.Errorf("GetById after Delete = %+v", got)
+	}
+	if n := countRows(t, database, &models.Post{}, "author_id = ?", owner.UserId); n != 1 {
+		t.Errorf("posts transferred to owner = %d, want 1", n)
+	}
+}
@@ -10,17 +10,25 @@ import (
 )
 
 type Deps struct {
-	TokenService    *service.TokenService
-	UserService     *service.UserService
-	PostService     *service.PostService
-	ConfigService   *service.ConfigService
-	TagService      *service.TagService
-	CategoryService *service.CategoryService
-	LinkService     *service.LinkService
-	MenuService     *service.MenuService
-	DiaryService    *service.DiaryService
-	FileService     *service.FileService
-	CommentService  *service.CommentService
+	TokenService        *service.TokenService
+	UserService         *service.UserService
+	PostService         *service.PostService
+	ConfigService       *service.ConfigService
+	TagService          *service.TagService
+	CategoryService     *service.CategoryService
+	LinkService         *service.LinkService
+	MenuService         *service.MenuService
+	DiaryService        *service.DiaryService
+	FileService         *service.FileService
+	CommentService      *service.CommentService
+	AuditService        *service.AuditService
+	FeedService         *service.FeedService
+	SitemapService      *service.SitemapService
+	SeoService          *service.SeoService
+	SeriesService       *service.SeriesService
+	NotificationService *service.NotificationService
+	CaptchaService      *service.CaptchaService
+	CommenterService    *service.CommenterService
 }
 
 // SetupRouters 初始化 Gin 路由
@@ -38,7 +46,7 @@ func SetupRouters(r *gin.Engine, deps *Deps) *gin.Engine {
 		userHandler.RegisterAdmin(adminHandler)
 
 		// 配置接口
-		configHandler := admin.NewConfigAdminHandler(deps.ConfigService, deps.UserService, deps.TokenService)
+		configHandler := admin.NewConfigAdminHandler(deps.ConfigService, deps.UserService, deps.TokenService, deps.NotificationService)
 		configHandler.RegisterAdmin(adminHandler)
 
 		// 标签接口
@@ -49,6 +57,10 @@ func SetupRouters(r *gin.Engine, deps *Deps) *gin.Engine {
 		categoryHandler := admin.NewCategoryAdminHandler(deps.CategoryService, deps.TokenService)
 		categoryHandler.RegisterAdmin(adminHandler)
 
+		// 系列接口
+		seriesHandler := admin.NewSeriesAdminHandler(deps.SeriesService, deps.TokenService)
+		seriesHandler.RegisterAdmin(adminHandler)
+
 		// 文章接口
 		postHandler := admin.NewPostAdminHandler(deps.PostService, deps.TokenService)
 		postHandler.RegisterAdmin(adminHandler)
@@ -76,6 +88,14 @@ func SetupRouters(r *gin.Engine, deps *Deps) *gin.Engine {
 		// 评论路由
 		commentHandler := admin.NewCommentAdminHandler(deps.CommentService, deps.TokenService)
 		commentHandler.RegisterAdmin(adminHandler)
+
+		// 评论人路由
+		commenterHandler := admin.NewCommenterAdminHandler(deps.CommenterService, deps.TokenService)
+		commenterHandler.RegisterAdmin(adminHandler)
+
+		// 审计日志路由
+		auditHandler := admin.NewAuditAdminHandler(deps.AuditService, deps.TokenService)
+		auditHandler.RegisterAdmin(adminHandler)
 	}
 
 	// 博客接口（无需登录）
@@ -97,8 +117,12 @@ func SetupRouters(r *gin.Engine, deps *Deps) *gin.Engine {
 		categoryHandler := api.NewCategoryApiHandler(deps.CategoryService)
 		categoryHandler.RegisterApi(apiHandler)
 
+		// 系列接口
+		seriesHandler := api.NewSeriesApiHandler(deps.SeriesService)
+		seriesHandler.RegisterApi(apiHandler)
+
 		// 文章接口
-		postHandler := api.NewPostApiHandler(deps.PostService)
+		postHandler := api.NewPostApiHandler(deps.PostService, deps.SeoService, deps.SeriesService)
 		postHandler.RegisterApi(apiHandler)
 
 		// 友联接口
@@ -114,9 +138,25 @@ func SetupRouters(r *gin.Engine, deps *Deps) *gin.Engine {
 		diaryHandler.RegisterApi(apiHandler)
 
 		// 评论路由
-		commentHandler := api.NewCommentApiHandler(deps.CommentService)
+		commentHandler := api.NewCommentApiHandler(deps.CommentService, deps.CaptchaService)
 		commentHandler.RegisterApi(apiHandler)
+
+		// 验证码接口
+		captchaHandler := api.NewCaptchaApiHandler(deps.CaptchaService)
+		captchaHandler.RegisterApi(apiHandler)
+
+		// 邮件退订接口
+		mailHandler := api.NewMailApiHandler(deps.NotificationService)
+		mailHandler.RegisterApi(apiHandler)
 	}
 
+	// 订阅（RSS / Atom）
+	feedHandler := api.NewFeedApiHandler(deps.FeedService)
+	feedHandler.RegisterFeed(r)
+
+	// 站点地图和 robots.txt
+	sitemapHandler := api.NewSitemapApiHandler(deps.SitemapService)
+	sitemapHandler.RegisterSitemap(r)
+
 	return r
 }
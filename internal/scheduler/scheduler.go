@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"context"
+	"nola-go/internal/kv"
+	"nola-go/internal/logger"
+	"nola-go/internal/util"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// lockKeyPrefix 任务锁的键前缀
+//   - nola:scheduler:lock:<任务名称> 执行任务的实例 ID，一个执行间隔后过期
+const lockKeyPrefix = "nola:scheduler:lock:"
+
+// Job 定时任务
+type Job struct {
+	// Name 任务名称，同时作为任务锁的键
+	Name string
+	// Interval 执行间隔
+	Interval time.Duration
+	// Run 执行任务，ctx 在一个执行间隔后超时
+	Run func(ctx context.Context) error
+}
+
+// Scheduler 后台定时任务调度器
+// 多个实例同时运行时，每次执行前先通过键值存储抢占任务锁，抢到锁的实例才执行任务。
+// 锁在一个执行间隔后过期，持有锁的实例下一次执行时续期，其他实例在同一个执行间隔内不会重复执行，
+// 持有锁的实例停止后，最晚一个执行间隔后由其他实例接管；
+// 键值存储不可用（改用内存存储）时锁只在本实例有效，因此任务本身也需要能够安全地重复执行
+type Scheduler struct {
+	store kv.Store
+	// instanceId 实例 ID，作为任务锁的值，便于排查由哪个实例执行
+	instanceId string
+	jobs       []*Job
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// New 创建后台定时任务调度器
+//   - store: 保存任务锁的键值存储，多实例部署时需要共享（例如 Redis）
+func New(store kv.Store) *Scheduler {
+	return &Scheduler{
+		store:      store,
+		instanceId: util.StringRandom(16),
+	}
+}
+
+// Register 注册定时任务，需要在 Start 之前调用
+func (s *Scheduler) Register(jobs ...*Job) {
+	s.jobs = append(s.jobs, jobs...)
+}
+
+// Start 在后台启动所有定时任务，启动后立即执行一次
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job *Job) {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+}
+
+// Stop 停止所有定时任务，并等待正在执行的任务结束
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// loop 按执行间隔循环执行任务，直到调度器停止
+func (s *Scheduler) loop(ctx context.Context, job *Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce 抢占任务锁并执行一次任务，返回本实例是否执行了任务
+func (s *Scheduler) runOnce(ctx context.Context, job *Job) bool {
+	key := lockKeyPrefix + job.Name
+	ttl := max(job.Interval, time.Second)
+
+	ok, err := s.store.SetNX(ctx, key, s.instanceId, ttl)
+	if err != nil {
+		logger.Log.Error("获取定时任务锁失败", zap.String("job", job.Name), zap.Error(err))
+		return false
+	}
+	if !ok {
+		// 本实例上一次执行时的锁可能还没有过期，锁仍属于本实例时原子地续期后继续执行
+		renewed, err := s.store.CompareAndExpire(ctx, key, s.instanceId, ttl)
+		if err != nil {
+			logger.Log.Error("续期定时任务锁失败", zap.String("job", job.Name), zap.Error(err))
+			return false
+		}
+		if !renewed {
+			// 其他实例在本执行间隔内已经执行过
+			return false
+		}
+	}
+
+	s.execute(ctx, job)
+	return true
+}
+
+// execute 执行任务，任务失败或异常只输出日志
+func (s *Scheduler) execute(ctx context.Context, job *Job) {
+	runCtx, cancel := context.WithTimeout(ctx, job.Interval)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Log.Error("定时任务异常", zap.String("job", job.Name), zap.Any("panic", r))
+		}
+	}()
+
+	if err := job.Run(runCtx); err != nil {
+		logger.Log.Error("定时任务执行失败", zap.String("job", job.Name), zap.Error(err))
+	}
+}
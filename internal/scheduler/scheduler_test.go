@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/kv"
+	"nola-go/internal/logger"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestScheduler_RunOnce(t *testing.T) {
+	logger.Log = zap.NewNop()
+	ctx := context.Background()
+
+	// 两个实例共享同一个键值存储
+	store := kv.NewMemoryStore()
+	first, second := New(store), New(store)
+
+	var runs atomic.Int32
+	job := &Job{Name: "job", Interval: time.Minute, Run: func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}}
+
+	if !first.runOnce(ctx, job) {
+		t.Error("first runOnce should run the job")
+	}
+	if second.runOnce(ctx, job) {
+		t.Error("runOnce should not run the job while another instance holds the lock")
+	}
+	// 持有锁的实例下一次执行时续期，其他实例仍然不能执行
+	if !first.runOnce(ctx, job) {
+		t.Error("runOnce should renew the lock held by the same instance")
+	}
+	if second.runOnce(ctx, job) {
+		t.Error("runOnce should not run the job after the lock is renewed")
+	}
+	if runs.Load() != 2 {
+		t.Errorf("runs = %d, want 2", runs.Load())
+	}
+	if owner, _, _ := store.Get(ctx, lockKeyPrefix+"job"); owner != first.instanceId {
+		t.Errorf("lock owner = %q, want %q", owner, first.instanceId)
+	}
+
+	// 任务失败和异常不影响调度器
+	failing := &Job{Name: "failing", Interval: time.Minute, Run: func(ctx context.Context) error {
+		return errors.New("failed")
+	}}
+	panicking := &Job{Name: "panicking", Interval: time.Minute, Run: func(ctx context.Context) error {
+		panic("boom")
+	}}
+	if !first.runOnce(ctx, failing) || !first.runOnce(ctx, panicking) {
+		t.Error("runOnce should report failing jobs as run")
+	}
+}
+
+func TestScheduler_StartStop(t *testing.T) {
+	logger.Log = zap.NewNop()
+
+	ran := make(chan struct{}, 1)
+	s := New(kv.NewMemoryStore())
+	s.Register(&Job{Name: "job", Interval: time.Hour, Run: func(ctx context.Context) error {
+		ran <- struct{}{}
+		return nil
+	}})
+	s.Start()
+
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run after Start")
+	}
+	s.Stop()
+}
@@ -0,0 +1,236 @@
+package search
+
+import (
+	"context"
+	"math"
+	"nola-go/internal/models"
+	"nola-go/internal/repository"
+	"sort"
+	"time"
+)
+
+// Document 待索引的文档
+type Document struct {
+	// Id 文档 ID（文章 ID）
+	Id uint
+	// Title 标题
+	Title string
+	// Slug 别名
+	Slug string
+	// Excerpt 摘要
+	Excerpt string
+	// Content 正文纯文本
+	Content string
+}
+
+// Hit 搜索结果
+type Hit struct {
+	// Id 文档 ID
+	Id uint
+	// Score 相关度得分
+	Score float64
+}
+
+// Engine 全文搜索引擎接口
+type Engine interface {
+	// Index 添加或更新文档
+	Index(ctx context.Context, docs ...*Document) error
+	// Delete 删除文档
+	Delete(ctx context.Context, ids ...uint) error
+	// Rebuild 清空索引后重新添加所有文档
+	Rebuild(ctx context.Context, docs []*Document) error
+	// Search 搜索文档，所有关键词都需要命中（最后一个词同时按前缀匹配），结果按相关度降序
+	Search(ctx context.Context, query string) ([]*Hit, error)
+	// Count 获取已索引的文档数
+	Count(ctx context.Context) (int64, error)
+}
+
+// 字段权重，标题命中比正文命中更相关
+const (
+	titleWeight   = 5
+	slugWeight    = 3
+	excerptWeight = 2
+	contentWeight = 1
+)
+
+// BM25 参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// indexEngine 基于数据库倒排索引的搜索引擎，使用 BM25 计算相关度
+type indexEngine struct {
+	repo repository.SearchRepository
+}
+
+// NewIndexEngine 创建基于数据库倒排索引的搜索引擎
+func NewIndexEngine(repo repository.SearchRepository) Engine {
+	return &indexEngine{repo: repo}
+}
+
+// Index 添加或更新文档
+func (e *indexEngine) Index(ctx context.Context, docs ...*Document) error {
+	records, terms := analyzeDocuments(docs)
+	return e.repo.ReplaceDocuments(ctx, records, terms)
+}
+
+// Delete 删除文档
+func (e *indexEngine) Delete(ctx context.Context, ids ...uint) error {
+	return e.repo.DeleteDocuments(ctx, ids)
+}
+
+// Rebuild 清空索引后重新添加所有文档
+func (e *indexEngine) Rebuild(ctx context.Context, docs []*Document) error {
+	records, terms := analyzeDocuments(docs)
+	return e.repo.Rebuild(ctx, records, terms)
+}
+
+// Count 获取已索引的文档数
+func (e *indexEngine) Count(ctx context.Context) (int64, error) {
+	stats, err := e.repo.Stats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return stats.Count, nil
+}
+
+// Search 搜索文档，所有关键词都需要命中，结果按相关度降序，相关度相同时 ID 大的（较新的文章）在前
+// 最后一个词同时按前缀匹配，以它开头的所有词项的词频合并计算
+func (e *indexEngine) Search(ctx context.Context, query string) ([]*Hit, error) {
+	q := ParseQuery(query)
+	terms := q.Terms
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	postings, err := e.repo.Postings(ctx, terms)
+	if err != nil {
+		return nil, err
+	}
+	var prefixPostings []*models.SearchTerm
+	if q.Prefix != "" {
+		prefixPostings, err = e.repo.PrefixPostings(ctx, q.Prefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 文档 ID -> 词项 -> 词频
+	frequencies := make(map[uint]map[string]int)
+	// 词项 -> 包含该词项的文档数
+	documentFrequency := make(map[string]int)
+	add := func(docId uint, term string, frequency int) {
+		if frequencies[docId] == nil {
+			frequencies[docId] = make(map[string]int)
+		}
+		if _, ok := frequencies[docId][term]; !ok {
+			documentFrequency[term]++
+		}
+		frequencies[docId][term] += frequency
+	}
+	for _, posting := range postings {
+		add(posting.DocId, posting.Term, posting.Frequency)
+	}
+	for _, posting := range prefixPostings {
+		// 与前缀相同的词项已经精确匹配
+		if posting.Term != q.Prefix {
+			add(posting.DocId, q.Prefix, posting.Frequency)
+		}
+	}
+
+	// 只保留命中所有词项的文档
+	var ids []uint
+	for id, termFrequencies := range frequencies {
+		if len(termFrequencies) == len(terms) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	stats, err := e.repo.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := e.repo.Documents(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]*Hit, 0, len(docs))
+	for _, doc := range docs {
+		score := 0.0
+		for _, term := range terms {
+			score += bm25(
+				float64(frequencies[doc.DocId][term]),
+				float64(documentFrequency[term]),
+				float64(doc.Length),
+				float64(stats.Count),
+				stats.AverageLength,
+			)
+		}
+		hits = append(hits, &Hit{Id: doc.DocId, Score: score})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Id > hits[j].Id
+	})
+	return hits, nil
+}
+
+// bm25 计算单个词项的 BM25 得分
+//   - tf: 词项在文档中的词频
+//   - df: 包含词项的文档数
+//   - length: 文档长度
+//   - count: 文档总数
+//   - averageLength: 文档平均长度
+func bm25(tf, df, length, count, averageLength float64) float64 {
+	idf := math.Log(1 + (count-df+0.5)/(df+0.5))
+	norm := 1.0
+	if averageLength > 0 {
+		norm = 1 - bm25B + bm25B*length/averageLength
+	}
+	return idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+}
+
+// analyzeDocuments 将文档拆分为词项并按字段权重统计词频
+func analyzeDocuments(docs []*Document) ([]*models.SearchDocument, []*models.SearchTerm) {
+	now := time.Now().UnixMilli()
+	records := make([]*models.SearchDocument, 0, len(docs))
+	var terms []*models.SearchTerm
+
+	for _, doc := range docs {
+		frequencies := make(map[string]int)
+		var order []string
+		length := 0
+		fields := []struct {
+			text   string
+			weight int
+		}{
+			{doc.Title, titleWeight},
+			{doc.Slug, slugWeight},
+			{doc.Excerpt, excerptWeight},
+			{doc.Content, contentWeight},
+		}
+		for _, field := range fields {
+			for _, token := range Tokenize(field.text) {
+				if _, ok := frequencies[token.Term]; !ok {
+					order = append(order, token.Term)
+				}
+				frequencies[token.Term] += field.weight
+				length += field.weight
+			}
+		}
+
+		records = append(records, &models.SearchDocument{DocId: doc.Id, Length: length, UpdateTime: now})
+		for _, term := range order {
+			terms = append(terms, &models.SearchTerm{Term: term, DocId: doc.Id, Frequency: frequencies[term]})
+		}
+	}
+	return records, terms
+}
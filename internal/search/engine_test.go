@@ -0,0 +1,98 @@
+package search
+
+import (
+	"context"
+	"nola-go/internal/repository"
+	"nola-go/internal/testutil"
+	"slices"
+	"testing"
+)
+
+// hitIds 获取搜索结果的文档 ID
+func hitIds(hits []*Hit) []uint {
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.Id
+	}
+	return ids
+}
+
+func TestIndexEngine(t *testing.T) {
+	ctx := context.Background()
+	engine := NewIndexEngine(repository.NewSearchRepository(testutil.NewDB(t)))
+
+	docs := []*Document{
+		{Id: 1, Title: "Go 语言入门", Slug: "go-intro", Content: "介绍 Go 语言的基础语法"},
+		{Id: 2, Title: "Gin 框架", Slug: "gin", Content: "使用 Go 语言编写 Web 服务"},
+		{Id: 3, Title: "随笔", Slug: "essay", Content: "今天天气很好"},
+	}
+	if err := engine.Rebuild(ctx, docs); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if n, err := engine.Count(ctx); err != nil || n != 3 {
+		t.Fatalf("Count = %d, %v", n, err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []uint
+	}{
+		{"标题命中排在前面", "go 语言", []uint{1, 2}},
+		{"所有关键词都需要命中", "gin 天气", nil},
+		{"中文单字", "天", []uint{3}},
+		{"不区分大小写", "GIN", []uint{2}},
+		{"没有命中", "rust", nil},
+		{"最后一个词前缀匹配", "go int", []uint{1}},
+		{"前缀匹配多个词项", "g", nil},
+		{"前缀匹配不区分大小写", "GI", []uint{2}},
+		{"前面的词不前缀匹配", "int go", nil},
+		{"空关键词", " ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := engine.Search(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if got := hitIds(hits); !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	// 增量更新：修改文档后旧内容不再命中
+	if err := engine.Index(ctx, &Document{Id: 3, Title: "随笔", Slug: "essay", Content: "学习 Rust"}); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	if hits, _ := engine.Search(ctx, "天气"); len(hits) != 0 {
+		t.Errorf("old content still matches: %v", hitIds(hits))
+	}
+	if hits, _ := engine.Search(ctx, "rust"); !slices.Equal(hitIds(hits), []uint{3}) {
+		t.Errorf("Search(rust) = %v", hitIds(hits))
+	}
+
+	if err := engine.Delete(ctx, 1, 3); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if hits, _ := engine.Search(ctx, "go"); !slices.Equal(hitIds(hits), []uint{2}) {
+		t.Errorf("Search after delete = %v", hitIds(hits))
+	}
+	if n, _ := engine.Count(ctx); n != 1 {
+		t.Errorf("Count after delete = %d", n)
+	}
+}
+
+func TestBm25(t *testing.T) {
+	// 词频越高、文档越短、包含词项的文档越少，得分越高
+	base := bm25(2, 1, 10, 10, 10)
+	if bm25(4, 1, 10, 10, 10) <= base {
+		t.Error("higher tf should score higher")
+	}
+	if bm25(2, 1, 5, 10, 10) <= base {
+		t.Error("shorter document should score higher")
+	}
+	if bm25(2, 5, 10, 10, 10) >= base {
+		t.Error("common term should score lower")
+	}
+}
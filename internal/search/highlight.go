@@ -0,0 +1,99 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// 高亮标记
+const (
+	highlightOpen  = "<mark>"
+	highlightClose = "</mark>"
+)
+
+// Highlight 将文本中命中的词项用 <mark> 标记，其余内容进行 HTML 转义
+//   - text: 纯文本
+//   - query: 搜索关键词（ParseQuery 的结果）
+func Highlight(text string, query *Query) string {
+	return highlightRange(text, 0, len(text), matchSpans(text, query))
+}
+
+// Matches 判断文本中是否有命中的词项
+func Matches(text string, query *Query) bool {
+	return len(matchSpans(text, query)) > 0
+}
+
+// Snippet 截取文本中第一个命中词项附近的片段并高亮，没有命中时截取开头
+//   - text: 纯文本
+//   - query: 搜索关键词（ParseQuery 的结果）
+//   - length: 片段最大字符数
+func Snippet(text string, query *Query, length int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	spans := matchSpans(text, query)
+
+	// 片段从第一个命中位置前约四分之一长度处开始
+	start := 0
+	if len(spans) > 0 {
+		start = spans[0].start
+		for n := 0; n < length/4 && start > 0; n++ {
+			_, size := utf8.DecodeLastRuneInString(text[:start])
+			start -= size
+		}
+	}
+	end := start
+	for n := 0; n < length && end < len(text); n++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	sb.WriteString(highlightRange(text, start, end, spans))
+	if end < len(text) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+// span 文本区间
+type span struct {
+	start, end int
+}
+
+// matchSpans 获取文本中命中词项的区间，重叠和相邻的区间会合并
+func matchSpans(text string, query *Query) []span {
+	var spans []span
+	for _, token := range Tokenize(text) {
+		if !query.Match(token.Term) {
+			continue
+		}
+		if n := len(spans); n > 0 && token.Start <= spans[n-1].end {
+			spans[n-1].end = max(spans[n-1].end, token.End)
+			continue
+		}
+		spans = append(spans, span{token.Start, token.End})
+	}
+	return spans
+}
+
+// highlightRange 高亮文本区间 [start, end) 中的命中内容
+func highlightRange(text string, start, end int, spans []span) string {
+	var sb strings.Builder
+	pos := start
+	for _, s := range spans {
+		if s.end <= pos || s.start >= end {
+			continue
+		}
+		s.start, s.end = max(s.start, pos), min(s.end, end)
+		sb.WriteString(html.EscapeString(text[pos:s.start]))
+		sb.WriteString(highlightOpen)
+		sb.WriteString(html.EscapeString(text[s.start:s.end]))
+		sb.WriteString(highlightClose)
+		pos = s.end
+	}
+	sb.WriteString(html.EscapeString(text[pos:end]))
+	return sb.String()
+}
@@ -0,0 +1,44 @@
+package search
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+	}{
+		{"英文不区分大小写", "Learn GO with go", "go", "Learn <mark>GO</mark> with <mark>go</mark>"},
+		{"中文相邻词项合并", "全文搜索引擎", "搜索引擎", "全文<mark>搜索引擎</mark>"},
+		{"转义 HTML", "<b>Go</b> & Gin", "gin", "&lt;b&gt;Go&lt;/b&gt; &amp; <mark>Gin</mark>"},
+		{"最后一个词前缀匹配", "Gopher & Go", "go", "<mark>Gopher</mark> &amp; <mark>Go</mark>"},
+		{"不匹配单词中间的一部分", "Ergo", "go", "Ergo"},
+		{"前面的词不前缀匹配", "Gopher gin", "go gin", "Gopher <mark>gin</mark>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.text, ParseQuery(tt.query)); got != tt.want {
+				t.Errorf("Highlight = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := "一二三四五六七八九十  关键词 十一十二十三十四十五十六"
+	terms := ParseQuery("关键词")
+
+	if got, want := Snippet(text, terms, 8), "…十 <mark>关键词</mark> 十一…"; got != want {
+		t.Errorf("Snippet = %q, want %q", got, want)
+	}
+	if got, want := Snippet("no match here", terms, 5), "no ma…"; got != want {
+		t.Errorf("Snippet(no match) = %q, want %q", got, want)
+	}
+	if got, want := Snippet("short", terms, 100), "short"; got != want {
+		t.Errorf("Snippet(short) = %q, want %q", got, want)
+	}
+	if !Matches(text, terms) || Matches("no match here", terms) {
+		t.Error("Matches mismatch")
+	}
+}
@@ -0,0 +1,183 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxTermLength 词项最大长度（字节），超过的部分会被截断，与索引表的列长度一致
+const maxTermLength = 64
+
+// minPrefixLength 前缀匹配的最小长度（字符），过短的前缀会命中太多词项
+const minPrefixLength = 2
+
+// Token 分词结果
+type Token struct {
+	// Term 词项（已转为小写）
+	Term string
+	// Start 在原文中的起始位置（字节）
+	Start int
+	// End 在原文中的结束位置（字节，不包含）
+	End int
+}
+
+// Tokenize 将文本拆分为词项，用于建立索引
+// 连续的字母和数字作为一个词；中日韩文字没有分隔符，每个字和相邻的两个字都作为词项（单字 + 二元分词），
+// 这样搜索单个字和多个字的词语都可以命中
+func Tokenize(text string) []*Token {
+	var tokens []*Token
+	for _, run := range splitRuns(text) {
+		if !run.cjk {
+			tokens = append(tokens, &Token{Term: normalize(text[run.start:run.end]), Start: run.start, End: run.end})
+			continue
+		}
+
+		// 中日韩文字：单字和二元分词
+		offsets := runeOffsets(text, run.start, run.end)
+		for i := 0; i < len(offsets)-1; i++ {
+			tokens = append(tokens, &Token{Term: text[offsets[i]:offsets[i+1]], Start: offsets[i], End: offsets[i+1]})
+			if i+2 < len(offsets) {
+				tokens = append(tokens, &Token{Term: text[offsets[i]:offsets[i+2]], Start: offsets[i], End: offsets[i+2]})
+			}
+		}
+	}
+	return tokens
+}
+
+// QueryTerms 将搜索关键词拆分为不重复的词项
+// 中日韩文字只有一个字时按单字搜索，否则按二元分词搜索，所有词项都需要命中
+func QueryTerms(query string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	add := func(term string) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	for _, run := range splitRuns(query) {
+		if !run.cjk {
+			add(normalize(query[run.start:run.end]))
+			continue
+		}
+
+		offsets := runeOffsets(query, run.start, run.end)
+		if len(offsets) == 2 {
+			add(query[offsets[0]:offsets[1]])
+			continue
+		}
+		for i := 0; i+2 < len(offsets); i++ {
+			add(query[offsets[i]:offsets[i+2]])
+		}
+	}
+	return terms
+}
+
+// Query 解析后的搜索关键词
+type Query struct {
+	// Terms 不重复的词项，所有词项都需要命中
+	Terms []string
+	// Prefix 同时按前缀匹配的词项（Terms 中的最后一个词），为空时只精确匹配
+	Prefix string
+}
+
+// ParseQuery 解析搜索关键词
+// 最后一个词由字母和数字组成时（例如还没有输入完整的单词），同时命中以它开头的词项；
+// 中日韩文字已经按单字和二元分词索引，不需要前缀匹配
+func ParseQuery(query string) *Query {
+	q := &Query{Terms: QueryTerms(query)}
+	runs := splitRuns(query)
+	if n := len(runs); n > 0 && !runs[n-1].cjk {
+		last := normalize(query[runs[n-1].start:runs[n-1].end])
+		if utf8.RuneCountInString(last) >= minPrefixLength {
+			q.Prefix = last
+		}
+	}
+	return q
+}
+
+// Match 判断索引中的词项是否命中搜索关键词
+func (q *Query) Match(term string) bool {
+	if q.Prefix != "" && strings.HasPrefix(term, q.Prefix) {
+		return true
+	}
+	for _, t := range q.Terms {
+		if t == term {
+			return true
+		}
+	}
+	return false
+}
+
+// textRun 文本中连续的同类字符
+type textRun struct {
+	start, end int
+	// cjk 是否为中日韩文字
+	cjk bool
+}
+
+// splitRuns 将文本拆分为连续的字母数字和中日韩文字，其他字符作为分隔符
+func splitRuns(text string) []textRun {
+	var runs []textRun
+	current := textRun{start: -1}
+	flush := func(end int) {
+		if current.start >= 0 {
+			current.end = end
+			runs = append(runs, current)
+		}
+		current = textRun{start: -1}
+	}
+
+	for i, r := range text {
+		switch {
+		case isCJK(r):
+			if current.start >= 0 && !current.cjk {
+				flush(i)
+			}
+			if current.start < 0 {
+				current = textRun{start: i, cjk: true}
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if current.start >= 0 && current.cjk {
+				flush(i)
+			}
+			if current.start < 0 {
+				current = textRun{start: i}
+			}
+		default:
+			flush(i)
+		}
+	}
+	flush(len(text))
+	return runs
+}
+
+// runeOffsets 获取文本区间中每个字符的起始位置，最后一个元素为区间结束位置
+func runeOffsets(text string, start, end int) []int {
+	offsets := make([]int, 0, utf8.RuneCountInString(text[start:end])+1)
+	for i := range text[start:end] {
+		offsets = append(offsets, start+i)
+	}
+	return append(offsets, end)
+}
+
+// isCJK 判断字符是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// normalize 将词转为小写并限制长度
+func normalize(term string) string {
+	term = strings.ToLower(term)
+	if len(term) <= maxTermLength {
+		return term
+	}
+	// 截断时不拆分多字节字符
+	end := maxTermLength
+	for end > 0 && !utf8.RuneStart(term[end]) {
+		end--
+	}
+	return term[:end]
+}
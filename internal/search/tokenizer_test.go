@@ -0,0 +1,83 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"英文和数字", "Hello, Go 1.24!", []string{"hello", "go", "1", "24"}},
+		{"中文单字和二元分词", "搜索引擎", []string{"搜", "搜索", "索", "索引", "引", "引擎", "擎"}},
+		{"中英混合", "Gin框架", []string{"gin", "框", "框架", "架"}},
+		{"日文", "すし", []string{"す", "すし", "し"}},
+		{"只有标点", "…，。!", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, token := range Tokenize(tt.text) {
+				got = append(got, token.Term)
+				// 非中日韩文字的词项是原文转为小写
+				if term := strings.ToLower(tt.text[token.Start:token.End]); term != token.Term {
+					t.Errorf("token %q has offsets of %q", token.Term, term)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+
+	long := strings.Repeat("a", 100)
+	if tokens := Tokenize(long); len(tokens) != 1 || len(tokens[0].Term) != maxTermLength {
+		t.Errorf("long term should be truncated to %d bytes", maxTermLength)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query  string
+		terms  []string
+		prefix string
+	}{
+		{"Go gin", []string{"go", "gin"}, "gin"},
+		{"go 语言", []string{"go", "语言"}, ""},
+		{"g", []string{"g"}, ""},
+		{" ", nil, ""},
+	}
+	for _, tt := range tests {
+		q := ParseQuery(tt.query)
+		if !slices.Equal(q.Terms, tt.terms) || q.Prefix != tt.prefix {
+			t.Errorf("ParseQuery(%q) = %+v, want %q, %q", tt.query, q, tt.terms, tt.prefix)
+		}
+	}
+
+	q := ParseQuery("go gin")
+	if !q.Match("go") || !q.Match("ginkgo") || q.Match("gopher") {
+		t.Errorf("Match with %+v", q)
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"Go go GO", []string{"go"}},
+		{"搜", []string{"搜"}},
+		{"搜索引擎", []string{"搜索", "索引", "引擎"}},
+		{"Gin 框架 gin", []string{"gin", "框架"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		if got := QueryTerms(tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("QueryTerms(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
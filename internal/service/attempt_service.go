@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/config"
+	"nola-go/internal/kv"
+	"nola-go/internal/logger"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AttemptScope 需要防止暴力破解的验证场景，不同场景的失败次数分别计算
+type AttemptScope string
+
+const (
+	// AttemptScopeLogin 用户登录（账户为用户名）
+	AttemptScopeLogin AttemptScope = "login"
+	// AttemptScopeTwoFactor 两步验证登录（账户为用户 ID）
+	AttemptScopeTwoFactor AttemptScope = "2fa"
+	// AttemptScopePostPassword 加密文章密码（账户为文章 ID）
+	AttemptScopePostPassword AttemptScope = "post_password"
+)
+
+// auditTargetType 审计日志中验证场景对应的操作对象类型
+func (s AttemptScope) auditTargetType() enum.AuditTargetType {
+	if s == AttemptScopePostPassword {
+		return enum.AuditTargetTypePost
+	}
+	return enum.AuditTargetTypeUser
+}
+
+// attemptKeyPrefix 失败次数和锁定状态的键前缀
+//   - nola:attempt:<场景>:<ip|account>:<值>:failures 失败次数，最后一次失败 reset 时长后过期
+//   - nola:attempt:<场景>:<ip|account>:<值>:lock 锁定标记，过期时间即锁定结束时间
+const attemptKeyPrefix = "nola:attempt:"
+
+// AttemptService 暴力破解防护
+// 分别按 IP 和账户统计连续失败次数，超过允许次数后临时锁定，锁定时长随失败次数指数增长；
+// IP 或账户任意一个被锁定时，验证前直接拒绝，不再验证密码
+// 验证失败和锁定都会记录审计日志（不记录尝试的密码）
+type AttemptService struct {
+	store              kv.Store
+	auditService       *AuditService
+	maxIPFailures      int64
+	maxAccountFailures int64
+	lock               time.Duration
+	maxLock            time.Duration
+	reset              time.Duration
+}
+
+// NewAttemptService 创建 AttemptService
+//   - cfg: 暴力破解防护配置
+//   - store: 保存失败次数和锁定状态的键值存储
+//   - auditService: 审计日志 Service
+func NewAttemptService(cfg config.BruteForceConfig, store kv.Store, auditService *AuditService) *AttemptService {
+	s := &AttemptService{
+		store:              store,
+		auditService:       auditService,
+		maxIPFailures:      cfg.MaxIPFailures,
+		maxAccountFailures: cfg.MaxAccountFailures,
+		lock:               time.Duration(cfg.LockSeconds) * time.Second,
+		maxLock:            time.Duration(cfg.MaxLockMinutes) * time.Minute,
+		reset:              time.Duration(cfg.ResetHours) * time.Hour,
+	}
+	if s.maxIPFailures <= 0 {
+		s.maxIPFailures = 10
+	}
+	if s.maxAccountFailures <= 0 {
+		s.maxAccountFailures = 5
+	}
+	if s.lock <= 0 {
+		s.lock = 30 * time.Second
+	}
+	if s.maxLock <= 0 {
+		s.maxLock = time.Hour
+	}
+	if s.maxLock < s.lock {
+		s.maxLock = s.lock
+	}
+	if s.reset <= 0 {
+		s.reset = 24 * time.Hour
+	}
+	// 失败次数至少要保留到最长锁定结束之后，否则锁定时长无法继续增长
+	if s.reset < s.maxLock {
+		s.reset = s.maxLock
+	}
+	return s
+}
+
+// Check 验证前检查 IP 和账户是否被锁定，被锁定时返回 *response.TooManyRequestsError
+// 存储出错时只记录日志，不影响验证
+//   - ctx: 上下文
+//   - scope: 验证场景
+//   - ip: 客户端 IP
+//   - account: 账户
+func (s *AttemptService) Check(ctx context.Context, scope AttemptScope, ip, account string) error {
+	var retryAfter time.Duration
+	for _, key := range s.keys(scope, ip, account) {
+		ttl, err := s.store.TTL(ctx, key+":lock")
+		if err != nil {
+			logger.Log.Error("获取锁定状态失败", zap.Error(err))
+			continue
+		}
+		retryAfter = max(retryAfter, ttl)
+	}
+
+	if retryAfter > 0 {
+		return &response.TooManyRequestsError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// Fail 记录一次验证失败并写入审计日志，超过允许次数后锁定 IP 或账户
+//   - ctx: 上下文
+//   - scope: 验证场景
+//   - ip: 客户端 IP
+//   - account: 账户
+func (s *AttemptService) Fail(ctx context.Context, scope AttemptScope, ip, account string) {
+	logger.Log.Warn("验证失败",
+		zap.String("scope", string(scope)), zap.String("ip", ip), zap.String("account", account))
+
+	// 验证失败时没有登录用户，只记录客户端 IP
+	operator := &Operator{Ip: ip}
+	s.auditService.Record(ctx, operator, enum.AuditActionLoginFailed, &AuditTarget{
+		Type:  scope.auditTargetType(),
+		Id:    account,
+		After: map[string]any{"scope": scope, "account": account, "ip": ip},
+	})
+
+	keys := s.keys(scope, ip, account)
+	limits := []int64{s.maxIPFailures, s.maxAccountFailures}
+	lockedBy := []string{"ip", "account"}
+	for i, key := range keys {
+		failures, err := s.store.Incr(ctx, key+":failures", s.reset)
+		if err != nil {
+			logger.Log.Error("记录验证失败次数失败", zap.Error(err))
+			continue
+		}
+		if failures <= limits[i] {
+			continue
+		}
+
+		lock := s.lockDuration(failures - limits[i])
+		if err := s.store.Set(ctx, key+":lock", "1", lock); err != nil {
+			logger.Log.Error("锁定失败", zap.Error(err))
+			continue
+		}
+		logger.Log.Warn("验证失败次数过多，已临时锁定",
+			zap.String("scope", string(scope)), zap.String("key", key),
+			zap.Int64("failures", failures), zap.Duration("lock", lock))
+		s.auditService.Record(ctx, operator, enum.AuditActionLoginLocked, &AuditTarget{
+			Type: scope.auditTargetType(),
+			Id:   account,
+			After: map[string]any{
+				"scope":       scope,
+				"account":     account,
+				"ip":          ip,
+				"lockedBy":    lockedBy[i],
+				"failures":    failures,
+				"lockSeconds": int64(lock / time.Second),
+			},
+		})
+	}
+}
+
+// Succeed 验证成功后清零账户的失败次数
+// IP 的失败次数不清零，避免知道某个账户（或某篇文章）密码的人借此不断重置 IP 的失败次数
+//   - ctx: 上下文
+//   - scope: 验证场景
+//   - account: 账户
+func (s *AttemptService) Succeed(ctx context.Context, scope AttemptScope, account string) {
+	key := s.accountKey(scope, account)
+	if err := s.store.Delete(ctx, key+":failures", key+":lock"); err != nil {
+		logger.Log.Error("清除验证失败次数失败", zap.Error(err))
+	}
+}
+
+// lockDuration 超出允许次数 over 次时的锁定时长，每次翻倍，最长 maxLock
+func (s *AttemptService) lockDuration(over int64) time.Duration {
+	lock := s.lock
+	for i := int64(1); i < over && lock < s.maxLock; i++ {
+		lock *= 2
+	}
+	return min(lock, s.maxLock)
+}
+
+// keys 获取 IP 和账户的键（不含后缀）
+func (s *AttemptService) keys(scope AttemptScope, ip, account string) []string {
+	return []string{
+		attemptKeyPrefix + string(scope) + ":ip:" + ip,
+		s.accountKey(scope, account),
+	}
+}
+
+// accountKey 获取账户的键（不含后缀），账户不区分大小写
+func (s *AttemptService) accountKey(scope AttemptScope, account string) string {
+	return attemptKeyPrefix + string(scope) + ":account:" + strings.ToLower(account)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/config"
+	"nola-go/internal/kv"
+	"nola-go/internal/logger"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/testutil"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// newTestAttemptService 创建使用默认配置和内存存储的 AttemptService
+func newTestAttemptService(database *gorm.DB) *AttemptService {
+	if logger.Log == nil {
+		logger.Log = zap.NewNop()
+	}
+	return NewAttemptService(config.BruteForceConfig{}, kv.NewMemoryStore(), newTestAuditService(database))
+}
+
+func TestAttemptService_LockDuration(t *testing.T) {
+	s := NewAttemptService(config.BruteForceConfig{LockSeconds: 30, MaxLockMinutes: 5}, kv.NewMemoryStore(), nil)
+
+	tests := []struct {
+		over int64
+		want time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{5, 5 * time.Minute},
+		{100, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := s.lockDuration(tt.over); got != tt.want {
+			t.Errorf("lockDuration(%d) = %v, want %v", tt.over, got, tt.want)
+		}
+	}
+}
+
+func TestAttemptService(t *testing.T) {
+	ctx := context.Background()
+	s := newTestAttemptService(testutil.NewDB(t))
+	s.maxIPFailures, s.maxAccountFailures = 3, 2
+
+	assertLocked := func(t *testing.T, ip, account string, want bool) {
+		t.Helper()
+		err := s.Check(ctx, AttemptScopeLogin, ip, account)
+		var tooMany *response.TooManyRequestsError
+		if errors.As(err, &tooMany) != want {
+			t.Fatalf("Check(%s, %s) = %v, want locked %v", ip, account, err, want)
+		}
+		if want && (tooMany.RetryAfter <= 0 || tooMany.RetryAfter > s.lock) {
+			t.Errorf("RetryAfter = %v", tooMany.RetryAfter)
+		}
+	}
+
+	// 账户 a 失败 2 次后未锁定，第 3 次后锁定
+	s.Fail(ctx, AttemptScopeLogin, "1.1.1.1", "a")
+	s.Fail(ctx, AttemptScopeLogin, "1.1.1.1", "a")
+	assertLocked(t, "1.1.1.1", "a", false)
+	s.Fail(ctx, AttemptScopeLogin, "1.1.1.1", "a")
+	assertLocked(t, "2.2.2.2", "a", true)
+
+	// 其他场景不受影响
+	if err := s.Check(ctx, AttemptScopePostPassword, "1.1.1.1", "a"); err != nil {
+		t.Errorf("Check(other scope) = %v", err)
+	}
+
+	// IP 失败第 4 次后锁定，对所有账户生效
+	assertLocked(t, "1.1.1.1", "b", false)
+	s.Fail(ctx, AttemptScopeLogin, "1.1.1.1", "b")
+	assertLocked(t, "1.1.1.1", "c", true)
+
+	// 验证成功只清零账户，不清零 IP
+	s.Succeed(ctx, AttemptScopeLogin, "a")
+	assertLocked(t, "2.2.2.2", "a", false)
+	assertLocked(t, "1.1.1.1", "a", true)
+}
+
+func TestAttemptService_Audit(t *testing.T) {
+	ctx := context.Background()
+	s := newTestAttemptService(testutil.NewDB(t))
+	s.maxIPFailures, s.maxAccountFailures = 3, 1
+
+	s.Fail(ctx, AttemptScopeLogin, "1.1.1.1", "admin")
+	s.Fail(ctx, AttemptScopePostPassword, "1.1.1.1", "12")
+	s.Fail(ctx, AttemptScopePostPassword, "1.1.1.1", "12")
+
+	pager, err := s.auditService.AuditLogsPager(ctx, 1, 10, nil, enum.AuditActionPtr(enum.AuditActionLoginFailed), nil, nil)
+	if err != nil || pager.TotalData != 3 {
+		t.Fatalf("AuditLogsPager(LOGIN_FAILED) = %+v, %v, want 3", pager, err)
+	}
+	// 按时间倒序，最早的一条为登录失败
+	log := pager.Data[2]
+	if log.TargetType != enum.AuditTargetTypeUser || *log.TargetId != "admin" || log.ActorId != nil ||
+		log.Ip == nil || *log.Ip != "1.1.1.1" || !strings.Contains(*log.Diff, `"scope":{"before":null,"after":"login"}`) {
+		t.Errorf("LOGIN_FAILED log = %+v, diff = %s", log, *log.Diff)
+	}
+
+	// 文章 12 第 2 次失败后锁定
+	pager, err = s.auditService.AuditLogsPager(ctx, 1, 10, nil, enum.AuditActionPtr(enum.AuditActionLoginLocked), nil, nil)
+	if err != nil || pager.TotalData != 1 {
+		t.Fatalf("AuditLogsPager(LOGIN_LOCKED) = %+v, %v, want 1", pager, err)
+	}
+	log = pager.Data[0]
+	if log.TargetType != enum.AuditTargetTypePost || *log.TargetId != "12" ||
+		!strings.Contains(*log.Diff, `"lockedBy":{"before":null,"after":"account"}`) {
+		t.Errorf("LOGIN_LOCKED log = %+v, diff = %s", log, *log.Diff)
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"nola-go/internal/logger"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/repository"
+	"nola-go/internal/util"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultAuditLogPageSize 未指定分页时审计日志的每页条数
+const defaultAuditLogPageSize = 20
+
+// auditRedactedFields 不记录到审计日志中的字段
+var auditRedactedFields = map[string]bool{
+	"password": true,
+}
+
+// AuditTarget 审计日志操作对象
+type AuditTarget struct {
+	// Type 操作对象类型
+	Type enum.AuditTargetType
+	// Id 操作对象 ID
+	Id string
+	// Before 操作前的数据，添加操作为空
+	Before any
+	// After 操作后的数据，删除操作为空
+	After any
+}
+
+// AuditValue 审计日志字段操作前后的值
+type AuditValue struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditService 审计日志 Service
+type AuditService struct {
+	auditLogRepo repository.AuditLogRepository
+}
+
+// NewAuditService 创建审计日志 Service
+func NewAuditService(auditLogRepo repository.AuditLogRepository) *AuditService {
+	return &AuditService{
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+// Record 记录审计日志，每个操作对象记录一条
+// 记录失败只输出日志，不影响已经完成的操作
+//   - ctx: 上下文
+//   - operator: 执行操作的用户，未登录和系统操作时用户 ID 为 0
+//   - action: 操作类型
+//   - targets: 操作对象
+func (s *AuditService) Record(ctx context.Context, operator *Operator, action enum.AuditAction, targets ...*AuditTarget) {
+	now := time.Now().UnixMilli()
+	logs := make([]*models.AuditLog, 0, len(targets))
+	for _, target := range targets {
+		log := &models.AuditLog{
+			Action:     action,
+			TargetType: target.Type,
+			TargetId:   util.StringPtr(target.Id),
+			CreateTime: now,
+		}
+		if operator != nil {
+			if operator.UserId != 0 {
+				log.ActorId = util.UintPrt(operator.UserId)
+			}
+			if operator.Username != "" {
+				log.ActorName = util.StringPtr(operator.Username)
+			}
+			if operator.Ip != "" {
+				log.Ip = util.StringPtr(operator.Ip)
+			}
+		}
+
+		diff, err := auditDiff(target.Before, target.After)
+		if err != nil {
+			logger.Log.Error("计算审计日志字段变化失败", zap.Error(err))
+		}
+		log.Diff = diff
+		logs = append(logs, log)
+	}
+
+	if err := s.auditLogRepo.Create(ctx, logs...); err != nil {
+		logger.Log.Error("添加审计日志失败", zap.String("action", string(action)), zap.Error(err))
+	}
+}
+
+// AuditLogsPager 分页获取审计日志，不分页时返回第一页
+//   - page: 当前页数
+//   - size: 每页条数
+//   - actorId: 操作用户 ID
+//   - action: 操作类型
+//   - startTime: 开始时间戳毫秒（包含）
+//   - endTime: 结束时间戳毫秒（不包含）
+func (s *AuditService) AuditLogsPager(
+	ctx context.Context,
+	page, size int,
+	actorId *uint,
+	action *enum.AuditAction,
+	startTime, endTime *int64,
+) (*models.Pager[models.AuditLog], error) {
+	if page == 0 {
+		// 审计日志只增不减，不支持一次获取所有数据
+		page, size = 1, defaultAuditLogPageSize
+	}
+
+	ret, err := s.auditLogRepo.AuditLogsPager(ctx, page, size, actorId, action, startTime, endTime)
+	if err != nil {
+		logger.Log.Error("获取审计日志失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	return ret, nil
+}
+
+// auditDiff 计算操作前后发生变化的字段，返回 {"字段": {"before": 旧值, "after": 新值}} 格式的 JSON
+// 没有变化时返回空
+//   - before: 操作前的数据
+//   - after: 操作后的数据
+func auditDiff(before, after any) (*string, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := map[string]*AuditValue{}
+	for key, value := range beforeFields {
+		if afterValue, ok := afterFields[key]; !ok || !reflect.DeepEqual(value, afterValue) {
+			diff[key] = &AuditValue{Before: value, After: afterFields[key]}
+		}
+	}
+	for key, value := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			diff[key] = &AuditValue{After: value}
+		}
+	}
+
+	if len(diff) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return nil, err
+	}
+	return util.StringPtr(string(data)), nil
+}
+
+// auditFields 将数据按 JSON 字段展开，并去掉不记录的敏感字段
+// 不是 JSON 对象的数据作为 value 字段
+func auditFields(value any) (map[string]any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	if raw == nil {
+		return map[string]any{}, nil
+	}
+
+	fields, ok := raw.(map[string]any)
+	if !ok {
+		return map[string]any{"value": raw}, nil
+	}
+	for key := range fields {
+		if auditRedactedFields[key] {
+			delete(fields, key)
+		}
+	}
+	return fields, nil
+}
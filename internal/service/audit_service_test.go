@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/repository"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// newTestAuditService 创建 AuditService
+//   - database: 审计日志所在的数据库
+func newTestAuditService(database *gorm.DB) *AuditService {
+	return NewAuditService(repository.NewAuditLogRepository(database))
+}
+
+func TestAuditDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before any
+		after  any
+		want   map[string]*AuditValue
+	}{
+		{
+			"修改",
+			&models.BlogInfo{Title: util.StringPtr("old"), Subtitle: util.StringPtr("sub")},
+			&models.BlogInfo{Title: util.StringPtr("new"), Subtitle: util.StringPtr("sub")},
+			map[string]*AuditValue{"title": {Before: "old", After: "new"}},
+		},
+		{
+			"添加",
+			nil,
+			map[string]any{"role": "OWNER"},
+			map[string]*AuditValue{"role": {After: "OWNER"}},
+		},
+		{
+			"删除时不记录密码",
+			map[string]any{"title": "post", "password": "hash"},
+			(*models.Post)(nil),
+			map[string]*AuditValue{"title": {Before: "post"}},
+		},
+		{
+			"没有变化",
+			map[string]any{"role": "OWNER"},
+			map[string]any{"role": "OWNER"},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := auditDiff(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("auditDiff: %v", err)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("auditDiff = %s, want nil", *got)
+				}
+				return
+			}
+
+			var diff map[string]*AuditValue
+			if got == nil || json.Unmarshal([]byte(*got), &diff) != nil || len(diff) != len(tt.want) {
+				t.Fatalf("auditDiff = %v, want %v", got, tt.want)
+			}
+			for key, want := range tt.want {
+				if diff[key] == nil || diff[key].Before != want.Before || diff[key].After != want.After {
+					t.Errorf("auditDiff[%s] = %+v, want %+v", key, diff[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditService_Record(t *testing.T) {
+	ctx := context.Background()
+	s := newTestAuditService(testutil.NewDB(t))
+
+	// 未登录的操作（例如初始化博客）没有操作用户
+	s.Record(ctx, &Operator{Ip: "10.0.0.1"}, enum.AuditActionConfigUpdate, &AuditTarget{
+		Type: enum.AuditTargetTypeConfig, Id: "BLOG_INFO", After: map[string]any{"title": "blog"},
+	})
+	s.Record(ctx, &Operator{UserId: 1, Username: "admin"}, enum.AuditActionPostDelete,
+		&AuditTarget{Type: enum.AuditTargetTypePost, Id: "1", Before: map[string]any{"title": "a"}},
+		&AuditTarget{Type: enum.AuditTargetTypePost, Id: "2", Before: map[string]any{"title": "b"}},
+	)
+
+	tests := []struct {
+		name      string
+		actorId   *uint
+		action    *enum.AuditAction
+		startTime *int64
+		want      int64
+	}{
+		{"全部", nil, nil, nil, 3},
+		{"按操作用户", util.UintPrt(1), nil, nil, 2},
+		{"按操作类型", nil, enum.AuditActionPtr(enum.AuditActionConfigUpdate), nil, 1},
+		{"按时间范围", nil, nil, util.Int64Ptr(1 << 62), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pager, err := s.AuditLogsPager(ctx, 1, 10, tt.actorId, tt.action, tt.startTime, nil)
+			if err != nil || pager.TotalData != tt.want {
+				t.Errorf("AuditLogsPager = %+v, %v, want %d", pager, err, tt.want)
+			}
+		})
+	}
+
+	pager, _ := s.AuditLogsPager(ctx, 1, 10, nil, enum.AuditActionPtr(enum.AuditActionConfigUpdate), nil, nil)
+	if log := pager.Data[0]; log.ActorId != nil || log.Ip == nil || *log.Ip != "10.0.0.1" || log.Diff == nil {
+		t.Errorf("anonymous audit log = %+v", log)
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"nola-go/internal/captcha"
+	"nola-go/internal/kv"
+	"nola-go/internal/logger"
+	"nola-go/internal/models/response"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultCaptchaDifficulty 默认工作量证明难度，浏览器中平均计算约 26 万次
+	DefaultCaptchaDifficulty = 18
+	// MinCaptchaDifficulty 最低工作量证明难度
+	MinCaptchaDifficulty = 8
+	// MaxCaptchaDifficulty 最高工作量证明难度
+	MaxCaptchaDifficulty = 28
+)
+
+// captchaCodeLength 图片验证码位数
+const captchaCodeLength = 5
+
+// captchaExpire 验证码有效期
+const captchaExpire = 5 * time.Minute
+
+// captchaKeyPrefix 验证码的键前缀
+//   - nola:captcha:<ID> 验证码类型和答案（图片验证码为数字，工作量证明为难度），过期时间即验证码有效期
+//   - nola:captcha:<ID>:used 已使用标记，保证验证码只能使用一次
+const captchaKeyPrefix = "nola:captcha:"
+
+// CaptchaService 评论验证码，是否启用和验证码类型在后台的评论验证码设置中配置
+// 验证码保存在键值存储中，不论验证是否通过，每个验证码只能使用一次
+type CaptchaService struct {
+	configService *ConfigService
+	store         kv.Store
+}
+
+// NewCaptchaService 创建 CaptchaService
+//   - configService: 配置 Service
+//   - store: 保存验证码的键值存储
+func NewCaptchaService(configService *ConfigService, store kv.Store) *CaptchaService {
+	return &CaptchaService{
+		configService: configService,
+		store:         store,
+	}
+}
+
+// Challenge 生成验证码，未启用时返回 nil
+func (s *CaptchaService) Challenge(ctx context.Context) (*response.CaptchaResponse, error) {
+	captchaConfig, err := s.configService.Captcha(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !captchaConfig.Enabled {
+		return nil, nil
+	}
+
+	id, err := captcha.NewId()
+	if err != nil {
+		logger.Log.Error("生成验证码 ID 失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	ret := &response.CaptchaResponse{
+		CaptchaId:  id,
+		Type:       captchaConfig.Type,
+		ExpireTime: time.Now().Add(captchaExpire).UnixMilli(),
+	}
+
+	var answer string
+	switch captchaConfig.Type {
+	case captcha.TypePoW:
+		ret.Difficulty = captchaConfig.Difficulty
+		answer = strconv.Itoa(captchaConfig.Difficulty)
+	default:
+		code, err := captcha.NewCode(captchaCodeLength)
+		if err != nil {
+			logger.Log.Error("生成验证码失败", zap.Error(err))
+			return nil, response.ServerError
+		}
+		image, err := captcha.RenderImage(code)
+		if err != nil {
+			logger.Log.Error("绘制验证码图片失败", zap.Error(err))
+			return nil, response.ServerError
+		}
+		ret.Image = "data:image/png;base64," + base64.StdEncoding.EncodeToString(image)
+		answer = code
+	}
+
+	if err := s.store.Set(ctx, captchaKeyPrefix+id, ret.Type+":"+answer, captchaExpire); err != nil {
+		logger.Log.Error("保存验证码失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	return ret, nil
+}
+
+// Verify 验证并使用验证码，未启用时不验证
+//   - captchaId: 验证码 ID
+//   - solution: 答案，图片验证码为图片中的数字，工作量证明为计算出的随机数
+func (s *CaptchaService) Verify(ctx context.Context, captchaId, solution string) error {
+	captchaConfig, err := s.configService.Captcha(ctx)
+	if err != nil {
+		return err
+	}
+	if !captchaConfig.Enabled {
+		return nil
+	}
+
+	captchaId = strings.TrimSpace(captchaId)
+	solution = strings.TrimSpace(solution)
+	if captchaId == "" || solution == "" {
+		return errors.New("请完成验证码")
+	}
+
+	// 先标记为已使用，同一个验证码同时提交多次时只有一次可以继续验证
+	key := captchaKeyPrefix + captchaId
+	ok, err := s.store.SetNX(ctx, key+":used", "1", captchaExpire)
+	if err != nil {
+		logger.Log.Error("标记验证码已使用失败", zap.Error(err))
+		return response.ServerError
+	}
+	if !ok {
+		return errors.New("验证码已使用，请重新获取")
+	}
+
+	value, ok, err := s.store.Get(ctx, key)
+	if err != nil {
+		logger.Log.Error("获取验证码失败", zap.Error(err))
+		return response.ServerError
+	}
+	if !ok {
+		return errors.New("验证码已过期，请重新获取")
+	}
+	if err := s.store.Delete(ctx, key); err != nil {
+		logger.Log.Warn("删除验证码失败", zap.Error(err))
+	}
+
+	kind, answer, _ := strings.Cut(value, ":")
+	switch kind {
+	case captcha.TypePoW:
+		difficulty, _ := strconv.Atoi(answer)
+		if !captcha.VerifyPoW(captchaId, solution, difficulty) {
+			return errors.New("验证码验证失败，请重新获取")
+		}
+	default:
+		if solution != answer {
+			return errors.New("验证码错误，请重新获取")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/captcha"
+	"nola-go/internal/kv"
+	"nola-go/internal/models"
+	"nola-go/internal/repository"
+	"nola-go/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestCaptchaService(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	configService := NewConfigService(repository.NewConfigRepository(database), newTestAuditService(database))
+	store := kv.NewMemoryStore()
+	s := NewCaptchaService(configService, store)
+
+	// 未启用时不生成也不验证
+	if ret, err := s.Challenge(ctx); err != nil || ret != nil {
+		t.Fatalf("disabled Challenge = %+v, %v", ret, err)
+	}
+	if err := s.Verify(ctx, "", ""); err != nil {
+		t.Fatalf("disabled Verify: %v", err)
+	}
+
+	// 图片验证码
+	if _, err := configService.SetCaptcha(ctx, SystemOperator, &models.CaptchaConfig{Enabled: true}); err != nil {
+		t.Fatalf("SetCaptcha: %v", err)
+	}
+	image, err := s.Challenge(ctx)
+	if err != nil || image.Type != captcha.TypeImage || !strings.HasPrefix(image.Image, "data:image/png;base64,") {
+		t.Fatalf("Challenge(image) = %+v, %v", image, err)
+	}
+	value, _, _ := store.Get(ctx, captchaKeyPrefix+image.CaptchaId)
+	code := strings.TrimPrefix(value, captcha.TypeImage+":")
+
+	if err := s.Verify(ctx, image.CaptchaId, ""); err == nil {
+		t.Error("Verify(empty solution) should fail")
+	}
+	if err := s.Verify(ctx, image.CaptchaId, code); err != nil {
+		t.Fatalf("Verify(image): %v", err)
+	}
+	// 只能使用一次
+	if err := s.Verify(ctx, image.CaptchaId, code); err == nil || !strings.Contains(err.Error(), "已使用") {
+		t.Errorf("Verify(reused) = %v", err)
+	}
+	if err := s.Verify(ctx, "unknown", code); err == nil || !strings.Contains(err.Error(), "已过期") {
+		t.Errorf("Verify(unknown) = %v", err)
+	}
+
+	// 答错后验证码失效
+	image, _ = s.Challenge(ctx)
+	if err := s.Verify(ctx, image.CaptchaId, "wrong"); err == nil {
+		t.Error("Verify(wrong) should fail")
+	}
+	value, _, _ = store.Get(ctx, captchaKeyPrefix+image.CaptchaId)
+	if err := s.Verify(ctx, image.CaptchaId, strings.TrimPrefix(value, captcha.TypeImage+":")); err == nil {
+		t.Error("Verify after wrong answer should fail")
+	}
+
+	// 工作量证明
+	if _, err := configService.SetCaptcha(ctx, SystemOperator, &models.CaptchaConfig{Enabled: true, Type: captcha.TypePoW, Difficulty: MinCaptchaDifficulty}); err != nil {
+		t.Fatalf("SetCaptcha: %v", err)
+	}
+	pow, err := s.Challenge(ctx)
+	if err != nil || pow.Type != captcha.TypePoW || pow.Difficulty != MinCaptchaDifficulty || pow.Image != "" {
+		t.Fatalf("Challenge(pow) = %+v, %v", pow, err)
+	}
+	if err := s.Verify(ctx, pow.CaptchaId, captcha.SolvePoW(pow.CaptchaId, pow.Difficulty)); err != nil {
+		t.Fatalf("Verify(pow): %v", err)
+	}
+}
+
+func TestConfigService_SetCaptcha(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	s := NewConfigService(repository.NewConfigRepository(database), newTestAuditService(database))
+
+	// 没有设置时默认未启用
+	got, err := s.Captcha(ctx)
+	if err != nil || got.Enabled || got.Type != captcha.TypeImage || got.Difficulty != DefaultCaptchaDifficulty {
+		t.Fatalf("Captcha = %+v, %v", got, err)
+	}
+
+	for _, invalid := range []*models.CaptchaConfig{
+		{Enabled: true, Type: "recaptcha"},
+		{Enabled: true, Type: captcha.TypePoW, Difficulty: MinCaptchaDifficulty - 1},
+		{Enabled: true, Type: captcha.TypePoW, Difficulty: MaxCaptchaDifficulty + 1},
+	} {
+		if _, err := s.SetCaptcha(ctx, SystemOperator, invalid); err == nil {
+			t.Errorf("SetCaptcha(%+v) should fail", invalid)
+		}
+	}
+
+	if _, err := s.SetCaptcha(ctx, SystemOperator, &models.CaptchaConfig{Enabled: true, Type: captcha.TypePoW}); err != nil {
+		t.Fatalf("SetCaptcha: %v", err)
+	}
+	got, _ = s.Captcha(ctx)
+	if !got.Enabled || got.Type != captcha.TypePoW || got.Difficulty != DefaultCaptchaDifficulty {
+		t.Errorf("Captcha = %+v", got)
+	}
+}
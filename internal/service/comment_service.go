@@ -5,38 +5,89 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"nola-go/internal/config"
 	"nola-go/internal/logger"
 	"nola-go/internal/models"
 	"nola-go/internal/models/enum"
 	"nola-go/internal/models/response"
 	"nola-go/internal/repository"
+	"nola-go/internal/spam"
 	"nola-go/internal/util"
+	"strconv"
 
 	"go.uber.org/zap"
 )
 
 // CommentService 评论 Service
 type CommentService struct {
-	commentRepo repository.CommentRepository
-	postRepo    repository.PostRepository
+	commentRepo         repository.CommentRepository
+	postRepo            repository.PostRepository
+	auditService        *AuditService
+	notificationService *NotificationService
+	spamFilter          *spam.Filter
+	commenterService    *CommenterService
+	commentConfig       config.CommentConfig
 }
 
 // NewCommentService 创建评论 Service
-func NewCommentService(commentRepo repository.CommentRepository, postRepo repository.PostRepository) *CommentService {
+func NewCommentService(
+	commentRepo repository.CommentRepository,
+	postRepo repository.PostRepository,
+	auditService *AuditService,
+	notificationService *NotificationService,
+	spamFilter *spam.Filter,
+	commenterService *CommenterService,
+	commentConfig config.CommentConfig,
+) *CommentService {
 	return &CommentService{
-		commentRepo: commentRepo,
-		postRepo:    postRepo,
+		commentRepo:         commentRepo,
+		postRepo:            postRepo,
+		auditService:        auditService,
+		notificationService: notificationService,
+		spamFilter:          spamFilter,
+		commenterService:    commenterService,
+		commentConfig:       commentConfig,
 	}
 }
 
+// ValidateComment 检查评论内容、名称、站点和邮箱，不访问数据库
+// 博客前端提交评论时在验证验证码之前调用，避免填写错误时消耗一次性的验证码
+func ValidateComment(comment *models.Comment) error {
+	// 评论内容为空
+	if util.StringIsBlank(comment.Content) {
+		return errors.New("评论内容不能为空")
+	}
+
+	// 名称不能为空
+	if util.StringIsBlank(comment.DisplayName) {
+		return errors.New("名称不能为空")
+	}
+
+	// 站点不合法
+	if comment.Site != nil {
+		if *comment.Site != "/" && !util.StringIsUrl(*comment.Site) {
+			return errors.New("站点格式错误")
+		}
+	}
+
+	// 邮箱不合法
+	if !util.StringIsEmail(comment.Email) {
+		return errors.New("邮箱格式错误")
+	}
+	return nil
+}
+
 // AddComment 添加评论
 //   - c: 上下文
 //   - comment: 评论
-//   - isApiRequest: 是否是 API 请求（非管理员请求，即从博客前端提交的请求）
+//   - isApiRequest: 是否是 API 请求（非管理员请求，即从博客前端提交的请求），只检查博客前端提交的评论人和评论是否为垃圾评论，
+//     信任的评论人提交的评论（垃圾评论除外）自动通过审核
+//   - client: 提交评论的客户端信息，用于检查垃圾评论，管理员请求时可以为 nil
 func (s *CommentService) AddComment(
 	c context.Context,
 	comment models.Comment,
 	isApiRequest bool,
+	client *spam.Client,
 ) (*models.Comment, error) {
 	// 检查评论对应的文章是否存在
 	post, err := s.postRepo.PostById(c, comment.PostId, false)
@@ -87,26 +138,28 @@ func (s *CommentService) AddComment(
 		comment.ReplyDisplayName = &replyComment.DisplayName
 	}
 
-	// 评论内容为空
-	if util.StringIsBlank(comment.Content) {
-		return nil, errors.New("评论内容不能为空")
+	if err := ValidateComment(&comment); err != nil {
+		return nil, err
 	}
 
-	// 名称不能为空
-	if util.StringIsBlank(comment.DisplayName) {
-		return nil, errors.New("名称不能为空")
+	// 检查评论人
+	trusted := false
+	if isApiRequest {
+		if trusted, err = s.checkCommenter(c, comment.Email); err != nil {
+			return nil, err
+		}
 	}
 
-	// 站点不合法
-	if comment.Site != nil {
-		if *comment.Site != "/" && !util.StringIsUrl(*comment.Site) {
-			return nil, errors.New("站点格式错误")
+	// 检查垃圾评论
+	if isApiRequest && s.spamFilter != nil {
+		if err := s.checkSpam(c, &comment, client); err != nil {
+			return nil, err
 		}
 	}
 
-	// 邮箱不合法
-	if !util.StringIsEmail(comment.Email) {
-		return nil, errors.New("邮箱格式错误")
+	// 信任的评论人不需要审核
+	if trusted && !comment.IsSpam {
+		comment.IsPass = true
 	}
 
 	// 添加评论
@@ -115,9 +168,90 @@ func (s *CommentService) AddComment(
 		logger.Log.Error(fmt.Sprintf("添加评论 [%d] 失败", comment.PostId), zap.Error(err))
 		return ret, response.ServerError
 	}
+
+	// 邮件通知：博客前端的新评论通知管理员（垃圾评论除外），已通过审核的回复通知被回复的评论人
+	if isApiRequest && !ret.IsSpam {
+		s.notificationService.NotifyNewComment(c, ret)
+	}
+	s.notificationService.NotifyReply(c, ret)
+
 	return ret, nil
 }
 
+// checkCommenter 检查评论人，禁止评论时返回错误
+// 返回是否为信任的评论人：后台标记为信任，或者已通过审核的评论数量达到 TrustThreshold
+func (s *CommentService) checkCommenter(c context.Context, email string) (bool, error) {
+	status, err := s.commenterService.CommenterStatus(c, email)
+	if err != nil {
+		return false, err
+	}
+	if status != nil {
+		switch *status {
+		case enum.CommenterStatusBlocked:
+			return false, errors.New("该邮箱已被禁止评论")
+		case enum.CommenterStatusTrusted:
+			return true, nil
+		}
+	}
+
+	if s.commentConfig.TrustThreshold <= 0 {
+		return false, nil
+	}
+	count, err := s.commentRepo.PassedCommentCount(c, email)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("获取评论人 [%s] 已通过审核的评论数量失败", email), zap.Error(err))
+		return false, response.ServerError
+	}
+	return count >= s.commentConfig.TrustThreshold, nil
+}
+
+// checkSpam 检查垃圾评论，分数过高时拒绝，否则记录分数，垃圾评论不通过审核
+func (s *CommentService) checkSpam(c context.Context, comment *models.Comment, client *spam.Client) error {
+	if client == nil {
+		client = &spam.Client{}
+	}
+	result := s.spamFilter.Check(c, &spam.Comment{
+		Client:      *client,
+		PostId:      comment.PostId,
+		Content:     comment.Content,
+		DisplayName: comment.DisplayName,
+		Email:       comment.Email,
+		Site:        util.StringDefault(comment.Site, ""),
+	})
+
+	comment.SpamScore = result.Score
+	if len(result.Reasons) > 0 {
+		reason := []rune(result.Reason())
+		if len(reason) > 512 {
+			reason = reason[:512]
+		}
+		comment.SpamReason = util.StringPtr(string(reason))
+	}
+
+	switch result.Action {
+	case spam.ActionReject:
+		logger.Log.Info("拒绝垃圾评论",
+			zap.Uint("postId", comment.PostId),
+			zap.String("ip", client.IP),
+			zap.Int("score", result.Score),
+			zap.Strings("reasons", result.Reasons),
+		)
+		return errors.New("评论被识别为垃圾评论")
+	case spam.ActionSpam:
+		comment.IsSpam = true
+		comment.IsPass = false
+	}
+	return nil
+}
+
+// CommentFormToken 生成评论表单令牌，博客前端打开评论框时获取，提交评论时带上
+func (s *CommentService) CommentFormToken() string {
+	if s.spamFilter == nil {
+		return ""
+	}
+	return s.spamFilter.FormToken()
+}
+
 // DeleteCommentById 根据评论 ID 删除评论
 func (s *CommentService) DeleteCommentById(c context.Context, id uint) (bool, error) {
 	// 先获取要删除的评论
@@ -150,7 +284,9 @@ func (s *CommentService) DeleteCommentById(c context.Context, id uint) (bool, er
 }
 
 // DeleteCommentByIds 根据评论 ID 数组删除评论
-func (s *CommentService) DeleteCommentByIds(c context.Context, ids []uint) (bool, error) {
+//   - operator: 执行删除操作的用户
+//   - ids: 评论 ID 数组
+func (s *CommentService) DeleteCommentByIds(c context.Context, operator *Operator, ids []uint) (bool, error) {
 	// 先尝试获取所有评论
 	comments, err := s.commentRepo.CommentByIds(c, ids)
 	if err != nil {
@@ -172,6 +308,12 @@ func (s *CommentService) DeleteCommentByIds(c context.Context, ids []uint) (bool
 		return ret, response.ServerError
 	}
 
+	if ret {
+		s.auditService.Record(c, operator, enum.AuditActionCommentDelete, util.Map(comments, func(comment *models.Comment) *AuditTarget {
+			return &AuditTarget{Type: enum.AuditTargetTypeComment, Id: strconv.FormatUint(uint64(comment.CommentId), 10), Before: comment}
+		})...)
+	}
+
 	// 删除上面被删除的评论的可能存在的子评论
 	if ret {
 		// 父评论 ID 为空的评论是顶层评论，可能有子评论
@@ -234,21 +376,72 @@ func (s *CommentService) UpdateComment(c context.Context, comment models.Comment
 		return false, errors.New("邮箱格式错误")
 	}
 
+	before, err := s.CommentById(c, comment.CommentId)
+	if err != nil {
+		return false, err
+	}
+
 	ret, err := s.commentRepo.UpdateComment(c, comment)
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("修改评论 [%d] 失败", comment.CommentId), zap.Error(err))
 		return ret, response.ServerError
 	}
+
+	// 评论通过审核，通知被回复的评论人
+	if ret && before != nil && !before.IsPass && comment.IsPass {
+		s.notifyPassed(c, []uint{comment.CommentId})
+	}
 	return ret, nil
 }
 
 // SetCommentPass 批量设置评论是否通过审核
 func (s *CommentService) SetCommentPass(c context.Context, ids []uint, isPass bool) (bool, error) {
+	// 先获取未通过审核的评论，通过审核后通知被回复的评论人
+	var passed []uint
+	if isPass {
+		comments, err := s.commentRepo.CommentByIds(c, ids)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("获取评论 [%v] 失败", ids), zap.Error(err))
+			return false, response.ServerError
+		}
+		for _, comment := range comments {
+			if !comment.IsPass {
+				passed = append(passed, comment.CommentId)
+			}
+		}
+	}
+
 	ret, err := s.commentRepo.SetCommentPass(c, ids, isPass)
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("批量设置评论 [%v] 是否通过审核失败", ids), zap.Error(err))
 		return ret, response.ServerError
 	}
+
+	if ret && len(passed) > 0 {
+		s.notifyPassed(c, passed)
+	}
+	return ret, nil
+}
+
+// notifyPassed 评论通过审核后通知被回复的评论人，失败只记录日志
+func (s *CommentService) notifyPassed(c context.Context, ids []uint) {
+	comments, err := s.commentRepo.CommentByIds(c, ids)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("获取评论 [%v] 失败", ids), zap.Error(err))
+		return
+	}
+	for _, comment := range comments {
+		s.notificationService.NotifyReply(c, comment)
+	}
+}
+
+// SetCommentSpam 批量设置评论是否为垃圾评论，垃圾评论不通过审核
+func (s *CommentService) SetCommentSpam(c context.Context, ids []uint, isSpam bool) (bool, error) {
+	ret, err := s.commentRepo.SetCommentSpam(c, ids, isSpam)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("批量设置评论 [%v] 是否为垃圾评论失败", ids), zap.Error(err))
+		return ret, response.ServerError
+	}
 	return ret, nil
 }
 
@@ -260,6 +453,7 @@ func (s *CommentService) SetCommentPass(c context.Context, ids []uint, isPass bo
 //   - commentId: 评论 ID
 //   - parentId: 父评论 ID
 //   - isPass: 是否通过审核
+//   - isSpam: 是否为垃圾评论，为空且 isPass 为 false 时不包含垃圾评论（待审核列表）
 //   - key: 关键字（内容、名称、邮箱）
 //   - sort: 排序方式（默认时间降序）
 //   - tree: 是否将子评论放置到父评论的 children 字段中 (默认 false)，
@@ -270,7 +464,7 @@ func (s *CommentService) Comments(
 	postId *uint,
 	slug *string,
 	commentId, parentId *uint,
-	isPass *bool,
+	isPass, isSpam *bool,
 	key *string,
 	sort *enum.CommentSort,
 	tree bool,
@@ -295,7 +489,7 @@ func (s *CommentService) Comments(
 
 	if tree {
 		// 需要把子评论放到父评论的 children 字段中
-		comments, err := s.commentRepo.Comments(c, mPostId, commentId, parentId, isPass, key, sort)
+		comments, err := s.commentRepo.Comments(c, mPostId, commentId, parentId, isPass, isSpam, key, sort)
 
 		if err != nil {
 			logger.Log.Error(fmt.Sprintf("获取文章 [%d] 的评论失败", *mPostId), zap.Error(err))
@@ -360,7 +554,7 @@ func (s *CommentService) Comments(
 	}
 
 	// 平铺获取所有评论
-	ret, err := s.commentRepo.CommentsPager(c, page, size, mPostId, commentId, parentId, isPass, key, sort)
+	ret, err := s.commentRepo.CommentsPager(c, page, size, mPostId, commentId, parentId, isPass, isSpam, key, sort)
 	if err != nil {
 		logger.Log.Error("分页获取评论失败", zap.Error(err))
 		return nil, response.ServerError
@@ -368,6 +562,23 @@ func (s *CommentService) Comments(
 	return ret, nil
 }
 
+// ApiComments 博客前端获取文章已通过审核的评论，子评论放置到父评论的 children 字段中，
+// 不返回评论人邮箱，使用头像地址代替
+//   - postId: 文章 ID
+//   - slug: 文章别名，文章 ID 为空时使用
+func (s *CommentService) ApiComments(c context.Context, page, size int, postId *uint, slug *string) (*models.Pager[response.CommentApiResponse], error) {
+	pager, err := s.Comments(c, page, size, postId, slug, nil, nil, util.BoolPtr(true), nil, nil, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	return response.NewCommentApiResponsePager(pager, s.commentConfig.AvatarURL), nil
+}
+
+// ApiComment 转换为博客前端评论响应体，不返回评论人邮箱，使用头像地址代替
+func (s *CommentService) ApiComment(comment *models.Comment) *response.CommentApiResponse {
+	return response.NewCommentApiResponse(comment, s.commentConfig.AvatarURL)
+}
+
 // CommentById 根据评论 ID 获取评论
 func (s *CommentService) CommentById(c context.Context, id uint) (*models.Comment, error) {
 	ret, err := s.commentRepo.CommentById(c, id)
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"nola-go/internal/config"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/repository"
+	"nola-go/internal/spam"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommentService_Spam(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	f := testutil.NewFixture(t, database)
+	notificationService, dir := newTestNotificationService(t, database, "admin@example.com")
+	commentRepo := repository.NewCommentRepository(database)
+	blocklist, err := spam.NewBlocklistChecker([]string{"casino"}, nil)
+	if err != nil {
+		t.Fatalf("NewBlocklistChecker: %v", err)
+	}
+	filter := spam.NewFilter(spam.DefaultSpamThreshold, spam.DefaultRejectThreshold, nil,
+		spam.NewHoneypotChecker(), blocklist, spam.NewLinkChecker(1), spam.NewDuplicateChecker(commentRepo, time.Hour),
+	)
+	s := NewCommentService(commentRepo, notificationService.postRepo, newTestAuditService(database), notificationService, filter,
+		NewCommenterService(repository.NewCommenterRepository(database)), config.CommentConfig{})
+
+	post := f.Post("hello", "正文")
+	add := func(content string, client *spam.Client) (*models.Comment, error) {
+		t.Helper()
+		return s.AddComment(ctx, models.Comment{PostId: post.PostId, Content: content, DisplayName: "访客", Email: "visitor@example.com"}, true, client)
+	}
+
+	// 正常评论，记录分数
+	ok, err := add("写得很好 https://a.com", &spam.Client{IP: "1.2.3.4"})
+	if err != nil || ok.IsSpam || ok.SpamScore != 0 || ok.SpamReason != nil {
+		t.Fatalf("AddComment(ok) = %+v, %v", ok, err)
+	}
+
+	// 链接过多不足以标记为垃圾评论
+	links, err := add("https://a.com https://b.com", nil)
+	if err != nil || links.IsSpam || links.SpamScore != spam.ScoreLinks || links.SpamReason == nil {
+		t.Fatalf("AddComment(links) = %+v, %v", links, err)
+	}
+
+	// 关键词标记为垃圾评论，不通知管理员
+	notificationService.Wait()
+	readSentMails(t, dir)
+	spammed, err := add("best casino", nil)
+	if err != nil || !spammed.IsSpam || spammed.IsPass || !strings.Contains(*spammed.SpamReason, "blocklist") {
+		t.Fatalf("AddComment(spam) = %+v, %v", spammed, err)
+	}
+	notificationService.Wait()
+	if mails := readSentMails(t, dir); len(mails) != 0 {
+		t.Errorf("spam mails = %+v", mails)
+	}
+
+	// 蜜罐、关键词加重复直接拒绝，不保存
+	for _, tt := range []struct {
+		content string
+		client  *spam.Client
+	}{
+		{"写得很好", &spam.Client{Honeypot: "https://spam.example.com"}},
+		{"best casino", nil},
+	} {
+		if _, err := add(tt.content, tt.client); err == nil || !strings.Contains(err.Error(), "垃圾评论") {
+			t.Errorf("AddComment(%s) = %v, want rejected", tt.content, err)
+		}
+	}
+	if count, _ := commentRepo.CommentCount(ctx); count != 3 {
+		t.Errorf("CommentCount = %d, want 3", count)
+	}
+
+	// 管理员添加的评论不检查
+	admin, err := s.AddComment(ctx, models.Comment{PostId: post.PostId, Content: "best casino", DisplayName: "博主", Email: "admin@example.com", IsPass: true}, false, nil)
+	if err != nil || admin.IsSpam || admin.SpamScore != 0 {
+		t.Errorf("AddComment(admin) = %+v, %v", admin, err)
+	}
+
+	// 后台按是否为垃圾评论筛选
+	pager, err := s.Comments(ctx, 0, 0, &post.PostId, nil, nil, nil, nil, util.BoolPtr(true), nil, nil, false)
+	if err != nil || len(pager.Data) != 1 || pager.Data[0].CommentId != spammed.CommentId {
+		t.Fatalf("Comments(spam) = %+v, %v", pager, err)
+	}
+
+	// 取消垃圾评论标记后可以审核通过
+	if _, err := s.SetCommentSpam(ctx, []uint{spammed.CommentId}, false); err != nil {
+		t.Fatalf("SetCommentSpam: %v", err)
+	}
+	got, _ := s.CommentById(ctx, spammed.CommentId)
+	if got.IsSpam || got.IsPass {
+		t.Errorf("not spam comment = %+v", got)
+	}
+}
+
+func TestCommentService_Commenter(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	f := testutil.NewFixture(t, database)
+	notificationService, _ := newTestNotificationService(t, database, "admin@example.com")
+	commentRepo := repository.NewCommentRepository(database)
+	commenterService := NewCommenterService(repository.NewCommenterRepository(database))
+	blocklist, err := spam.NewBlocklistChecker([]string{"casino"}, nil)
+	if err != nil {
+		t.Fatalf("NewBlocklistChecker: %v", err)
+	}
+	filter := spam.NewFilter(spam.DefaultSpamThreshold, spam.DefaultRejectThreshold, nil, blocklist)
+	s := NewCommentService(commentRepo, notificationService.postRepo, newTestAuditService(database), notificationService, filter,
+		commenterService, config.CommentConfig{AvatarURL: "https://cravatar.cn/avatar/{hash}", TrustThreshold: 2})
+
+	post := f.Post("hello", "正文")
+	add := func(email, content string) (*models.Comment, error) {
+		t.Helper()
+		return s.AddComment(ctx, models.Comment{PostId: post.PostId, Content: content, DisplayName: "访客", Email: email}, true, nil)
+	}
+
+	// 已通过审核的评论未达到 2 条时需要审核
+	f.Comment(post, nil, "Regular@Example.com", true)
+	f.Comment(post, nil, "regular@example.com", false)
+	if got, err := add("regular@example.com", "第二条"); err != nil || got.IsPass {
+		t.Fatalf("AddComment(untrusted) = %+v, %v", got, err)
+	}
+
+	// 达到 2 条后自动通过审核（邮箱不区分大小写），垃圾评论除外
+	f.Comment(post, nil, "regular@example.com", true)
+	if got, err := add("REGULAR@example.com", "第三条"); err != nil || !got.IsPass {
+		t.Errorf("AddComment(trusted by count) = %+v, %v", got, err)
+	}
+	if got, err := add("regular@example.com", "best casino"); err != nil || got.IsPass || !got.IsSpam {
+		t.Errorf("AddComment(trusted spam) = %+v, %v", got, err)
+	}
+
+	// 后台标记为信任和禁止评论
+	if _, err := commenterService.SaveCommenter(ctx, "friend@example.com", enum.CommenterStatusTrusted, nil); err != nil {
+		t.Fatalf("SaveCommenter: %v", err)
+	}
+	if got, err := add("friend@example.com", "第一条"); err != nil || !got.IsPass {
+		t.Errorf("AddComment(marked trusted) = %+v, %v", got, err)
+	}
+	if _, err := commenterService.SaveCommenter(ctx, "Friend@example.com", enum.CommenterStatusBlocked, nil); err != nil {
+		t.Fatalf("SaveCommenter: %v", err)
+	}
+	if _, err := add("friend@example.com", "又来了"); err == nil || !strings.Contains(err.Error(), "禁止评论") {
+		t.Errorf("AddComment(blocked) = %v", err)
+	}
+	pager, err := commenterService.Commenters(ctx, 0, 0, nil, nil)
+	if err != nil || len(pager.Data) != 1 || pager.Data[0].Status != enum.CommenterStatusBlocked {
+		t.Errorf("Commenters = %+v, %v", pager, err)
+	}
+
+	// 管理员添加的评论不检查评论人
+	if _, err := s.AddComment(ctx, models.Comment{PostId: post.PostId, Content: "管理员", DisplayName: "访客", Email: "friend@example.com", IsPass: true}, false, nil); err != nil {
+		t.Errorf("AddComment(admin) = %v", err)
+	}
+
+	// 博客前端的评论不返回邮箱，返回头像地址
+	comments, err := s.ApiComments(ctx, 0, 0, &post.PostId, nil)
+	if err != nil || len(comments.Data) == 0 {
+		t.Fatalf("ApiComments = %+v, %v", comments, err)
+	}
+	data, _ := json.Marshal(comments)
+	if strings.Contains(string(data), `"email"`) {
+		t.Errorf("ApiComments leaks email: %s", data)
+	}
+	if !strings.HasPrefix(comments.Data[0].Avatar, "https://cravatar.cn/avatar/") {
+		t.Errorf("Avatar = %s", comments.Data[0].Avatar)
+	}
+}
+
+func TestValidateComment(t *testing.T) {
+	valid := models.Comment{Content: "评论", DisplayName: "访客", Email: "visitor@example.com", Site: util.StringPtr("https://example.com")}
+	tests := []struct {
+		name    string
+		modify  func(c *models.Comment)
+		wantErr bool
+	}{
+		{"正确", func(c *models.Comment) {}, false},
+		{"站点为 /", func(c *models.Comment) { c.Site = util.StringPtr("/") }, false},
+		{"内容为空", func(c *models.Comment) { c.Content = " " }, true},
+		{"名称为空", func(c *models.Comment) { c.DisplayName = "" }, true},
+		{"站点格式错误", func(c *models.Comment) { c.Site = util.StringPtr("example") }, true},
+		{"邮箱格式错误", func(c *models.Comment) { c.Email = "visitor" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := valid
+			tt.modify(&comment)
+			if err := ValidateComment(&comment); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateComment = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
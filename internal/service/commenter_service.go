@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nola-go/internal/logger"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/repository"
+	"nola-go/internal/util"
+
+	"go.uber.org/zap"
+)
+
+// CommenterService 管理员标记的评论人 Service，按邮箱信任（评论不需要审核）或禁止评论
+type CommenterService struct {
+	commenterRepo repository.CommenterRepository
+}
+
+// NewCommenterService 创建 CommenterService
+func NewCommenterService(commenterRepo repository.CommenterRepository) *CommenterService {
+	return &CommenterService{
+		commenterRepo: commenterRepo,
+	}
+}
+
+// SaveCommenter 标记评论人，邮箱已标记时修改状态和备注
+//   - email: 评论人邮箱，不区分大小写
+//   - status: 状态
+//   - remark: 备注
+func (s *CommenterService) SaveCommenter(ctx context.Context, email string, status enum.CommenterStatus, remark *string) (*models.Commenter, error) {
+	if !util.StringIsEmail(email) {
+		return nil, errors.New("邮箱格式错误")
+	}
+	if enum.CommenterStatusValueOf(string(status)) == nil {
+		return nil, errors.New("不支持的评论人状态 [" + string(status) + "]")
+	}
+	if util.StringIsNilOrBlank(remark) {
+		remark = nil
+	}
+
+	ret, err := s.commenterRepo.SaveCommenter(ctx, &models.Commenter{
+		Email:  email,
+		Status: status,
+		Remark: remark,
+	})
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("标记评论人 [%s] 失败", email), zap.Error(err))
+		return nil, response.ServerError
+	}
+	return ret, nil
+}
+
+// DeleteCommenters 取消标记评论人
+func (s *CommenterService) DeleteCommenters(ctx context.Context, ids []uint) (bool, error) {
+	ret, err := s.commenterRepo.DeleteCommenters(ctx, ids)
+	if err != nil {
+		logger.Log.Error("取消标记评论人失败", zap.Error(err))
+		return false, response.ServerError
+	}
+	return ret, nil
+}
+
+// Commenters 分页获取标记的评论人
+//   - status: 状态
+//   - key: 关键字（邮箱、备注）
+func (s *CommenterService) Commenters(ctx context.Context, page, size int, status *enum.CommenterStatus, key *string) (*models.Pager[models.Commenter], error) {
+	ret, err := s.commenterRepo.CommentersPager(ctx, page, size, status, key)
+	if err != nil {
+		logger.Log.Error("分页获取评论人失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	return ret, nil
+}
+
+// CommenterStatus 获取评论人状态，没有标记时返回 nil
+//   - email: 评论人邮箱，不区分大小写
+func (s *CommenterService) CommenterStatus(ctx context.Context, email string) (*enum.CommenterStatus, error) {
+	commenter, err := s.commenterRepo.CommenterByEmail(ctx, email)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("获取评论人 [%s] 失败", email), zap.Error(err))
+		return nil, response.ServerError
+	}
+	if commenter == nil {
+		return nil, nil
+	}
+	return &commenter.Status, nil
+}
@@ -2,24 +2,33 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"nola-go/internal/captcha"
 	"nola-go/internal/logger"
+	"nola-go/internal/mail"
 	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
 	"nola-go/internal/models/response"
 	"nola-go/internal/repository"
 	"nola-go/internal/util"
+	"slices"
+	"strings"
 
 	"go.uber.org/zap"
 )
 
 // ConfigService 配置 Service
 type ConfigService struct {
-	configRepo repository.ConfigRepository
+	configRepo   repository.ConfigRepository
+	auditService *AuditService
 }
 
 // NewConfigService 创建配置 Service
-func NewConfigService(configRepo repository.ConfigRepository) *ConfigService {
+func NewConfigService(configRepo repository.ConfigRepository, auditService *AuditService) *ConfigService {
 	return &ConfigService{
-		configRepo: configRepo,
+		configRepo:   configRepo,
+		auditService: auditService,
 	}
 }
 
@@ -80,8 +89,19 @@ func (s *ConfigService) Config(ctx context.Context, key models.ConfigKey) (*stri
 }
 
 // SetBlogInfo 设置博客信息
-func (s *ConfigService) SetBlogInfo(ctx context.Context, blogInfo *models.BlogInfo) (bool, error) {
-	_, err := s.SetConfig(ctx, &models.Config{
+//   - operator: 执行操作的用户，初始化博客时未登录
+//   - blogInfo: 博客信息
+func (s *ConfigService) SetBlogInfo(ctx context.Context, operator *Operator, blogInfo *models.BlogInfo) (bool, error) {
+	if err := checkSiteLinks(blogInfo); err != nil {
+		return false, err
+	}
+
+	before, err := s.BlogInfo(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.SetConfig(ctx, &models.Config{
 		Key:   models.ConfigKeyBlogInfo,
 		Value: util.StringDefault(util.ToJsonString(blogInfo), ""),
 	})
@@ -90,6 +110,13 @@ func (s *ConfigService) SetBlogInfo(ctx context.Context, blogInfo *models.BlogIn
 		return false, err
 	}
 
+	s.auditService.Record(ctx, operator, enum.AuditActionConfigUpdate, &AuditTarget{
+		Type:   enum.AuditTargetTypeConfig,
+		Id:     string(models.ConfigKeyBlogInfo),
+		Before: before,
+		After:  blogInfo,
+	})
+
 	return true, nil
 }
 
@@ -114,8 +141,15 @@ func (s *ConfigService) BlogInfo(ctx context.Context) (*models.BlogInfo, error)
 }
 
 // SetICPFiling 设置备案信息
-func (s *ConfigService) SetICPFiling(ctx context.Context, filing *models.ICPFiling) (bool, error) {
-	_, err := s.SetConfig(ctx, &models.Config{
+//   - operator: 执行操作的用户
+//   - filing: 备案信息
+func (s *ConfigService) SetICPFiling(ctx context.Context, operator *Operator, filing *models.ICPFiling) (bool, error) {
+	before, err := s.ICPFiling(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.SetConfig(ctx, &models.Config{
 		Key:   models.ConfigKeyICPFiling,
 		Value: util.StringDefault(util.ToJsonString(filing), ""),
 	})
@@ -124,6 +158,13 @@ func (s *ConfigService) SetICPFiling(ctx context.Context, filing *models.ICPFili
 		return false, err
 	}
 
+	s.auditService.Record(ctx, operator, enum.AuditActionConfigUpdate, &AuditTarget{
+		Type:   enum.AuditTargetTypeConfig,
+		Id:     string(models.ConfigKeyICPFiling),
+		Before: before,
+		After:  filing,
+	})
+
 	return true, nil
 }
 
@@ -146,3 +187,214 @@ func (s *ConfigService) ICPFiling(ctx context.Context) (*models.ICPFiling, error
 
 	return icp, nil
 }
+
+// SetRobotsTxt 设置 robots.txt 内容，为空时使用默认内容
+//   - operator: 执行操作的用户
+//   - content: robots.txt 内容
+func (s *ConfigService) SetRobotsTxt(ctx context.Context, operator *Operator, content string) (bool, error) {
+	before, err := s.RobotsTxt(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if strings.TrimSpace(content) == "" {
+		// 恢复默认内容
+		_, err = s.DeleteConfig(ctx, models.ConfigKeyRobotsTxt)
+	} else {
+		_, err = s.SetConfig(ctx, &models.Config{
+			Key:   models.ConfigKeyRobotsTxt,
+			Value: content,
+		})
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	s.auditService.Record(ctx, operator, enum.AuditActionConfigUpdate, &AuditTarget{
+		Type:   enum.AuditTargetTypeConfig,
+		Id:     string(models.ConfigKeyRobotsTxt),
+		Before: before,
+		After:  content,
+	})
+
+	return true, nil
+}
+
+// RobotsTxt 获取 robots.txt 内容，没有设置时返回 nil
+func (s *ConfigService) RobotsTxt(ctx context.Context) (*string, error) {
+	return s.Config(ctx, models.ConfigKeyRobotsTxt)
+}
+
+// SetSMTP 设置邮件通知，密码为空时保留原来的密码
+//   - operator: 执行操作的用户
+//   - smtp: 邮件通知设置
+func (s *ConfigService) SetSMTP(ctx context.Context, operator *Operator, smtp *models.SMTPConfig) (bool, error) {
+	if err := checkSMTPConfig(smtp); err != nil {
+		return false, err
+	}
+	if smtp.Enabled {
+		// 邮件中的链接需要使用博客前端地址
+		blogInfo, err := s.BlogInfo(ctx)
+		if err != nil {
+			return false, err
+		}
+		if blogInfo == nil || util.StringIsNilOrBlank(blogInfo.SiteURL) {
+			return false, errors.New("启用邮件通知前需要在博客信息中设置博客前端地址")
+		}
+	}
+
+	before, err := s.SMTP(ctx)
+	if err != nil {
+		return false, err
+	}
+	if smtp.Password == "" && before != nil {
+		smtp.Password = before.Password
+	}
+
+	_, err = s.SetConfig(ctx, &models.Config{
+		Key:   models.ConfigKeySMTP,
+		Value: util.StringDefault(util.ToJsonString(smtp), ""),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	s.auditService.Record(ctx, operator, enum.AuditActionConfigUpdate, &AuditTarget{
+		Type:   enum.AuditTargetTypeConfig,
+		Id:     string(models.ConfigKeySMTP),
+		Before: redactSMTPConfig(before),
+		After:  redactSMTPConfig(smtp),
+	})
+
+	return true, nil
+}
+
+// SMTP 获取邮件通知设置（包含密码），没有设置时返回 nil
+func (s *ConfigService) SMTP(ctx context.Context) (*models.SMTPConfig, error) {
+	smtp := &models.SMTPConfig{}
+	config, err := s.Config(ctx, models.ConfigKeySMTP)
+	if err != nil {
+		return nil, err
+	}
+
+	if config == nil {
+		return nil, nil
+	}
+
+	if err := util.FromJsonString(config, smtp); err != nil {
+		logger.Log.Error("解析邮件通知设置失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	return smtp, nil
+}
+
+// checkSMTPConfig 检查邮件通知设置，未启用时不检查
+func checkSMTPConfig(smtp *models.SMTPConfig) error {
+	smtp.Host = strings.TrimSpace(smtp.Host)
+	smtp.From = strings.TrimSpace(smtp.From)
+	smtp.AdminEmail = strings.TrimSpace(smtp.AdminEmail)
+	if smtp.Encryption == "" {
+		smtp.Encryption = mail.EncryptionSTARTTLS
+	}
+
+	if !smtp.Enabled {
+		return nil
+	}
+	if smtp.Host == "" {
+		return errors.New("SMTP 服务器地址不能为空")
+	}
+	if smtp.Port <= 0 || smtp.Port > 65535 {
+		return errors.New("SMTP 服务器端口不正确")
+	}
+	if !slices.Contains([]string{mail.EncryptionNone, mail.EncryptionSTARTTLS, mail.EncryptionTLS}, smtp.Encryption) {
+		return errors.New("不支持的加密方式 [" + smtp.Encryption + "]")
+	}
+	if !util.StringIsEmail(smtp.From) {
+		return errors.New("发件人地址格式错误")
+	}
+	if smtp.AdminEmail != "" && !util.StringIsEmail(smtp.AdminEmail) {
+		return errors.New("接收通知的邮箱格式错误")
+	}
+	return nil
+}
+
+// redactSMTPConfig 隐藏邮件通知设置中的密码，用于返回给前端和记录审计日志
+func redactSMTPConfig(smtp *models.SMTPConfig) *models.SMTPConfig {
+	if smtp == nil {
+		return nil
+	}
+	redacted := *smtp
+	redacted.Password = ""
+	return &redacted
+}
+
+// SetCaptcha 设置评论验证码
+//   - operator: 执行操作的用户
+//   - captchaConfig: 评论验证码设置
+func (s *ConfigService) SetCaptcha(ctx context.Context, operator *Operator, captchaConfig *models.CaptchaConfig) (bool, error) {
+	if err := checkCaptchaConfig(captchaConfig); err != nil {
+		return false, err
+	}
+
+	before, err := s.Captcha(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.SetConfig(ctx, &models.Config{
+		Key:   models.ConfigKeyCaptcha,
+		Value: util.StringDefault(util.ToJsonString(captchaConfig), ""),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	s.auditService.Record(ctx, operator, enum.AuditActionConfigUpdate, &AuditTarget{
+		Type:   enum.AuditTargetTypeConfig,
+		Id:     string(models.ConfigKeyCaptcha),
+		Before: before,
+		After:  captchaConfig,
+	})
+
+	return true, nil
+}
+
+// Captcha 获取评论验证码设置，没有设置时返回未启用的默认设置
+func (s *ConfigService) Captcha(ctx context.Context) (*models.CaptchaConfig, error) {
+	captchaConfig := &models.CaptchaConfig{Type: captcha.TypeImage, Difficulty: DefaultCaptchaDifficulty}
+	config, err := s.Config(ctx, models.ConfigKeyCaptcha)
+	if err != nil {
+		return nil, err
+	}
+
+	if config == nil {
+		return captchaConfig, nil
+	}
+
+	if err := util.FromJsonString(config, captchaConfig); err != nil {
+		logger.Log.Error("解析评论验证码设置失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	return captchaConfig, nil
+}
+
+// checkCaptchaConfig 检查评论验证码设置，类型为空时使用图片验证码，难度为 0 时使用默认难度
+func checkCaptchaConfig(captchaConfig *models.CaptchaConfig) error {
+	if captchaConfig.Type == "" {
+		captchaConfig.Type = captcha.TypeImage
+	}
+	if captchaConfig.Difficulty == 0 {
+		captchaConfig.Difficulty = DefaultCaptchaDifficulty
+	}
+
+	if captchaConfig.Type != captcha.TypeImage && captchaConfig.Type != captcha.TypePoW {
+		return errors.New("不支持的验证码类型 [" + captchaConfig.Type + "]")
+	}
+	if captchaConfig.Difficulty < MinCaptchaDifficulty || captchaConfig.Difficulty > MaxCaptchaDifficulty {
+		return fmt.Errorf("工作量证明难度需要在 %d - %d 之间", MinCaptchaDifficulty, MaxCaptchaDifficulty)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/config"
+	"nola-go/internal/feed"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/util"
+	"time"
+)
+
+// defaultFeedLimit 订阅中默认的文章数量
+const defaultFeedLimit = 20
+
+// encryptedPostDescription 加密文章在订阅中的摘要
+const encryptedPostDescription = "该文章已加密，请前往博客阅读。"
+
+// FeedScope 订阅范围
+type FeedScope string
+
+const (
+	// FeedScopeAll 所有文章
+	FeedScopeAll FeedScope = "all"
+	// FeedScopeTag 指定标签的文章
+	FeedScopeTag FeedScope = "tag"
+	// FeedScopeCategory 指定分类的文章
+	FeedScopeCategory FeedScope = "category"
+)
+
+// FeedService RSS / Atom 订阅 Service
+type FeedService struct {
+	postService     *PostService
+	configService   *ConfigService
+	tagService      *TagService
+	categoryService *CategoryService
+	fullContent     bool
+	limit           int
+}
+
+// NewFeedService 创建订阅 Service
+func NewFeedService(
+	psv *PostService,
+	csv *ConfigService,
+	tsv *TagService,
+	casv *CategoryService,
+	fc config.FeedConfig,
+) *FeedService {
+	limit := fc.Limit
+	if limit <= 0 {
+		limit = defaultFeedLimit
+	}
+	return &FeedService{
+		postService:     psv,
+		configService:   csv,
+		tagService:      tsv,
+		categoryService: casv,
+		fullContent:     fc.FullContent,
+		limit:           limit,
+	}
+}
+
+// Feed 获取最新的已发布、可见的文章订阅，标签或分类不存在时返回 nil
+//
+// Parameters:
+//   - ctx: 上下文
+//   - origin: 请求的地址（如 https://example.com），博客信息中没有设置前端地址时使用
+//   - selfPath: 订阅自身的路径（如 /feed.xml）
+//   - scope: 订阅范围
+//   - slug: 标签或分类别名，订阅所有文章时忽略
+func (s *FeedService) Feed(ctx context.Context, origin string, selfPath string, scope FeedScope, slug string) (*feed.Feed, error) {
+	blogInfo, err := s.configService.BlogInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	links := newSiteLinks(blogInfo, origin)
+	title := "Nola"
+	var description, blogger string
+	if blogInfo != nil {
+		title = util.StringDefault(blogInfo.Title, title)
+		description = util.StringDefault(blogInfo.Subtitle, "")
+		blogger = util.StringDefault(blogInfo.Blogger, "")
+	}
+
+	f := &feed.Feed{
+		Title:       title,
+		Description: description,
+		Link:        links.Home(),
+		SelfLink:    links.URL(selfPath),
+		Author:      blogger,
+	}
+
+	var tagId, categoryId *uint
+	switch scope {
+	case FeedScopeTag:
+		tag, err := s.tagService.TagBySlug(ctx, slug)
+		if err != nil || tag == nil {
+			return nil, err
+		}
+		tagId = &tag.TagId
+		f.Title = title + " - " + tag.DisplayName
+		f.Link = links.Tag(tag.Slug)
+	case FeedScopeCategory:
+		category, err := s.categoryService.CategoryBySlug(ctx, slug)
+		if err != nil || category == nil {
+			return nil, err
+		}
+		categoryId = &category.CategoryId
+		f.Title = title + " - " + category.DisplayName
+		f.Link = links.Category(category.Slug)
+	}
+	if f.Description == "" {
+		f.Description = f.Title
+	}
+
+	// 订阅中按发布时间降序，不优先置顶文章，避免较早的置顶文章挤掉最新的文章
+	posts, err := s.postService.LatestApiPosts(ctx, s.limit, tagId, categoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, post := range posts {
+		item, err := s.feedItem(ctx, links, post)
+		if err != nil {
+			return nil, err
+		}
+		if item.Updated.After(f.Updated) {
+			f.Updated = item.Updated
+		}
+		f.Items = append(f.Items, item)
+	}
+	if f.Updated.IsZero() {
+		// 没有文章时使用当前时间
+		f.Updated = time.Now().Truncate(time.Second)
+	}
+	return f, nil
+}
+
+// feedItem 将文章转为订阅中的文章
+func (s *FeedService) feedItem(ctx context.Context, links *siteLinks, post *response.PostApiResponse) (*feed.Item, error) {
+	item := &feed.Item{
+		Title:     post.Title,
+		Link:      links.Post(post.Slug),
+		Published: time.UnixMilli(post.CreateTime),
+		Updated:   time.UnixMilli(post.CreateTime),
+	}
+	if post.LastModifyTime != nil && *post.LastModifyTime > post.CreateTime {
+		item.Updated = time.UnixMilli(*post.LastModifyTime)
+	}
+	if post.Author != nil {
+		item.Author = post.Author.DisplayName
+	}
+	if post.Category != nil {
+		item.Categories = append(item.Categories, post.Category.DisplayName)
+	}
+	for _, tag := range post.Tags {
+		item.Categories = append(item.Categories, tag.DisplayName)
+	}
+
+	if post.Encrypted {
+		// 加密文章不输出摘要和内容
+		item.Description = encryptedPostDescription
+		return item, nil
+	}
+
+	item.Description = util.StringDefault(post.Excerpt, "")
+	if s.fullContent {
+		content, err := s.postService.PostContent(ctx, post.PostId, enum.PostContentStatusPublished, nil)
+		if err != nil {
+			return nil, err
+		}
+		if content != nil {
+			item.Content = content.HTML
+		}
+	}
+	return item, nil
+}
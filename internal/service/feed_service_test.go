@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/config"
+	"nola-go/internal/models"
+	"nola-go/internal/repository"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+	"time"
+)
+
+func TestFeedService_Feed(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	f := testutil.NewFixture(t, database)
+	postService := newTestPostService(t, database)
+	configService := NewConfigService(repository.NewConfigRepository(database), newTestAuditService(database))
+	newFeedService := func(fc config.FeedConfig) *FeedService {
+		return NewFeedService(postService, configService,
+			NewTagService(repository.NewTagRepository(database)),
+			NewCategoryService(repository.NewCategoryRepository(database)),
+			fc,
+		)
+	}
+
+	if _, err := configService.SetBlogInfo(ctx, SystemOperator, &models.BlogInfo{
+		Title:          util.StringPtr("Nola"),
+		SiteURL:        util.StringPtr("https://blog.example.com/"),
+		PostURLPattern: util.StringPtr("/p/{slug}"),
+	}); err != nil {
+		t.Fatalf("SetBlogInfo: %v", err)
+	}
+
+	gin := f.Tag("gin")
+	golang := f.Category("golang")
+	first := f.Post("first", "# 第一篇", gin)
+	f.PostCategory(first, golang)
+	pinned := f.Post("pinned", "置顶")
+	database.Model(pinned).Updates(map[string]any{"pinned": true, "excerpt": "置顶摘要"})
+	encrypted := f.Post("encrypted", "加密内容")
+	database.Model(encrypted).Update("password", "hash")
+	latest := f.Post("latest", "最新")
+	database.Model(latest).Update("last_modify_time", f.Now())
+
+	s := newFeedService(config.FeedConfig{FullContent: true})
+	feed, err := s.Feed(ctx, "http://ignored", "/feed.xml", FeedScopeAll, "")
+	if err != nil {
+		t.Fatalf("Feed: %v", err)
+	}
+	if feed.Title != "Nola" || feed.Link != "https://blog.example.com/" || feed.SelfLink != "https://blog.example.com/feed.xml" {
+		t.Errorf("feed = %+v", feed)
+	}
+
+	// 按发布时间降序，不受置顶影响
+	var links []string
+	for _, item := range feed.Items {
+		links = append(links, item.Link)
+	}
+	want := []string{
+		"https://blog.example.com/p/latest",
+		"https://blog.example.com/p/encrypted",
+		"https://blog.example.com/p/pinned",
+		"https://blog.example.com/p/first",
+	}
+	if len(links) != len(want) {
+		t.Fatalf("links = %v", links)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Fatalf("links = %v, want %v", links, want)
+		}
+	}
+
+	if !feed.Updated.Equal(feed.Items[0].Updated) || feed.Items[0].Updated.Equal(feed.Items[0].Published) {
+		t.Errorf("updated = %v, latest item = %+v", feed.Updated, feed.Items[0])
+	}
+	if item := feed.Items[1]; item.Content != "" || item.Description != encryptedPostDescription {
+		t.Errorf("encrypted item = %+v", item)
+	}
+	if item := feed.Items[3]; item.Content != "<h1>第一篇</h1>\n" || len(item.Categories) != 2 {
+		t.Errorf("first item = %+v", item)
+	}
+
+	// 只输出摘要
+	feed, _ = newFeedService(config.FeedConfig{Limit: 1}).Feed(ctx, "", "/feed.xml", FeedScopeAll, "")
+	if len(feed.Items) != 1 || feed.Items[0].Content != "" {
+		t.Errorf("excerpt feed = %+v", feed.Items)
+	}
+
+	// 标签、分类订阅
+	feed, err = s.Feed(ctx, "", "/feed/tag/gin", FeedScopeTag, "gin")
+	if err != nil || len(feed.Items) != 1 || feed.Title != "Nola - gin" || feed.Link != "https://blog.example.com/tag/gin" {
+		t.Errorf("tag feed = %+v, %v", feed, err)
+	}
+	feed, err = s.Feed(ctx, "", "/feed/category/golang", FeedScopeCategory, "golang")
+	if err != nil || len(feed.Items) != 1 || feed.Items[0].Title != "first" {
+		t.Errorf("category feed = %+v, %v", feed, err)
+	}
+	if feed, err := s.Feed(ctx, "", "/feed/tag/missing", FeedScopeTag, "missing"); err != nil || feed != nil {
+		t.Errorf("missing tag feed = %+v, %v", feed, err)
+	}
+
+	// 没有文章时使用当前时间作为更新时间
+	empty := f.Tag("empty")
+	feed, err = s.Feed(ctx, "", "/feed/tag/empty", FeedScopeTag, empty.Slug)
+	if err != nil || len(feed.Items) != 0 || time.Since(feed.Updated) > time.Minute {
+		t.Errorf("empty feed = %+v, %v", feed, err)
+	}
+}
+
+func TestFeedService_FeedLimit(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	f := testutil.NewFixture(t, database)
+	s := NewFeedService(newTestPostService(t, database),
+		NewConfigService(repository.NewConfigRepository(database), newTestAuditService(database)),
+		NewTagService(repository.NewTagRepository(database)),
+		NewCategoryService(repository.NewCategoryRepository(database)),
+		config.FeedConfig{Limit: 2},
+	)
+
+	// 较早的置顶文章不占用订阅中的文章数量
+	pinned := f.Post("pinned", "置顶")
+	database.Model(pinned).Update("pinned", true)
+	f.Post("second", "第二篇")
+	f.Post("third", "第三篇")
+
+	feed, err := s.Feed(ctx, "https://example.com", "/feed.xml", FeedScopeAll, "")
+	if err != nil || len(feed.Items) != 2 || feed.Items[0].Title != "third" || feed.Items[1].Title != "second" {
+		t.Errorf("feed = %+v, %v", feed, err)
+	}
+}
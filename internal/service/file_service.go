@@ -16,6 +16,7 @@ import (
 	"nola-go/internal/util"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"time"
 
 	"go.uber.org/zap"
@@ -30,11 +31,14 @@ type FileService struct {
 	tencentCOS file.Option
 	// 腾讯云对象存储配置
 	tencentConfig *config.TencentCosConfig
+
+	auditService *AuditService
 }
 
-func NewFileService(fileRepo repository.FileRepository) *FileService {
+func NewFileService(fileRepo repository.FileRepository, auditService *AuditService) *FileService {
 	return &FileService{
 		fileRepo:      fileRepo,
+		auditService:  auditService,
 		localStorage:  file.LocalFileStorageImpl{},
 		tencentCOS:    nil,
 		tencentConfig: nil,
@@ -560,10 +564,11 @@ func (s *FileService) UploadFileRecord(ctx context.Context, record request.FileR
 }
 
 // DeleteFiles 根据文件 ID 数组删除文件
+//   - operator: 执行删除操作的用户
 //   - ids: 文件 ID 数组
 //
 // Returns: 删除成功的文件 ID 数组
-func (s *FileService) DeleteFiles(ctx context.Context, ids []uint) ([]uint, error) {
+func (s *FileService) DeleteFiles(ctx context.Context, operator *Operator, ids []uint) ([]uint, error) {
 	if len(ids) == 0 {
 		return []uint{}, nil
 	}
@@ -595,15 +600,23 @@ func (s *FileService) DeleteFiles(ctx context.Context, ids []uint) ([]uint, erro
 		return nil, err
 	}
 
+	deletedIds := util.Map(deleteResult, func(f *models.FileIndex) uint {
+		return *f.FileId
+	})
+	deletedFiles := util.Filter(files, func(f *models.FileWithGroup) bool {
+		return slices.Contains(deletedIds, f.FileId)
+	})
+	s.auditService.Record(ctx, operator, enum.AuditActionFileDelete, util.Map(deletedFiles, func(f *models.FileWithGroup) *AuditTarget {
+		return &AuditTarget{Type: enum.AuditTargetTypeFile, Id: strconv.FormatUint(uint64(f.FileId), 10), Before: f}
+	})...)
+
 	if len(deleteResult) == len(ids) {
 		// 成功删除的文件数量和要删除的文件数量相同，返回要删除的文件 ID 数组
 		return ids, nil
 	}
 
 	// 返回删除成功的文件 ID
-	return util.Map(deleteResult, func(f *models.FileIndex) uint {
-		return *f.FileId
-	}), nil
+	return deletedIds, nil
 }
 
 // DeleteFilesByFileIndexes 根据文件索引删除文件
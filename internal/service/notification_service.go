@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"nola-go/internal/config"
+	"nola-go/internal/logger"
+	"nola-go/internal/mail"
+	"nola-go/internal/models"
+	"nola-go/internal/models/response"
+	"nola-go/internal/repository"
+	"nola-go/internal/util"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sendTimeout 后台发送一封邮件的最长时间
+const sendTimeout = time.Minute
+
+// NotificationService 邮件通知 Service
+// 新评论通知管理员，评论（回复）通过审核后通知被回复的评论人，邮件在后台发送，不影响评论的提交
+type NotificationService struct {
+	configService   *ConfigService
+	postRepo        repository.PostRepository
+	commentRepo     repository.CommentRepository
+	unsubscribeRepo repository.MailUnsubscribeRepository
+	mailConfig      config.MailConfig
+	// secret 生成退订链接签名的密钥
+	secret []byte
+	// wg 正在后台发送的邮件
+	wg sync.WaitGroup
+}
+
+// NewNotificationService 创建邮件通知 Service
+//   - mailConfig: 邮件发送配置
+//   - secret: 生成退订链接签名的密钥
+func NewNotificationService(
+	configService *ConfigService,
+	postRepo repository.PostRepository,
+	commentRepo repository.CommentRepository,
+	unsubscribeRepo repository.MailUnsubscribeRepository,
+	mailConfig config.MailConfig,
+	secret string,
+) *NotificationService {
+	return &NotificationService{
+		configService:   configService,
+		postRepo:        postRepo,
+		commentRepo:     commentRepo,
+		unsubscribeRepo: unsubscribeRepo,
+		mailConfig:      mailConfig,
+		secret:          []byte(secret),
+	}
+}
+
+// notifyContext 发送通知需要的设置
+type notifyContext struct {
+	smtp      *models.SMTPConfig
+	blogTitle string
+	links     *siteLinks
+}
+
+// NotifyNewComment 通知管理员有新评论（博客前端访客提交的评论）
+// 没有启用邮件通知或没有设置接收通知的邮箱时不发送，发送失败只记录日志
+func (s *NotificationService) NotifyNewComment(ctx context.Context, comment *models.Comment) {
+	nc := s.notifyContext(ctx)
+	if nc == nil || nc.smtp.AdminEmail == "" {
+		return
+	}
+
+	post, err := s.postRepo.PostById(ctx, comment.PostId, false)
+	if err != nil || post == nil {
+		logger.Log.Error(fmt.Sprintf("发送新评论通知时获取文章 [%d] 失败", comment.PostId), zap.Error(err))
+		return
+	}
+
+	content, err := mail.Render("comment_new", map[string]any{
+		"BlogTitle":   nc.blogTitle,
+		"Pending":     !comment.IsPass,
+		"PostTitle":   post.Title,
+		"DisplayName": comment.DisplayName,
+		"Email":       comment.Email,
+		"Content":     comment.Content,
+		"PostURL":     nc.links.Post(post.Slug),
+	})
+	if err != nil {
+		logger.Log.Error("生成新评论通知邮件失败", zap.Error(err))
+		return
+	}
+
+	s.sendAsync(nc, nc.smtp.AdminEmail, content, nil)
+}
+
+// NotifyReply 通知被回复的评论人（评论通过审核后）
+// 被回复的评论未通过审核、与回复的邮箱相同或邮箱已退订时不发送，发送失败只记录日志
+func (s *NotificationService) NotifyReply(ctx context.Context, comment *models.Comment) {
+	if !comment.IsPass {
+		return
+	}
+
+	// 回复的评论为空时，当前评论回复的是父评论
+	targetId := comment.ReplyCommentId
+	if targetId == nil {
+		targetId = comment.ParentCommentId
+	}
+	if targetId == nil {
+		return
+	}
+
+	nc := s.notifyContext(ctx)
+	if nc == nil {
+		return
+	}
+
+	target, err := s.commentRepo.CommentById(ctx, *targetId)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("发送回复通知时获取评论 [%d] 失败", *targetId), zap.Error(err))
+		return
+	}
+	if target == nil || !target.IsPass || strings.EqualFold(target.Email, comment.Email) {
+		return
+	}
+
+	unsubscribed, err := s.unsubscribeRepo.IsUnsubscribed(ctx, target.Email)
+	if err != nil {
+		logger.Log.Error("获取邮件退订状态失败", zap.Error(err))
+		return
+	}
+	if unsubscribed {
+		return
+	}
+
+	post, err := s.postRepo.PostById(ctx, comment.PostId, false)
+	if err != nil || post == nil {
+		logger.Log.Error(fmt.Sprintf("发送回复通知时获取文章 [%d] 失败", comment.PostId), zap.Error(err))
+		return
+	}
+
+	unsubscribeURL := nc.links.URL("/api/mail/unsubscribe?" + url.Values{
+		"email": {target.Email},
+		"token": {s.UnsubscribeToken(target.Email)},
+	}.Encode())
+	content, err := mail.Render("comment_reply", map[string]any{
+		"BlogTitle":      nc.blogTitle,
+		"DisplayName":    comment.DisplayName,
+		"PostTitle":      post.Title,
+		"ReplyToName":    target.DisplayName,
+		"ReplyToContent": target.Content,
+		"Content":        comment.Content,
+		"PostURL":        nc.links.Post(post.Slug),
+		"UnsubscribeURL": unsubscribeURL,
+	})
+	if err != nil {
+		logger.Log.Error("生成回复通知邮件失败", zap.Error(err))
+		return
+	}
+
+	s.sendAsync(nc, target.Email, content, map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	})
+}
+
+// SendTestMail 使用已保存的邮件设置发送测试邮件（未启用邮件通知时也可以发送）
+//   - to: 收件人
+func (s *NotificationService) SendTestMail(ctx context.Context, to string) error {
+	if !util.StringIsEmail(to) {
+		return errors.New("收件人邮箱格式错误")
+	}
+
+	smtp, err := s.configService.SMTP(ctx)
+	if err != nil {
+		return err
+	}
+	if smtp == nil {
+		return errors.New("请先保存邮件设置")
+	}
+	blogInfo, err := s.configService.BlogInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	nc := &notifyContext{smtp: smtp, blogTitle: blogTitle(blogInfo)}
+	content, err := mail.Render("test", map[string]any{"BlogTitle": nc.blogTitle})
+	if err != nil {
+		logger.Log.Error("生成测试邮件失败", zap.Error(err))
+		return response.ServerError
+	}
+
+	if err := s.mailer(smtp).Send(ctx, s.message(nc, to, content, nil)); err != nil {
+		logger.Log.Warn("发送测试邮件失败", zap.Error(err))
+		return errors.New("发送测试邮件失败: " + err.Error())
+	}
+	return nil
+}
+
+// UnsubscribeToken 生成退订链接中的签名
+func (s *NotificationService) UnsubscribeToken(email string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte("mail-unsubscribe:" + strings.ToLower(email)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CheckUnsubscribeToken 检查退订链接中的签名是否正确
+//   - email: 邮箱
+//   - token: 退订链接中的签名
+func (s *NotificationService) CheckUnsubscribeToken(email string, token string) bool {
+	return email != "" && hmac.Equal([]byte(token), []byte(s.UnsubscribeToken(email)))
+}
+
+// Unsubscribe 退订邮件通知
+//   - email: 邮箱
+//   - token: 退订链接中的签名
+//
+// Returns:
+//   - bool: 签名是否正确
+func (s *NotificationService) Unsubscribe(ctx context.Context, email string, token string) (bool, error) {
+	if !s.CheckUnsubscribeToken(email, token) {
+		return false, nil
+	}
+
+	if err := s.unsubscribeRepo.AddUnsubscribe(ctx, email); err != nil {
+		logger.Log.Error("退订邮件通知失败", zap.Error(err))
+		return false, response.ServerError
+	}
+	return true, nil
+}
+
+// Wait 等待后台发送的邮件全部完成
+func (s *NotificationService) Wait() {
+	s.wg.Wait()
+}
+
+// notifyContext 获取发送通知需要的设置，没有启用邮件通知或没有设置博客前端地址时返回 nil
+func (s *NotificationService) notifyContext(ctx context.Context) *notifyContext {
+	smtp, err := s.configService.SMTP(ctx)
+	if err != nil || smtp == nil || !smtp.Enabled {
+		return nil
+	}
+
+	blogInfo, err := s.configService.BlogInfo(ctx)
+	if err != nil {
+		return nil
+	}
+	if blogInfo == nil || util.StringIsNilOrBlank(blogInfo.SiteURL) {
+		// 没有前端地址时无法生成邮件中的链接
+		logger.Log.Warn("没有设置博客前端地址，不发送邮件通知")
+		return nil
+	}
+
+	return &notifyContext{
+		smtp:      smtp,
+		blogTitle: blogTitle(blogInfo),
+		links:     newSiteLinks(blogInfo, ""),
+	}
+}
+
+// sendAsync 在后台发送邮件，发送失败只记录日志
+func (s *NotificationService) sendAsync(nc *notifyContext, to string, content *mail.Content, headers map[string]string) {
+	mailer := s.mailer(nc.smtp)
+	msg := s.message(nc, to, content, headers)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+		if err := mailer.Send(ctx, msg); err != nil {
+			logger.Log.Warn("发送邮件失败", zap.String("to", to), zap.String("subject", msg.Subject), zap.Error(err))
+		}
+	}()
+}
+
+// message 生成邮件，发件人名称为空时使用博客标题
+func (s *NotificationService) message(nc *notifyContext, to string, content *mail.Content, headers map[string]string) *mail.Message {
+	fromName := nc.smtp.FromName
+	if fromName == "" {
+		fromName = nc.blogTitle
+	}
+	return &mail.Message{
+		From:     nc.smtp.From,
+		FromName: fromName,
+		To:       []string{to},
+		Subject:  content.Subject,
+		Text:     content.Text,
+		HTML:     content.HTML,
+		Headers:  headers,
+	}
+}
+
+// mailer 根据配置文件中的发送方式创建邮件发送器
+func (s *NotificationService) mailer(smtp *models.SMTPConfig) mail.Mailer {
+	switch s.mailConfig.Driver {
+	case mail.DriverFile:
+		dir := s.mailConfig.Dir
+		if dir == "" {
+			dir = "data/mail"
+		}
+		return mail.NewFileMailer(dir)
+	case mail.DriverLog:
+		return mail.NewLogMailer()
+	default:
+		return mail.NewSMTPMailer(mail.SMTPSettings{
+			Host:       smtp.Host,
+			Port:       smtp.Port,
+			Username:   smtp.Username,
+			Password:   smtp.Password,
+			Encryption: smtp.Encryption,
+		})
+	}
+}
+
+// blogTitle 博客标题，没有设置时使用 Nola
+func blogTitle(blogInfo *models.BlogInfo) string {
+	if blogInfo == nil {
+		return "Nola"
+	}
+	return util.StringDefault(blogInfo.Title, "Nola")
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"mime"
+	netmail "net/mail"
+	"net/url"
+	"nola-go/internal/config"
+	"nola-go/internal/mail"
+	"nola-go/internal/models"
+	"nola-go/internal/repository"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// sentMail 写入文件的邮件
+type sentMail struct {
+	To          string
+	Subject     string
+	Unsubscribe string
+}
+
+// readSentMails 读取并清空目录中的邮件
+func readSentMails(t *testing.T, dir string) []sentMail {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("ReadDir: %v", err)
+	}
+
+	var ret []sentMail
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		m, err := netmail.ReadMessage(strings.NewReader(string(raw)))
+		if err != nil {
+			t.Fatalf("ReadMessage: %v", err)
+		}
+		subject, _ := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+		ret = append(ret, sentMail{To: m.Header.Get("To"), Subject: subject, Unsubscribe: m.Header.Get("List-Unsubscribe")})
+		_ = os.Remove(path)
+	}
+	return ret
+}
+
+// newTestNotificationService 创建写入文件的邮件通知 Service，并启用邮件通知
+//   - adminEmail: 接收新评论通知的邮箱
+//
+// Returns:
+//   - 邮件保存的目录
+func newTestNotificationService(t *testing.T, database *gorm.DB, adminEmail string) (*NotificationService, string) {
+	t.Helper()
+	ctx := context.Background()
+	configService := NewConfigService(repository.NewConfigRepository(database), newTestAuditService(database))
+	if _, err := configService.SetBlogInfo(ctx, SystemOperator, &models.BlogInfo{
+		Title:   util.StringPtr("我的博客"),
+		SiteURL: util.StringPtr("https://blog.example.com"),
+	}); err != nil {
+		t.Fatalf("SetBlogInfo: %v", err)
+	}
+	if _, err := configService.SetSMTP(ctx, SystemOperator, &models.SMTPConfig{
+		Enabled:    true,
+		Host:       "smtp.example.com",
+		Port:       587,
+		From:       "blog@example.com",
+		AdminEmail: adminEmail,
+	}); err != nil {
+		t.Fatalf("SetSMTP: %v", err)
+	}
+
+	dir := t.TempDir()
+	s := NewNotificationService(
+		configService,
+		newTestPostService(t, database).postRepo,
+		repository.NewCommentRepository(database),
+		repository.NewMailUnsubscribeRepository(database),
+		config.MailConfig{Driver: mail.DriverFile, Dir: dir},
+		"secret",
+	)
+	// 临时目录删除前等待后台发送的邮件写入完成
+	t.Cleanup(s.Wait)
+	return s, dir
+}
+
+func TestNotificationService_Comment(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	f := testutil.NewFixture(t, database)
+	notificationService, dir := newTestNotificationService(t, database, "admin@example.com")
+	commentService := NewCommentService(repository.NewCommentRepository(database), notificationService.postRepo, newTestAuditService(database), notificationService, nil,
+		NewCommenterService(repository.NewCommenterRepository(database)), config.CommentConfig{})
+	sent := func() []sentMail {
+		t.Helper()
+		notificationService.Wait()
+		return readSentMails(t, dir)
+	}
+
+	post := f.Post("hello", "正文")
+	root := f.Comment(post, nil, "reader@example.com", true)
+
+	// 访客的新评论通知管理员，评论未审核，不通知被回复的人
+	visitor, err := commentService.AddComment(ctx, models.Comment{
+		PostId: post.PostId, ParentCommentId: &root.CommentId, Content: "访客回复", DisplayName: "访客", Email: "visitor@example.com",
+	}, true, nil)
+	if err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	mails := sent()
+	if len(mails) != 1 || mails[0].To != "admin@example.com" || mails[0].Subject != "[我的博客] 新评论待审核：hello" {
+		t.Fatalf("new comment mails = %+v", mails)
+	}
+
+	// 审核通过后通知被回复的人，再次设置通过不重复通知
+	if _, err := commentService.SetCommentPass(ctx, []uint{visitor.CommentId}, true); err != nil {
+		t.Fatalf("SetCommentPass: %v", err)
+	}
+	mails = sent()
+	if len(mails) != 1 || mails[0].To != "reader@example.com" || mails[0].Subject != "[我的博客] 访客 回复了你在《hello》中的评论" {
+		t.Fatalf("reply mails = %+v", mails)
+	}
+	if _, err := commentService.SetCommentPass(ctx, []uint{visitor.CommentId}, true); err != nil {
+		t.Fatalf("SetCommentPass: %v", err)
+	}
+	if mails := sent(); len(mails) != 0 {
+		t.Errorf("repeated pass mails = %+v", mails)
+	}
+
+	// 管理员的回复直接通过审核，不通知管理员
+	if _, err := commentService.AddComment(ctx, models.Comment{
+		PostId: post.PostId, ParentCommentId: &root.CommentId, ReplyCommentId: &visitor.CommentId,
+		Content: "博主回复", DisplayName: "博主", Email: "admin@example.com", IsPass: true,
+	}, false, nil); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	mails = sent()
+	if len(mails) != 1 || mails[0].To != "visitor@example.com" {
+		t.Fatalf("admin reply mails = %+v", mails)
+	}
+
+	// 退订链接
+	u, err := url.Parse(strings.Trim(mails[0].Unsubscribe, "<>"))
+	if err != nil || u.Host != "blog.example.com" || u.Path != "/api/mail/unsubscribe" || u.Query().Get("email") != "visitor@example.com" {
+		t.Fatalf("List-Unsubscribe = %q", mails[0].Unsubscribe)
+	}
+	if !notificationService.CheckUnsubscribeToken("visitor@example.com", u.Query().Get("token")) ||
+		notificationService.CheckUnsubscribeToken("visitor@example.com", "bad") || notificationService.CheckUnsubscribeToken("", "") {
+		t.Error("CheckUnsubscribeToken mismatch")
+	}
+	if ok, err := notificationService.Unsubscribe(ctx, "visitor@example.com", "bad"); ok || err != nil {
+		t.Errorf("Unsubscribe(bad token) = %v, %v", ok, err)
+	}
+	if ok, err := notificationService.Unsubscribe(ctx, "other@example.com", u.Query().Get("token")); ok || err != nil {
+		t.Errorf("Unsubscribe(other email) = %v, %v", ok, err)
+	}
+	if ok, err := notificationService.Unsubscribe(ctx, "Visitor@Example.com", u.Query().Get("token")); !ok || err != nil {
+		t.Fatalf("Unsubscribe = %v, %v", ok, err)
+	}
+
+	// 已退订、回复自己的评论不通知
+	for _, comment := range []models.Comment{
+		{PostId: post.PostId, ParentCommentId: &root.CommentId, ReplyCommentId: &visitor.CommentId, Content: "再次回复", DisplayName: "博主", Email: "admin@example.com", IsPass: true},
+		{PostId: post.PostId, ParentCommentId: &root.CommentId, Content: "补充", DisplayName: "读者", Email: "READER@example.com", IsPass: true},
+	} {
+		if _, err := commentService.AddComment(ctx, comment, false, nil); err != nil {
+			t.Fatalf("AddComment: %v", err)
+		}
+	}
+	if mails := sent(); len(mails) != 0 {
+		t.Errorf("unsubscribed / self reply mails = %+v", mails)
+	}
+
+	// 修改评论为通过审核时通知
+	pending := f.Comment(post, root, "pending@example.com", false)
+	pending.IsPass = true
+	if _, err := commentService.UpdateComment(ctx, *pending); err != nil {
+		t.Fatalf("UpdateComment: %v", err)
+	}
+	if mails := sent(); len(mails) != 1 || mails[0].To != "reader@example.com" {
+		t.Errorf("update pass mails = %+v", mails)
+	}
+}
+
+func TestNotificationService_Disabled(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	f := testutil.NewFixture(t, database)
+	s, dir := newTestNotificationService(t, database, "")
+
+	post := f.Post("hello", "正文")
+	root := f.Comment(post, nil, "reader@example.com", true)
+	reply := f.Comment(post, root, "visitor@example.com", true)
+
+	// 没有设置接收通知的邮箱
+	s.NotifyNewComment(ctx, reply)
+	s.Wait()
+	if mails := readSentMails(t, dir); len(mails) != 0 {
+		t.Errorf("no admin email mails = %+v", mails)
+	}
+
+	// 关闭邮件通知
+	smtp, _ := s.configService.SMTP(ctx)
+	smtp.Enabled = false
+	if _, err := s.configService.SetSMTP(ctx, SystemOperator, smtp); err != nil {
+		t.Fatalf("SetSMTP: %v", err)
+	}
+	s.NotifyReply(ctx, reply)
+	s.Wait()
+	if mails := readSentMails(t, dir); len(mails) != 0 {
+		t.Errorf("disabled mails = %+v", mails)
+	}
+
+	// 测试邮件不需要启用邮件通知
+	if err := s.SendTestMail(ctx, "not an email"); err == nil {
+		t.Error("SendTestMail(invalid) should fail")
+	}
+	if err := s.SendTestMail(ctx, "admin@example.com"); err != nil {
+		t.Fatalf("SendTestMail: %v", err)
+	}
+	if mails := readSentMails(t, dir); len(mails) != 1 || mails[0].Subject != "[我的博客] 测试邮件" {
+		t.Errorf("test mails = %+v", mails)
+	}
+}
+
+func TestConfigService_SetSMTP(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	s := NewConfigService(repository.NewConfigRepository(database), newTestAuditService(database))
+	valid := func() *models.SMTPConfig {
+		return &models.SMTPConfig{Enabled: true, Host: " smtp.example.com ", Port: 465, Username: "u", Password: "p", Encryption: mail.EncryptionTLS, From: "blog@example.com"}
+	}
+
+	// 没有设置博客前端地址时不能启用
+	if _, err := s.SetSMTP(ctx, SystemOperator, valid()); err == nil {
+		t.Error("SetSMTP without site url should fail")
+	}
+	if _, err := s.SetBlogInfo(ctx, SystemOperator, &models.BlogInfo{SiteURL: util.StringPtr("https://blog.example.com")}); err != nil {
+		t.Fatalf("SetBlogInfo: %v", err)
+	}
+
+	invalid := []func(*models.SMTPConfig){
+		func(c *models.SMTPConfig) { c.Host = " " },
+		func(c *models.SMTPConfig) { c.Port = 0 },
+		func(c *models.SMTPConfig) { c.Port = 70000 },
+		func(c *models.SMTPConfig) { c.Encryption = "ssl" },
+		func(c *models.SMTPConfig) { c.From = "blog" },
+		func(c *models.SMTPConfig) { c.AdminEmail = "admin" },
+	}
+	for i, modify := range invalid {
+		c := valid()
+		modify(c)
+		if _, err := s.SetSMTP(ctx, SystemOperator, c); err == nil {
+			t.Errorf("invalid[%d] should fail", i)
+		}
+	}
+
+	if _, err := s.SetSMTP(ctx, SystemOperator, valid()); err != nil {
+		t.Fatalf("SetSMTP: %v", err)
+	}
+
+	// 密码为空时保留原来的密码，加密方式默认 STARTTLS
+	c := valid()
+	c.Password = ""
+	c.Encryption = ""
+	if _, err := s.SetSMTP(ctx, SystemOperator, c); err != nil {
+		t.Fatalf("SetSMTP: %v", err)
+	}
+	got, err := s.SMTP(ctx)
+	if err != nil || got.Password != "p" || got.Host != "smtp.example.com" || got.Encryption != mail.EncryptionSTARTTLS {
+		t.Errorf("SMTP = %+v, %v", got, err)
+	}
+
+	// 审计日志中不记录密码
+	var logs []string
+	database.Table("audit_log").Where("target_id = ?", models.ConfigKeySMTP).Pluck("diff", &logs)
+	if len(logs) != 2 {
+		t.Errorf("audit logs = %q", logs)
+	}
+	for _, log := range logs {
+		if strings.Contains(log, `"p"`) {
+			t.Errorf("audit log contains password: %s", log)
+		}
+	}
+}
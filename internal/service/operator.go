@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+	"nola-go/internal/models/enum"
+)
+
+// ErrPermissionDenied 当前用户没有权限执行此操作
+var ErrPermissionDenied = errors.New("没有权限执行此操作")
+
+// SystemOperator 后台定时任务等系统操作使用的操作用户，拥有所有者的全部权限
+// 用户 ID 为 0，审计日志中没有操作用户 ID，只记录名称
+var SystemOperator = &Operator{Username: "system", Role: enum.UserRoleOwner}
+
+// Operator 执行操作的登录用户
+type Operator struct {
+	// UserId 用户 ID
+	UserId uint
+	// Username 用户名
+	Username string
+	// Role 用户角色
+	Role enum.UserRole
+	// Scopes 使用 API 令牌访问时令牌的权限范围，使用登录会话访问时为空
+	Scopes []enum.ApiTokenScope
+	// Ip 客户端 IP 地址
+	Ip string
+}
+
+// Can 判断操作用户是否拥有指定权限，使用 API 令牌访问时还需要令牌的权限范围包含该权限
+func (o *Operator) Can(p enum.Permission) bool {
+	if !o.Role.Can(p) {
+		return false
+	}
+	if o.Scopes == nil {
+		return true
+	}
+
+	for _, scope := range o.Scopes {
+		if scope.Allows(p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nola-go/internal/logger"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/repository"
+	"nola-go/internal/util"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// revisionDiffContext 统一差异格式中每处修改前后保留的行数
+const revisionDiffContext = 3
+
+// PostRevisionService 文章修订版本 Service
+type PostRevisionService struct {
+	revisionRepo repository.PostRevisionRepository
+	userRepo     repository.UserRepository
+}
+
+// NewPostRevisionService 创建文章修订版本 Service
+func NewPostRevisionService(revisionRepo repository.PostRevisionRepository, userRepo repository.UserRepository) *PostRevisionService {
+	return &PostRevisionService{
+		revisionRepo: revisionRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// Record 记录文章内容的修订版本，内容与该正文（或同名草稿）最新的版本相同时不记录
+// 记录失败只输出日志，不影响已经保存的内容
+//   - ctx: 上下文
+//   - operator: 保存内容的用户
+//   - postId: 文章 ID
+//   - status: 文章内容状态
+//   - draftName: 草稿名，正文忽略该参数
+//   - content: 内容
+func (s *PostRevisionService) Record(
+	ctx context.Context,
+	operator *Operator,
+	postId uint,
+	status enum.PostContentStatus,
+	draftName *string,
+	content string,
+) {
+	if status == enum.PostContentStatusPublished {
+		// 正文可能被草稿转换后带有名称，修订历史中正文统一不记录名称
+		draftName = nil
+	}
+
+	latest, err := s.revisionRepo.Latest(ctx, postId, status, draftName)
+	if err != nil {
+		logger.Log.Error("获取文章最新修订版本失败", zap.Uint("postId", postId), zap.Error(err))
+		return
+	}
+	if latest != nil && latest.Content == content {
+		return
+	}
+
+	revision := &models.PostRevision{
+		PostId:     postId,
+		Status:     status,
+		DraftName:  draftName,
+		Content:    content,
+		WordCount:  util.StringWordCount(content),
+		CreateTime: time.Now().UnixMilli(),
+	}
+	if operator != nil && operator.UserId != 0 {
+		revision.AuthorId = util.UintPrt(operator.UserId)
+	}
+
+	if err := s.revisionRepo.Create(ctx, revision); err != nil {
+		logger.Log.Error("添加文章修订版本失败", zap.Uint("postId", postId), zap.Error(err))
+	}
+}
+
+// Revisions 获取文章所有修订版本（按保存时间降序）
+func (s *PostRevisionService) Revisions(ctx context.Context, postId uint) ([]*response.PostRevisionResponse, error) {
+	revisions, err := s.revisionRepo.GetByPostId(ctx, postId)
+	if err != nil {
+		logger.Log.Error("获取文章修订版本失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	ret := util.Map(revisions, response.NewPostRevisionResponse)
+	if err := s.fillAuthor(ctx, ret...); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Revision 获取文章的修订版本（包含内容），版本不属于该文章时返回错误
+//   - postId: 文章 ID
+//   - revisionId: 修订版本 ID
+func (s *PostRevisionService) Revision(ctx context.Context, postId uint, revisionId uint) (*models.PostRevision, error) {
+	revision, err := s.revisionRepo.GetById(ctx, revisionId)
+	if err != nil {
+		logger.Log.Error("获取文章修订版本失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	if revision == nil || revision.PostId != postId {
+		return nil, errors.New(fmt.Sprintf("修订版本 [%d] 不存在", revisionId))
+	}
+	return revision, nil
+}
+
+// Diff 比较文章的两个修订版本
+//   - postId: 文章 ID
+//   - fromId: 旧修订版本 ID
+//   - toId: 新修订版本 ID
+func (s *PostRevisionService) Diff(ctx context.Context, postId uint, fromId uint, toId uint) (*response.PostRevisionDiffResponse, error) {
+	from, err := s.Revision(ctx, postId, fromId)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.Revision(ctx, postId, toId)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := util.LineDiff(from.Content, to.Content)
+	ret := &response.PostRevisionDiffResponse{
+		From:    response.NewPostRevisionResponse(from),
+		To:      response.NewPostRevisionResponse(to),
+		Lines:   lines,
+		Unified: util.UnifiedDiff(lines, fmt.Sprintf("revision/%d", fromId), fmt.Sprintf("revision/%d", toId), revisionDiffContext),
+	}
+	if err := s.fillAuthor(ctx, ret.From, ret.To); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// fillAuthor 填充修订版本的保存用户
+func (s *PostRevisionService) fillAuthor(ctx context.Context, revisions ...*response.PostRevisionResponse) error {
+	var authorIds []uint
+	for _, revision := range revisions {
+		if revision.AuthorId != nil {
+			authorIds = append(authorIds, *revision.AuthorId)
+		}
+	}
+	if len(authorIds) == 0 {
+		return nil
+	}
+
+	users, err := s.userRepo.GetByIds(ctx, authorIds)
+	if err != nil {
+		logger.Log.Error("获取修订版本用户失败", zap.Error(err))
+		return response.ServerError
+	}
+
+	authors := make(map[uint]*response.PostAuthorResponse, len(users))
+	for _, user := range users {
+		authors[user.UserId] = response.NewPostAuthorResponse(user)
+	}
+	for _, revision := range revisions {
+		if revision.AuthorId != nil {
+			revision.Author = authors[*revision.AuthorId]
+		}
+	}
+	return nil
+}
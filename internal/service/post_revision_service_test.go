@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestPostService_Revision(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	s := newTestPostService(t, database)
+	post := testutil.NewFixture(t, database).Post("post", "title\nv0")
+	operator := &Operator{UserId: 1, Role: enum.UserRoleOwner}
+
+	save := func(content string) {
+		t.Helper()
+		if _, err := s.UpdatePostContent(ctx, operator, request.PostContentRequest{PostId: post.PostId, Content: content},
+			enum.PostContentStatusPublished, nil); err != nil {
+			t.Fatalf("UpdatePostContent: %v", err)
+		}
+	}
+	save("title\nv1")
+	// 内容没有变化时不记录新版本
+	save("title\nv1")
+	if _, err := s.AddPostDraft(ctx, operator, &request.PostDraftRequest{PostId: post.PostId, DraftName: "draft", Content: "draft"}); err != nil {
+		t.Fatalf("AddPostDraft: %v", err)
+	}
+	save("title\nv2\nmore")
+
+	revisions, err := s.PostRevisions(ctx, operator, post.PostId)
+	if err != nil || len(revisions) != 3 {
+		t.Fatalf("PostRevisions = %+v, %v", revisions, err)
+	}
+	latest, draft, first := revisions[0], revisions[1], revisions[2]
+	if draft.Status != enum.PostContentStatusDraft || *draft.DraftName != "draft" ||
+		first.AuthorId == nil || *first.AuthorId != 1 || latest.WordCount != 3 {
+		t.Errorf("PostRevisions = %+v, %+v, %+v", latest, draft, first)
+	}
+
+	diff, err := s.PostRevisionDiff(ctx, operator, post.PostId, first.RevisionId, latest.RevisionId)
+	if err != nil {
+		t.Fatalf("PostRevisionDiff: %v", err)
+	}
+	if len(diff.Lines) != 4 || !strings.Contains(diff.Unified, "-v1\n+v2\n+more\n") {
+		t.Errorf("PostRevisionDiff = %+v\n%s", diff.Lines, diff.Unified)
+	}
+	if _, err := s.PostRevisionDiff(ctx, operator, post.PostId+1, first.RevisionId, latest.RevisionId); err == nil {
+		t.Error("PostRevisionDiff with other post should fail")
+	}
+
+	// 恢复为正文，恢复后记录为新版本
+	if ok, err := s.RestorePostRevision(ctx, operator, post.PostId, first.RevisionId, nil); err != nil || !ok {
+		t.Fatalf("RestorePostRevision = %v, %v", ok, err)
+	}
+	content, _ := s.PostContent(ctx, post.PostId, enum.PostContentStatusPublished, nil)
+	if content.Content != "title\nv1" {
+		t.Errorf("published content = %q, want %q", content.Content, "title\nv1")
+	}
+
+	// 恢复为新草稿
+	draftName := "v2"
+	if ok, err := s.RestorePostRevision(ctx, operator, post.PostId, latest.RevisionId, &draftName); err != nil || !ok {
+		t.Fatalf("RestorePostRevision(draft) = %v, %v", ok, err)
+	}
+	content, _ = s.PostContent(ctx, post.PostId, enum.PostContentStatusDraft, &draftName)
+	if content == nil || content.Content != "title\nv2\nmore" {
+		t.Errorf("draft content = %+v", content)
+	}
+
+	if revisions, _ := s.PostRevisions(ctx, operator, post.PostId); len(revisions) != 5 {
+		t.Errorf("PostRevisions after restore = %d, want 5", len(revisions))
+	}
+
+	// 没有修改其他用户文章权限时不能恢复
+	contributor := &Operator{UserId: 2, Role: enum.UserRoleContributor}
+	if _, err := s.RestorePostRevision(ctx, contributor, post.PostId, first.RevisionId, nil); err != ErrPermissionDenied {
+		t.Errorf("RestorePostRevision(contributor) error = %v, want ErrPermissionDenied", err)
+	}
+
+	// 没有修改其他用户文章权限时也不能查看其他用户文章的修订版本
+	author := &Operator{UserId: 3, Role: enum.UserRoleAuthor}
+	if _, err := s.PostRevisions(ctx, author, post.PostId); err != ErrPermissionDenied {
+		t.Errorf("PostRevisions(author) error = %v, want ErrPermissionDenied", err)
+	}
+	if _, err := s.PostRevision(ctx, author, post.PostId, first.RevisionId); err != ErrPermissionDenied {
+		t.Errorf("PostRevision(author) error = %v, want ErrPermissionDenied", err)
+	}
+	if _, err := s.PostRevisionDiff(ctx, author, post.PostId, first.RevisionId, latest.RevisionId); err != ErrPermissionDenied {
+		t.Errorf("PostRevisionDiff(author) error = %v, want ErrPermissionDenied", err)
+	}
+	if revision, err := s.PostRevision(ctx, operator, post.PostId, first.RevisionId); err != nil || revision.Content != "title\nv1" {
+		t.Errorf("PostRevision(owner) = %+v, %v", revision, err)
+	}
+}
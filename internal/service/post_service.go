@@ -4,18 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"net/url"
+	"nola-go/internal/config"
 	"nola-go/internal/logger"
 	"nola-go/internal/models"
 	"nola-go/internal/models/enum"
 	"nola-go/internal/models/request"
 	"nola-go/internal/models/response"
+	"nola-go/internal/ratelimit"
 	"nola-go/internal/repository"
 	"nola-go/internal/util"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"go.uber.org/zap"
 )
@@ -24,14 +31,64 @@ type PostService struct {
 	postRepo        repository.PostRepository
 	tagService      *TagService
 	categoryService *CategoryService
+	attemptService  *AttemptService
+	auditService    *AuditService
+	revisionService *PostRevisionService
+	searchService   *SearchService
+	visitLimiter    *ratelimit.Limiter
+	// recycleRetention 文章在回收站中的保留时长，为 0 时不自动删除
+	recycleRetention time.Duration
 }
 
-func NewPostService(p repository.PostRepository, tsv *TagService, csv *CategoryService) *PostService {
-	return &PostService{postRepo: p, tagService: tsv, categoryService: csv}
+// defaultRecycleRetentionDays 文章在回收站中默认保留的天数
+const defaultRecycleRetentionDays = 30
+
+// postVisitPolicy 增加文章访问量的限流策略名称，同一 IP 超过限制后仍然返回文章内容，只是不再增加访问量
+const postVisitPolicy = "post_visit"
+
+func NewPostService(
+	p repository.PostRepository,
+	tsv *TagService,
+	csv *CategoryService,
+	asv *AttemptService,
+	ausv *AuditService,
+	rsv *PostRevisionService,
+	ssv *SearchService,
+	vl *ratelimit.Limiter,
+	rbc config.RecycleBinConfig,
+) *PostService {
+	s := &PostService{
+		postRepo:        p,
+		tagService:      tsv,
+		categoryService: csv,
+		attemptService:  asv,
+		auditService:    ausv,
+		revisionService: rsv,
+		searchService:   ssv,
+		visitLimiter:    vl,
+	}
+
+	switch {
+	case rbc.RetentionDays == 0:
+		s.recycleRetention = defaultRecycleRetentionDays * 24 * time.Hour
+	case rbc.RetentionDays > 0:
+		s.recycleRetention = time.Duration(rbc.RetentionDays) * 24 * time.Hour
+	}
+	return s
 }
 
-// AddPost 添加文章
-func (s *PostService) AddPost(ctx context.Context, req *request.PostRequest) (*response.PostResponse, error) {
+// AddPost 添加文章，文章作者为当前操作用户
+func (s *PostService) AddPost(ctx context.Context, operator *Operator, req *request.PostRequest) (*response.PostResponse, error) {
+	if err := checkPostStatusAllowed(operator, req.Status); err != nil {
+		return nil, err
+	}
+	if err := checkPostSchedule(req.Status, req.PublishAt); err != nil {
+		return nil, err
+	}
+	if err := checkPostSeo(req); err != nil {
+		return nil, err
+	}
+
 	// 检查别名是否重复
 	p, err := s.PostBySlug(ctx, req.Slug, false)
 
@@ -54,6 +111,7 @@ func (s *PostService) AddPost(ctx context.Context, req *request.PostRequest) (*r
 	}
 
 	// 添加文章
+	req.AuthorId = &operator.UserId
 	post, err := s.postRepo.AddPost(ctx, req)
 
 	if err != nil {
@@ -61,6 +119,9 @@ func (s *PostService) AddPost(ctx context.Context, req *request.PostRequest) (*r
 		return nil, response.ServerError
 	}
 
+	s.revisionService.Record(ctx, operator, post.PostId, enum.PostContentStatusPublished, nil, util.StringDefault(req.Content, ""))
+	s.searchService.IndexPost(ctx, post.PostId)
+
 	return response.NewPostResponse(post), nil
 }
 
@@ -68,12 +129,18 @@ func (s *PostService) AddPost(ctx context.Context, req *request.PostRequest) (*r
 //
 // Parameters:
 //   - ctx: 上下文
+//   - operator: 当前操作用户，作为文章作者
 //   - names: 文章名称数组
 //   - contents: 文章内容数组
 //
 // Returns:
 //   - []*response.PostResponse: 添加成功的文章数组
-func (s *PostService) AddPostByNamesAndContents(ctx context.Context, names []string, contents []string) ([]*response.PostResponse, error) {
+func (s *PostService) AddPostByNamesAndContents(
+	ctx context.Context,
+	operator *Operator,
+	names []string,
+	contents []string,
+) ([]*response.PostResponse, error) {
 
 	if len(names) == 0 || len(contents) == 0 {
 		return nil, errors.New("名称或内容不能为空")
@@ -88,6 +155,7 @@ func (s *PostService) AddPostByNamesAndContents(ctx context.Context, names []str
 	for i, name := range names {
 		// 封装文章请求类，用于添加文章
 		pr := request.NewPostRequestByNameAndContent(name, contents[i])
+		pr.AuthorId = &operator.UserId
 
 		// 检查别名是否重复
 		p, err := s.PostBySlug(ctx, pr.Slug, false)
@@ -111,6 +179,9 @@ func (s *PostService) AddPostByNamesAndContents(ctx context.Context, names []str
 			logger.Log.Error("添加文章失败", zap.Error(err))
 			return nil, response.ServerError
 		}
+		s.revisionService.Record(ctx, operator, ret.PostId, enum.PostContentStatusPublished, nil, contents[i])
+		s.searchService.IndexPost(ctx, ret.PostId)
+
 		// 将添加成功的文章加到结果数组
 		result = append(result, response.NewPostResponse(ret))
 	}
@@ -119,7 +190,7 @@ func (s *PostService) AddPostByNamesAndContents(ctx context.Context, names []str
 }
 
 // DeletePosts 根据文章 ID 批量删除文章
-func (s *PostService) DeletePosts(ctx context.Context, ids []uint) (bool, error) {
+func (s *PostService) DeletePosts(ctx context.Context, operator *Operator, ids []uint) (bool, error) {
 
 	if len(ids) == 0 {
 		return false, nil
@@ -131,6 +202,10 @@ func (s *PostService) DeletePosts(ctx context.Context, ids []uint) (bool, error)
 		return false, err
 	}
 
+	if err := checkPostsEditable(operator, posts); err != nil {
+		return false, err
+	}
+
 	for _, post := range posts {
 		// 判断给定的文章是否都处于回收状态
 		if post.Status != enum.PostStatusDeleted {
@@ -146,15 +221,24 @@ func (s *PostService) DeletePosts(ctx context.Context, ids []uint) (bool, error)
 		return false, response.ServerError
 	}
 
+	s.searchService.RemovePosts(ctx, ids)
+	s.auditService.Record(ctx, operator, enum.AuditActionPostDelete, util.Map(posts, func(post *response.PostResponse) *AuditTarget {
+		return &AuditTarget{Type: enum.AuditTargetTypePost, Id: strconv.FormatUint(uint64(post.PostId), 10), Before: post}
+	})...)
+
 	return ret, nil
 }
 
 // UpdatePostStatusToDeleted 将文章状态修改为已删除（回收站）
-func (s *PostService) UpdatePostStatusToDeleted(ctx context.Context, ids []uint) (bool, error) {
+func (s *PostService) UpdatePostStatusToDeleted(ctx context.Context, operator *Operator, ids []uint) (bool, error) {
 	if len(ids) == 0 {
 		return false, nil
 	}
 
+	if err := s.checkPostIdsEditable(ctx, operator, ids...); err != nil {
+		return false, err
+	}
+
 	ret, err := s.postRepo.UpdatePostStatusToDeleted(ctx, ids)
 
 	if err != nil {
@@ -166,12 +250,19 @@ func (s *PostService) UpdatePostStatusToDeleted(ctx context.Context, ids []uint)
 }
 
 // UpdatePostStatusTo 将文章转为指定状态
-func (s *PostService) UpdatePostStatusTo(ctx context.Context, ids []uint, status enum.PostStatus) (bool, error) {
+func (s *PostService) UpdatePostStatusTo(ctx context.Context, operator *Operator, ids []uint, status enum.PostStatus) (bool, error) {
 
 	if len(ids) == 0 {
 		return false, nil
 	}
 
+	if err := checkPostStatusAllowed(operator, status); err != nil {
+		return false, err
+	}
+	if err := s.checkPostIdsEditable(ctx, operator, ids...); err != nil {
+		return false, err
+	}
+
 	ret, err := s.postRepo.UpdatePostStatusTo(ctx, ids, status)
 
 	if err != nil {
@@ -183,13 +274,26 @@ func (s *PostService) UpdatePostStatusTo(ctx context.Context, ids []uint, status
 }
 
 // UpdatePost 修改文章
-func (s *PostService) UpdatePost(ctx context.Context, req *request.PostRequest) (bool, error) {
+func (s *PostService) UpdatePost(ctx context.Context, operator *Operator, req *request.PostRequest) (bool, error) {
 
 	if req == nil {
 		logger.Log.Error("文章请求体不能为 nil")
 		return false, response.ServerError
 	}
 
+	if err := checkPostStatusAllowed(operator, req.Status); err != nil {
+		return false, err
+	}
+	if err := checkPostSchedule(req.Status, req.PublishAt); err != nil {
+		return false, err
+	}
+	if err := checkPostSeo(req); err != nil {
+		return false, err
+	}
+	if err := s.checkPostIdsEditable(ctx, operator, *req.PostId); err != nil {
+		return false, err
+	}
+
 	// 检查别名是否重复
 	p, err := s.PostBySlug(ctx, req.Slug, false)
 	if err != nil {
@@ -219,15 +323,31 @@ func (s *PostService) UpdatePost(ctx context.Context, req *request.PostRequest)
 		return false, response.ServerError
 	}
 
+	if ret {
+		s.searchService.IndexPost(ctx, *req.PostId)
+	}
+
 	return ret, nil
 }
 
 // UpdatePostStatus 修改文章状态（状态、可见性、置顶）
-func (s *PostService) UpdatePostStatus(ctx context.Context, req *request.PostStatusRequest) (bool, error) {
+func (s *PostService) UpdatePostStatus(ctx context.Context, operator *Operator, req *request.PostStatusRequest) (bool, error) {
 	if req.Status == nil && req.Visible == nil && req.Pinned == nil {
 		return false, nil
 	}
 
+	if req.Status != nil {
+		if err := checkPostStatusAllowed(operator, *req.Status); err != nil {
+			return false, err
+		}
+		if err := checkPostSchedule(*req.Status, req.PublishAt); err != nil {
+			return false, err
+		}
+	}
+	if err := s.checkPostIdsEditable(ctx, operator, req.PostId); err != nil {
+		return false, err
+	}
+
 	// 修改状态
 	ret, err := s.postRepo.UpdatePostStatus(ctx, req)
 
@@ -339,6 +459,9 @@ func (s *PostService) PostById(ctx context.Context, id uint, includeTagAndCatego
 		logger.Log.Error("获取文章失败", zap.Error(err))
 		return nil, response.ServerError
 	}
+	if post != nil {
+		s.fillDaysUntilPurge(post)
+	}
 	return post, nil
 }
 
@@ -388,6 +511,7 @@ func (s *PostService) PostPager(
 		logger.Log.Error("分页获取文章失败", zap.Error(err))
 		return nil, response.ServerError
 	}
+	s.fillDaysUntilPurge(pager.Data...)
 	return pager, nil
 }
 
@@ -421,11 +545,13 @@ func (s *PostService) PostByKey(ctx context.Context, key string) ([]*response.Po
 }
 
 // ApiPosts 获取文章 API 接口，用于博客前端页面，不包含敏感信息
+// 有关键词时使用全文搜索，结果按相关度排序并带有高亮片段
 //
 // Parameters:
 //   - ctx: 上下文
 //   - page: 当前页码
 //   - size: 每页条数
+//   - key: 关键词
 //   - tagId: 标签 ID
 //   - categoryId: 分类 ID
 //   - tag: 标签名或别名
@@ -439,7 +565,11 @@ func (s *PostService) ApiPosts(
 	tag *string,
 	category *string,
 ) (*models.Pager[response.PostApiResponse], error) {
-	posts, err := s.postRepo.PostApi(ctx, page, size, key, tagId, categoryId, tag, category)
+	if !util.StringIsNilOrBlank(key) {
+		return s.searchApiPosts(ctx, page, size, *key, tagId, categoryId, tag, category)
+	}
+
+	posts, err := s.postRepo.PostApi(ctx, page, size, nil, tagId, categoryId, tag, category, nil)
 	if err != nil {
 		logger.Log.Error("获取文章失败", zap.Error(err))
 		return nil, response.ServerError
@@ -447,8 +577,76 @@ func (s *PostService) ApiPosts(
 	return posts, nil
 }
 
-// PostContents 获取文章所有内容
-func (s *PostService) PostContents(ctx context.Context, id uint) ([]*response.PostContentResponse, error) {
+// LatestApiPosts 获取最新的已发布、可见的文章（博客前端），按创建时间降序，不优先置顶文章
+//   - size: 文章数量
+//   - tagId: 标签 ID
+//   - categoryId: 分类 ID
+func (s *PostService) LatestApiPosts(ctx context.Context, size int, tagId, categoryId *uint) ([]*response.PostApiResponse, error) {
+	posts, err := s.postRepo.PostApi(ctx, 1, size, nil, tagId, categoryId, nil, nil, enum.PostSortPtr(enum.PostSortCreateDesc))
+	if err != nil {
+		logger.Log.Error("获取最新文章失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	return posts.Data, nil
+}
+
+// searchApiPosts 全文搜索文章（博客前端），结果按相关度降序，页码为 0 时返回所有结果
+func (s *PostService) searchApiPosts(
+	ctx context.Context,
+	page, size int,
+	key string,
+	tagId *uint,
+	categoryId *uint,
+	tag *string,
+	category *string,
+) (*models.Pager[response.PostApiResponse], error) {
+	ids, err := s.searchService.Search(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.postRepo.PostApiByIds(ctx, ids, tagId, categoryId, tag, category)
+	if err != nil {
+		logger.Log.Error("获取文章失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	// 按搜索结果的相关度排序
+	rank := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		rank[id] = i
+	}
+	slices.SortFunc(posts, func(a, b *response.PostResponse) int {
+		return rank[a.PostId] - rank[b.PostId]
+	})
+
+	pager := &models.Pager[response.PostApiResponse]{
+		Page:       0,
+		Size:       0,
+		TotalData:  int64(len(posts)),
+		TotalPages: 1,
+	}
+	if page > 0 {
+		pager.Page, pager.Size = page, size
+		pager.TotalPages = int64(math.Ceil(float64(len(posts)) / float64(size)))
+		start := min((page-1)*size, len(posts))
+		posts = posts[start:min(start+size, len(posts))]
+	}
+
+	pager.Data = make([]*response.PostApiResponse, len(posts))
+	for i, post := range posts {
+		pager.Data[i] = response.NewPostApiResponse(post, true)
+		pager.Data[i].Highlight = s.searchService.Highlight(ctx, post, key)
+	}
+	return pager, nil
+}
+
+// PostContents 获取文章所有内容，没有修改其他用户文章权限的用户只能获取自己的文章
+func (s *PostService) PostContents(ctx context.Context, operator *Operator, id uint) ([]*response.PostContentResponse, error) {
+	if err := s.checkPostIdsReadable(ctx, operator, id); err != nil {
+		return nil, err
+	}
+
 	// 判断文章是否存在
 	exist, err := s.isPostExist(ctx, id)
 	if err != nil {
@@ -484,6 +682,27 @@ func (s *PostService) PostContent(ctx context.Context, id uint, status enum.Post
 	return content, nil
 }
 
+// AdminPostContent 获取文章内容（博客后台），没有修改其他用户文章权限的用户只能获取自己的文章
+//
+// Parameters:
+//   - ctx: 上下文
+//   - operator: 当前操作用户
+//   - id: 文章 ID
+//   - status: 文章内容状态
+//   - draftName: 草稿名称
+func (s *PostService) AdminPostContent(
+	ctx context.Context,
+	operator *Operator,
+	id uint,
+	status enum.PostContentStatus,
+	draftName *string,
+) (*models.PostContent, error) {
+	if err := s.checkPostIdsReadable(ctx, operator, id); err != nil {
+		return nil, err
+	}
+	return s.PostContent(ctx, id, status, draftName)
+}
+
 // ApiPostContent 获取文章博客 API 接口，用于博客前端页面获取文章内容。
 //
 // Parameters:
@@ -491,7 +710,14 @@ func (s *PostService) PostContent(ctx context.Context, id uint, status enum.Post
 //   - id: 文章 ID（ID 和别名至少存在一个）
 //   - slug: 文章别名（ID 和别名至少存在一个）
 //   - password: 文章密码（如果有）
-func (s *PostService) ApiPostContent(ctx context.Context, id *uint, slug *string, password *string) (*response.PostContentApiResponse, error) {
+//   - ip: 客户端 IP，用于防止暴力破解文章密码
+func (s *PostService) ApiPostContent(
+	ctx context.Context,
+	id *uint,
+	slug *string,
+	password *string,
+	ip string,
+) (*response.PostContentApiResponse, error) {
 	if id == nil && slug == nil {
 		return nil, nil
 	}
@@ -530,6 +756,12 @@ func (s *PostService) ApiPostContent(ctx context.Context, id *uint, slug *string
 			return nil, nil
 		}
 
+		// 检查是否因失败次数过多被锁定
+		account := strconv.FormatUint(uint64(post.PostId), 10)
+		if err := s.attemptService.Check(ctx, AttemptScopePostPassword, ip, account); err != nil {
+			return nil, err
+		}
+
 		// 验证密码是否正确
 		valid, err := s.isPostPasswordValid(ctx, post.PostId, *password)
 
@@ -539,8 +771,10 @@ func (s *PostService) ApiPostContent(ctx context.Context, id *uint, slug *string
 
 		if !valid {
 			// 密码错误
+			s.attemptService.Fail(ctx, AttemptScopePostPassword, ip, account)
 			return nil, errors.New("文章密码不正确")
 		}
+		s.attemptService.Succeed(ctx, AttemptScopePostPassword, account)
 	}
 
 	// 获取文章正文
@@ -551,10 +785,12 @@ func (s *PostService) ApiPostContent(ctx context.Context, id *uint, slug *string
 	}
 
 	// 增加文章浏览量
-	go func() {
-		bCtx := context.Background()
-		_, _ = s.AddPostVisit(bCtx, post.PostId)
-	}()
+	if s.allowPostVisit(ctx, ip) {
+		go func() {
+			bCtx := context.Background()
+			_, _ = s.AddPostVisit(bCtx, post.PostId)
+		}()
+	}
 
 	// 封装博客 API 文章内容响应体
 	return &response.PostContentApiResponse{
@@ -564,7 +800,7 @@ func (s *PostService) ApiPostContent(ctx context.Context, id *uint, slug *string
 }
 
 // AddPostDraft 添加文章草稿
-func (s *PostService) AddPostDraft(ctx context.Context, req *request.PostDraftRequest) (*models.PostContent, error) {
+func (s *PostService) AddPostDraft(ctx context.Context, operator *Operator, req *request.PostDraftRequest) (*models.PostContent, error) {
 	// 先判断文章是否存在
 	exist, err := s.isPostExist(ctx, req.PostId)
 	if err != nil {
@@ -575,6 +811,10 @@ func (s *PostService) AddPostDraft(ctx context.Context, req *request.PostDraftRe
 		return nil, errors.New("文章 [" + strconv.Itoa(int(req.PostId)) + "] 不存在")
 	}
 
+	if err := s.checkPostIdsEditable(ctx, operator, req.PostId); err != nil {
+		return nil, err
+	}
+
 	// 判断草稿名是否已存在
 	exist, err = s.isPostDraftNameExist(ctx, req.PostId, req.DraftName)
 	if err != nil {
@@ -592,6 +832,8 @@ func (s *PostService) AddPostDraft(ctx context.Context, req *request.PostDraftRe
 		return nil, response.ServerError
 	}
 
+	s.revisionService.Record(ctx, operator, req.PostId, enum.PostContentStatusDraft, &req.DraftName, req.Content)
+
 	return ret, nil
 }
 
@@ -599,15 +841,21 @@ func (s *PostService) AddPostDraft(ctx context.Context, req *request.PostDraftRe
 //
 // Parameters:
 //   - ctx: 上下文
+//   - operator: 当前操作用户
 //   - id: 文章 ID
 //   - status: 文章内容状态
 //   - draftNames: 草稿名数组
 func (s *PostService) DeletePostContent(
 	ctx context.Context,
+	operator *Operator,
 	id uint,
 	status enum.PostContentStatus,
 	draftNames []string,
 ) (bool, error) {
+	if err := s.checkPostIdsEditable(ctx, operator, id); err != nil {
+		return false, err
+	}
+
 	ret, err := s.postRepo.DeletePostContent(ctx, id, status, draftNames)
 
 	if err != nil {
@@ -621,24 +869,35 @@ func (s *PostService) DeletePostContent(
 //
 // Parameters:
 //   - ctx: 上下文
+//   - operator: 当前操作用户
 //   - pc: 文章内容请求体
 //   - status: 文章内容状态
 //   - draftName: 草稿名
 func (s *PostService) UpdatePostContent(
 	ctx context.Context,
+	operator *Operator,
 	pc request.PostContentRequest,
 	status enum.PostContentStatus,
 	draftName *string,
 ) (bool, error) {
+	if err := s.checkPostIdsEditable(ctx, operator, pc.PostId); err != nil {
+		return false, err
+	}
+
 	ret, err := s.postRepo.UpdatePostContent(ctx, pc, status, draftName)
 	if err != nil {
 		logger.Log.Error("修改文章内容失败", zap.Error(err))
 		return false, response.ServerError
 	}
 
+	if ret {
+		s.revisionService.Record(ctx, operator, pc.PostId, status, draftName, pc.Content)
+	}
+
 	if ret && status == enum.PostContentStatusPublished {
 		// 文章内容修改成功，并且当前修改的是文章正文，尝试更新文章摘要
 		_, _ = s.TryUpdatePostExcerptByPostContent(ctx, pc.PostId)
+		s.searchService.IndexPost(ctx, pc.PostId)
 	}
 
 	return ret, nil
@@ -648,14 +907,25 @@ func (s *PostService) UpdatePostContent(
 //
 // Parameters:
 //   - ctx: 上下文
+//   - operator: 当前操作用户
 //   - id: 文章 ID
 //   - oldName: 旧草稿名
 //   - newName: 新草稿名
-func (s *PostService) UpdatePostDraftName(ctx context.Context, id uint, oldName string, newName string) (bool, error) {
+func (s *PostService) UpdatePostDraftName(
+	ctx context.Context,
+	operator *Operator,
+	id uint,
+	oldName string,
+	newName string,
+) (bool, error) {
 	if oldName == newName {
 		return false, nil
 	}
 
+	if err := s.checkPostIdsEditable(ctx, operator, id); err != nil {
+		return false, err
+	}
+
 	// 先判断新的草稿名是否已经存在
 	exist, err := s.isPostDraftNameExist(ctx, id, newName)
 	if err != nil {
@@ -678,12 +948,14 @@ func (s *PostService) UpdatePostDraftName(ctx context.Context, id uint, oldName
 //
 // Parameters:
 //   - ctx: 上下文
+//   - operator: 当前操作用户
 //   - id: 文章 ID
 //   - draftName: 草稿名
 //   - deleteContent: 是否删除原来的正文
 //   - contentName: 文章正文名，留空将默认使用被转换为正文的旧草稿名。
 func (s *PostService) UpdatePostDraftToContent(
 	ctx context.Context,
+	operator *Operator,
 	id uint,
 	draftName string,
 	deleteContent bool,
@@ -698,6 +970,10 @@ func (s *PostService) UpdatePostDraftToContent(
 		return false, errors.New("文章 [" + strconv.Itoa(int(id)) + "] 不存在")
 	}
 
+	if err := s.checkPostIdsEditable(ctx, operator, id); err != nil {
+		return false, err
+	}
+
 	// 判断草稿名是否存在
 	exist, err = s.isPostDraftNameExist(ctx, id, draftName)
 	if err != nil {
@@ -714,8 +990,15 @@ func (s *PostService) UpdatePostDraftToContent(
 	}
 
 	if ret {
-		// 草稿转换成功，尝试更新文章摘要
+		// 草稿转换成功，记录新正文的修订版本
+		if content, err := s.postRepo.PostContent(ctx, id, enum.PostContentStatusPublished, nil); err != nil {
+			logger.Log.Error("获取文章正文失败", zap.Error(err))
+		} else if content != nil {
+			s.revisionService.Record(ctx, operator, id, enum.PostContentStatusPublished, nil, content.Content)
+		}
+		// 尝试更新文章摘要
 		_, _ = s.TryUpdatePostExcerptByPostContent(ctx, id)
+		s.searchService.IndexPost(ctx, id)
 		// 修改文章最后修改时间
 		_, err = s.postRepo.UpdatePostLastModifyTime(ctx, id, util.Int64Ptr(time.Now().UnixMilli()))
 		if err != nil {
@@ -726,6 +1009,299 @@ func (s *PostService) UpdatePostDraftToContent(
 	return ret, nil
 }
 
+// ScheduledPosts 获取所有定时发布的文章（按发布时间升序）
+func (s *PostService) ScheduledPosts(ctx context.Context) ([]*response.PostResponse, error) {
+	posts, err := s.postRepo.ScheduledPosts(ctx)
+	if err != nil {
+		logger.Log.Error("获取定时发布文章失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	return posts, nil
+}
+
+// PublishScheduledPosts 发布所有已到发布时间的定时发布文章，返回本次发布的文章数量
+// 每篇文章通过带条件的更新发布，多个实例同时执行时同一篇文章只会被发布一次
+func (s *PostService) PublishScheduledPosts(ctx context.Context) (int, error) {
+	now := time.Now().UnixMilli()
+	ids, err := s.postRepo.DueScheduledPostIds(ctx, now)
+	if err != nil {
+		logger.Log.Error("获取待发布的定时发布文章失败", zap.Error(err))
+		return 0, response.ServerError
+	}
+
+	count := 0
+	for _, id := range ids {
+		ret, err := s.postRepo.PublishScheduledPost(ctx, id, now)
+		if err != nil {
+			logger.Log.Error("发布定时发布文章失败", zap.Uint("postId", id), zap.Error(err))
+			continue
+		}
+		if ret {
+			logger.Log.Info("已发布定时发布文章", zap.Uint("postId", id))
+			count++
+		}
+	}
+	return count, nil
+}
+
+// PurgeRecycleBin 彻底删除在回收站中超过保留时长的文章（包括内容和评论），返回删除的文章数量
+func (s *PostService) PurgeRecycleBin(ctx context.Context) (int, error) {
+	if s.recycleRetention <= 0 {
+		return 0, nil
+	}
+
+	ids, err := s.postRepo.ExpiredDeletedPostIds(ctx, time.Now().Add(-s.recycleRetention).UnixMilli())
+	if err != nil {
+		logger.Log.Error("获取回收站中过期的文章失败", zap.Error(err))
+		return 0, response.ServerError
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	purged := s.purgePosts(ctx, ids)
+	if len(purged) > 0 {
+		logger.Log.Info("已清理回收站中过期的文章", zap.Uints("postIds", purged))
+	}
+	return len(purged), nil
+}
+
+// purgePosts 逐篇彻底删除回收站中的文章，返回删除成功的文章 ID
+// 文章可能在查询之后被恢复，这时只跳过这一篇，不影响其他文章
+func (s *PostService) purgePosts(ctx context.Context, ids []uint) []uint {
+	var purged []uint
+	for _, id := range ids {
+		if _, err := s.DeletePosts(ctx, SystemOperator, []uint{id}); err != nil {
+			logger.Log.Warn("清理回收站中的文章失败", zap.Uint("postId", id), zap.Error(err))
+			continue
+		}
+		purged = append(purged, id)
+	}
+	return purged
+}
+
+// fillDaysUntilPurge 填充回收站中的文章距离自动删除的天数，不足一天按一天计算
+func (s *PostService) fillDaysUntilPurge(posts ...*response.PostResponse) {
+	if s.recycleRetention <= 0 {
+		return
+	}
+
+	now := time.Now().UnixMilli()
+	day := (24 * time.Hour).Milliseconds()
+	for _, post := range posts {
+		if post.Status != enum.PostStatusDeleted || post.DeletedAt == nil {
+			continue
+		}
+		remaining := *post.DeletedAt + s.recycleRetention.Milliseconds() - now
+		days := 0
+		if remaining > 0 {
+			days = int((remaining + day - 1) / day)
+		}
+		post.DaysUntilPurge = &days
+	}
+}
+
+// PostRevisions 获取文章所有修订版本（按保存时间降序），没有修改其他用户文章权限的用户只能获取自己的文章
+func (s *PostService) PostRevisions(ctx context.Context, operator *Operator, id uint) ([]*response.PostRevisionResponse, error) {
+	if err := s.checkPostIdsReadable(ctx, operator, id); err != nil {
+		return nil, err
+	}
+
+	exist, err := s.isPostExist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.New("文章 [" + strconv.Itoa(int(id)) + "] 不存在")
+	}
+
+	return s.revisionService.Revisions(ctx, id)
+}
+
+// PostRevision 获取文章修订版本（包含内容），没有修改其他用户文章权限的用户只能获取自己的文章
+//
+// Parameters:
+//   - ctx: 上下文
+//   - operator: 当前操作用户
+//   - id: 文章 ID
+//   - revisionId: 修订版本 ID
+func (s *PostService) PostRevision(ctx context.Context, operator *Operator, id uint, revisionId uint) (*models.PostRevision, error) {
+	if err := s.checkPostIdsReadable(ctx, operator, id); err != nil {
+		return nil, err
+	}
+	return s.revisionService.Revision(ctx, id, revisionId)
+}
+
+// PostRevisionDiff 比较文章的两个修订版本，没有修改其他用户文章权限的用户只能比较自己的文章
+//
+// Parameters:
+//   - ctx: 上下文
+//   - operator: 当前操作用户
+//   - id: 文章 ID
+//   - fromId: 旧修订版本 ID
+//   - toId: 新修订版本 ID
+func (s *PostService) PostRevisionDiff(ctx context.Context, operator *Operator, id uint, fromId uint, toId uint) (*response.PostRevisionDiffResponse, error) {
+	if err := s.checkPostIdsReadable(ctx, operator, id); err != nil {
+		return nil, err
+	}
+	return s.revisionService.Diff(ctx, id, fromId, toId)
+}
+
+// RestorePostRevision 恢复文章修订版本，恢复后会记录为新的修订版本，历史版本保持不变
+//
+// Parameters:
+//   - ctx: 上下文
+//   - operator: 当前操作用户
+//   - id: 文章 ID
+//   - revisionId: 修订版本 ID
+//   - draftName: 为空时恢复为文章正文，否则恢复为该名称的新草稿
+func (s *PostService) RestorePostRevision(
+	ctx context.Context,
+	operator *Operator,
+	id uint,
+	revisionId uint,
+	draftName *string,
+) (bool, error) {
+	revision, err := s.revisionService.Revision(ctx, id, revisionId)
+	if err != nil {
+		return false, err
+	}
+
+	if draftName != nil {
+		_, err := s.AddPostDraft(ctx, operator, &request.PostDraftRequest{
+			PostId:    id,
+			DraftName: *draftName,
+			Content:   revision.Content,
+		})
+		return err == nil, err
+	}
+
+	return s.UpdatePostContent(ctx, operator, request.PostContentRequest{
+		PostId:  id,
+		Content: revision.Content,
+	}, enum.PostContentStatusPublished, nil)
+}
+
+// checkPostIdsEditable 检查当前操作用户是否可以修改指定文章，不存在的文章会被忽略
+func (s *PostService) checkPostIdsEditable(ctx context.Context, operator *Operator, ids ...uint) error {
+	if operator.Can(enum.PermissionEditOthersPosts) {
+		return nil
+	}
+
+	posts, err := s.PostByIds(ctx, ids, false)
+	if err != nil {
+		return err
+	}
+	return checkPostsEditable(operator, posts)
+}
+
+// allowPostVisit 判断本次访问是否增加文章访问量，同一 IP 访问过于频繁时不增加
+// 没有配置限流策略或计数存储出错时总是增加
+func (s *PostService) allowPostVisit(ctx context.Context, ip string) bool {
+	if s.visitLimiter == nil {
+		return true
+	}
+	policy := s.visitLimiter.Policy(postVisitPolicy)
+	if policy == nil {
+		return true
+	}
+
+	ret, err := s.visitLimiter.Allow(ctx, policy, ip)
+	if err != nil {
+		logger.Log.Warn("文章访问量限流计数失败", zap.Error(err))
+		return true
+	}
+	return ret.Allowed
+}
+
+// checkPostIdsReadable 检查当前操作用户是否可以获取指定文章的内容（包括草稿），不存在的文章会被忽略
+// 没有修改其他用户文章权限的用户只能获取自己的文章
+func (s *PostService) checkPostIdsReadable(ctx context.Context, operator *Operator, ids ...uint) error {
+	if operator.Can(enum.PermissionEditOthersPosts) {
+		return nil
+	}
+
+	posts, err := s.PostByIds(ctx, ids, false)
+	if err != nil {
+		return err
+	}
+	for _, post := range posts {
+		if post.AuthorId == nil || *post.AuthorId != operator.UserId {
+			return ErrPermissionDenied
+		}
+	}
+	return nil
+}
+
+// checkPostsEditable 检查当前操作用户是否可以修改文章
+// 没有修改其他用户文章权限的用户只能修改自己的文章，没有发布权限的用户（投稿者）不能修改已发布和定时发布的文章
+func checkPostsEditable(operator *Operator, posts []*response.PostResponse) error {
+	if operator.Can(enum.PermissionEditOthersPosts) {
+		return nil
+	}
+
+	for _, post := range posts {
+		if post.AuthorId == nil || *post.AuthorId != operator.UserId {
+			return ErrPermissionDenied
+		}
+		if (post.Status == enum.PostStatusPublished || post.Status == enum.PostStatusScheduled) &&
+			!operator.Can(enum.PermissionPublishPost) {
+			return ErrPermissionDenied
+		}
+	}
+	return nil
+}
+
+// checkPostStatusAllowed 检查当前操作用户是否可以将文章设为指定状态，没有发布权限的用户不能发布和定时发布文章
+func checkPostStatusAllowed(operator *Operator, status enum.PostStatus) error {
+	if (status == enum.PostStatusPublished || status == enum.PostStatusScheduled) && !operator.Can(enum.PermissionPublishPost) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// checkPostSchedule 检查定时发布时间，定时发布的文章必须提供晚于当前时间的发布时间
+func checkPostSchedule(status enum.PostStatus, publishAt *int64) error {
+	if status != enum.PostStatusScheduled {
+		return nil
+	}
+	if publishAt == nil {
+		return errors.New("定时发布需要提供发布时间")
+	}
+	if *publishAt <= time.Now().UnixMilli() {
+		return errors.New("定时发布时间必须晚于当前时间")
+	}
+	return nil
+}
+
+// checkPostSeo 检查文章 SEO 字段，空白的字段视为未设置
+func checkPostSeo(req *request.PostRequest) error {
+	for _, field := range []**string{&req.SeoTitle, &req.SeoDescription, &req.CanonicalURL, &req.OgImage} {
+		if util.StringIsNilOrBlank(*field) {
+			*field = nil
+		} else {
+			*field = util.StringPtr(strings.TrimSpace(**field))
+		}
+	}
+
+	if req.SeoTitle != nil && utf8.RuneCountInString(*req.SeoTitle) > 256 {
+		return errors.New("SEO 标题不能超过 256 个字符")
+	}
+	if req.SeoDescription != nil && utf8.RuneCountInString(*req.SeoDescription) > 512 {
+		return errors.New("SEO 描述不能超过 512 个字符")
+	}
+	if req.CanonicalURL != nil {
+		u, err := url.Parse(*req.CanonicalURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(*req.CanonicalURL) > 512 {
+			return errors.New("规范链接 [" + *req.CanonicalURL + "] 不是有效的 http(s) 地址")
+		}
+	}
+	if req.OgImage != nil && len(*req.OgImage) > 512 {
+		return errors.New("分享图片地址不能超过 512 个字符")
+	}
+	return nil
+}
+
 // isPostPasswordValid 验证文章密码是否正确
 func (s *PostService) isPostPasswordValid(ctx context.Context, id uint, password string) (bool, error) {
 	valid, err := s.postRepo.IsPostPasswordValid(ctx, id, password)
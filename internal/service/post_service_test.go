@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/config"
+	"nola-go/internal/kv"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/ratelimit"
+	"nola-go/internal/repository"
+	"nola-go/internal/search"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// newTestPostService 创建 PostService
+//   - database: 文章所在的数据库
+func newTestPostService(t *testing.T, database *gorm.DB) *PostService {
+	t.Helper()
+	return newTestPostServiceWithRecycleBin(t, database, config.RecycleBinConfig{})
+}
+
+func newTestPostServiceWithRecycleBin(t *testing.T, database *gorm.DB, rbc config.RecycleBinConfig) *PostService {
+	t.Helper()
+	tagRepo := repository.NewTagRepository(database)
+	categoryRepo := repository.NewCategoryRepository(database)
+	userRepo := repository.NewUserRepository(database)
+	postRepo := repository.NewPostRepository(database, tagRepo, categoryRepo, userRepo, testutil.NewHasher(t))
+	return NewPostService(
+		postRepo,
+		NewTagService(tagRepo),
+		NewCategoryService(categoryRepo),
+		newTestAttemptService(database),
+		newTestAuditService(database),
+		NewPostRevisionService(repository.NewPostRevisionRepository(database), userRepo),
+		NewSearchService(postRepo, search.NewIndexEngine(repository.NewSearchRepository(database))),
+		nil,
+		rbc,
+	)
+}
+
+func TestPostService_Schedule(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	s := newTestPostService(t, database)
+	owner := &Operator{UserId: 1, Role: enum.UserRoleOwner}
+	future := time.Now().Add(time.Hour).UnixMilli()
+
+	newRequest := func(slug string, publishAt *int64) *request.PostRequest {
+		return &request.PostRequest{
+			Title:               slug,
+			AutoGenerateExcerpt: util.BoolPtr(false),
+			Slug:                slug,
+			AllowComment:        util.BoolPtr(true),
+			Status:              enum.PostStatusScheduled,
+			Visible:             enum.PostVisibleVisible,
+			PublishAt:           publishAt,
+			Content:             util.StringPtr("content"),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		operator *Operator
+		req      *request.PostRequest
+		wantErr  bool
+	}{
+		{"定时发布", owner, newRequest("scheduled", &future), false},
+		{"没有发布时间", owner, newRequest("no-time", nil), true},
+		{"发布时间已过", owner, newRequest("past", util.Int64Ptr(1000)), true},
+		{"投稿者不能定时发布", &Operator{UserId: 2, Role: enum.UserRoleContributor}, newRequest("contributor", &future), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.AddPost(ctx, tt.operator, tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("AddPost error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// 已到发布时间的定时发布文章
+	f := testutil.NewFixture(t, database)
+	due := &models.Post{Title: "due", Slug: "due", Status: enum.PostStatusScheduled, Visible: enum.PostVisibleVisible,
+		PublishAt: util.Int64Ptr(1000), CreateTime: f.Now()}
+	f.Create(due)
+
+	if posts, err := s.ScheduledPosts(ctx); err != nil || len(posts) != 2 || posts[0].PostId != due.PostId {
+		t.Fatalf("ScheduledPosts = %+v, %v", posts, err)
+	}
+
+	if n, err := s.PublishScheduledPosts(ctx); err != nil || n != 1 {
+		t.Fatalf("PublishScheduledPosts = %d, %v", n, err)
+	}
+	if n, err := s.PublishScheduledPosts(ctx); err != nil || n != 0 {
+		t.Errorf("PublishScheduledPosts again = %d, %v", n, err)
+	}
+	if post, _ := s.PostById(ctx, due.PostId, false); post.Status != enum.PostStatusPublished {
+		t.Errorf("due post status = %s, want PUBLISHED", post.Status)
+	}
+}
+
+func TestPostService_PurgeRecycleBin(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	s := newTestPostService(t, database)
+	f := testutil.NewFixture(t, database)
+
+	trash := func(slug string, deletedAt time.Time) *models.Post {
+		post := f.Post(slug, "content")
+		f.Comment(post, nil, slug+"@example.com", true)
+		database.Model(post).Updates(map[string]any{"status": enum.PostStatusDeleted, "deleted_at": deletedAt.UnixMilli()})
+		return post
+	}
+	expired := trash("expired", time.Now().Add(-31*24*time.Hour))
+	recent := trash("recent", time.Now().Add(-24*time.Hour+time.Minute))
+
+	pager, err := s.PostPager(ctx, 1, 10, enum.PostStatusPtr(enum.PostStatusDeleted), nil, nil, nil, nil, nil)
+	if err != nil || len(pager.Data) != 2 {
+		t.Fatalf("PostPager = %+v, %v", pager, err)
+	}
+	for _, post := range pager.Data {
+		want := map[uint]int{expired.PostId: 0, recent.PostId: 30}[post.PostId]
+		if post.DaysUntilPurge == nil || *post.DaysUntilPurge != want {
+			t.Errorf("post %s DaysUntilPurge = %v, want %d", post.Slug, post.DaysUntilPurge, want)
+		}
+	}
+
+	if n, err := s.PurgeRecycleBin(ctx); err != nil || n != 1 {
+		t.Fatalf("PurgeRecycleBin = %d, %v", n, err)
+	}
+	if post, _ := s.PostById(ctx, expired.PostId, false); post != nil {
+		t.Errorf("expired post still exists: %+v", post)
+	}
+	if post, _ := s.PostById(ctx, recent.PostId, false); post == nil {
+		t.Error("recent post should not be purged")
+	}
+	var comments int64
+	database.Model(&models.Comment{}).Where("post_id = ?", expired.PostId).Count(&comments)
+	if comments != 0 {
+		t.Errorf("expired post comments = %d, want 0", comments)
+	}
+
+	// 清理前被恢复的文章跳过，不影响同一批的其他文章
+	another := trash("another-expired", time.Now().Add(-40*24*time.Hour))
+	restored := trash("restored", time.Now().Add(-40*24*time.Hour))
+	database.Model(restored).Update("status", enum.PostStatusPublished)
+	if purged := s.purgePosts(ctx, []uint{restored.PostId, another.PostId}); len(purged) != 1 || purged[0] != another.PostId {
+		t.Errorf("purgePosts = %v, want [%d]", purged, another.PostId)
+	}
+	if post, _ := s.PostById(ctx, restored.PostId, false); post == nil {
+		t.Error("restored post should not be purged")
+	}
+
+	// 关闭自动删除（保留天数小于 0）时不删除
+	disabled := newTestPostServiceWithRecycleBin(t, database, config.RecycleBinConfig{RetentionDays: -1})
+	trash("another", time.Now().Add(-365*24*time.Hour))
+	if n, err := disabled.PurgeRecycleBin(ctx); err != nil || n != 0 {
+		t.Errorf("PurgeRecycleBin(disabled) = %d, %v", n, err)
+	}
+}
+
+func TestPostService_ContentPermission(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	s := newTestPostService(t, database)
+	f := testutil.NewFixture(t, database)
+	post := f.Post("draft-post", "content")
+	database.Model(post).Updates(map[string]any{"author_id": 2, "status": enum.PostStatusDraft})
+	f.Create(&models.PostContent{PostId: post.PostId, Content: "draft", Status: enum.PostContentStatusDraft, DraftName: util.StringPtr("draft")})
+
+	tests := []struct {
+		name     string
+		operator *Operator
+		denied   bool
+	}{
+		{"编辑", &Operator{UserId: 1, Role: enum.UserRoleEditor}, false},
+		{"作者本人", &Operator{UserId: 2, Role: enum.UserRoleContributor}, false},
+		{"其他作者", &Operator{UserId: 3, Role: enum.UserRoleAuthor}, true},
+		{"其他投稿者", &Operator{UserId: 4, Role: enum.UserRoleContributor}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.PostContents(ctx, tt.operator, post.PostId)
+			if (err == ErrPermissionDenied) != tt.denied {
+				t.Errorf("PostContents error = %v, want denied %v", err, tt.denied)
+			}
+			_, err = s.AdminPostContent(ctx, tt.operator, post.PostId, enum.PostContentStatusPublished, nil)
+			if (err == ErrPermissionDenied) != tt.denied {
+				t.Errorf("AdminPostContent(published) error = %v, want denied %v", err, tt.denied)
+			}
+			content, err := s.AdminPostContent(ctx, tt.operator, post.PostId, enum.PostContentStatusDraft, util.StringPtr("draft"))
+			if (err == ErrPermissionDenied) != tt.denied || (!tt.denied && (content == nil || content.Content != "draft")) {
+				t.Errorf("AdminPostContent(draft) = %+v, %v, want denied %v", content, err, tt.denied)
+			}
+		})
+	}
+}
+
+func TestPostService_AllowPostVisit(t *testing.T) {
+	ctx := context.Background()
+	s := newTestPostService(t, testutil.NewDB(t))
+
+	// 没有配置限流时总是增加访问量
+	if !s.allowPostVisit(ctx, "1.1.1.1") {
+		t.Error("allowPostVisit without limiter = false")
+	}
+
+	limiter, err := ratelimit.New(config.RateLimitConfig{Enabled: true, Policies: []config.RateLimitPolicy{
+		{Name: postVisitPolicy, Limit: 2, WindowSeconds: 60},
+	}}, kv.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("ratelimit.New: %v", err)
+	}
+	s.visitLimiter = limiter
+
+	for i, want := range []bool{true, true, false} {
+		if got := s.allowPostVisit(ctx, "1.1.1.1"); got != want {
+			t.Errorf("allowPostVisit #%d = %v, want %v", i+1, got, want)
+		}
+	}
+	if !s.allowPostVisit(ctx, "2.2.2.2") {
+		t.Error("allowPostVisit(other ip) = false")
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/logger"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/repository"
+	"nola-go/internal/search"
+	"nola-go/internal/util"
+
+	"go.uber.org/zap"
+)
+
+// searchSnippetLength 搜索结果片段的最大字符数
+const searchSnippetLength = 120
+
+// SearchService 文章全文搜索 Service
+// 索引包含所有状态的文章，文章状态和可见性在查询文章时过滤，修改状态时不需要更新索引；
+// 加密文章只索引标题和别名，防止通过搜索泄露文章内容
+type SearchService struct {
+	postRepo repository.PostRepository
+	engine   search.Engine
+}
+
+// NewSearchService 创建文章全文搜索 Service
+func NewSearchService(postRepo repository.PostRepository, engine search.Engine) *SearchService {
+	return &SearchService{
+		postRepo: postRepo,
+		engine:   engine,
+	}
+}
+
+// IndexPost 更新文章索引，文章不存在时删除索引
+// 更新失败只输出日志，不影响已经保存的文章，可以通过重建索引修复
+func (s *SearchService) IndexPost(ctx context.Context, postId uint) {
+	post, err := s.postRepo.PostById(ctx, postId, false)
+	if err != nil {
+		logger.Log.Error("获取待索引文章失败", zap.Uint("postId", postId), zap.Error(err))
+		return
+	}
+	if post == nil {
+		s.RemovePosts(ctx, []uint{postId})
+		return
+	}
+
+	doc, err := s.postDocument(ctx, post)
+	if err != nil {
+		logger.Log.Error("获取待索引文章内容失败", zap.Uint("postId", postId), zap.Error(err))
+		return
+	}
+
+	if err := s.engine.Index(ctx, doc); err != nil {
+		logger.Log.Error("更新文章索引失败", zap.Uint("postId", postId), zap.Error(err))
+	}
+}
+
+// RemovePosts 删除文章索引，删除失败只输出日志
+func (s *SearchService) RemovePosts(ctx context.Context, ids []uint) {
+	if err := s.engine.Delete(ctx, ids...); err != nil {
+		logger.Log.Error("删除文章索引失败", zap.Uints("postIds", ids), zap.Error(err))
+	}
+}
+
+// Rebuild 重建所有文章的索引
+//
+// Returns:
+//   - int: 已索引的文章数
+func (s *SearchService) Rebuild(ctx context.Context) (int, error) {
+	posts, err := s.postRepo.Posts(ctx, false)
+	if err != nil {
+		logger.Log.Error("获取待索引文章失败", zap.Error(err))
+		return 0, response.ServerError
+	}
+
+	docs := make([]*search.Document, 0, len(posts))
+	for _, post := range posts {
+		doc, err := s.postDocument(ctx, post)
+		if err != nil {
+			logger.Log.Error("获取待索引文章内容失败", zap.Uint("postId", post.PostId), zap.Error(err))
+			return 0, response.ServerError
+		}
+		docs = append(docs, doc)
+	}
+
+	if err := s.engine.Rebuild(ctx, docs); err != nil {
+		logger.Log.Error("重建文章索引失败", zap.Error(err))
+		return 0, response.ServerError
+	}
+	return len(docs), nil
+}
+
+// RebuildIfEmpty 索引为空时重建索引，用于服务启动时初始化索引
+func (s *SearchService) RebuildIfEmpty(ctx context.Context) {
+	count, err := s.engine.Count(ctx)
+	if err != nil {
+		logger.Log.Error("获取文章索引数量失败", zap.Error(err))
+		return
+	}
+	if count > 0 {
+		return
+	}
+
+	n, err := s.Rebuild(ctx)
+	if err != nil {
+		return
+	}
+	if n > 0 {
+		logger.Log.Info("文章索引已重建", zap.Int("count", n))
+	}
+}
+
+// Search 搜索文章
+//
+// Returns:
+//   - []uint: 命中的文章 ID，按相关度降序
+func (s *SearchService) Search(ctx context.Context, key string) ([]uint, error) {
+	hits, err := s.engine.Search(ctx, key)
+	if err != nil {
+		logger.Log.Error("搜索文章失败", zap.String("key", key), zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.Id
+	}
+	return ids, nil
+}
+
+// Highlight 生成文章搜索高亮，加密文章只高亮标题
+// 优先截取正文中命中关键词的片段，正文没有命中时使用摘要
+func (s *SearchService) Highlight(ctx context.Context, post *response.PostResponse, key string) *response.PostHighlightResponse {
+	query := search.ParseQuery(key)
+	highlight := &response.PostHighlightResponse{Title: search.Highlight(post.Title, query)}
+	if post.Encrypted {
+		return highlight
+	}
+
+	text := post.Excerpt
+	content, err := s.postRepo.PostContent(ctx, post.PostId, enum.PostContentStatusPublished, nil)
+	if err != nil {
+		logger.Log.Error("获取文章内容失败", zap.Uint("postId", post.PostId), zap.Error(err))
+	} else if content != nil {
+		if plain := util.MarkdownToPlainText(content.Content); search.Matches(plain, query) || text == "" {
+			text = plain
+		}
+	}
+
+	highlight.Snippet = util.StringPtr(search.Snippet(text, query, searchSnippetLength))
+	return highlight
+}
+
+// postDocument 将文章转为待索引的文档
+func (s *SearchService) postDocument(ctx context.Context, post *response.PostResponse) (*search.Document, error) {
+	doc := &search.Document{Id: post.PostId, Title: post.Title, Slug: post.Slug}
+	if post.Encrypted {
+		return doc, nil
+	}
+
+	doc.Excerpt = post.Excerpt
+	content, err := s.postRepo.PostContent(ctx, post.PostId, enum.PostContentStatusPublished, nil)
+	if err != nil {
+		return nil, err
+	}
+	if content != nil {
+		doc.Content = util.MarkdownToPlainText(content.Content)
+	}
+	return doc, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPostService_Search(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	s := newTestPostService(t, database)
+	owner := &Operator{UserId: 1, Role: enum.UserRoleOwner}
+
+	add := func(title, content string, status enum.PostStatus, password *string) uint {
+		t.Helper()
+		post, err := s.AddPost(ctx, owner, &request.PostRequest{
+			Title:               title,
+			AutoGenerateExcerpt: util.BoolPtr(true),
+			Slug:                title,
+			AllowComment:        util.BoolPtr(true),
+			Status:              status,
+			Visible:             enum.PostVisibleVisible,
+			Content:             util.StringPtr(content),
+			Encrypted:           util.BoolPtr(password != nil),
+			Password:            password,
+		})
+		if err != nil {
+			t.Fatalf("AddPost(%s): %v", title, err)
+		}
+		return post.PostId
+	}
+
+	inContent := add("笔记", "# 标题\n\n这是一篇关于**全文搜索**的文章", enum.PostStatusPublished, nil)
+	inTitle := add("全文搜索实践", "正文", enum.PostStatusPublished, nil)
+	add("全文搜索草稿", "草稿", enum.PostStatusDraft, nil)
+	encrypted := add("加密", "加密文章中的全文搜索", enum.PostStatusPublished, util.StringPtr("secret"))
+
+	pager, err := s.ApiPosts(ctx, 0, 0, util.StringPtr("全文搜索"), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("ApiPosts: %v", err)
+	}
+	// 标题命中排在前面，草稿不返回，加密文章内容不参与搜索
+	if len(pager.Data) != 2 || pager.Data[0].PostId != inTitle || pager.Data[1].PostId != inContent {
+		t.Fatalf("ApiPosts = %+v", pager.Data)
+	}
+	if got := pager.Data[0].Highlight; got == nil || got.Title != "<mark>全文搜索</mark>实践" {
+		t.Errorf("title highlight = %+v", got)
+	}
+	if got := pager.Data[1].Highlight; got == nil || got.Snippet == nil ||
+		!strings.Contains(*got.Snippet, "关于<mark>全文搜索</mark>的文章") || strings.Contains(*got.Snippet, "**") {
+		t.Errorf("snippet highlight = %+v", got)
+	}
+
+	// 分页
+	pager, err = s.ApiPosts(ctx, 2, 1, util.StringPtr("全文搜索"), nil, nil, nil, nil)
+	if err != nil || pager.TotalData != 2 || pager.TotalPages != 2 || len(pager.Data) != 1 || pager.Data[0].PostId != inContent {
+		t.Errorf("ApiPosts(page 2) = %+v, %v", pager, err)
+	}
+
+	// 加密文章只能通过标题搜索，不返回片段
+	pager, _ = s.ApiPosts(ctx, 0, 0, util.StringPtr("加密"), nil, nil, nil, nil)
+	if len(pager.Data) != 1 || pager.Data[0].PostId != encrypted || pager.Data[0].Highlight.Snippet != nil {
+		t.Errorf("ApiPosts(encrypted) = %+v", pager.Data)
+	}
+
+	// 修改正文后更新索引
+	if _, err := s.UpdatePostContent(ctx, owner, request.PostContentRequest{PostId: inContent, Content: "内容已修改"},
+		enum.PostContentStatusPublished, nil); err != nil {
+		t.Fatalf("UpdatePostContent: %v", err)
+	}
+	pager, _ = s.ApiPosts(ctx, 0, 0, util.StringPtr("全文搜索"), nil, nil, nil, nil)
+	if len(pager.Data) != 1 || pager.Data[0].PostId != inTitle {
+		t.Errorf("ApiPosts after update = %+v", pager.Data)
+	}
+
+	// 重建索引
+	if n, err := s.searchService.Rebuild(ctx); err != nil || n != 4 {
+		t.Errorf("Rebuild = %d, %v", n, err)
+	}
+
+	// 彻底删除文章后删除索引
+	if _, err := s.UpdatePostStatusToDeleted(ctx, owner, []uint{inTitle}); err != nil {
+		t.Fatalf("UpdatePostStatusToDeleted: %v", err)
+	}
+	if _, err := s.DeletePosts(ctx, owner, []uint{inTitle}); err != nil {
+		t.Fatalf("DeletePosts: %v", err)
+	}
+	// 索引包含所有状态的文章，只剩下草稿
+	if ids, _ := s.searchService.Search(ctx, "全文搜索"); slices.Contains(ids, inTitle) || len(ids) != 1 {
+		t.Errorf("Search after delete = %v", ids)
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/util"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// seoDescriptionLength 从摘要生成的描述最多包含的字符数
+const seoDescriptionLength = 160
+
+// SeoService 文章 SEO 元数据 Service
+type SeoService struct {
+	postService   *PostService
+	configService *ConfigService
+}
+
+// NewSeoService 创建文章 SEO 元数据 Service
+func NewSeoService(psv *PostService, csv *ConfigService) *SeoService {
+	return &SeoService{
+		postService:   psv,
+		configService: csv,
+	}
+}
+
+// PostSeo 获取已发布文章的 SEO 元数据，包含 Open Graph、Twitter Card 和 schema.org BlogPosting 结构化数据
+// 未设置的字段使用默认值：标题使用文章标题，描述使用摘要，规范链接使用文章页面地址，
+// 分享图片依次使用封面、分类统一封面和博客 Logo；隐藏的文章禁止索引，加密的文章不输出摘要
+//
+// Parameters:
+//   - ctx: 上下文
+//   - id: 文章 ID
+//   - origin: 请求的地址（如 https://example.com），博客信息中没有设置前端地址时使用
+//
+// Returns:
+//   - *response.PostSeoResponse: SEO 元数据，文章不存在或未发布时返回 nil
+func (s *SeoService) PostSeo(ctx context.Context, id uint, origin string) (*response.PostSeoResponse, error) {
+	post, err := s.postService.PostById(ctx, id, true)
+	if err != nil || post == nil || post.Status != enum.PostStatusPublished {
+		return nil, err
+	}
+	blogInfo, err := s.configService.BlogInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	links := newSiteLinks(blogInfo, origin)
+	var siteName, blogger, logo string
+	if blogInfo != nil {
+		siteName = util.StringDefault(blogInfo.Title, "")
+		blogger = util.StringDefault(blogInfo.Blogger, "")
+		logo = util.StringDefault(blogInfo.Logo, "")
+	}
+
+	title := stringOrDefault(post.SeoTitle, post.Title)
+	description := stringOrDefault(post.SeoDescription, "")
+	if description == "" {
+		if post.Encrypted {
+			description = encryptedPostDescription
+		} else {
+			description = truncateDescription(post.Excerpt, seoDescriptionLength)
+		}
+	}
+	canonical := stringOrDefault(post.CanonicalURL, links.Post(post.Slug))
+	noIndex := post.NoIndex || post.Visible == enum.PostVisibleHidden
+	robots := "index, follow"
+	if noIndex {
+		robots = "noindex, follow"
+	}
+
+	// 分享图片：分享图片 > 封面 > 分类统一封面 > 博客 Logo
+	image := stringOrDefault(post.OgImage, stringOrDefault(post.Cover, ""))
+	if image == "" && post.Category != nil && post.Category.UnifiedCover {
+		image = stringOrDefault(post.Category.Cover, "")
+	}
+	if image == "" {
+		image = logo
+	}
+
+	published := time.UnixMilli(post.CreateTime).Format(time.RFC3339)
+	modified := published
+	if post.LastModifyTime != nil && *post.LastModifyTime > post.CreateTime && !post.Encrypted {
+		// 加密文章不公开最后修改时间
+		modified = time.UnixMilli(*post.LastModifyTime).Format(time.RFC3339)
+	}
+	author := blogger
+	if post.Author != nil {
+		author = post.Author.DisplayName
+	}
+	var section string
+	if post.Category != nil {
+		section = post.Category.DisplayName
+	}
+	var tags []string
+	for _, tag := range post.Tags {
+		tags = append(tags, tag.DisplayName)
+	}
+
+	ret := &response.PostSeoResponse{
+		Title:       title,
+		Description: description,
+		Canonical:   canonical,
+		Robots:      robots,
+		NoIndex:     noIndex,
+	}
+
+	og := metaTags("property", [][2]string{
+		{"og:type", "article"},
+		{"og:title", title},
+		{"og:description", description},
+		{"og:url", canonical},
+		{"og:site_name", siteName},
+		{"article:published_time", published},
+		{"article:modified_time", modified},
+		{"article:author", author},
+		{"article:section", section},
+	})
+	for _, tag := range tags {
+		og = append(og, &response.MetaTagResponse{Property: "article:tag", Content: tag})
+	}
+
+	card := "summary"
+	jsonLd := &response.BlogPostingJsonLd{
+		Context:          "https://schema.org",
+		Type:             "BlogPosting",
+		Headline:         title,
+		Description:      description,
+		URL:              canonical,
+		MainEntityOfPage: &response.JsonLdThing{Type: "WebPage", Id: canonical},
+		DatePublished:    published,
+		DateModified:     modified,
+		ArticleSection:   section,
+		Keywords:         strings.Join(tags, ", "),
+	}
+	if author != "" {
+		jsonLd.Author = &response.JsonLdThing{Type: "Person", Name: author}
+	}
+	if siteName != "" {
+		jsonLd.Publisher = &response.JsonLdThing{Type: "Organization", Name: siteName, URL: links.Home()}
+		if logo != "" {
+			jsonLd.Publisher.Logo = &response.JsonLdThing{Type: "ImageObject", URL: absoluteURL(links, logo)}
+		}
+	}
+	if image != "" {
+		image = absoluteURL(links, image)
+		ret.Image = &image
+		card = "summary_large_image"
+		og = append(og, &response.MetaTagResponse{Property: "og:image", Content: image})
+		jsonLd.Image = []string{image}
+	}
+
+	twitter := metaTags("name", [][2]string{
+		{"twitter:card", card},
+		{"twitter:title", title},
+		{"twitter:description", description},
+	})
+	if ret.Image != nil {
+		twitter = append(twitter, &response.MetaTagResponse{Name: "twitter:image", Content: image})
+	}
+
+	ret.OpenGraph = og
+	ret.Twitter = twitter
+	ret.JsonLd = jsonLd
+	return ret, nil
+}
+
+// metaTags 创建元标签，跳过内容为空的元标签
+//   - attr: 元标签名称使用的属性（property 或 name）
+//   - pairs: 名称和内容
+func metaTags(attr string, pairs [][2]string) []*response.MetaTagResponse {
+	tags := make([]*response.MetaTagResponse, 0, len(pairs))
+	for _, pair := range pairs {
+		if pair[1] == "" {
+			continue
+		}
+		tag := &response.MetaTagResponse{Content: pair[1]}
+		if attr == "property" {
+			tag.Property = pair[0]
+		} else {
+			tag.Name = pair[0]
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// absoluteURL 将站点内的相对地址转为绝对链接，已是绝对链接时不变
+func absoluteURL(links *siteLinks, path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if strings.HasPrefix(path, "//") {
+		return "https:" + path
+	}
+	return links.URL(path)
+}
+
+// truncateDescription 将文本合并空白后截取到指定字符数，超出时以省略号结尾
+func truncateDescription(text string, length int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= length {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:length-1])) + "…"
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/models/response"
+	"nola-go/internal/repository"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"strings"
+	"testing"
+)
+
+func TestSeoService_PostSeo(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	f := testutil.NewFixture(t, database)
+	postService := newTestPostService(t, database)
+	configService := NewConfigService(repository.NewConfigRepository(database), newTestAuditService(database))
+	s := NewSeoService(postService, configService)
+	owner := &Operator{UserId: 1, Role: enum.UserRoleOwner}
+
+	if _, err := configService.SetBlogInfo(ctx, SystemOperator, &models.BlogInfo{
+		Title:   util.StringPtr("Nola"),
+		Blogger: util.StringPtr("博主"),
+		Logo:    util.StringPtr("/logo.png"),
+		SiteURL: util.StringPtr("https://blog.example.com"),
+	}); err != nil {
+		t.Fatalf("SetBlogInfo: %v", err)
+	}
+
+	add := func(req *request.PostRequest) uint {
+		t.Helper()
+		req.AutoGenerateExcerpt = util.BoolPtr(false)
+		req.AllowComment = util.BoolPtr(true)
+		req.Status = enum.PostStatusPublished
+		if req.Visible == "" {
+			req.Visible = enum.PostVisibleVisible
+		}
+		post, err := postService.AddPost(ctx, owner, req)
+		if err != nil {
+			t.Fatalf("AddPost(%s): %v", req.Slug, err)
+		}
+		return post.PostId
+	}
+	tag := func(tags []*response.MetaTagResponse, key string) string {
+		for _, tag := range tags {
+			if tag.Property == key || tag.Name == key {
+				return tag.Content
+			}
+		}
+		return ""
+	}
+
+	golang := f.Category("golang")
+	database.Model(golang).Updates(map[string]any{"cover": "https://cdn.example.com/golang.png", "unified_cover": true})
+	gin := f.Tag("gin")
+
+	// 默认值
+	plain := add(&request.PostRequest{
+		Title:      "Gin 入门",
+		Slug:       "gin-start",
+		Excerpt:    util.StringPtr(strings.Repeat("摘要", 100)),
+		CategoryId: &golang.CategoryId,
+		TagIds:     []uint{gin.TagId},
+	})
+	seo, err := s.PostSeo(ctx, plain, "http://ignored")
+	if err != nil || seo == nil {
+		t.Fatalf("PostSeo = %v, %v", seo, err)
+	}
+	if seo.Title != "Gin 入门" || seo.Canonical != "https://blog.example.com/post/gin-start" || seo.Robots != "index, follow" {
+		t.Errorf("seo = %+v", seo)
+	}
+	if n := len([]rune(seo.Description)); n != seoDescriptionLength || !strings.HasSuffix(seo.Description, "…") {
+		t.Errorf("description = %q (%d)", seo.Description, n)
+	}
+	// 没有封面时使用分类的统一封面
+	if seo.Image == nil || *seo.Image != "https://cdn.example.com/golang.png" {
+		t.Errorf("image = %v", seo.Image)
+	}
+	if tag(seo.OpenGraph, "og:type") != "article" || tag(seo.OpenGraph, "article:section") != "golang" ||
+		tag(seo.OpenGraph, "article:tag") != "gin" || tag(seo.OpenGraph, "og:site_name") != "Nola" {
+		t.Errorf("openGraph = %+v", seo.OpenGraph)
+	}
+	if tag(seo.Twitter, "twitter:card") != "summary_large_image" || tag(seo.Twitter, "twitter:image") != *seo.Image {
+		t.Errorf("twitter = %+v", seo.Twitter)
+	}
+	ld := seo.JsonLd
+	if ld.Type != "BlogPosting" || ld.Headline != "Gin 入门" || ld.MainEntityOfPage.Id != seo.Canonical ||
+		ld.Publisher.Logo.URL != "https://blog.example.com/logo.png" || ld.Keywords != "gin" {
+		t.Errorf("jsonLd = %+v", ld)
+	}
+
+	// 自定义字段
+	custom := add(&request.PostRequest{
+		Title:          "原标题",
+		Slug:           "custom",
+		Cover:          util.StringPtr("/cover.png"),
+		SeoTitle:       util.StringPtr(" SEO 标题 "),
+		SeoDescription: util.StringPtr("SEO 描述"),
+		CanonicalURL:   util.StringPtr("https://origin.example.com/post/1"),
+		NoIndex:        true,
+		OgImage:        util.StringPtr("   "),
+	})
+	seo, _ = s.PostSeo(ctx, custom, "")
+	if seo.Title != "SEO 标题" || seo.Description != "SEO 描述" || seo.Canonical != "https://origin.example.com/post/1" ||
+		seo.Robots != "noindex, follow" || *seo.Image != "https://blog.example.com/cover.png" {
+		t.Errorf("custom seo = %+v", seo)
+	}
+
+	// 加密的文章不输出摘要，隐藏的文章禁止索引
+	encrypted := add(&request.PostRequest{
+		Title:     "加密",
+		Slug:      "encrypted",
+		Excerpt:   util.StringPtr("秘密摘要"),
+		Visible:   enum.PostVisibleHidden,
+		Encrypted: util.BoolPtr(true),
+		Password:  util.StringPtr("secret"),
+	})
+	seo, _ = s.PostSeo(ctx, encrypted, "")
+	if seo.Description != encryptedPostDescription || !seo.NoIndex || *seo.Image != "https://blog.example.com/logo.png" {
+		t.Errorf("encrypted seo = %+v", seo)
+	}
+
+	// 未发布的文章
+	database.Model(&models.Post{}).Where("post_id = ?", plain).Update("status", enum.PostStatusDraft)
+	if seo, err := s.PostSeo(ctx, plain, ""); seo != nil || err != nil {
+		t.Errorf("draft PostSeo = %+v, %v", seo, err)
+	}
+}
+
+func TestCheckPostSeo(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *request.PostRequest
+		wantErr bool
+	}{
+		{"empty", &request.PostRequest{}, false},
+		{"valid", &request.PostRequest{CanonicalURL: util.StringPtr("https://example.com/a")}, false},
+		{"relative canonical", &request.PostRequest{CanonicalURL: util.StringPtr("/post/a")}, true},
+		{"ftp canonical", &request.PostRequest{CanonicalURL: util.StringPtr("ftp://example.com/a")}, true},
+		{"long title", &request.PostRequest{SeoTitle: util.StringPtr(strings.Repeat("标", 257))}, true},
+		{"long description", &request.PostRequest{SeoDescription: util.StringPtr(strings.Repeat("述", 513))}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkPostSeo(tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("checkPostSeo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nola-go/internal/logger"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/repository"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// SeriesService 系列 Service
+type SeriesService struct {
+	seriesRepo repository.SeriesRepository
+	postRepo   repository.PostRepository
+}
+
+// NewSeriesService 创建系列 Service
+func NewSeriesService(seriesRepo repository.SeriesRepository, postRepo repository.PostRepository) *SeriesService {
+	return &SeriesService{
+		seriesRepo: seriesRepo,
+		postRepo:   postRepo,
+	}
+}
+
+// AddSeries 添加系列
+func (s *SeriesService) AddSeries(c context.Context, displayName string, slug string, description *string, cover *string) (*models.Series, error) {
+	// 先判断系列别名是否已经存在
+	exist, err := s.isSlugExist(c, slug, nil)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, errors.New("系列别名 [" + slug + "] 已经存在")
+	}
+
+	ret, err := s.seriesRepo.AddSeries(c, &models.Series{
+		DisplayName: displayName,
+		Slug:        slug,
+		Description: description,
+		Cover:       cover,
+	})
+	if err != nil {
+		logger.Log.Error("添加系列失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	// 默认文章数量 0
+	ret.PostCount = 0
+
+	return ret, nil
+}
+
+// DeleteSeries 根据系列 ID 数组删除系列，系列中的文章不会被删除
+func (s *SeriesService) DeleteSeries(c context.Context, seriesIds []uint) (bool, error) {
+	ret, err := s.seriesRepo.DeleteSeries(c, seriesIds)
+	if err != nil {
+		logger.Log.Error("删除系列失败 - Ids", zap.Error(err))
+		return false, response.ServerError
+	}
+	return ret, nil
+}
+
+// DeleteSeriesBySlugs 根据别名数组删除系列，系列中的文章不会被删除
+func (s *SeriesService) DeleteSeriesBySlugs(c context.Context, slugs []string) (bool, error) {
+	ret, err := s.seriesRepo.DeleteSeriesBySlugs(c, slugs)
+	if err != nil {
+		logger.Log.Error("删除系列失败 - Slugs", zap.Error(err))
+		return false, response.ServerError
+	}
+	return ret, nil
+}
+
+// UpdateSeries 修改系列
+func (s *SeriesService) UpdateSeries(c context.Context, series *models.Series) (bool, error) {
+	// 先判断系列别名是否已存在
+	exist, err := s.isSlugExist(c, series.Slug, &series.SeriesId)
+	if err != nil {
+		return false, err
+	}
+	if exist {
+		return false, errors.New("系列别名 [" + series.Slug + "] 已经存在")
+	}
+
+	ret, err := s.seriesRepo.UpdateSeries(c, series)
+	if err != nil {
+		logger.Log.Error("修改系列失败", zap.Error(err))
+		return false, response.ServerError
+	}
+	return ret, nil
+}
+
+// UpdateSeriesPosts 设置系列中的文章及顺序，文章原来所属的其他系列会被移除
+//
+// Parameters:
+//   - c: 上下文
+//   - id: 系列 ID
+//   - postIds: 文章 ID 数组，数组顺序即文章在系列中的顺序，为空时清空系列
+func (s *SeriesService) UpdateSeriesPosts(c context.Context, id uint, postIds []uint) (bool, error) {
+	series, err := s.SeriesById(c, id)
+	if err != nil {
+		return false, err
+	}
+	if series == nil {
+		return false, errors.New("系列不存在")
+	}
+
+	// 检查文章是否重复、是否都存在
+	for i, postId := range postIds {
+		if slices.Contains(postIds[:i], postId) {
+			return false, fmt.Errorf("文章 [%d] 重复", postId)
+		}
+	}
+	if len(postIds) > 0 {
+		posts, err := s.postRepo.PostByIds(c, postIds, false)
+		if err != nil {
+			logger.Log.Error("获取文章失败", zap.Error(err))
+			return false, response.ServerError
+		}
+		for _, postId := range postIds {
+			if !slices.ContainsFunc(posts, func(post *response.PostResponse) bool { return post.PostId == postId }) {
+				return false, fmt.Errorf("文章 [%d] 不存在", postId)
+			}
+		}
+	}
+
+	ret, err := s.seriesRepo.UpdateSeriesPosts(c, id, postIds)
+	if err != nil {
+		logger.Log.Error("修改系列文章失败", zap.Error(err))
+		return false, response.ServerError
+	}
+	return ret, nil
+}
+
+// SeriesPager 分页获取所有系列，page 为 0 时获取所有系列
+func (s *SeriesService) SeriesPager(c context.Context, page, size int) (*models.Pager[models.Series], error) {
+	if page == 0 {
+		seriesList, err := s.seriesRepo.SeriesList(c)
+		if err != nil {
+			logger.Log.Error("获取系列失败", zap.Error(err))
+			return nil, response.ServerError
+		}
+		return &models.Pager[models.Series]{
+			Page:       0,
+			Size:       0,
+			Data:       seriesList,
+			TotalData:  int64(len(seriesList)),
+			TotalPages: 1,
+		}, nil
+	}
+
+	ret, err := s.seriesRepo.SeriesPager(c, page, size)
+	if err != nil {
+		logger.Log.Error("获取系列失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	return ret, nil
+}
+
+// SeriesById 根据系列 ID 获取系列
+func (s *SeriesService) SeriesById(c context.Context, id uint) (*models.Series, error) {
+	ret, err := s.seriesRepo.SeriesById(c, id)
+	if err != nil {
+		logger.Log.Error("获取系列失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	return ret, nil
+}
+
+// SeriesPosts 获取系列中的所有文章（包括未发布的文章），按文章在系列中的顺序
+func (s *SeriesService) SeriesPosts(c context.Context, id uint) ([]*response.PostResponse, error) {
+	return s.seriesPosts(c, id, false)
+}
+
+// ApiSeries 根据别名获取系列和系列中已发布、可见的文章（博客前端）
+//
+// Returns:
+//   - *response.SeriesApiResponse: 系列，系列不存在时返回 nil
+func (s *SeriesService) ApiSeries(c context.Context, slug string) (*response.SeriesApiResponse, error) {
+	series, err := s.seriesRepo.SeriesBySlug(c, slug)
+	if err != nil {
+		logger.Log.Error("获取系列失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	if series == nil {
+		return nil, nil
+	}
+
+	posts, err := s.seriesPosts(c, series.SeriesId, true)
+	if err != nil {
+		return nil, err
+	}
+	posts = slices.DeleteFunc(posts, func(post *response.PostResponse) bool {
+		return !isPostPublic(post)
+	})
+
+	// 博客前端只统计公开的文章
+	series.PostCount = int64(len(posts))
+	ret := &response.SeriesApiResponse{Series: series, Posts: response.NewPostApiResponses(posts, true)}
+	if ret.Posts == nil {
+		ret.Posts = []*response.PostApiResponse{}
+	}
+	return ret, nil
+}
+
+// PostSeries 获取文章所属系列及系列中的上一篇、下一篇文章（博客前端）
+// 导航只包含系列中已发布、可见的文章，当前文章为隐藏文章时也包含当前文章
+//
+// Returns:
+//   - *response.PostSeriesResponse: 文章所属系列，文章不属于任何系列时返回 nil
+func (s *SeriesService) PostSeries(c context.Context, postId uint) (*response.PostSeriesResponse, error) {
+	series, err := s.seriesRepo.SeriesByPostId(c, postId)
+	if err != nil {
+		logger.Log.Error("获取文章系列失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	if series == nil {
+		return nil, nil
+	}
+
+	posts, err := s.seriesPosts(c, series.SeriesId, false)
+	if err != nil {
+		return nil, err
+	}
+	posts = slices.DeleteFunc(posts, func(post *response.PostResponse) bool {
+		return post.PostId != postId && !isPostPublic(post)
+	})
+
+	index := slices.IndexFunc(posts, func(post *response.PostResponse) bool { return post.PostId == postId })
+	if index < 0 {
+		return nil, nil
+	}
+
+	series.PostCount = int64(len(posts))
+	ret := &response.PostSeriesResponse{
+		Series: series,
+		Index:  index + 1,
+		Total:  len(posts),
+	}
+	if index > 0 {
+		ret.Prev = response.NewPostLinkResponse(posts[index-1])
+	}
+	if index < len(posts)-1 {
+		ret.Next = response.NewPostLinkResponse(posts[index+1])
+	}
+	return ret, nil
+}
+
+// seriesPosts 获取系列中的文章，按文章在系列中的顺序
+//   - includeTagAndCategory: 是否包含标签、分类和作者
+func (s *SeriesService) seriesPosts(c context.Context, id uint, includeTagAndCategory bool) ([]*response.PostResponse, error) {
+	ids, err := s.seriesRepo.SeriesPostIds(c, id)
+	if err != nil {
+		logger.Log.Error("获取系列文章失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	if len(ids) == 0 {
+		return []*response.PostResponse{}, nil
+	}
+
+	posts, err := s.postRepo.PostByIds(c, ids, includeTagAndCategory)
+	if err != nil {
+		logger.Log.Error("获取系列文章失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	order := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		order[id] = i
+	}
+	slices.SortFunc(posts, func(a, b *response.PostResponse) int {
+		return order[a.PostId] - order[b.PostId]
+	})
+	return posts, nil
+}
+
+// isSlugExist 判断系列别名是否已经存在，并且不是当前系列自己
+//   - c: 上下文
+//   - slug: 别名
+//   - seriesId: 系列 ID，用于排除自己（添加新系列时可以传 nil）
+func (s *SeriesService) isSlugExist(c context.Context, slug string, seriesId *uint) (bool, error) {
+	series, err := s.seriesRepo.SeriesBySlug(c, slug)
+	if err != nil {
+		logger.Log.Error("获取系列失败", zap.Error(err))
+		return false, response.ServerError
+	}
+	return series != nil && (seriesId == nil || series.SeriesId != *seriesId), nil
+}
+
+// isPostPublic 文章是否公开（已发布且可见）
+func isPostPublic(post *response.PostResponse) bool {
+	return post.Status == enum.PostStatusPublished && post.Visible == enum.PostVisibleVisible
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/response"
+	"nola-go/internal/repository"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"slices"
+	"testing"
+)
+
+func TestSeriesService(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	f := testutil.NewFixture(t, database)
+	tagRepo := repository.NewTagRepository(database)
+	categoryRepo := repository.NewCategoryRepository(database)
+	postRepo := repository.NewPostRepository(database, tagRepo, categoryRepo, repository.NewUserRepository(database), testutil.NewHasher(t))
+	s := NewSeriesService(repository.NewSeriesRepository(database), postRepo)
+
+	series, err := s.AddSeries(ctx, "Go 入门", "go-101", util.StringPtr("从零开始"), nil)
+	if err != nil {
+		t.Fatalf("AddSeries: %v", err)
+	}
+	if _, err := s.AddSeries(ctx, "重复", "go-101", nil, nil); err == nil {
+		t.Error("AddSeries should reject duplicate slug")
+	}
+
+	part1, part2, part3 := f.Post("part-1", "content"), f.Post("part-2", "content"), f.Post("part-3", "content")
+	draft := f.Post("draft", "content")
+	database.Model(draft).Update("status", enum.PostStatusDraft)
+	hidden := f.Post("hidden", "content")
+	database.Model(hidden).Update("visible", enum.PostVisibleHidden)
+	order := []uint{part1.PostId, draft.PostId, part2.PostId, hidden.PostId, part3.PostId}
+
+	if _, err := s.UpdateSeriesPosts(ctx, series.SeriesId, []uint{part1.PostId, part1.PostId}); err == nil {
+		t.Error("UpdateSeriesPosts should reject duplicate posts")
+	}
+	if _, err := s.UpdateSeriesPosts(ctx, series.SeriesId, []uint{part1.PostId, 999}); err == nil {
+		t.Error("UpdateSeriesPosts should reject missing posts")
+	}
+	if _, err := s.UpdateSeriesPosts(ctx, 999, order); err == nil {
+		t.Error("UpdateSeriesPosts should reject missing series")
+	}
+	if ok, err := s.UpdateSeriesPosts(ctx, series.SeriesId, order); err != nil || !ok {
+		t.Fatalf("UpdateSeriesPosts = %v, %v", ok, err)
+	}
+
+	// 后台包含所有文章
+	posts, err := s.SeriesPosts(ctx, series.SeriesId)
+	if err != nil || len(posts) != 5 || posts[1].Slug != "draft" || posts[4].Slug != "part-3" {
+		t.Errorf("SeriesPosts = %v, %v", postSlugs(posts), err)
+	}
+
+	// 博客前端只包含已发布、可见的文章
+	ret, err := s.ApiSeries(ctx, "go-101")
+	if err != nil || ret == nil {
+		t.Fatalf("ApiSeries = %v, %v", ret, err)
+	}
+	var slugs []string
+	for _, post := range ret.Posts {
+		slugs = append(slugs, post.Slug)
+	}
+	if !slices.Equal(slugs, []string{"part-1", "part-2", "part-3"}) {
+		t.Errorf("ApiSeries posts = %v", slugs)
+	}
+	if ret.Series.PostCount != 3 {
+		t.Errorf("PostCount = %d, want 3", ret.Series.PostCount)
+	}
+	if ret, err := s.ApiSeries(ctx, "missing"); ret != nil || err != nil {
+		t.Errorf("ApiSeries(missing) = %v, %v", ret, err)
+	}
+
+	link := func(post *models.Post) *response.PostLinkResponse {
+		if post == nil {
+			return nil
+		}
+		return &response.PostLinkResponse{PostId: post.PostId, Title: post.Title, Slug: post.Slug}
+	}
+	tests := []struct {
+		name         string
+		post         *models.Post
+		index, total int
+		prev, next   *models.Post
+	}{
+		{"第一篇", part1, 1, 3, nil, part2},
+		{"跳过草稿和隐藏文章", part2, 2, 3, part1, part3},
+		{"最后一篇", part3, 3, 3, part2, nil},
+		{"隐藏文章包含自身", hidden, 3, 4, part2, part3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nav, err := s.PostSeries(ctx, tt.post.PostId)
+			if err != nil || nav == nil {
+				t.Fatalf("PostSeries = %v, %v", nav, err)
+			}
+			if nav.Series.Slug != "go-101" || nav.Index != tt.index || nav.Total != tt.total {
+				t.Errorf("nav = %d/%d, want %d/%d", nav.Index, nav.Total, tt.index, tt.total)
+			}
+			if !samePostLink(nav.Prev, link(tt.prev)) || !samePostLink(nav.Next, link(tt.next)) {
+				t.Errorf("prev = %+v, next = %+v", nav.Prev, nav.Next)
+			}
+		})
+	}
+
+	other := f.Post("other", "content")
+	if nav, err := s.PostSeries(ctx, other.PostId); nav != nil || err != nil {
+		t.Errorf("PostSeries(other) = %v, %v", nav, err)
+	}
+}
+
+// postSlugs 获取文章别名数组
+func postSlugs(posts []*response.PostResponse) []string {
+	slugs := make([]string, len(posts))
+	for i, post := range posts {
+		slugs[i] = post.Slug
+	}
+	return slugs
+}
+
+// samePostLink 判断文章链接是否相同
+func samePostLink(a, b *response.PostLinkResponse) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
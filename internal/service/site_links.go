@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"nola-go/internal/models"
+	"nola-go/internal/util"
+	"strings"
+)
+
+// 博客前端页面默认路径
+const (
+	defaultPostURLPattern     = "/post/{slug}"
+	defaultTagURLPattern      = "/tag/{slug}"
+	defaultCategoryURLPattern = "/category/{slug}"
+)
+
+// siteLinks 生成博客前端页面的绝对链接，页面路径在博客信息中配置
+type siteLinks struct {
+	// base 博客前端地址，不包含末尾的 /
+	base            string
+	postPattern     string
+	tagPattern      string
+	categoryPattern string
+}
+
+// newSiteLinks 创建博客前端页面链接生成器
+//   - blogInfo: 博客信息，可以为 nil
+//   - origin: 请求的地址（如 https://example.com），博客信息中没有设置前端地址时使用
+func newSiteLinks(blogInfo *models.BlogInfo, origin string) *siteLinks {
+	if blogInfo == nil {
+		blogInfo = &models.BlogInfo{}
+	}
+	return &siteLinks{
+		base:            strings.TrimRight(stringOrDefault(blogInfo.SiteURL, origin), "/"),
+		postPattern:     stringOrDefault(blogInfo.PostURLPattern, defaultPostURLPattern),
+		tagPattern:      stringOrDefault(blogInfo.TagURLPattern, defaultTagURLPattern),
+		categoryPattern: stringOrDefault(blogInfo.CategoryURLPattern, defaultCategoryURLPattern),
+	}
+}
+
+// stringOrDefault 字符串为 nil 或空白时返回默认值
+func stringOrDefault(s *string, defaultValue string) string {
+	if util.StringIsNilOrBlank(s) {
+		return defaultValue
+	}
+	return strings.TrimSpace(*s)
+}
+
+// Home 博客首页
+func (l *siteLinks) Home() string {
+	return l.base + "/"
+}
+
+// URL 将站点内的路径转为绝对链接
+func (l *siteLinks) URL(path string) string {
+	return l.base + "/" + strings.TrimLeft(path, "/")
+}
+
+// Post 文章页面
+func (l *siteLinks) Post(slug string) string {
+	return l.page(l.postPattern, slug)
+}
+
+// Tag 标签页面
+func (l *siteLinks) Tag(slug string) string {
+	return l.page(l.tagPattern, slug)
+}
+
+// Category 分类页面
+func (l *siteLinks) Category(slug string) string {
+	return l.page(l.categoryPattern, slug)
+}
+
+// page 将页面路径中的 {slug} 替换为别名后转为绝对链接
+func (l *siteLinks) page(pattern string, slug string) string {
+	return l.URL(strings.ReplaceAll(pattern, "{slug}", url.PathEscape(slug)))
+}
+
+// checkSiteLinks 检查博客信息中的前端地址和页面路径
+func checkSiteLinks(blogInfo *models.BlogInfo) error {
+	if !util.StringIsNilOrBlank(blogInfo.SiteURL) {
+		u, err := url.Parse(*blogInfo.SiteURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("博客前端地址 [" + *blogInfo.SiteURL + "] 不是有效的 http(s) 地址")
+		}
+	}
+
+	for _, pattern := range []*string{blogInfo.PostURLPattern, blogInfo.TagURLPattern, blogInfo.CategoryURLPattern} {
+		if util.StringIsNilOrBlank(pattern) {
+			continue
+		}
+		if !strings.HasPrefix(*pattern, "/") || !strings.Contains(*pattern, "{slug}") {
+			return errors.New("页面路径 [" + *pattern + "] 需要以 / 开头并包含 {slug}")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"nola-go/internal/logger"
+	"nola-go/internal/models/response"
+	"nola-go/internal/repository"
+	"nola-go/internal/sitemap"
+	"nola-go/internal/util"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SitemapService 站点地图和 robots.txt Service
+type SitemapService struct {
+	postRepo        repository.PostRepository
+	tagService      *TagService
+	categoryService *CategoryService
+	configService   *ConfigService
+	// maxURLs 单个站点地图最多包含的网址数量
+	maxURLs int
+}
+
+// NewSitemapService 创建站点地图 Service
+func NewSitemapService(
+	postRepo repository.PostRepository,
+	tsv *TagService,
+	casv *CategoryService,
+	csv *ConfigService,
+) *SitemapService {
+	return &SitemapService{
+		postRepo:        postRepo,
+		tagService:      tsv,
+		categoryService: casv,
+		configService:   csv,
+		maxURLs:         sitemap.MaxURLs,
+	}
+}
+
+// Sitemap 获取站点地图，包含首页、公开文章（已发布、可见、未加密）、分类和标签页面
+// 网址数量超过单个站点地图的上限时拆分为多个站点地图，page 为 0 时返回站点地图索引
+//
+// Parameters:
+//   - ctx: 上下文
+//   - origin: 请求的地址（如 https://example.com），博客信息中没有设置前端地址时使用
+//   - page: 站点地图序号（从 1 开始），为 0 时返回完整的站点地图或站点地图索引
+//
+// Returns:
+//   - *sitemap.Document: 站点地图，序号超出范围时返回 nil
+func (s *SitemapService) Sitemap(ctx context.Context, origin string, page int) (*sitemap.Document, error) {
+	blogInfo, err := s.configService.BlogInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	links := newSiteLinks(blogInfo, origin)
+
+	urls, err := s.urls(ctx, links)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := util.Chunk(urls, s.maxURLs)
+	switch {
+	case page == 0 && len(chunks) <= 1:
+		return &sitemap.Document{URLs: urls}, nil
+	case page == 0:
+		index := &sitemap.Document{Index: true}
+		for i, chunk := range chunks {
+			index.URLs = append(index.URLs, &sitemap.URL{
+				Loc:     links.URL(fmt.Sprintf("/sitemap/%d.xml", i+1)),
+				LastMod: (&sitemap.Document{URLs: chunk}).LastModified(),
+			})
+		}
+		return index, nil
+	case page > len(chunks):
+		return nil, nil
+	default:
+		return &sitemap.Document{URLs: chunks[page-1]}, nil
+	}
+}
+
+// RobotsTxt 获取 robots.txt，没有设置时允许抓取所有页面
+// 内容中没有声明站点地图时自动添加
+//   - origin: 请求的地址，博客信息中没有设置前端地址时使用
+func (s *SitemapService) RobotsTxt(ctx context.Context, origin string) (string, error) {
+	content, err := s.configService.RobotsTxt(ctx)
+	if err != nil {
+		return "", err
+	}
+	blogInfo, err := s.configService.BlogInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	robots := strings.TrimSpace(util.StringDefault(content, ""))
+	if robots == "" {
+		robots = "User-agent: *\nAllow: /"
+	}
+	if !strings.Contains(strings.ToLower(robots), "sitemap:") {
+		robots += "\n\nSitemap: " + newSiteLinks(blogInfo, origin).URL("/sitemap.xml")
+	}
+	return robots + "\n", nil
+}
+
+// urls 获取站点地图中的所有网址
+func (s *SitemapService) urls(ctx context.Context, links *siteLinks) ([]*sitemap.URL, error) {
+	posts, err := s.postRepo.PublicPostLinks(ctx)
+	if err != nil {
+		logger.Log.Error("获取公开文章失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	categories, err := s.categoryService.Categories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagService.Tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	home := &sitemap.URL{Loc: links.Home()}
+	urls := make([]*sitemap.URL, 0, len(posts)+len(categories)+len(tags)+1)
+	urls = append(urls, home)
+
+	for _, post := range posts {
+		modified := post.CreateTime
+		if post.LastModifyTime != nil && *post.LastModifyTime > modified {
+			modified = *post.LastModifyTime
+		}
+		u := &sitemap.URL{Loc: links.Post(post.Slug), LastMod: time.UnixMilli(modified)}
+		if u.LastMod.After(home.LastMod) {
+			// 首页的修改时间为最新的文章修改时间
+			home.LastMod = u.LastMod
+		}
+		urls = append(urls, u)
+	}
+	for _, category := range categories {
+		urls = append(urls, &sitemap.URL{Loc: links.Category(category.Slug)})
+	}
+	for _, tag := range tags {
+		urls = append(urls, &sitemap.URL{Loc: links.Tag(tag.Slug)})
+	}
+	return urls, nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/repository"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"strings"
+	"testing"
+)
+
+func TestSitemapService(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	f := testutil.NewFixture(t, database)
+	tagRepo := repository.NewTagRepository(database)
+	categoryRepo := repository.NewCategoryRepository(database)
+	postRepo := repository.NewPostRepository(database, tagRepo, categoryRepo, repository.NewUserRepository(database), testutil.NewHasher(t))
+	configService := NewConfigService(repository.NewConfigRepository(database), newTestAuditService(database))
+	s := NewSitemapService(postRepo, NewTagService(tagRepo), NewCategoryService(categoryRepo), configService)
+
+	f.Category("golang")
+	f.Tag("gin")
+	old := f.Post("old", "content")
+	latest := f.Post("latest", "content")
+	modified := f.Now()
+	database.Model(old).Update("last_modify_time", modified)
+	hidden := f.Post("hidden", "content")
+	database.Model(hidden).Update("visible", enum.PostVisibleHidden)
+	encrypted := f.Post("encrypted", "content")
+	database.Model(encrypted).Update("password", "hash")
+	noIndex := f.Post("noindex", "content")
+	database.Model(noIndex).Update("no_index", true)
+
+	locs := func(doc interface{ Render() ([]byte, error) }) string {
+		t.Helper()
+		body, err := doc.Render()
+		if err != nil {
+			t.Fatalf("Render: %v", err)
+		}
+		return string(body)
+	}
+
+	doc, err := s.Sitemap(ctx, "http://localhost:8098", 0)
+	if err != nil {
+		t.Fatalf("Sitemap: %v", err)
+	}
+	if doc.Index || len(doc.URLs) != 5 {
+		t.Fatalf("Sitemap = %+v", doc.URLs)
+	}
+	body := locs(doc)
+	for _, want := range []string{
+		"<loc>http://localhost:8098/</loc>",
+		"<loc>http://localhost:8098/post/latest</loc>",
+		"<loc>http://localhost:8098/post/old</loc>",
+		"<loc>http://localhost:8098/category/golang</loc>",
+		"<loc>http://localhost:8098/tag/gin</loc>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("sitemap missing %s:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "hidden") || strings.Contains(body, "encrypted") {
+		t.Errorf("sitemap contains private posts:\n%s", body)
+	}
+	// 禁止索引的文章不放入站点地图
+	if strings.Contains(body, "/post/noindex") {
+		t.Errorf("sitemap contains noindex post:\n%s", body)
+	}
+	// 文章最后修改时间作为 lastmod，首页为最新的修改时间
+	if !doc.URLs[0].LastMod.Equal(doc.URLs[2].LastMod) || doc.URLs[2].LastMod.UnixMilli() != modified {
+		t.Errorf("lastmod = %v, %v", doc.URLs[0].LastMod, doc.URLs[2].LastMod)
+	}
+	if doc.URLs[1].LastMod.UnixMilli() != latest.CreateTime {
+		t.Errorf("latest lastmod = %v", doc.URLs[1].LastMod)
+	}
+
+	// 使用博客信息中的前端地址和页面路径，超过上限时拆分
+	if _, err := configService.SetBlogInfo(ctx, SystemOperator, &models.BlogInfo{
+		Title:          util.StringPtr("Nola"),
+		SiteURL:        util.StringPtr("https://blog.example.com"),
+		PostURLPattern: util.StringPtr("/{slug}.html"),
+	}); err != nil {
+		t.Fatalf("SetBlogInfo: %v", err)
+	}
+	s.maxURLs = 2
+
+	index, err := s.Sitemap(ctx, "http://localhost:8098", 0)
+	if err != nil || !index.Index || len(index.URLs) != 3 || index.URLs[2].Loc != "https://blog.example.com/sitemap/3.xml" {
+		t.Fatalf("Sitemap index = %+v, %v", index, err)
+	}
+	page, err := s.Sitemap(ctx, "", 1)
+	if err != nil || len(page.URLs) != 2 || page.URLs[1].Loc != "https://blog.example.com/latest.html" {
+		t.Errorf("Sitemap(1) = %+v, %v", page, err)
+	}
+	if page, err := s.Sitemap(ctx, "", 4); err != nil || page != nil {
+		t.Errorf("Sitemap(4) = %+v, %v", page, err)
+	}
+
+	// robots.txt
+	robots, err := s.RobotsTxt(ctx, "")
+	if err != nil || robots != "User-agent: *\nAllow: /\n\nSitemap: https://blog.example.com/sitemap.xml\n" {
+		t.Errorf("RobotsTxt = %q, %v", robots, err)
+	}
+	if _, err := configService.SetRobotsTxt(ctx, SystemOperator, "User-agent: *\nDisallow: /private\nSitemap: https://cdn.example.com/sitemap.xml"); err != nil {
+		t.Fatalf("SetRobotsTxt: %v", err)
+	}
+	if robots, _ := s.RobotsTxt(ctx, ""); robots != "User-agent: *\nDisallow: /private\nSitemap: https://cdn.example.com/sitemap.xml\n" {
+		t.Errorf("custom RobotsTxt = %q", robots)
+	}
+	if _, err := configService.SetRobotsTxt(ctx, SystemOperator, " "); err != nil {
+		t.Fatalf("SetRobotsTxt(reset): %v", err)
+	}
+	if robots, _ := s.RobotsTxt(ctx, ""); !strings.HasPrefix(robots, "User-agent: *\nAllow: /\n") {
+		t.Errorf("reset RobotsTxt = %q", robots)
+	}
+
+	// 无效的页面路径
+	if _, err := configService.SetBlogInfo(ctx, SystemOperator, &models.BlogInfo{TagURLPattern: util.StringPtr("/tag")}); err == nil {
+		t.Error("SetBlogInfo should reject pattern without {slug}")
+	}
+	if _, err := configService.SetBlogInfo(ctx, SystemOperator, &models.BlogInfo{SiteURL: util.StringPtr("example.com")}); err == nil {
+		t.Error("SetBlogInfo should reject relative site url")
+	}
+}
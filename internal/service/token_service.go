@@ -1,84 +1,459 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"nola-go/internal/config"
-	"sync"
+	"nola-go/internal/logger"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/models/response"
+	"nola-go/internal/repository"
+	"nola-go/internal/session"
+	"nola-go/internal/util"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
 )
 
-// TokenService 用于管理 Token 的签发与 userId -> token 的绑定验证
+// sessionTouchInterval 会话最后活跃时间的最小更新间隔，避免每个请求都写存储
+const sessionTouchInterval = time.Minute
+
+// defaultRefreshExpires 未配置时刷新令牌的默认有效期
+const defaultRefreshExpires = 30 * 24 * time.Hour
+
+// challengeExpires 两步验证挑战令牌的有效期
+const challengeExpires = 5 * time.Minute
+
+// challengePurpose 两步验证挑战令牌的用途声明，用于区分挑战令牌和访问令牌
+const challengePurpose = "2fa"
+
+// apiTokenPrefixLength 保存的 API 令牌前缀长度，用于在令牌列表中辨认令牌
+const apiTokenPrefixLength = len(util.ApiTokenPrefix) + 8
+
+var (
+	// ErrRefreshTokenInvalid 刷新令牌无效或会话已过期
+	ErrRefreshTokenInvalid = errors.New("登录已过期，请重新登录")
+	// ErrChallengeInvalid 两步验证挑战令牌无效或已过期
+	ErrChallengeInvalid = errors.New("验证已过期，请重新登录")
+)
+
+// TokenService 用于管理 Token 的签发与登录会话的绑定验证
+// 访问令牌（JWT）有效期较短，过期后使用刷新令牌换取新的访问令牌和刷新令牌；
+// 每个刷新令牌只能使用一次，已使用过的刷新令牌再次出现时视为泄露，注销整个会话。
+// 自动化场景使用长期有效的个人 API 令牌，API 令牌不绑定会话，只保存哈希，并限制权限范围。
 type TokenService struct {
-	secret   string
-	issuer   string
-	audience string
-	expires  time.Duration
-	lock     sync.RWMutex
-	tokenMap map[uint]string
+	secret         string
+	issuer         string
+	audience       string
+	expires        time.Duration
+	refreshExpires time.Duration
+	store          session.Store
+	apiTokenRepo   repository.ApiTokenRepository
+	userRepo       repository.UserRepository
 }
 
 // NewTokenService 创建 TokenService
-func NewTokenService(config config.JWTConfig) *TokenService {
+//   - config: JWT 配置
+//   - store: 会话存储
+//   - apiTokenRepo: API 令牌 Repo
+//   - userRepo: 用户 Repo，验证 API 令牌时获取用户当前角色
+func NewTokenService(
+	config config.JWTConfig,
+	store session.Store,
+	apiTokenRepo repository.ApiTokenRepository,
+	userRepo repository.UserRepository,
+) *TokenService {
+	refreshExpires := time.Hour * 24 * config.RefreshExpireDays
+	if refreshExpires <= 0 {
+		refreshExpires = defaultRefreshExpires
+	}
+
 	return &TokenService{
 		secret:   config.Secret,
 		issuer:   config.Issuer,
 		audience: config.Audience,
 		// 分钟
-		expires:  time.Minute * config.ExpireMinutes,
-		tokenMap: make(map[uint]string),
+		expires:        time.Minute * config.ExpireMinutes,
+		refreshExpires: refreshExpires,
+		store:          store,
+		apiTokenRepo:   apiTokenRepo,
+		userRepo:       userRepo,
 	}
 }
 
-// Generate 为指定用户签发 JWT，并把签发的 Token 与 userId 绑定
+// Generate 为指定用户创建登录会话，并签发访问令牌和刷新令牌，同一用户可以同时存在多个会话
+//   - ctx: 上下文
 //   - userId: 用户 ID
 //   - username: 用户名
-//   - extra: 令牌额外附带信息
-func (s *TokenService) Generate(userId uint, username string, extra map[string]string) (string, error) {
+//   - role: 用户角色
+//   - userAgent: 登录设备的浏览器标识
+//   - ip: 登录设备的 IP 地址
+func (s *TokenService) Generate(
+	ctx context.Context,
+	userId uint,
+	username string,
+	role enum.UserRole,
+	userAgent, ip string,
+) (*response.TokenResponse, error) {
 	now := time.Now()
-	exp := now.Add(s.expires)
 
-	claims := jwt.MapClaims{
-		"aud":      s.audience,
-		"iss":      s.issuer,
-		"iat":      now.Unix(),
-		"exp":      exp.Unix(),
-		"user_id":  userId,
-		"username": username,
+	sessionId, err := session.NewId()
+	if err != nil {
+		return nil, err
 	}
-	for k, v := range extra {
-		claims[k] = v
+
+	refreshToken, refreshHash, err := session.NewRefreshToken(sessionId)
+	if err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	ss, err := token.SignedString([]byte(s.secret))
+	token, exp, err := s.sign(userId, username, role, sessionId, now)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// 将 token 与 userId 绑定（同时使可能存在的旧 Token 过期）
-	s.lock.Lock()
-	s.tokenMap[userId] = ss
-	s.lock.Unlock()
+	// 保存会话，Token 只有在会话存在时才有效
+	err = s.store.Save(ctx, &session.Session{
+		SessionId:        sessionId,
+		UserId:           userId,
+		Username:         username,
+		Role:             string(role),
+		RefreshTokenHash: refreshHash,
+		UserAgent:        userAgent,
+		IP:               ip,
+		CreateTime:       now.UnixMilli(),
+		LastActiveTime:   now.UnixMilli(),
+		ExpireTime:       now.Add(s.refreshExpires).UnixMilli(),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return ss, nil
+	return &response.TokenResponse{
+		Token:           token,
+		TokenExpireTime: exp.UnixMilli(),
+		RefreshToken:    refreshToken,
+	}, nil
 }
 
-// Verify 验证用户 ID 与 Token 是否匹配
+// Refresh 使用刷新令牌换取新的访问令牌和刷新令牌，并顺延会话过期时间
+// 旧的刷新令牌立即失效；如果已失效的刷新令牌被再次使用，说明令牌可能已经泄露，注销该会话
+//   - ctx: 上下文
+//   - refreshToken: 刷新令牌
+func (s *TokenService) Refresh(ctx context.Context, refreshToken string) (*response.TokenResponse, error) {
+	sessionId, ok := session.ParseRefreshToken(refreshToken)
+	if !ok {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	stored, err := s.store.Get(ctx, sessionId)
+	if err != nil {
+		logger.Log.Error("获取登录会话失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	if stored == nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	now := time.Now()
+	newToken, newHash, err := session.NewRefreshToken(sessionId)
+	if err != nil {
+		logger.Log.Error("生成刷新令牌失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	oldHash := session.HashRefreshToken(refreshToken)
+	rotated, err := s.store.Rotate(ctx, sessionId, oldHash, newHash, now.UnixMilli(), now.Add(s.refreshExpires).UnixMilli())
+	if err != nil {
+		logger.Log.Error("轮换刷新令牌失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	if !rotated {
+		// 刷新令牌不是会话当前的令牌，已被使用过，注销整个会话
+		logger.Log.Warn("检测到刷新令牌重复使用，已注销会话",
+			zap.Uint("userId", stored.UserId), zap.String("sessionId", sessionId))
+		if err := s.store.Delete(ctx, sessionId); err != nil {
+			logger.Log.Error("注销登录会话失败", zap.Error(err))
+		}
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	token, exp, err := s.sign(stored.UserId, stored.Username, enum.UserRole(stored.Role), sessionId, now)
+	if err != nil {
+		logger.Log.Error("签发访问令牌失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	return &response.TokenResponse{
+		Token:           token,
+		TokenExpireTime: exp.UnixMilli(),
+		RefreshToken:    newToken,
+	}, nil
+}
+
+// Verify 验证会话是否存在并且属于该用户，同时刷新会话的最后活跃时间
+//   - ctx: 上下文
 //   - userId: 用户 ID
-//   - token: Token
-func (s *TokenService) Verify(userId uint, token string) bool {
-	s.lock.RLock()
-	defer s.lock.RUnlock()
-	if token == "" {
+//   - sessionId: 会话 ID
+func (s *TokenService) Verify(ctx context.Context, userId uint, sessionId string) bool {
+	if sessionId == "" {
 		return false
 	}
-	stored, ok := s.tokenMap[userId]
-	if !ok {
+
+	stored, err := s.store.Get(ctx, sessionId)
+	if err != nil {
+		logger.Log.Error("获取登录会话失败", zap.Error(err))
+		return false
+	}
+	if stored == nil || stored.UserId != userId {
 		return false
 	}
-	return stored == token
+
+	now := time.Now().UnixMilli()
+	if now-stored.LastActiveTime >= sessionTouchInterval.Milliseconds() {
+		if err := s.store.Touch(ctx, sessionId, now); err != nil {
+			// 只影响会话列表中的活跃时间，不影响本次请求
+			logger.Log.Warn("更新登录会话活跃时间失败", zap.Error(err))
+		}
+	}
+	return true
+}
+
+// Sessions 获取用户的所有登录会话
+//   - ctx: 上下文
+//   - userId: 用户 ID
+//   - currentSessionId: 当前请求所属的会话 ID
+func (s *TokenService) Sessions(
+	ctx context.Context,
+	userId uint,
+	currentSessionId string,
+) ([]*response.SessionResponse, error) {
+	sessions, err := s.store.ListByUser(ctx, userId)
+	if err != nil {
+		logger.Log.Error("获取登录会话失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	ret := make([]*response.SessionResponse, len(sessions))
+	for i, item := range sessions {
+		ret[i] = &response.SessionResponse{
+			SessionId:      item.SessionId,
+			UserAgent:      item.UserAgent,
+			IP:             item.IP,
+			CreateTime:     item.CreateTime,
+			LastActiveTime: item.LastActiveTime,
+			ExpireTime:     item.ExpireTime,
+			Current:        item.SessionId == currentSessionId,
+		}
+	}
+	return ret, nil
+}
+
+// RevokeSession 注销用户的指定会话，会话不属于该用户时返回 false
+//   - ctx: 上下文
+//   - userId: 用户 ID
+//   - sessionId: 会话 ID
+func (s *TokenService) RevokeSession(ctx context.Context, userId uint, sessionId string) (bool, error) {
+	stored, err := s.store.Get(ctx, sessionId)
+	if err != nil {
+		logger.Log.Error("获取登录会话失败", zap.Error(err))
+		return false, response.ServerError
+	}
+	if stored == nil || stored.UserId != userId {
+		return false, nil
+	}
+
+	if err := s.store.Delete(ctx, sessionId); err != nil {
+		logger.Log.Error("注销登录会话失败", zap.Error(err))
+		return false, response.ServerError
+	}
+	return true, nil
+}
+
+// RevokeUserSessions 注销用户的所有会话
+//   - ctx: 上下文
+//   - userId: 用户 ID
+func (s *TokenService) RevokeUserSessions(ctx context.Context, userId uint) error {
+	if err := s.store.DeleteByUser(ctx, userId); err != nil {
+		logger.Log.Error("注销用户所有登录会话失败", zap.Error(err))
+		return response.ServerError
+	}
+	return nil
+}
+
+// CreateApiToken 为用户创建 API 令牌，令牌明文只在创建时返回一次
+// 权限范围需要用户角色拥有其中的权限，使用令牌时仍以用户当前角色为准
+//   - ctx: 上下文
+//   - operator: 创建令牌的登录用户
+//   - req: 创建 API 令牌请求
+func (s *TokenService) CreateApiToken(
+	ctx context.Context,
+	operator *Operator,
+	req *request.ApiTokenRequest,
+) (*response.ApiTokenResponse, error) {
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		allowed := false
+		for _, permission := range scope.Permissions() {
+			if operator.Role.Can(permission) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, errors.New("当前角色不能授予权限范围 [" + string(scope) + "]")
+		}
+		if !slices.Contains(scopes, string(scope)) {
+			scopes = append(scopes, string(scope))
+		}
+	}
+
+	token, err := util.GenerateApiToken()
+	if err != nil {
+		logger.Log.Error("生成 API 令牌失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	now := time.Now()
+	apiToken := &models.ApiToken{
+		UserId:      operator.UserId,
+		Name:        req.Name,
+		TokenHash:   util.GenerateHash(token),
+		TokenPrefix: token[:apiTokenPrefixLength],
+		Scopes:      strings.Join(scopes, ","),
+		CreateTime:  now.UnixMilli(),
+	}
+	if req.ExpireDays != nil {
+		apiToken.ExpireTime = util.Int64Ptr(now.AddDate(0, 0, *req.ExpireDays).UnixMilli())
+	}
+
+	if err := s.apiTokenRepo.Create(ctx, apiToken); err != nil {
+		logger.Log.Error("添加 API 令牌失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+
+	ret := response.NewApiTokenResponse(apiToken)
+	ret.Token = &token
+	return ret, nil
+}
+
+// ApiTokens 获取用户的所有 API 令牌
+//   - ctx: 上下文
+//   - userId: 用户 ID
+func (s *TokenService) ApiTokens(ctx context.Context, userId uint) ([]*response.ApiTokenResponse, error) {
+	tokens, err := s.apiTokenRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		logger.Log.Error("获取 API 令牌失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	return util.Map(tokens, response.NewApiTokenResponse), nil
+}
+
+// RevokeApiToken 删除用户的 API 令牌，令牌不属于该用户时返回 false
+//   - ctx: 上下文
+//   - userId: 用户 ID
+//   - tokenId: 令牌 ID
+func (s *TokenService) RevokeApiToken(ctx context.Context, userId uint, tokenId uint) (bool, error) {
+	ret, err := s.apiTokenRepo.Delete(ctx, userId, tokenId)
+	if err != nil {
+		logger.Log.Error("删除 API 令牌失败", zap.Error(err))
+		return false, response.ServerError
+	}
+	return ret, nil
+}
+
+// VerifyApiToken 验证 API 令牌，同时记录令牌的最后使用时间和 IP 地址
+// 令牌无效、已过期或所属用户已删除时返回 nil
+//   - ctx: 上下文
+//   - token: API 令牌
+//   - ip: 请求的 IP 地址
+func (s *TokenService) VerifyApiToken(ctx context.Context, token string, ip string) *Operator {
+	if !util.IsApiToken(token) {
+		return nil
+	}
+
+	apiToken, err := s.apiTokenRepo.GetByHash(ctx, util.GenerateHash(token))
+	if err != nil {
+		logger.Log.Error("获取 API 令牌失败", zap.Error(err))
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	if apiToken == nil || (apiToken.ExpireTime != nil && *apiToken.ExpireTime <= now) {
+		return nil
+	}
+
+	// 使用用户当前角色，角色修改后立即生效
+	user, err := s.userRepo.GetById(ctx, apiToken.UserId)
+	if err != nil {
+		logger.Log.Error("获取用户失败 - Id", zap.Error(err))
+		return nil
+	}
+	if user == nil {
+		return nil
+	}
+
+	if apiToken.LastUsedTime == nil || now-*apiToken.LastUsedTime >= sessionTouchInterval.Milliseconds() {
+		if err := s.apiTokenRepo.UpdateLastUsed(ctx, apiToken.TokenId, now, ip); err != nil {
+			// 只影响令牌列表中的最后使用时间，不影响本次请求
+			logger.Log.Warn("更新 API 令牌最后使用时间失败", zap.Error(err))
+		}
+	}
+
+	return &Operator{
+		UserId:   user.UserId,
+		Username: user.Username,
+		Role:     user.Role,
+		Scopes:   enum.ParseApiTokenScopes(apiToken.Scopes),
+		Ip:       ip,
+	}
+}
+
+// SignChallenge 签发两步验证挑战令牌，返回令牌和过期时间戳毫秒
+// 挑战令牌只证明密码验证已经通过，不绑定会话，不能作为访问令牌使用
+//   - userId: 用户 ID
+func (s *TokenService) SignChallenge(userId uint) (string, int64, error) {
+	now := time.Now()
+	exp := now.Add(challengeExpires)
+	claims := jwt.MapClaims{
+		"aud":     s.audience,
+		"iss":     s.issuer,
+		"iat":     now.Unix(),
+		"exp":     exp.Unix(),
+		"user_id": userId,
+		"purpose": challengePurpose,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	ss, err := token.SignedString([]byte(s.secret))
+	if err != nil {
+		return "", 0, err
+	}
+	return ss, exp.UnixMilli(), nil
+}
+
+// ParseChallenge 解析两步验证挑战令牌，返回用户 ID
+//   - challenge: 挑战令牌
+func (s *TokenService) ParseChallenge(challenge string) (uint, error) {
+	claims, err := s.ParseAndValidate(challenge)
+	if err != nil {
+		return 0, ErrChallengeInvalid
+	}
+
+	if purpose, _ := claims["purpose"].(string); purpose != challengePurpose {
+		return 0, ErrChallengeInvalid
+	}
+
+	userId, ok := claims["user_id"].(float64)
+	if !ok || userId <= 0 {
+		return 0, ErrChallengeInvalid
+	}
+	return uint(userId), nil
 }
 
 // ParseAndValidate 解析 Token 并验证 Audience 和 Issuer
@@ -127,3 +502,36 @@ func (s *TokenService) ParseAndValidate(tokenString string) (jwt.MapClaims, erro
 	}
 	return claims, nil
 }
+
+// sign 签发访问令牌，返回令牌和过期时间
+//   - userId: 用户 ID
+//   - username: 用户名
+//   - role: 用户角色
+//   - sessionId: 会话 ID
+//   - now: 签发时间
+func (s *TokenService) sign(
+	userId uint,
+	username string,
+	role enum.UserRole,
+	sessionId string,
+	now time.Time,
+) (string, time.Time, error) {
+	exp := now.Add(s.expires)
+	claims := jwt.MapClaims{
+		"aud":      s.audience,
+		"iss":      s.issuer,
+		"iat":      now.Unix(),
+		"exp":      exp.Unix(),
+		"user_id":  userId,
+		"username": username,
+		"role":     string(role),
+		"sid":      sessionId,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	ss, err := token.SignedString([]byte(s.secret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return ss, exp, nil
+}
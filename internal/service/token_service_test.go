@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/config"
+	"nola-go/internal/logger"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/repository"
+	"nola-go/internal/session"
+	"nola-go/internal/testutil"
+	"nola-go/internal/util"
+	"testing"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// newTestTokenService 创建使用内存会话存储的 TokenService
+//   - database: API 令牌和用户所在的数据库
+func newTestTokenService(t *testing.T, database *gorm.DB) *TokenService {
+	t.Helper()
+	if logger.Log == nil {
+		logger.Log = zap.NewNop()
+	}
+	return NewTokenService(config.JWTConfig{
+		Secret:        "secret",
+		Issuer:        "nola",
+		Audience:      "nola",
+		ExpireMinutes: 15,
+	}, session.NewMemoryStore(), repository.NewApiTokenRepository(database), repository.NewUserRepository(database))
+}
+
+// sessionIdOf 解析访问令牌中的会话 ID
+func sessionIdOf(t *testing.T, s *TokenService, token string) string {
+	t.Helper()
+	claims, err := s.ParseAndValidate(token)
+	if err != nil {
+		t.Fatalf("ParseAndValidate: %v", err)
+	}
+	sid, _ := claims["sid"].(string)
+	return sid
+}
+
+func TestTokenService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	s := newTestTokenService(t, testutil.NewDB(t))
+
+	login, err := s.Generate(ctx, 1, "admin", enum.UserRoleOwner, "ua", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	sid := sessionIdOf(t, s, login.Token)
+	if !s.Verify(ctx, 1, sid) {
+		t.Fatal("Verify after login should succeed")
+	}
+	if s.Verify(ctx, 2, sid) {
+		t.Fatal("Verify with another user should fail")
+	}
+
+	refreshed, err := s.Refresh(ctx, login.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if refreshed.RefreshToken == login.RefreshToken || sessionIdOf(t, s, refreshed.Token) != sid {
+		t.Fatalf("Refresh should rotate token within the same session: %+v", refreshed)
+	}
+	if claims, _ := s.ParseAndValidate(refreshed.Token); claims["role"] != string(enum.UserRoleOwner) {
+		t.Errorf("refreshed token role = %v, want %s", claims["role"], enum.UserRoleOwner)
+	}
+
+	// 再次使用已轮换的刷新令牌，注销整个会话
+	if _, err := s.Refresh(ctx, login.RefreshToken); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("Refresh with reused token err = %v, want ErrRefreshTokenInvalid", err)
+	}
+	if s.Verify(ctx, 1, sid) {
+		t.Error("session should be revoked after refresh token reuse")
+	}
+	if _, err := s.Refresh(ctx, refreshed.RefreshToken); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("Refresh with latest token after reuse err = %v, want ErrRefreshTokenInvalid", err)
+	}
+
+	invalidTests := []string{"", "no-dot", "missing.secret"}
+	for _, token := range invalidTests {
+		if _, err := s.Refresh(ctx, token); !errors.Is(err, ErrRefreshTokenInvalid) {
+			t.Errorf("Refresh(%q) err = %v, want ErrRefreshTokenInvalid", token, err)
+		}
+	}
+}
+
+func TestTokenService_Sessions(t *testing.T) {
+	ctx := context.Background()
+	s := newTestTokenService(t, testutil.NewDB(t))
+
+	desktop, _ := s.Generate(ctx, 1, "admin", enum.UserRoleOwner, "desktop", "10.0.0.1")
+	phone, _ := s.Generate(ctx, 1, "admin", enum.UserRoleOwner, "phone", "10.0.0.2")
+	other, _ := s.Generate(ctx, 2, "editor", enum.UserRoleEditor, "other", "10.0.0.3")
+	desktopId, phoneId := sessionIdOf(t, s, desktop.Token), sessionIdOf(t, s, phone.Token)
+
+	sessions, err := s.Sessions(ctx, 1, desktopId)
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("Sessions = %v, %v", sessions, err)
+	}
+	for _, item := range sessions {
+		if item.Current != (item.SessionId == desktopId) {
+			t.Errorf("session %s Current = %v", item.UserAgent, item.Current)
+		}
+	}
+
+	// 不能注销其他用户的会话
+	if ok, err := s.RevokeSession(ctx, 1, sessionIdOf(t, s, other.Token)); ok || err != nil {
+		t.Errorf("RevokeSession(other user) = %v, %v", ok, err)
+	}
+	if ok, err := s.RevokeSession(ctx, 1, phoneId); !ok || err != nil {
+		t.Fatalf("RevokeSession = %v, %v", ok, err)
+	}
+	if s.Verify(ctx, 1, phoneId) || !s.Verify(ctx, 1, desktopId) {
+		t.Error("only the revoked session should be invalid")
+	}
+
+	if err := s.RevokeUserSessions(ctx, 1); err != nil {
+		t.Fatalf("RevokeUserSessions: %v", err)
+	}
+	if s.Verify(ctx, 1, desktopId) {
+		t.Error("all sessions of user should be revoked")
+	}
+	if !s.Verify(ctx, 2, sessionIdOf(t, s, other.Token)) {
+		t.Error("sessions of other users should be kept")
+	}
+}
+
+func TestTokenService_Challenge(t *testing.T) {
+	ctx := context.Background()
+	s := newTestTokenService(t, testutil.NewDB(t))
+
+	challenge, _, err := s.SignChallenge(7)
+	if err != nil {
+		t.Fatalf("SignChallenge: %v", err)
+	}
+	if userId, err := s.ParseChallenge(challenge); err != nil || userId != 7 {
+		t.Fatalf("ParseChallenge = %d, %v", userId, err)
+	}
+
+	// 挑战令牌没有绑定会话，不能作为访问令牌使用
+	if s.Verify(ctx, 7, sessionIdOf(t, s, challenge)) {
+		t.Error("challenge should not be accepted as access token")
+	}
+
+	// 访问令牌不能作为挑战令牌使用
+	token, err := s.Generate(ctx, 7, "admin", enum.UserRoleOwner, "ua", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, err := s.ParseChallenge(token.Token); !errors.Is(err, ErrChallengeInvalid) {
+		t.Errorf("ParseChallenge(access token) err = %v, want ErrChallengeInvalid", err)
+	}
+}
+
+func TestTokenService_ApiToken(t *testing.T) {
+	ctx := context.Background()
+	database := testutil.NewDB(t)
+	s := newTestTokenService(t, database)
+
+	author := &models.User{Username: "author", Email: "a@a.com", DisplayName: "Author", Password: "x", Role: enum.UserRoleAuthor, CreateDate: 1}
+	if err := repository.NewUserRepository(database).Create(ctx, author); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	operator := &Operator{UserId: author.UserId, Role: author.Role}
+
+	// 作者没有审核评论权限，不能授予该权限范围
+	if _, err := s.CreateApiToken(ctx, operator, &request.ApiTokenRequest{
+		Name: "ci", Scopes: []enum.ApiTokenScope{enum.ApiTokenScopeCommentModerate},
+	}); err == nil {
+		t.Error("CreateApiToken with scope beyond role should fail")
+	}
+
+	created, err := s.CreateApiToken(ctx, operator, &request.ApiTokenRequest{
+		Name: "ci", Scopes: []enum.ApiTokenScope{enum.ApiTokenScopePostWrite, enum.ApiTokenScopePostWrite},
+	})
+	if err != nil || created.Token == nil || !util.IsApiToken(*created.Token) || len(created.Scopes) != 1 {
+		t.Fatalf("CreateApiToken = %+v, %v", created, err)
+	}
+	expired, _ := s.CreateApiToken(ctx, operator, &request.ApiTokenRequest{
+		Name: "expired", Scopes: []enum.ApiTokenScope{enum.ApiTokenScopeFileUpload}, ExpireDays: util.IntPtr(1),
+	})
+	database.Model(&models.ApiToken{}).Where("token_id = ?", expired.TokenId).Update("expire_time", 1)
+
+	got := s.VerifyApiToken(ctx, *created.Token, "10.0.0.1")
+	if got == nil || got.UserId != author.UserId {
+		t.Fatalf("VerifyApiToken = %+v", got)
+	}
+	if !got.Can(enum.PermissionPublishPost) || got.Can(enum.PermissionUploadFile) {
+		t.Errorf("VerifyApiToken permissions = %+v", got)
+	}
+
+	invalidTests := []struct {
+		name  string
+		token string
+	}{
+		{"空令牌", ""},
+		{"JWT", "header.payload.signature"},
+		{"不存在", util.ApiTokenPrefix + "missing"},
+		{"已过期", *expired.Token},
+	}
+	for _, tt := range invalidTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.VerifyApiToken(ctx, tt.token, "10.0.0.1"); got != nil {
+				t.Errorf("VerifyApiToken(%q) = %+v, want nil", tt.token, got)
+			}
+		})
+	}
+
+	tokens, err := s.ApiTokens(ctx, author.UserId)
+	if err != nil || len(tokens) != 2 {
+		t.Fatalf("ApiTokens = %v, %v", tokens, err)
+	}
+	for _, item := range tokens {
+		if item.Token != nil {
+			t.Error("ApiTokens should not return plain token")
+		}
+		if item.TokenId == created.TokenId && (item.LastUsedIp == nil || *item.LastUsedIp != "10.0.0.1") {
+			t.Errorf("LastUsedIp = %v, want 10.0.0.1", item.LastUsedIp)
+		}
+	}
+
+	// 令牌使用用户当前角色，降级为投稿者后不能再发布文章
+	database.Model(&models.User{}).Where("user_id = ?", author.UserId).Update("role", enum.UserRoleContributor)
+	if got := s.VerifyApiToken(ctx, *created.Token, "10.0.0.1"); got == nil || got.Can(enum.PermissionPublishPost) {
+		t.Errorf("VerifyApiToken after role change = %+v", got)
+	}
+
+	if ok, _ := s.RevokeApiToken(ctx, author.UserId+1, created.TokenId); ok {
+		t.Error("RevokeApiToken of other user should fail")
+	}
+	if ok, err := s.RevokeApiToken(ctx, author.UserId, created.TokenId); err != nil || !ok {
+		t.Fatalf("RevokeApiToken = %v, %v", ok, err)
+	}
+	if got := s.VerifyApiToken(ctx, *created.Token, "10.0.0.1"); got != nil {
+		t.Errorf("VerifyApiToken after revoke = %+v", got)
+	}
+}
@@ -5,56 +5,318 @@ import (
 	"errors"
 	"nola-go/internal/logger"
 	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
 	"nola-go/internal/models/request"
 	"nola-go/internal/models/response"
+	"nola-go/internal/password"
 	"nola-go/internal/repository"
 	"nola-go/internal/util"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
+const (
+	// totpIssuer 两步验证签发者，显示在身份验证器中
+	totpIssuer = "Nola"
+	// totpCodeLength TOTP 验证码长度
+	totpCodeLength = 6
+	// recoveryCodeCount 每次生成的恢复码数量
+	recoveryCodeCount = 10
+)
+
+var (
+	// ErrTotpCodeInvalid 两步验证码错误或已被使用
+	ErrTotpCodeInvalid = errors.New("验证码错误")
+)
+
 type UserService struct {
-	userRepo     repository.UserRepository
-	tokenService *TokenService
+	userRepo       repository.UserRepository
+	tokenService   *TokenService
+	attemptService *AttemptService
+	hasher         password.Hasher
+	auditService   *AuditService
 }
 
-func NewUserService(userRepo repository.UserRepository, tokenService *TokenService) *UserService {
+func NewUserService(
+	userRepo repository.UserRepository,
+	tokenService *TokenService,
+	attemptService *AttemptService,
+	hasher password.Hasher,
+	auditService *AuditService,
+) *UserService {
 	return &UserService{
-		userRepo:     userRepo,
-		tokenService: tokenService,
+		userRepo:       userRepo,
+		tokenService:   tokenService,
+		attemptService: attemptService,
+		hasher:         hasher,
+		auditService:   auditService,
 	}
 }
 
 // Login 用户登录
+// 用户开启了两步验证时，密码验证通过后不会签发令牌，而是返回两步验证挑战，
+// 需要使用挑战令牌和验证码调用 LoginTwoFactor 完成登录；
+// 同一 IP 或同一用户名连续失败次数过多时返回 *response.TooManyRequestsError
 //   - ctx: 上下文
 //   - username: 用户名
 //   - password: 密码
+//   - userAgent: 登录设备的浏览器标识
+//   - ip: 登录设备的 IP 地址
 func (s *UserService) Login(
 	ctx context.Context,
-	username, password string,
-) (*response.AuthResponse, error) {
+	username, password, userAgent, ip string,
+) (*response.AuthResponse, *response.TwoFactorChallengeResponse, error) {
+
+	// 检查是否因失败次数过多被锁定
+	if err := s.attemptService.Check(ctx, AttemptScopeLogin, ip, username); err != nil {
+		return nil, nil, err
+	}
 
 	// 查询用户
 	user, err := s.UserByUsername(ctx, username)
 
 	if err != nil {
-		return nil, errors.New("非法用户名或密码")
+		return nil, nil, errors.New("非法用户名或密码")
+	}
+
+	// 用户不存在时同样记录失败，避免通过是否锁定判断用户名是否存在
+	if user == nil || !s.verifyPassword(ctx, user, password) {
+		s.attemptService.Fail(ctx, AttemptScopeLogin, ip, username)
+		return nil, nil, errors.New("非法用户名或密码")
+	}
+	s.attemptService.Succeed(ctx, AttemptScopeLogin, username)
+
+	// 开启了两步验证，返回挑战
+	if user.TotpEnabled {
+		challenge, exp, err := s.tokenService.SignChallenge(user.UserId)
+		if err != nil {
+			logger.Log.Error("签发两步验证挑战令牌失败", zap.Error(err))
+			return nil, nil, response.ServerError
+		}
+		return nil, &response.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			Challenge:         challenge,
+			ExpireTime:        exp,
+		}, nil
+	}
+
+	auth, err := s.authResponse(ctx, user, userAgent, ip)
+	if err != nil {
+		return nil, nil, err
+	}
+	return auth, nil, nil
+}
+
+// LoginTwoFactor 使用两步验证挑战令牌和验证码（或恢复码）完成登录
+//   - ctx: 上下文
+//   - challenge: 登录时返回的挑战令牌
+//   - code: TOTP 验证码或恢复码
+//   - userAgent: 登录设备的浏览器标识
+//   - ip: 登录设备的 IP 地址
+func (s *UserService) LoginTwoFactor(
+	ctx context.Context,
+	challenge, code, userAgent, ip string,
+) (*response.AuthResponse, error) {
+	userId, err := s.tokenService.ParseChallenge(challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	account := strconv.FormatUint(uint64(userId), 10)
+	if err := s.attemptService.Check(ctx, AttemptScopeTwoFactor, ip, account); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.TotpEnabled {
+		return nil, ErrChallengeInvalid
+	}
+
+	if err := s.verifySecondFactor(ctx, user, code); err != nil {
+		if errors.Is(err, ErrTotpCodeInvalid) {
+			s.attemptService.Fail(ctx, AttemptScopeTwoFactor, ip, account)
+		}
+		return nil, err
+	}
+	s.attemptService.Succeed(ctx, AttemptScopeTwoFactor, account)
+
+	return s.authResponse(ctx, user, userAgent, ip)
+}
+
+// TotpStatus 获取用户两步验证状态
+//   - ctx: 上下文
+//   - userId: 用户 ID
+func (s *UserService) TotpStatus(ctx context.Context, userId uint) (*response.TotpStatusResponse, error) {
+	user, err := s.UserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
 	}
 
+	ret := &response.TotpStatusResponse{Enabled: user.TotpEnabled}
+	if user.TotpEnabled {
+		ret.RecoveryCodeCount, err = s.userRepo.RecoveryCodeCount(ctx, userId)
+		if err != nil {
+			logger.Log.Error("获取恢复码数量失败", zap.Error(err))
+			return nil, response.ServerError
+		}
+	}
+	return ret, nil
+}
+
+// SetupTotp 生成新的 TOTP 密钥，返回密钥和用于生成二维码的 otpauth 地址
+// 此时两步验证尚未开启，需要使用身份验证器生成的验证码调用 EnableTotp 确认
+//   - ctx: 上下文
+//   - userId: 用户 ID
+func (s *UserService) SetupTotp(ctx context.Context, userId uint) (*response.TotpSetupResponse, error) {
+	user, err := s.UserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
 	if user == nil {
-		return nil, errors.New("非法用户名或密码")
+		return nil, errors.New("用户不存在")
+	}
+	if user.TotpEnabled {
+		return nil, errors.New("两步验证已开启，请先关闭")
+	}
+
+	secret, err := util.GenerateTotpSecret()
+	if err != nil {
+		logger.Log.Error("生成 TOTP 密钥失败", zap.Error(err))
+		return nil, response.ServerError
 	}
 
-	// 验证密码合法性
-	if !util.VerifySaltedHash(password, &util.SaltedHash{
-		Hash: user.Password,
-		Salt: user.Salt,
-	}) {
-		return nil, errors.New("非法用户名或密码")
+	if _, err := s.userRepo.SetTotpSecret(ctx, userId, secret); err != nil {
+		logger.Log.Error("保存 TOTP 密钥失败", zap.Error(err))
+		return nil, response.ServerError
 	}
 
+	return &response.TotpSetupResponse{
+		Secret: secret,
+		Uri:    util.TotpURI(totpIssuer, user.Username, secret),
+	}, nil
+}
+
+// EnableTotp 验证身份验证器生成的验证码并开启两步验证，返回恢复码（只返回一次）
+//   - ctx: 上下文
+//   - userId: 用户 ID
+//   - code: TOTP 验证码
+func (s *UserService) EnableTotp(ctx context.Context, userId uint, code string) ([]string, error) {
+	user, err := s.UserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+	if user.TotpEnabled {
+		return nil, errors.New("两步验证已开启")
+	}
+	if user.TotpSecret == nil {
+		return nil, errors.New("请先设置两步验证")
+	}
+
+	if err := s.verifyTotp(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.userRepo.EnableTotp(ctx, userId, hashes)
+	if err != nil {
+		logger.Log.Error("开启两步验证失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	if !ok {
+		return nil, errors.New("请先设置两步验证")
+	}
+	return codes, nil
+}
+
+// DisableTotp 关闭两步验证，需要同时验证密码和验证码（或恢复码）
+//   - ctx: 上下文
+//   - userId: 用户 ID
+//   - password: 密码
+//   - code: TOTP 验证码或恢复码
+func (s *UserService) DisableTotp(ctx context.Context, userId uint, password, code string) (bool, error) {
+	user, err := s.UserById(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return false, errors.New("用户不存在")
+	}
+	if !user.TotpEnabled {
+		return false, errors.New("两步验证未开启")
+	}
+
+	if !s.verifyPassword(ctx, user, password) {
+		return false, errors.New("密码错误")
+	}
+
+	if err := s.verifySecondFactor(ctx, user, code); err != nil {
+		return false, err
+	}
+
+	ret, err := s.userRepo.DisableTotp(ctx, userId)
+	if err != nil {
+		logger.Log.Error("关闭两步验证失败", zap.Error(err))
+		return false, response.ServerError
+	}
+	return ret, nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，原有的恢复码全部失效
+//   - ctx: 上下文
+//   - userId: 用户 ID
+//   - code: TOTP 验证码
+func (s *UserService) RegenerateRecoveryCodes(ctx context.Context, userId uint, code string) ([]string, error) {
+	user, err := s.UserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+	if !user.TotpEnabled {
+		return nil, errors.New("两步验证未开启")
+	}
+
+	if err := s.verifyTotp(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ReplaceRecoveryCodes(ctx, userId, hashes); err != nil {
+		logger.Log.Error("重新生成恢复码失败", zap.Error(err))
+		return nil, response.ServerError
+	}
+	return codes, nil
+}
+
+// authResponse 为用户签发令牌并封装登录响应
+func (s *UserService) authResponse(
+	ctx context.Context,
+	user *models.User,
+	userAgent, ip string,
+) (*response.AuthResponse, error) {
 	// 生成 Token
-	token, err := s.tokenService.Generate(user.UserId, user.Username, nil)
+	token, err := s.tokenService.Generate(ctx, user.UserId, user.Username, user.Role, userAgent, ip)
 	if err != nil {
 		logger.Log.Error(err.Error())
 		return nil, response.ServerError
@@ -62,17 +324,103 @@ func (s *UserService) Login(
 
 	// 封装登录响应数据类
 	return &response.AuthResponse{
-		Username:      user.Username,
-		Email:         user.Email,
-		DisplayName:   user.DisplayName,
-		Description:   user.Description,
-		CreateDate:    user.CreateDate,
-		LastLoginDate: user.LastLoginDate,
-		Avatar:        user.Avatar,
-		Token:         token,
+		Username:        user.Username,
+		Email:           user.Email,
+		DisplayName:     user.DisplayName,
+		Role:            user.Role,
+		Description:     user.Description,
+		CreateDate:      user.CreateDate,
+		LastLoginDate:   user.LastLoginDate,
+		Avatar:          user.Avatar,
+		Token:           token.Token,
+		TokenExpireTime: token.TokenExpireTime,
+		RefreshToken:    token.RefreshToken,
 	}, nil
 }
 
+// verifyPassword 验证用户密码，验证成功且哈希需要升级（旧版哈希或哈希参数变化）时重新生成并保存
+func (s *UserService) verifyPassword(ctx context.Context, user *models.User, password string) bool {
+	ok, needsRehash := s.hasher.Verify(password, user.Password, user.Salt)
+	if !ok {
+		return false
+	}
+
+	if needsRehash {
+		hash, err := s.hasher.Hash(password)
+		if err != nil {
+			logger.Log.Error("密码生成失败", zap.Error(err))
+			return true
+		}
+		// 升级失败不影响本次验证，下次验证成功时会再次尝试
+		if _, err := s.userRepo.UpdatePassword(ctx, user.UserId, hash); err != nil {
+			logger.Log.Warn("升级密码哈希失败", zap.Uint("userId", user.UserId), zap.Error(err))
+			return true
+		}
+		user.Password, user.Salt = hash, ""
+	}
+	return true
+}
+
+// verifySecondFactor 验证 TOTP 验证码或恢复码
+func (s *UserService) verifySecondFactor(ctx context.Context, user *models.User, code string) error {
+	// 6 位数字视为 TOTP 验证码，否则视为恢复码
+	if len(strings.TrimSpace(code)) == totpCodeLength {
+		return s.verifyTotp(ctx, user, code)
+	}
+
+	normalized := util.NormalizeRecoveryCode(code)
+	if normalized == "" {
+		return ErrTotpCodeInvalid
+	}
+
+	ok, err := s.userRepo.UseRecoveryCode(ctx, user.UserId, util.GenerateHash(normalized))
+	if err != nil {
+		logger.Log.Error("使用恢复码失败", zap.Error(err))
+		return response.ServerError
+	}
+	if !ok {
+		return ErrTotpCodeInvalid
+	}
+	return nil
+}
+
+// verifyTotp 验证 TOTP 验证码，每个验证码只能使用一次
+func (s *UserService) verifyTotp(ctx context.Context, user *models.User, code string) error {
+	if user.TotpSecret == nil {
+		return ErrTotpCodeInvalid
+	}
+
+	step, ok := util.ValidateTotp(*user.TotpSecret, code, time.Now())
+	if !ok {
+		return ErrTotpCodeInvalid
+	}
+
+	// 记录已使用的时间步，同一个验证码（以及更早的验证码）不能再次使用
+	ok, err := s.userRepo.UseTotpStep(ctx, user.UserId, step)
+	if err != nil {
+		logger.Log.Error("记录 TOTP 时间步失败", zap.Error(err))
+		return response.ServerError
+	}
+	if !ok {
+		return ErrTotpCodeInvalid
+	}
+	return nil
+}
+
+// newRecoveryCodes 生成恢复码，返回恢复码明文和哈希
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := util.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		logger.Log.Error("生成恢复码失败", zap.Error(err))
+		return nil, nil, response.ServerError
+	}
+
+	hashes := util.Map(codes, func(code string) string {
+		return util.GenerateHash(util.NormalizeRecoveryCode(code))
+	})
+	return codes, hashes, nil
+}
+
 // UpdateUser 更新用户信息
 func (s *UserService) UpdateUser(ctx context.Context, userId uint, userInfo *request.UserInfoRequest) (bool, error) {
 	if !util.StringIsEmail(userInfo.Email) {
@@ -106,8 +454,8 @@ func (s *UserService) UpdatePassword(ctx context.Context, userId uint, password
 		return false, errors.New("用户不存在")
 	}
 
-	// 对密码生成加盐哈希
-	hash, err := util.GenerateSaltedHash(password, 32)
+	// 生成密码哈希
+	hash, err := s.hasher.Hash(password)
 
 	if err != nil {
 		logger.Log.Error("密码生成失败", zap.Error(err))
@@ -120,6 +468,11 @@ func (s *UserService) UpdatePassword(ctx context.Context, userId uint, password
 		return false, response.ServerError
 	}
 
+	// 注销该用户的所有会话（包括当前会话），需要使用新密码重新登录
+	if err := s.tokenService.RevokeUserSessions(ctx, userId); err != nil {
+		return false, err
+	}
+
 	return ret, nil
 }
 
@@ -155,7 +508,7 @@ func (s *UserService) AllUsers(ctx context.Context) ([]*models.User, error) {
 	return users, nil
 }
 
-// InitAdmin 初始化博客管理员
+// InitAdmin 初始化博客管理员（所有者）
 func (s *UserService) InitAdmin(c context.Context, u *models.User) (bool, error) {
 	users, err := s.AllUsers(c)
 	if err != nil {
@@ -166,39 +519,192 @@ func (s *UserService) InitAdmin(c context.Context, u *models.User) (bool, error)
 		return false, errors.New("管理员已经创建")
 	}
 
+	u.Role = enum.UserRoleOwner
+	if err := s.createUser(c, u); err != nil {
+		return false, err
+	}
+
+	// TODO("如果没有文章和菜单话添加默认初始文章和菜单")
+
+	return true, nil
+}
+
+// CreateUser 添加用户
+//   - ctx: 上下文
+//   - operator: 执行添加操作的用户
+//   - req: 添加用户请求体
+func (s *UserService) CreateUser(ctx context.Context, operator *Operator, req *request.UserCreateRequest) (*models.User, error) {
+	exist, err := s.UserByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if exist != nil {
+		return nil, errors.New("用户名 [" + req.Username + "] 已存在")
+	}
+
+	user := &models.User{
+		Username:    req.Username,
+		Email:       req.Email,
+		DisplayName: req.DisplayName,
+		Password:    req.Password,
+		Role:        req.Role,
+		CreateDate:  time.Now().UnixMilli(),
+	}
+	if err := s.createUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, operator, enum.AuditActionUserCreate, &AuditTarget{
+		Type:  enum.AuditTargetTypeUser,
+		Id:    strconv.FormatUint(uint64(user.UserId), 10),
+		After: user,
+	})
+	return user, nil
+}
+
+// UpdateUserRole 修改用户角色，修改后注销该用户的所有会话，需要重新登录
+//   - ctx: 上下文
+//   - operator: 执行修改操作的用户
+//   - userId: 用户 ID
+//   - role: 新角色
+func (s *UserService) UpdateUserRole(ctx context.Context, operator *Operator, userId uint, role enum.UserRole) (bool, error) {
+	user, err := s.UserById(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return false, errors.New("用户不存在")
+	}
+	if user.Role == role {
+		return false, nil
+	}
+
+	if err := s.checkNotLastOwner(ctx, user); err != nil {
+		return false, err
+	}
+
+	ret, err := s.userRepo.UpdateRole(ctx, userId, role)
+	if err != nil {
+		logger.Log.Error("修改用户角色失败", zap.Error(err))
+		return false, response.ServerError
+	}
+
+	s.auditService.Record(ctx, operator, enum.AuditActionUserRoleUpdate, &AuditTarget{
+		Type:   enum.AuditTargetTypeUser,
+		Id:     strconv.FormatUint(uint64(userId), 10),
+		Before: map[string]any{"username": user.Username, "role": user.Role},
+		After:  map[string]any{"username": user.Username, "role": role},
+	})
+
+	// 令牌中保存了角色，注销所有会话使新角色立即生效
+	if err := s.tokenService.RevokeUserSessions(ctx, userId); err != nil {
+		return false, err
+	}
+	return ret, nil
+}
+
+// DeleteUser 删除用户，用户的文章转移给执行删除操作的用户，并注销该用户的所有会话
+//   - ctx: 上下文
+//   - operator: 执行删除操作的用户
+//   - userId: 要删除的用户 ID
+func (s *UserService) DeleteUser(ctx context.Context, operator *Operator, userId uint) (bool, error) {
+	if operator.UserId == userId {
+		return false, errors.New("不能删除当前登录的用户")
+	}
+
+	user, err := s.UserById(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return false, errors.New("用户不存在")
+	}
+
+	if err := s.checkNotLastOwner(ctx, user); err != nil {
+		return false, err
+	}
+
+	ret, err := s.userRepo.Delete(ctx, userId, operator.UserId)
+	if err != nil {
+		logger.Log.Error("删除用户失败", zap.Error(err))
+		return false, response.ServerError
+	}
+
+	s.auditService.Record(ctx, operator, enum.AuditActionUserDelete, &AuditTarget{
+		Type:   enum.AuditTargetTypeUser,
+		Id:     strconv.FormatUint(uint64(userId), 10),
+		Before: user,
+	})
+
+	if err := s.tokenService.RevokeUserSessions(ctx, userId); err != nil {
+		return false, err
+	}
+	return ret, nil
+}
+
+// Blogger 获取博主（最早创建的所有者），还没有初始化管理员时返回 nil
+func (s *UserService) Blogger(ctx context.Context) (*models.User, error) {
+	users, err := s.AllUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if user.Role == enum.UserRoleOwner {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkNotLastOwner 检查用户是否是最后一个所有者，博客至少需要保留一个所有者
+func (s *UserService) checkNotLastOwner(ctx context.Context, user *models.User) error {
+	if user.Role != enum.UserRoleOwner {
+		return nil
+	}
+
+	count, err := s.userRepo.CountByRole(ctx, enum.UserRoleOwner)
+	if err != nil {
+		logger.Log.Error("获取所有者数量失败", zap.Error(err))
+		return response.ServerError
+	}
+	if count <= 1 {
+		return errors.New("至少需要保留一个所有者")
+	}
+	return nil
+}
+
+// createUser 验证用户信息，生成密码哈希并添加用户
+func (s *UserService) createUser(ctx context.Context, u *models.User) error {
 	if !util.StringIsNumberAndChar(u.Username) {
-		return false, errors.New("用户名只支持英文和数字")
+		return errors.New("用户名只支持英文和数字")
 	}
 
 	if len(u.Username) < 4 {
-		return false, errors.New("用户名不能小于 4 位")
+		return errors.New("用户名不能小于 4 位")
 	}
 
 	if !util.StringIsEmail(u.Email) {
-		return false, errors.New("邮箱格式错误")
+		return errors.New("邮箱格式错误")
 	}
 
 	if len(u.Password) < 8 {
-		return false, errors.New("密码长度不能小于 8 位")
+		return errors.New("密码长度不能小于 8 位")
 	}
 
-	// 对密码生成加盐哈希
-	hash, err := util.GenerateSaltedHash(u.Password, 32)
+	// 生成密码哈希
+	hash, err := s.hasher.Hash(u.Password)
 	if err != nil {
 		logger.Log.Error("密码生成失败", zap.Error(err))
-		return false, response.ServerError
+		return response.ServerError
 	}
-	u.Password = hash.Hash
-	u.Salt = hash.Salt
+	u.Password = hash
+	u.Salt = ""
 
 	// 添加用户
-	err = s.userRepo.Create(c, u)
-	if err != nil {
+	if err := s.userRepo.Create(ctx, u); err != nil {
 		logger.Log.Error("添加用户失败", zap.Error(err))
-		return false, response.ServerError
+		return response.ServerError
 	}
-
-	// TODO("如果没有文章和菜单话添加默认初始文章和菜单")
-
-	return true, nil
+	return nil
 }
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/models/request"
+	"nola-go/internal/models/response"
+	"nola-go/internal/repository"
+	"nola-go/internal/testutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestUserService 创建 UserService 测试环境，并添加一个密码为 password 的用户
+func newTestUserService(t *testing.T) (*UserService, *models.User) {
+	t.Helper()
+	database := testutil.NewDB(t)
+	userRepo := repository.NewUserRepository(database)
+	s := NewUserService(userRepo, newTestTokenService(t, database), newTestAttemptService(database), testutil.NewHasher(t), newTestAuditService(database))
+
+	// 使用旧版 SHA-256 加盐哈希，验证登录时自动升级
+	sum := sha256.Sum256([]byte("salt" + "password"))
+	user := &models.User{
+		Username: "admin", Email: "admin@a.com", DisplayName: "Admin",
+		Password: hex.EncodeToString(sum[:]), Salt: "salt", Role: enum.UserRoleOwner, CreateDate: 1,
+	}
+	if err := userRepo.Create(context.Background(), user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return s, user
+}
+
+// totpCodeAt 计算指定时间的 TOTP 验证码（RFC 6238，HMAC-SHA1，6 位，30 秒）
+func totpCodeAt(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(at.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000)
+}
+
+func TestUserService_Login(t *testing.T) {
+	ctx := context.Background()
+	s, user := newTestUserService(t)
+
+	if _, _, err := s.Login(ctx, "admin", "wrong", "ua", "127.0.0.1"); err == nil {
+		t.Fatal("Login with wrong password should fail")
+	}
+	if got, _ := s.UserById(ctx, user.UserId); got.Salt != "salt" {
+		t.Fatal("failed login should not rehash password")
+	}
+
+	// 旧版哈希登录成功后升级为 Argon2id
+	auth, _, err := s.Login(ctx, "admin", "password", "ua", "127.0.0.1")
+	if err != nil || auth == nil {
+		t.Fatalf("Login = %+v, %v", auth, err)
+	}
+	got, _ := s.UserById(ctx, user.UserId)
+	if !strings.HasPrefix(got.Password, "$argon2id$") || got.Salt != "" {
+		t.Fatalf("Password, Salt = %q, %q, want rehashed", got.Password, got.Salt)
+	}
+
+	// 升级后的哈希仍然可以登录，并且不再重新生成
+	if auth, _, err := s.Login(ctx, "admin", "password", "ua", "127.0.0.1"); err != nil || auth == nil {
+		t.Fatalf("Login after rehash = %+v, %v", auth, err)
+	}
+	if again, _ := s.UserById(ctx, user.UserId); again.Password != got.Password {
+		t.Error("password should not be rehashed again")
+	}
+
+	// 修改密码后使用新算法
+	if ok, err := s.UpdatePassword(ctx, user.UserId, "new-password"); err != nil || !ok {
+		t.Fatalf("UpdatePassword = %v, %v", ok, err)
+	}
+	if _, _, err := s.Login(ctx, "admin", "new-password", "ua", "127.0.0.1"); err != nil {
+		t.Errorf("Login with new password: %v", err)
+	}
+}
+
+func TestUserService_LoginLockout(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestUserService(t)
+
+	// 默认允许同一账户连续失败 5 次
+	for i := 0; i < 5; i++ {
+		if _, _, err := s.Login(ctx, "admin", "wrong", "ua", "127.0.0.1"); err == nil || isTooMany(err) {
+			t.Fatalf("Login #%d err = %v, want wrong password", i+1, err)
+		}
+	}
+	if _, _, err := s.Login(ctx, "admin", "wrong", "ua", "127.0.0.1"); err == nil || isTooMany(err) {
+		t.Fatalf("Login #6 err = %v, want wrong password", err)
+	}
+
+	// 锁定后即使密码正确也直接拒绝，用户名不区分大小写，换 IP 也不行
+	for _, ip := range []string{"127.0.0.1", "10.0.0.1"} {
+		if _, _, err := s.Login(ctx, "ADMIN", "password", "ua", ip); !isTooMany(err) {
+			t.Errorf("Login from %s err = %v, want TooManyRequestsError", ip, err)
+		}
+	}
+}
+
+// isTooMany 判断是否为失败次数过多错误
+func isTooMany(err error) bool {
+	var tooMany *response.TooManyRequestsError
+	return errors.As(err, &tooMany)
+}
+
+func TestUserService_TwoFactor(t *testing.T) {
+	ctx := context.Background()
+	s, user := newTestUserService(t)
+
+	setup, err := s.SetupTotp(ctx, user.UserId)
+	if err != nil || setup.Secret == "" || setup.Uri == "" {
+		t.Fatalf("SetupTotp = %+v, %v", setup, err)
+	}
+
+	now := time.Now()
+	if _, err := s.EnableTotp(ctx, user.UserId, "abcdef"); !errors.Is(err, ErrTotpCodeInvalid) {
+		t.Fatalf("EnableTotp(wrong code) err = %v, want ErrTotpCodeInvalid", err)
+	}
+
+	codes, err := s.EnableTotp(ctx, user.UserId, totpCodeAt(t, setup.Secret, now.Add(-30*time.Second)))
+	if err != nil || len(codes) != recoveryCodeCount {
+		t.Fatalf("EnableTotp = %v, %v", codes, err)
+	}
+
+	// 开启后登录返回挑战而不是令牌
+	auth, challenge, err := s.Login(ctx, "admin", "password", "ua", "127.0.0.1")
+	if err != nil || auth != nil || challenge == nil || !challenge.TwoFactorRequired {
+		t.Fatalf("Login = %+v, %+v, %v", auth, challenge, err)
+	}
+
+	code := totpCodeAt(t, setup.Secret, now)
+	loginTests := []struct {
+		name    string
+		code    string
+		wantErr bool
+	}{
+		{"验证码", code, false},
+		{"验证码不能重复使用", code, true},
+		{"恢复码", codes[0], false},
+		{"恢复码不能重复使用", codes[0], true},
+		{"恢复码忽略大小写和连字符", " " + strings.ToUpper(strings.Replace(codes[1], "-", "", 1)), false},
+		{"错误的恢复码", "aaaa-aaaa", true},
+	}
+	for _, tt := range loginTests {
+		t.Run("LoginTwoFactor/"+tt.name, func(t *testing.T) {
+			auth, err := s.LoginTwoFactor(ctx, challenge.Challenge, tt.code, "ua", "127.0.0.1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoginTwoFactor err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (auth == nil || auth.Token == "") {
+				t.Errorf("LoginTwoFactor = %+v", auth)
+			}
+		})
+	}
+
+	if _, err := s.LoginTwoFactor(ctx, "invalid", code, "ua", "127.0.0.1"); !errors.Is(err, ErrChallengeInvalid) {
+		t.Errorf("LoginTwoFactor(invalid challenge) err = %v", err)
+	}
+
+	status, err := s.TotpStatus(ctx, user.UserId)
+	if err != nil || !status.Enabled || status.RecoveryCodeCount != recoveryCodeCount-2 {
+		t.Errorf("TotpStatus = %+v, %v", status, err)
+	}
+
+	if _, err := s.DisableTotp(ctx, user.UserId, "wrong-password", codes[2]); err == nil {
+		t.Error("DisableTotp with wrong password should fail")
+	}
+	if ok, err := s.DisableTotp(ctx, user.UserId, "password", codes[2]); err != nil || !ok {
+		t.Fatalf("DisableTotp = %v, %v", ok, err)
+	}
+
+	auth, challenge, err = s.Login(ctx, "admin", "password", "ua", "127.0.0.1")
+	if err != nil || auth == nil || challenge != nil {
+		t.Errorf("Login after DisableTotp = %+v, %+v, %v", auth, challenge, err)
+	}
+}
+
+func TestUserService_ManageUsers(t *testing.T) {
+	ctx := context.Background()
+	s, owner := newTestUserService(t)
+	operator := &Operator{UserId: owner.UserId, Username: owner.Username, Role: owner.Role, Ip: "10.0.0.1"}
+
+	editor, err := s.CreateUser(ctx, operator, &request.UserCreateRequest{
+		Username: "editor", Email: "editor@a.com", DisplayName: "Editor", Password: "password", Role: enum.UserRoleEditor,
+	})
+	if err != nil || editor.Role != enum.UserRoleEditor || !strings.HasPrefix(editor.Password, "$") {
+		t.Fatalf("CreateUser = %+v, %v", editor, err)
+	}
+	if _, err := s.CreateUser(ctx, operator, &request.UserCreateRequest{
+		Username: "editor", Email: "editor@a.com", DisplayName: "Editor", Password: "password", Role: enum.UserRoleAuthor,
+	}); err == nil {
+		t.Error("CreateUser with duplicate username should fail")
+	}
+
+	// 登录令牌携带角色，修改角色后会话失效
+	auth, _, err := s.Login(ctx, "editor", "password", "ua", "127.0.0.1")
+	if err != nil || auth.Role != enum.UserRoleEditor {
+		t.Fatalf("Login = %+v, %v", auth, err)
+	}
+	sid := sessionIdOf(t, s.tokenService, auth.Token)
+	if ok, err := s.UpdateUserRole(ctx, operator, editor.UserId, enum.UserRoleAuthor); err != nil || !ok {
+		t.Fatalf("UpdateUserRole = %v, %v", ok, err)
+	}
+	if s.tokenService.Verify(ctx, editor.UserId, sid) {
+		t.Error("sessions should be revoked after role change")
+	}
+
+	// 不能降级或删除最后一个所有者
+	if _, err := s.UpdateUserRole(ctx, operator, owner.UserId, enum.UserRoleEditor); err == nil {
+		t.Error("UpdateUserRole of the last owner should fail")
+	}
+	if _, err := s.DeleteUser(ctx, &Operator{UserId: editor.UserId, Role: enum.UserRoleOwner}, owner.UserId); err == nil {
+		t.Error("DeleteUser of the last owner should fail")
+	}
+	if _, err := s.DeleteUser(ctx, operator, owner.UserId); err == nil {
+		t.Error("DeleteUser of current user should fail")
+	}
+
+	if ok, err := s.DeleteUser(ctx, operator, editor.UserId); err != nil || !ok {
+		t.Fatalf("DeleteUser = %v, %v", ok, err)
+	}
+	if users, _ := s.AllUsers(ctx); len(users) != 1 {
+		t.Errorf("AllUsers = %d users, want 1", len(users))
+	}
+	if blogger, err := s.Blogger(ctx); err != nil || blogger == nil || blogger.UserId != owner.UserId {
+		t.Errorf("Blogger = %+v, %v", blogger, err)
+	}
+
+	// 添加、修改角色、删除用户都记录审计日志，失败的操作不记录
+	logs, err := s.auditService.AuditLogsPager(ctx, 0, 0, &owner.UserId, nil, nil, nil)
+	if err != nil || logs.TotalData != 3 {
+		t.Fatalf("AuditLogsPager = %+v, %v", logs, err)
+	}
+	wantActions := []enum.AuditAction{enum.AuditActionUserDelete, enum.AuditActionUserRoleUpdate, enum.AuditActionUserCreate}
+	for i, want := range wantActions {
+		if logs.Data[i].Action != want || *logs.Data[i].ActorName != "admin" || *logs.Data[i].Ip != "10.0.0.1" {
+			t.Errorf("audit log %d = %+v, want action %s", i, logs.Data[i], want)
+		}
+	}
+}
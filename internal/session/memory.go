@@ -0,0 +1,118 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryStore 进程内存会话存储
+type memoryStore struct {
+	lock     sync.RWMutex
+	sessions map[string]Session
+}
+
+// NewMemoryStore 创建内存会话存储，服务重启后所有会话失效
+func NewMemoryStore() Store {
+	return &memoryStore{
+		sessions: make(map[string]Session),
+	}
+}
+
+// Save 保存新会话
+func (m *memoryStore) Save(_ context.Context, s *Session) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.removeExpired()
+	m.sessions[s.SessionId] = *s
+	return nil
+}
+
+// Get 根据会话 ID 获取会话
+func (m *memoryStore) Get(_ context.Context, sessionId string) (*Session, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	s, ok := m.sessions[sessionId]
+	if !ok || s.ExpireTime <= time.Now().UnixMilli() {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// Touch 更新会话的最后活跃时间
+func (m *memoryStore) Touch(_ context.Context, sessionId string, lastActiveTime int64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if s, ok := m.sessions[sessionId]; ok {
+		s.LastActiveTime = lastActiveTime
+		m.sessions[sessionId] = s
+	}
+	return nil
+}
+
+// Rotate 轮换会话的刷新令牌并顺延过期时间
+func (m *memoryStore) Rotate(_ context.Context, sessionId, oldHash, newHash string, now, expireTime int64) (bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	s, ok := m.sessions[sessionId]
+	if !ok || s.ExpireTime <= now || s.RefreshTokenHash != oldHash {
+		return false, nil
+	}
+	s.RefreshTokenHash = newHash
+	s.LastActiveTime = now
+	s.ExpireTime = expireTime
+	m.sessions[sessionId] = s
+	return true, nil
+}
+
+// Delete 根据会话 ID 删除会话
+func (m *memoryStore) Delete(_ context.Context, sessionId string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.sessions, sessionId)
+	return nil
+}
+
+// ListByUser 获取用户的所有会话，按创建时间升序
+func (m *memoryStore) ListByUser(_ context.Context, userId uint) ([]*Session, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	now := time.Now().UnixMilli()
+	sessions := make([]*Session, 0)
+	for _, s := range m.sessions {
+		if s.UserId == userId && s.ExpireTime > now {
+			sessions = append(sessions, &s)
+		}
+	}
+	sortByCreateTime(sessions)
+	return sessions, nil
+}
+
+// DeleteByUser 删除用户的所有会话
+func (m *memoryStore) DeleteByUser(_ context.Context, userId uint) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for id, s := range m.sessions {
+		if s.UserId == userId {
+			delete(m.sessions, id)
+		}
+	}
+	return nil
+}
+
+// removeExpired 删除所有过期的会话，调用前需要持有写锁
+func (m *memoryStore) removeExpired() {
+	now := time.Now().UnixMilli()
+	for id, s := range m.sessions {
+		if s.ExpireTime <= now {
+			delete(m.sessions, id)
+		}
+	}
+}
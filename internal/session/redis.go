@@ -0,0 +1,195 @@
+package session
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix Redis 会话键前缀
+//   - nola:session:<sessionId> 哈希表，保存会话各字段，过期时间和会话一致
+//   - nola:session:user:<userId> 有序集合，成员为会话 ID，分数为会话过期时间戳毫秒
+const redisKeyPrefix = "nola:session:"
+
+// touchScript 会话存在时才更新最后活跃时间，避免给已删除的会话写入残缺的哈希表
+//   - KEYS[1]: 会话键
+//   - ARGV[1]: 最后活跃时间戳毫秒
+var touchScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('HSET', KEYS[1], 'lastActiveTime', ARGV[1])
+end
+return 0
+`)
+
+// rotateScript 比较并轮换刷新令牌哈希，同时顺延会话过期时间
+//   - KEYS[1]: 会话键
+//   - KEYS[2]: 用户会话 ID 集合键
+//   - ARGV[1]: 旧刷新令牌哈希
+//   - ARGV[2]: 新刷新令牌哈希
+//   - ARGV[3]: 当前时间戳毫秒
+//   - ARGV[4]: 新的过期时间戳毫秒
+//   - ARGV[5]: 会话 ID
+var rotateScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'refreshTokenHash') ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'refreshTokenHash', ARGV[2], 'lastActiveTime', ARGV[3], 'expireTime', ARGV[4])
+redis.call('PEXPIREAT', KEYS[1], ARGV[4])
+redis.call('ZADD', KEYS[2], ARGV[4], ARGV[5])
+return 1
+`)
+
+// redisStore Redis 会话存储
+type redisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore 创建 Redis 会话存储，服务重启后会话仍然有效
+func NewRedisStore(client *redis.Client) Store {
+	return &redisStore{client: client}
+}
+
+// Save 保存新会话
+func (r *redisStore) Save(ctx context.Context, s *Session) error {
+	if s.ExpireTime <= time.Now().UnixMilli() {
+		return nil
+	}
+
+	key := r.sessionKey(s.SessionId)
+	userKey := r.userKey(s.UserId)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, s)
+		pipe.PExpireAt(ctx, key, time.UnixMilli(s.ExpireTime))
+		pipe.ZAdd(ctx, userKey, redis.Z{Score: float64(s.ExpireTime), Member: s.SessionId})
+		// 顺便清理用户已经过期的会话 ID
+		pipe.ZRemRangeByScore(ctx, userKey, "-inf", strconv.FormatInt(time.Now().UnixMilli(), 10))
+		return nil
+	})
+	return err
+}
+
+// Get 根据会话 ID 获取会话
+func (r *redisStore) Get(ctx context.Context, sessionId string) (*Session, error) {
+	cmd := r.client.HGetAll(ctx, r.sessionKey(sessionId))
+	return r.scan(cmd)
+}
+
+// Touch 更新会话的最后活跃时间
+func (r *redisStore) Touch(ctx context.Context, sessionId string, lastActiveTime int64) error {
+	return touchScript.Run(ctx, r.client, []string{r.sessionKey(sessionId)}, lastActiveTime).Err()
+}
+
+// Rotate 轮换会话的刷新令牌并顺延过期时间
+func (r *redisStore) Rotate(ctx context.Context, sessionId, oldHash, newHash string, now, expireTime int64) (bool, error) {
+	s, err := r.Get(ctx, sessionId)
+	if err != nil || s == nil {
+		return false, err
+	}
+
+	keys := []string{r.sessionKey(sessionId), r.userKey(s.UserId)}
+	ret, err := rotateScript.Run(ctx, r.client, keys, oldHash, newHash, now, expireTime, sessionId).Int()
+	if err != nil {
+		return false, err
+	}
+	return ret == 1, nil
+}
+
+// Delete 根据会话 ID 删除会话
+func (r *redisStore) Delete(ctx context.Context, sessionId string) error {
+	s, err := r.Get(ctx, sessionId)
+	if err != nil || s == nil {
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, r.sessionKey(sessionId))
+		pipe.ZRem(ctx, r.userKey(s.UserId), sessionId)
+		return nil
+	})
+	return err
+}
+
+// ListByUser 获取用户的所有会话，按创建时间升序
+func (r *redisStore) ListByUser(ctx context.Context, userId uint) ([]*Session, error) {
+	userKey := r.userKey(userId)
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	if err := r.client.ZRemRangeByScore(ctx, userKey, "-inf", now).Err(); err != nil {
+		return nil, err
+	}
+
+	ids, err := r.client.ZRange(ctx, userKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGetAll(ctx, r.sessionKey(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*Session, 0, len(ids))
+	for _, cmd := range cmds {
+		s, err := r.scan(cmd)
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			sessions = append(sessions, s)
+		}
+	}
+
+	sortByCreateTime(sessions)
+	return sessions, nil
+}
+
+// DeleteByUser 删除用户的所有会话
+func (r *redisStore) DeleteByUser(ctx context.Context, userId uint) error {
+	userKey := r.userKey(userId)
+	ids, err := r.client.ZRange(ctx, userKey, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, r.sessionKey(id))
+	}
+	keys = append(keys, userKey)
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// scan 将 HGETALL 结果转换为会话，哈希表不存在时返回 nil
+func (r *redisStore) scan(cmd *redis.MapStringStringCmd) (*Session, error) {
+	values, err := cmd.Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 || values["sessionId"] == "" {
+		return nil, nil
+	}
+
+	var s Session
+	if err := cmd.Scan(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// sessionKey 会话键
+func (r *redisStore) sessionKey(sessionId string) string {
+	return redisKeyPrefix + sessionId
+}
+
+// userKey 用户会话 ID 集合键
+func (r *redisStore) userKey(userId uint) string {
+	return redisKeyPrefix + "user:" + strconv.FormatUint(uint64(userId), 10)
+}
@@ -0,0 +1,109 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+const (
+	// StoreRedis 使用 Redis 保存会话，服务重启后会话仍然有效
+	StoreRedis = "redis"
+	// StoreMemory 使用进程内存保存会话，服务重启后所有会话失效
+	StoreMemory = "memory"
+)
+
+// Session 登录会话
+type Session struct {
+	// SessionId 会话 ID
+	SessionId string `redis:"sessionId"`
+	// UserId 用户 ID
+	UserId uint `redis:"userId"`
+	// Username 用户名，刷新访问令牌时写入令牌
+	Username string `redis:"username"`
+	// Role 用户角色，刷新访问令牌时写入令牌（角色修改后会注销用户的所有会话）
+	Role string `redis:"role"`
+	// RefreshTokenHash 当前有效的刷新令牌哈希，每次刷新后轮换
+	RefreshTokenHash string `redis:"refreshTokenHash"`
+	// UserAgent 登录时的浏览器标识
+	UserAgent string `redis:"userAgent"`
+	// IP 登录时的 IP 地址
+	IP string `redis:"ip"`
+	// CreateTime 创建时间戳毫秒
+	CreateTime int64 `redis:"createTime"`
+	// LastActiveTime 最后活跃时间戳毫秒
+	LastActiveTime int64 `redis:"lastActiveTime"`
+	// ExpireTime 过期时间戳毫秒，即刷新令牌的过期时间，每次刷新后顺延
+	ExpireTime int64 `redis:"expireTime"`
+}
+
+// Store 会话存储接口
+// 过期的会话由存储自行清理，Get 和 ListByUser 不会返回过期的会话
+type Store interface {
+	// Save 保存新会话，会话在 ExpireTime 之后失效
+	Save(ctx context.Context, s *Session) error
+	// Get 根据会话 ID 获取会话，会话不存在或已过期时返回 nil
+	Get(ctx context.Context, sessionId string) (*Session, error)
+	// Touch 更新会话的最后活跃时间，会话不存在时忽略
+	Touch(ctx context.Context, sessionId string, lastActiveTime int64) error
+	// Rotate 轮换会话的刷新令牌并顺延过期时间
+	// 只有会话当前的刷新令牌哈希等于 oldHash 时才会轮换，返回是否轮换成功
+	Rotate(ctx context.Context, sessionId, oldHash, newHash string, now, expireTime int64) (bool, error)
+	// Delete 根据会话 ID 删除会话
+	Delete(ctx context.Context, sessionId string) error
+	// ListByUser 获取用户的所有会话
+	ListByUser(ctx context.Context, userId uint) ([]*Session, error)
+	// DeleteByUser 删除用户的所有会话
+	DeleteByUser(ctx context.Context, userId uint) error
+}
+
+// NewId 生成随机会话 ID
+func NewId() (string, error) {
+	return randomHex(16)
+}
+
+// NewRefreshToken 为会话生成新的刷新令牌，格式为 <会话 ID>.<随机串>
+// 返回刷新令牌和需要保存到会话中的令牌哈希
+//   - sessionId: 会话 ID
+func NewRefreshToken(sessionId string) (token string, hash string, err error) {
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", "", err
+	}
+	token = sessionId + "." + secret
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken 计算刷新令牌哈希
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseRefreshToken 从刷新令牌中解析出会话 ID，格式错误时返回 false
+func ParseRefreshToken(token string) (string, bool) {
+	sessionId, secret, ok := strings.Cut(token, ".")
+	if !ok || sessionId == "" || secret == "" {
+		return "", false
+	}
+	return sessionId, true
+}
+
+// randomHex 生成 n 个随机字节的十六进制字符串
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// sortByCreateTime 按创建时间升序排序会话
+func sortByCreateTime(sessions []*Session) {
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreateTime < sessions[j].CreateTime
+	})
+}
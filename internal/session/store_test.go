@@ -0,0 +1,148 @@
+package session
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// testStore 会话存储的通用测试
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now().UnixMilli()
+	hour := time.Hour.Milliseconds()
+
+	newSession := func(id string, userId uint, createTime, expireTime int64) *Session {
+		return &Session{
+			SessionId: id, UserId: userId, RefreshTokenHash: "h1",
+			CreateTime: createTime, LastActiveTime: createTime, ExpireTime: expireTime,
+		}
+	}
+
+	sessions := []*Session{
+		newSession("desktop", 1, now-2, now+hour),
+		newSession("phone", 1, now-1, now+hour),
+		newSession("expired", 1, now-hour, now-1),
+		newSession("other", 2, now, now+hour),
+	}
+	for _, s := range sessions {
+		if err := store.Save(ctx, s); err != nil {
+			t.Fatalf("Save(%s): %v", s.SessionId, err)
+		}
+	}
+
+	getTests := []struct {
+		id     string
+		wantOk bool
+	}{
+		{"desktop", true},
+		{"phone", true},
+		{"expired", false},
+		{"missing", false},
+	}
+	for _, tt := range getTests {
+		t.Run("Get/"+tt.id, func(t *testing.T) {
+			s, err := store.Get(ctx, tt.id)
+			if err != nil || (s != nil) != tt.wantOk {
+				t.Fatalf("Get = %+v, %v, want exists %v", s, err, tt.wantOk)
+			}
+		})
+	}
+
+	assertIds := func(t *testing.T, userId uint, want ...string) {
+		t.Helper()
+		list, err := store.ListByUser(ctx, userId)
+		if err != nil {
+			t.Fatalf("ListByUser: %v", err)
+		}
+		if len(list) != len(want) {
+			t.Fatalf("ListByUser = %d sessions, want %v", len(list), want)
+		}
+		for i, s := range list {
+			if s.SessionId != want[i] {
+				t.Errorf("ListByUser[%d] = %s, want %s", i, s.SessionId, want[i])
+			}
+		}
+	}
+
+	// 同一用户可以同时存在多个会话
+	assertIds(t, 1, "desktop", "phone")
+
+	if err := store.Touch(ctx, "phone", now+1000); err != nil {
+		t.Fatalf("Touch: %v", err)
+	}
+	if got, _ := store.Get(ctx, "phone"); got.LastActiveTime != now+1000 {
+		t.Errorf("LastActiveTime = %d, want %d", got.LastActiveTime, now+1000)
+	}
+	// 不存在的会话不会被 Touch 创建
+	if err := store.Touch(ctx, "missing", now); err != nil {
+		t.Fatalf("Touch(missing): %v", err)
+	}
+	if got, _ := store.Get(ctx, "missing"); got != nil {
+		t.Errorf("Touch created session %+v", got)
+	}
+
+	rotateTests := []struct {
+		name    string
+		id      string
+		oldHash string
+		newHash string
+		wantOk  bool
+	}{
+		{"轮换", "phone", "h1", "h2", true},
+		{"旧令牌不能再次轮换", "phone", "h1", "h3", false},
+		{"新令牌可以继续轮换", "phone", "h2", "h3", true},
+		{"会话已过期", "expired", "h1", "h2", false},
+		{"会话不存在", "missing", "", "h2", false},
+	}
+	for _, tt := range rotateTests {
+		t.Run("Rotate/"+tt.name, func(t *testing.T) {
+			ok, err := store.Rotate(ctx, tt.id, tt.oldHash, tt.newHash, now+2000, now+2*hour)
+			if err != nil || ok != tt.wantOk {
+				t.Fatalf("Rotate = %v, %v, want %v", ok, err, tt.wantOk)
+			}
+		})
+	}
+	phone, _ := store.Get(ctx, "phone")
+	if phone.RefreshTokenHash != "h3" || phone.ExpireTime != now+2*hour || phone.LastActiveTime != now+2000 {
+		t.Errorf("rotated session = %+v", phone)
+	}
+
+	if err := store.Delete(ctx, "desktop"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(ctx, "missing"); err != nil {
+		t.Fatalf("Delete(missing): %v", err)
+	}
+	assertIds(t, 1, "phone")
+
+	if err := store.DeleteByUser(ctx, 1); err != nil {
+		t.Fatalf("DeleteByUser: %v", err)
+	}
+	assertIds(t, 1)
+	assertIds(t, 2, "other")
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+// TestRedisStore 需要设置环境变量 NOLA_TEST_REDIS_ADDR，使用其中的第 15 号数据库
+func TestRedisStore(t *testing.T) {
+	addr := os.Getenv("NOLA_TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("未设置 NOLA_TEST_REDIS_ADDR")
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr, DB: 15})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	if err := client.FlushDB(context.Background()).Err(); err != nil {
+		t.Fatalf("FlushDB: %v", err)
+	}
+	testStore(t, NewRedisStore(client))
+}
@@ -0,0 +1,82 @@
+// Package sitemap 生成 XML 站点地图（https://www.sitemaps.org/protocol.html）
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs 单个站点地图最多包含的网址数量，超过时需要拆分并使用站点地图索引
+const MaxURLs = 50000
+
+// namespace 站点地图命名空间
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// ContentType 站点地图的响应类型
+const ContentType = "application/xml; charset=utf-8"
+
+// URL 站点地图中的网址
+type URL struct {
+	// Loc 网址
+	Loc string
+	// LastMod 最后修改时间，零值表示未知
+	LastMod time.Time
+}
+
+// Document 站点地图或站点地图索引
+type Document struct {
+	// Index 是否为站点地图索引，为 true 时 URLs 为各个站点地图的地址
+	Index bool
+	// URLs 网址
+	URLs []*URL
+}
+
+// LastModified 所有网址中最新的修改时间
+func (d *Document) LastModified() time.Time {
+	var last time.Time
+	for _, u := range d.URLs {
+		if u.LastMod.After(last) {
+			last = u.LastMod
+		}
+	}
+	return last
+}
+
+type xmlURLSet struct {
+	XMLName   xml.Name  `xml:"urlset"`
+	Namespace string    `xml:"xmlns,attr"`
+	URLs      []*xmlURL `xml:"url"`
+}
+
+type xmlIndex struct {
+	XMLName   xml.Name  `xml:"sitemapindex"`
+	Namespace string    `xml:"xmlns,attr"`
+	Sitemaps  []*xmlURL `xml:"sitemap"`
+}
+
+type xmlURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Render 生成站点地图 XML
+func (d *Document) Render() ([]byte, error) {
+	urls := make([]*xmlURL, len(d.URLs))
+	for i, u := range d.URLs {
+		urls[i] = &xmlURL{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			urls[i].LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+	}
+
+	var doc any = &xmlURLSet{Namespace: namespace, URLs: urls}
+	if d.Index {
+		doc = &xmlIndex{Namespace: namespace, Sitemaps: urls}
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,45 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocument_Render(t *testing.T) {
+	modified := time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("CST", 8*3600))
+	doc := &Document{URLs: []*URL{
+		{Loc: "https://example.com/post/a?x=1&y=2", LastMod: modified},
+		{Loc: "https://example.com/tag/go"},
+	}}
+
+	body, err := doc.Render()
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	var got struct {
+		XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+		URLs    []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"url"`
+	}
+	if err := xml.Unmarshal(body, &got); err != nil {
+		t.Fatalf("invalid xml: %v\n%s", err, body)
+	}
+	if len(got.URLs) != 2 || got.URLs[0].Loc != "https://example.com/post/a?x=1&y=2" ||
+		got.URLs[0].LastMod != "2026-03-03T21:06:07Z" || got.URLs[1].LastMod != "" {
+		t.Errorf("urls = %+v", got.URLs)
+	}
+	if !doc.LastModified().Equal(modified) {
+		t.Errorf("LastModified = %v", doc.LastModified())
+	}
+
+	doc.Index = true
+	body, _ = doc.Render()
+	if !strings.Contains(string(body), "<sitemapindex") || strings.Count(string(body), "<sitemap>") != 2 {
+		t.Errorf("index = %s", body)
+	}
+}
@@ -0,0 +1,126 @@
+package spam
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"nola-go/internal/config"
+	"strings"
+	"time"
+)
+
+// DefaultAkismetEndpoint Akismet 接口地址
+const DefaultAkismetEndpoint = "https://rest.akismet.com"
+
+// AkismetClient Akismet 兼容接口的客户端，接口地址可以配置为其他兼容服务或本地模拟服务
+type AkismetClient struct {
+	endpoint   string
+	key        string
+	blog       string
+	httpClient *http.Client
+}
+
+// NewAkismetClient 创建 Akismet 客户端
+//   - cfg: Akismet 配置
+//   - httpClient: HTTP 客户端，为 nil 时使用 10 秒超时的默认客户端
+func NewAkismetClient(cfg config.AkismetConfig, httpClient *http.Client) (*AkismetClient, error) {
+	if cfg.Key == "" {
+		return nil, errors.New("Akismet API Key 不能为空")
+	}
+	if cfg.Blog == "" {
+		return nil, errors.New("Akismet 博客地址不能为空")
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultAkismetEndpoint
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &AkismetClient{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		key:        cfg.Key,
+		blog:       cfg.Blog,
+		httpClient: httpClient,
+	}, nil
+}
+
+// CommentCheck 检查评论是否为垃圾评论
+//
+// Returns:
+//   - spam: 是否为垃圾评论
+//   - discard: 是否为明显的垃圾评论（可以直接丢弃）
+func (c *AkismetClient) CommentCheck(ctx context.Context, comment *Comment) (spam bool, discard bool, err error) {
+	form := url.Values{
+		"api_key":              {c.key},
+		"blog":                 {c.blog},
+		"user_ip":              {comment.IP},
+		"user_agent":           {comment.UserAgent},
+		"referrer":             {comment.Referrer},
+		"comment_type":         {"comment"},
+		"comment_author":       {comment.DisplayName},
+		"comment_author_email": {comment.Email},
+		"comment_author_url":   {comment.Site},
+		"comment_content":      {comment.Content},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/1.1/comment-check", strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, false, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return false, false, err
+	}
+
+	switch strings.TrimSpace(string(body)) {
+	case "true":
+		return true, resp.Header.Get("X-akismet-pro-tip") == "discard", nil
+	case "false":
+		return false, false, nil
+	default:
+		// 出错时返回 invalid 等内容，原因在 X-akismet-debug-help 中
+		msg := resp.Header.Get("X-akismet-debug-help")
+		if msg == "" {
+			msg = strings.TrimSpace(string(body))
+		}
+		return false, false, errors.New("Akismet 返回错误: " + resp.Status + " " + msg)
+	}
+}
+
+// akismetChecker 使用 Akismet 检查
+type akismetChecker struct {
+	client *AkismetClient
+}
+
+// NewAkismetChecker 创建 Akismet 检查器
+func NewAkismetChecker(client *AkismetClient) Checker {
+	return &akismetChecker{client: client}
+}
+
+func (c *akismetChecker) Name() string { return "akismet" }
+
+func (c *akismetChecker) Check(ctx context.Context, comment *Comment) (*Verdict, error) {
+	spam, discard, err := c.client.CommentCheck(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case discard:
+		return &Verdict{Score: ScoreAkismetMax, Reason: "明显的垃圾评论"}, nil
+	case spam:
+		return &Verdict{Score: ScoreAkismet, Reason: "垃圾评论"}, nil
+	}
+	return nil, nil
+}
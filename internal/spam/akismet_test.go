@@ -0,0 +1,97 @@
+package spam
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"nola-go/internal/config"
+	"testing"
+)
+
+// newFakeAkismet 模拟 Akismet 接口，内容为 spam 时返回垃圾评论，为 discard 时返回明显的垃圾评论
+func newFakeAkismet(t *testing.T) (*httptest.Server, chan url.Values) {
+	t.Helper()
+	requests := make(chan url.Values, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1.1/comment-check" || r.ParseForm() != nil {
+			http.NotFound(w, r)
+			return
+		}
+		requests <- r.PostForm
+		if r.PostForm.Get("api_key") != "key" {
+			w.Header().Set("X-akismet-debug-help", "Invalid API key")
+			_, _ = w.Write([]byte("invalid"))
+			return
+		}
+		switch r.PostForm.Get("comment_content") {
+		case "discard":
+			w.Header().Set("X-akismet-pro-tip", "discard")
+			_, _ = w.Write([]byte("true"))
+		case "spam":
+			_, _ = w.Write([]byte("true"))
+		default:
+			_, _ = w.Write([]byte("false"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestAkismetClient(t *testing.T) {
+	ctx := context.Background()
+	server, requests := newFakeAkismet(t)
+	client, err := NewAkismetClient(config.AkismetConfig{Key: "key", Blog: "https://blog.example.com", Endpoint: server.URL + "/"}, server.Client())
+	if err != nil {
+		t.Fatalf("NewAkismetClient: %v", err)
+	}
+
+	spam, discard, err := client.CommentCheck(ctx, &Comment{
+		Client:      Client{IP: "1.2.3.4", UserAgent: "UA", Referrer: "https://blog.example.com/post/a"},
+		Content:     "spam",
+		DisplayName: "x",
+		Email:       "x@example.com",
+	})
+	if err != nil || !spam || discard {
+		t.Fatalf("CommentCheck(spam) = %v, %v, %v", spam, discard, err)
+	}
+	form := <-requests
+	for key, want := range map[string]string{
+		"blog": "https://blog.example.com", "user_ip": "1.2.3.4", "user_agent": "UA", "referrer": "https://blog.example.com/post/a",
+		"comment_type": "comment", "comment_author": "x", "comment_author_email": "x@example.com",
+	} {
+		if form.Get(key) != want {
+			t.Errorf("form[%s] = %q, want %q", key, form.Get(key), want)
+		}
+	}
+
+	checker := NewAkismetChecker(client)
+	tests := []struct {
+		content string
+		want    int
+	}{
+		{"写得很好", 0},
+		{"spam", ScoreAkismet},
+		{"discard", ScoreAkismetMax},
+	}
+	for _, tt := range tests {
+		verdict, err := checker.Check(ctx, &Comment{Content: tt.content})
+		score := 0
+		if verdict != nil {
+			score = verdict.Score
+		}
+		if err != nil || score != tt.want {
+			t.Errorf("Check(%s) = %+v, %v, want %d", tt.content, verdict, err, tt.want)
+		}
+	}
+
+	// API Key 错误
+	client, _ = NewAkismetClient(config.AkismetConfig{Key: "wrong", Blog: "https://blog.example.com", Endpoint: server.URL}, server.Client())
+	if _, _, err := client.CommentCheck(ctx, &Comment{Content: "spam"}); err == nil {
+		t.Error("CommentCheck(wrong key) should fail")
+	}
+
+	if _, err := NewAkismetClient(config.AkismetConfig{Blog: "https://blog.example.com"}, nil); err == nil {
+		t.Error("NewAkismetClient(no key) should fail")
+	}
+}
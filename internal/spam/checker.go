@@ -0,0 +1,145 @@
+package spam
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// honeypotChecker 蜜罐字段检查，蜜罐字段有内容时认为是机器人提交
+type honeypotChecker struct{}
+
+// NewHoneypotChecker 创建蜜罐字段检查器
+func NewHoneypotChecker() Checker {
+	return honeypotChecker{}
+}
+
+func (honeypotChecker) Name() string { return "honeypot" }
+
+func (honeypotChecker) Check(_ context.Context, comment *Comment) (*Verdict, error) {
+	if strings.TrimSpace(comment.Honeypot) == "" {
+		return nil, nil
+	}
+	return &Verdict{Score: ScoreHoneypot, Reason: "蜜罐字段不为空"}, nil
+}
+
+// blocklistChecker 关键词和正则表达式黑名单，检查评论内容、名称、邮箱和站点
+type blocklistChecker struct {
+	keywords []string
+	patterns []*regexp.Regexp
+}
+
+// NewBlocklistChecker 创建黑名单检查器
+//   - keywords: 关键词，不区分大小写
+//   - patterns: 正则表达式
+func NewBlocklistChecker(keywords []string, patterns []string) (Checker, error) {
+	c := &blocklistChecker{}
+	for _, keyword := range keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			c.keywords = append(c.keywords, strings.ToLower(keyword))
+		}
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("垃圾评论正则表达式 [%s] 错误: %w", pattern, err)
+		}
+		c.patterns = append(c.patterns, re)
+	}
+	return c, nil
+}
+
+func (c *blocklistChecker) Name() string { return "blocklist" }
+
+func (c *blocklistChecker) Check(_ context.Context, comment *Comment) (*Verdict, error) {
+	text := strings.Join([]string{comment.Content, comment.DisplayName, comment.Email, comment.Site}, "\n")
+	lower := strings.ToLower(text)
+	for _, keyword := range c.keywords {
+		if strings.Contains(lower, keyword) {
+			return &Verdict{Score: ScoreBlocklist, Reason: "包含关键词 [" + keyword + "]"}, nil
+		}
+	}
+	for _, re := range c.patterns {
+		if re.MatchString(text) {
+			return &Verdict{Score: ScoreBlocklist, Reason: "匹配正则表达式 [" + re.String() + "]"}, nil
+		}
+	}
+	return nil, nil
+}
+
+// linkPattern 评论内容中的链接
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)`)
+
+// linkChecker 链接数量检查
+type linkChecker struct {
+	maxLinks int
+}
+
+// NewLinkChecker 创建链接数量检查器
+//   - maxLinks: 评论内容中最多允许的链接数量
+func NewLinkChecker(maxLinks int) Checker {
+	return &linkChecker{maxLinks: maxLinks}
+}
+
+func (c *linkChecker) Name() string { return "links" }
+
+func (c *linkChecker) Check(_ context.Context, comment *Comment) (*Verdict, error) {
+	count := len(linkPattern.FindAllStringIndex(comment.Content, -1))
+	if count <= c.maxLinks {
+		return nil, nil
+	}
+	return &Verdict{Score: ScoreLinks, Reason: fmt.Sprintf("包含 %d 个链接，最多 %d 个", count, c.maxLinks)}, nil
+}
+
+// History 评论历史
+type History interface {
+	// DuplicateCommentCount 获取创建时间在 since（毫秒时间戳）之后，内容相同的评论数量
+	DuplicateCommentCount(ctx context.Context, content string, since int64) (int64, error)
+}
+
+// duplicateChecker 重复评论检查
+type duplicateChecker struct {
+	history History
+	window  time.Duration
+}
+
+// NewDuplicateChecker 创建重复评论检查器
+//   - history: 评论历史
+//   - window: 检查最近多长时间内的评论
+func NewDuplicateChecker(history History, window time.Duration) Checker {
+	return &duplicateChecker{history: history, window: window}
+}
+
+func (c *duplicateChecker) Name() string { return "duplicate" }
+
+func (c *duplicateChecker) Check(ctx context.Context, comment *Comment) (*Verdict, error) {
+	count, err := c.history.DuplicateCommentCount(ctx, comment.Content, time.Now().Add(-c.window).UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	return &Verdict{Score: ScoreDuplicate, Reason: fmt.Sprintf("最近 %s 内有 %d 条相同的评论", c.window, count)}, nil
+}
+
+// formTokenChecker 表单令牌检查，拒绝没有令牌或打开页面后立即提交的评论
+type formTokenChecker struct {
+	tokens *FormTokens
+}
+
+// NewFormTokenChecker 创建表单令牌检查器
+func NewFormTokenChecker(tokens *FormTokens) Checker {
+	return &formTokenChecker{tokens: tokens}
+}
+
+func (c *formTokenChecker) Name() string { return "form_token" }
+
+func (c *formTokenChecker) Check(_ context.Context, comment *Comment) (*Verdict, error) {
+	if err := c.tokens.Verify(comment.FormToken); err != nil {
+		return &Verdict{Score: ScoreFormToken, Reason: err.Error()}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,221 @@
+// Package spam 垃圾评论过滤，由多个检查器对评论打分，根据总分决定接受、标记为垃圾评论或直接拒绝
+package spam
+
+import (
+	"context"
+	"nola-go/internal/config"
+	"nola-go/internal/logger"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 默认阈值
+const (
+	// DefaultSpamThreshold 分数达到此值的评论标记为垃圾评论
+	DefaultSpamThreshold = 50
+	// DefaultRejectThreshold 分数达到此值的评论直接拒绝
+	DefaultRejectThreshold = 100
+)
+
+// 各检查器命中时的分数
+const (
+	ScoreHoneypot   = 100
+	ScoreBlocklist  = 60
+	ScoreLinks      = 40
+	ScoreDuplicate  = 50
+	ScoreFormToken  = 50
+	ScoreAkismet    = 60
+	ScoreAkismetMax = 100
+)
+
+// Action 过滤结果
+type Action string
+
+const (
+	// ActionAccept 正常评论
+	ActionAccept Action = "accept"
+	// ActionSpam 标记为垃圾评论（保存但不显示，也不进入待审核列表）
+	ActionSpam Action = "spam"
+	// ActionReject 直接拒绝，不保存
+	ActionReject Action = "reject"
+)
+
+// Client 提交评论的客户端信息
+type Client struct {
+	// IP 客户端 IP
+	IP string
+	// UserAgent 客户端 User-Agent
+	UserAgent string
+	// Referrer 来源页面
+	Referrer string
+	// Honeypot 蜜罐字段，博客前端隐藏此字段，正常访客提交时为空
+	Honeypot string
+	// FormToken 打开评论框时获取的表单令牌
+	FormToken string
+}
+
+// Comment 待检查的评论
+type Comment struct {
+	Client
+	// PostId 文章 ID
+	PostId uint
+	// Content 评论内容
+	Content string
+	// DisplayName 评论人名称
+	DisplayName string
+	// Email 评论人邮箱
+	Email string
+	// Site 评论人站点
+	Site string
+}
+
+// Verdict 检查器的判断结果
+type Verdict struct {
+	// Score 分数，0 表示没有问题
+	Score int
+	// Reason 原因
+	Reason string
+}
+
+// Checker 垃圾评论检查器
+type Checker interface {
+	// Name 检查器名称
+	Name() string
+	// Check 检查评论，没有问题时返回 nil
+	Check(ctx context.Context, comment *Comment) (*Verdict, error)
+}
+
+// Result 过滤结果
+type Result struct {
+	// Score 所有检查器的分数之和
+	Score int
+	// Reasons 命中的原因
+	Reasons []string
+	// Action 处理方式
+	Action Action
+}
+
+// Reason 所有原因，使用 ; 分隔
+func (r *Result) Reason() string {
+	return strings.Join(r.Reasons, "; ")
+}
+
+// Filter 垃圾评论过滤器
+type Filter struct {
+	checkers        []Checker
+	spamThreshold   int
+	rejectThreshold int
+	tokens          *FormTokens
+}
+
+// NewFilter 创建垃圾评论过滤器
+//   - spamThreshold: 分数达到此值时标记为垃圾评论
+//   - rejectThreshold: 分数达到此值时直接拒绝
+//   - tokens: 表单令牌，可以为 nil
+//   - checkers: 检查器
+func NewFilter(spamThreshold, rejectThreshold int, tokens *FormTokens, checkers ...Checker) *Filter {
+	return &Filter{
+		checkers:        checkers,
+		spamThreshold:   spamThreshold,
+		rejectThreshold: rejectThreshold,
+		tokens:          tokens,
+	}
+}
+
+// New 根据配置创建垃圾评论过滤器，未启用时不检查任何评论
+//   - cfg: 垃圾评论过滤配置
+//   - history: 评论历史，用于检查重复评论
+//   - secret: 表单令牌的签名密钥
+func New(cfg config.SpamConfig, history History, secret string) (*Filter, error) {
+	tokens := NewFormTokens(secret, time.Duration(cfg.MinSubmitSeconds)*time.Second, formTokenMaxAge)
+	if !cfg.Enabled {
+		return NewFilter(0, 0, tokens), nil
+	}
+
+	spamThreshold := cfg.SpamThreshold
+	if spamThreshold <= 0 {
+		spamThreshold = DefaultSpamThreshold
+	}
+	rejectThreshold := cfg.RejectThreshold
+	if rejectThreshold <= 0 {
+		rejectThreshold = DefaultRejectThreshold
+	}
+
+	checkers := []Checker{NewHoneypotChecker()}
+
+	if len(cfg.Keywords) > 0 || len(cfg.Patterns) > 0 {
+		blocklist, err := NewBlocklistChecker(cfg.Keywords, cfg.Patterns)
+		if err != nil {
+			return nil, err
+		}
+		checkers = append(checkers, blocklist)
+	}
+
+	// 链接数量默认最多 3 个，小于 0 不限制
+	switch {
+	case cfg.MaxLinks == 0:
+		checkers = append(checkers, NewLinkChecker(3))
+	case cfg.MaxLinks > 0:
+		checkers = append(checkers, NewLinkChecker(cfg.MaxLinks))
+	}
+
+	// 重复评论默认检查 60 分钟内，小于 0 不检查
+	switch {
+	case cfg.DuplicateMinutes == 0:
+		checkers = append(checkers, NewDuplicateChecker(history, time.Hour))
+	case cfg.DuplicateMinutes > 0:
+		checkers = append(checkers, NewDuplicateChecker(history, time.Duration(cfg.DuplicateMinutes)*time.Minute))
+	}
+
+	// 需要博客前端配合获取表单令牌，默认不检查
+	if cfg.MinSubmitSeconds > 0 {
+		checkers = append(checkers, NewFormTokenChecker(tokens))
+	}
+
+	if cfg.Akismet.Key != "" {
+		client, err := NewAkismetClient(cfg.Akismet, nil)
+		if err != nil {
+			return nil, err
+		}
+		checkers = append(checkers, NewAkismetChecker(client))
+	}
+
+	return NewFilter(spamThreshold, rejectThreshold, tokens, checkers...), nil
+}
+
+// Check 依次执行所有检查器，检查器出错时忽略该检查器
+func (f *Filter) Check(ctx context.Context, comment *Comment) *Result {
+	ret := &Result{Action: ActionAccept}
+	for _, checker := range f.checkers {
+		verdict, err := checker.Check(ctx, comment)
+		if err != nil {
+			logger.Log.Warn("垃圾评论检查失败", zap.String("checker", checker.Name()), zap.Error(err))
+			continue
+		}
+		if verdict == nil || verdict.Score <= 0 {
+			continue
+		}
+		ret.Score += verdict.Score
+		ret.Reasons = append(ret.Reasons, checker.Name()+": "+verdict.Reason)
+	}
+
+	switch {
+	case len(f.checkers) == 0:
+		// 未启用
+	case ret.Score >= f.rejectThreshold:
+		ret.Action = ActionReject
+	case ret.Score >= f.spamThreshold:
+		ret.Action = ActionSpam
+	}
+	return ret
+}
+
+// FormToken 生成表单令牌，博客前端打开评论框时获取，提交评论时带上
+func (f *Filter) FormToken() string {
+	if f.tokens == nil {
+		return ""
+	}
+	return f.tokens.Issue()
+}
@@ -0,0 +1,227 @@
+package spam
+
+import (
+	"context"
+	"errors"
+	"nola-go/internal/config"
+	"nola-go/internal/logger"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.Log = zap.NewNop()
+}
+
+// fakeHistory 评论历史，记录已有的评论内容
+type fakeHistory struct {
+	contents []string
+	err      error
+}
+
+func (h *fakeHistory) DuplicateCommentCount(_ context.Context, content string, _ int64) (int64, error) {
+	var count int64
+	for _, c := range h.contents {
+		if c == content {
+			count++
+		}
+	}
+	return count, h.err
+}
+
+// fixedChecker 返回固定分数的检查器
+type fixedChecker struct {
+	name  string
+	score int
+	err   error
+}
+
+func (c fixedChecker) Name() string { return c.name }
+
+func (c fixedChecker) Check(context.Context, *Comment) (*Verdict, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.score == 0 {
+		return nil, nil
+	}
+	return &Verdict{Score: c.score, Reason: "fixed"}, nil
+}
+
+func TestFilter_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkers   []Checker
+		wantScore  int
+		wantAction Action
+		wantReason string
+	}{
+		{"没有问题", []Checker{fixedChecker{name: "a"}}, 0, ActionAccept, ""},
+		{"低于阈值", []Checker{fixedChecker{name: "a", score: 40}}, 40, ActionAccept, "a: fixed"},
+		{"垃圾评论", []Checker{fixedChecker{name: "a", score: 40}, fixedChecker{name: "b", score: 10}}, 50, ActionSpam, "a: fixed; b: fixed"},
+		{"拒绝", []Checker{fixedChecker{name: "a", score: 60}, fixedChecker{name: "b", score: 40}}, 100, ActionReject, "a: fixed; b: fixed"},
+		{"出错的检查器被忽略", []Checker{fixedChecker{name: "a", err: errors.New("down")}, fixedChecker{name: "b", score: 10}}, 10, ActionAccept, "b: fixed"},
+		{"未启用", nil, 0, ActionAccept, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFilter(DefaultSpamThreshold, DefaultRejectThreshold, nil, tt.checkers...)
+			got := f.Check(context.Background(), &Comment{})
+			if got.Score != tt.wantScore || got.Action != tt.wantAction || got.Reason() != tt.wantReason {
+				t.Errorf("Check = %+v, want %d %s %q", got, tt.wantScore, tt.wantAction, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCheckers(t *testing.T) {
+	ctx := context.Background()
+	blocklist, err := NewBlocklistChecker([]string{" Casino ", ""}, []string{`(?i)viagra\d*`})
+	if err != nil {
+		t.Fatalf("NewBlocklistChecker: %v", err)
+	}
+	if _, err := NewBlocklistChecker(nil, []string{"("}); err == nil {
+		t.Error("NewBlocklistChecker(invalid pattern) should fail")
+	}
+
+	tests := []struct {
+		name    string
+		checker Checker
+		comment Comment
+		want    int
+	}{
+		{"蜜罐为空", NewHoneypotChecker(), Comment{}, 0},
+		{"蜜罐有内容", NewHoneypotChecker(), Comment{Client: Client{Honeypot: "http://spam"}}, ScoreHoneypot},
+		{"关键词不区分大小写", blocklist, Comment{Content: "best CASINO online"}, ScoreBlocklist},
+		{"关键词检查站点", blocklist, Comment{Content: "hi", Site: "https://casino.example.com"}, ScoreBlocklist},
+		{"正则表达式", blocklist, Comment{DisplayName: "Viagra123"}, ScoreBlocklist},
+		{"黑名单未命中", blocklist, Comment{Content: "写得很好"}, 0},
+		{"链接未超过", NewLinkChecker(2), Comment{Content: "https://a.com 和 http://b.com"}, 0},
+		{"链接超过", NewLinkChecker(2), Comment{Content: "https://a.com http://b.com www.c.com"}, ScoreLinks},
+		{"重复评论", NewDuplicateChecker(&fakeHistory{contents: []string{"沙发"}}, time.Hour), Comment{Content: "沙发"}, ScoreDuplicate},
+		{"不重复", NewDuplicateChecker(&fakeHistory{contents: []string{"沙发"}}, time.Hour), Comment{Content: "板凳"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := tt.checker.Check(ctx, &tt.comment)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			score := 0
+			if verdict != nil {
+				score = verdict.Score
+			}
+			if score != tt.want {
+				t.Errorf("score = %d (%+v), want %d", score, verdict, tt.want)
+			}
+		})
+	}
+
+	// 评论历史出错时返回错误
+	if _, err := NewDuplicateChecker(&fakeHistory{err: errors.New("db")}, time.Hour).Check(ctx, &Comment{}); err == nil {
+		t.Error("duplicate checker should return history error")
+	}
+}
+
+func TestFormTokens(t *testing.T) {
+	now := time.UnixMilli(1_700_000_000_000)
+	tokens := NewFormTokens("secret", 3*time.Second, time.Hour)
+	tokens.nowFunc = func() time.Time { return now }
+	token := tokens.Issue()
+
+	tests := []struct {
+		name    string
+		token   string
+		elapsed time.Duration
+		wantErr string
+	}{
+		{"正常", token, 5 * time.Second, ""},
+		{"提交过快", token, time.Second, "提交过快"},
+		{"过期", token, 2 * time.Hour, "已过期"},
+		{"时间在未来", token, -time.Second, "无效"},
+		{"缺少", "", 5 * time.Second, "缺少"},
+		{"格式错误", "abc", 5 * time.Second, "无效"},
+		{"签名错误", strings.Replace(token, ".", ".x", 1), 5 * time.Second, "无效"},
+		{"其他密钥", NewFormTokens("other", 0, time.Hour).Issue(), 5 * time.Second, "无效"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens.nowFunc = func() time.Time { return now.Add(tt.elapsed) }
+			err := tokens.Verify(tt.token)
+			if (tt.wantErr == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Verify = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// 检查器：令牌有问题时加分
+	checker := NewFormTokenChecker(tokens)
+	tokens.nowFunc = func() time.Time { return now.Add(time.Second) }
+	if verdict, _ := checker.Check(context.Background(), &Comment{Client: Client{FormToken: token}}); verdict == nil || verdict.Score != ScoreFormToken {
+		t.Errorf("form token verdict = %+v", verdict)
+	}
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	history := &fakeHistory{contents: []string{"沙发"}}
+
+	// 未启用时不检查
+	f, err := New(config.SpamConfig{}, history, "secret")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := f.Check(ctx, &Comment{Content: "沙发", Client: Client{Honeypot: "x"}}); got.Action != ActionAccept || got.Score != 0 {
+		t.Errorf("disabled Check = %+v", got)
+	}
+	if f.FormToken() == "" {
+		t.Error("FormToken should not be empty")
+	}
+
+	f, err = New(config.SpamConfig{Enabled: true, Keywords: []string{"casino"}}, history, "secret")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	tests := []struct {
+		name    string
+		comment Comment
+		want    Action
+	}{
+		{"正常", Comment{Content: "写得很好"}, ActionAccept},
+		// 默认最多 3 个链接
+		{"链接过多", Comment{Content: strings.Repeat("https://a.com ", 4)}, ActionAccept},
+		{"重复评论", Comment{Content: "沙发"}, ActionSpam},
+		{"关键词和链接过多", Comment{Content: "casino " + strings.Repeat("https://a.com ", 4)}, ActionReject},
+		{"蜜罐", Comment{Content: "写得很好", Client: Client{Honeypot: "x"}}, ActionReject},
+		// 没有设置 MinSubmitSeconds 时不检查表单令牌
+		{"没有表单令牌", Comment{Content: "写得很好"}, ActionAccept},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.Check(ctx, &tt.comment); got.Action != tt.want {
+				t.Errorf("Check = %+v, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// 启用表单令牌，不检查链接和重复评论
+	f, err = New(config.SpamConfig{Enabled: true, MinSubmitSeconds: 3, MaxLinks: -1, DuplicateMinutes: -1}, history, "secret")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	got := f.Check(ctx, &Comment{Content: "沙发 " + strings.Repeat("https://a.com ", 10), Client: Client{FormToken: f.FormToken()}})
+	if got.Action != ActionSpam || len(got.Reasons) != 1 || !strings.HasPrefix(got.Reasons[0], "form_token: 提交过快") {
+		t.Errorf("form token Check = %+v", got)
+	}
+
+	// 配置错误
+	if _, err := New(config.SpamConfig{Enabled: true, Patterns: []string{"["}}, history, "secret"); err == nil {
+		t.Error("New(invalid pattern) should fail")
+	}
+	if _, err := New(config.SpamConfig{Enabled: true, Akismet: config.AkismetConfig{Key: "key"}}, history, "secret"); err == nil {
+		t.Error("New(akismet without blog) should fail")
+	}
+}
@@ -0,0 +1,76 @@
+package spam
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formTokenMaxAge 表单令牌的有效期
+const formTokenMaxAge = 24 * time.Hour
+
+// FormTokens 表单令牌，记录博客前端打开评论框的时间，用于拒绝打开页面后立即提交的评论
+// 令牌格式为 "毫秒时间戳.签名"，不需要保存
+type FormTokens struct {
+	secret  []byte
+	minAge  time.Duration
+	maxAge  time.Duration
+	nowFunc func() time.Time
+}
+
+// NewFormTokens 创建表单令牌
+//   - secret: 签名密钥
+//   - minAge: 获取令牌后最短多久可以提交评论
+//   - maxAge: 令牌有效期
+func NewFormTokens(secret string, minAge, maxAge time.Duration) *FormTokens {
+	return &FormTokens{
+		secret:  []byte(secret),
+		minAge:  minAge,
+		maxAge:  maxAge,
+		nowFunc: time.Now,
+	}
+}
+
+// Issue 生成表单令牌
+func (t *FormTokens) Issue() string {
+	ts := strconv.FormatInt(t.nowFunc().UnixMilli(), 10)
+	return ts + "." + t.sign(ts)
+}
+
+// Verify 检查表单令牌，令牌无效、过期或提交过快时返回错误
+func (t *FormTokens) Verify(token string) error {
+	if token == "" {
+		return errors.New("缺少表单令牌")
+	}
+
+	ts, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(t.sign(ts))) {
+		return errors.New("表单令牌无效")
+	}
+	issued, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.New("表单令牌无效")
+	}
+
+	age := t.nowFunc().Sub(time.UnixMilli(issued))
+	switch {
+	case age < 0:
+		return errors.New("表单令牌无效")
+	case age < t.minAge:
+		return errors.New("提交过快（" + age.Round(time.Millisecond).String() + "）")
+	case age > t.maxAge:
+		return errors.New("表单令牌已过期")
+	}
+	return nil
+}
+
+// sign 签名
+func (t *FormTokens) sign(ts string) string {
+	h := hmac.New(sha256.New, t.secret)
+	h.Write([]byte("comment-form:" + ts))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:16])
+}
@@ -0,0 +1,49 @@
+package testutil
+
+import (
+	"context"
+	"nola-go/internal/config"
+	"nola-go/internal/db"
+	"nola-go/internal/logger"
+	"nola-go/internal/migration"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// NewDB 创建临时 SQLite 测试数据库，并执行所有迁移。
+// 数据库文件位于测试临时目录中，测试结束后自动关闭并删除
+func NewDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	// 部分 Repo 会直接使用全局日志
+	if logger.Log == nil {
+		logger.Log = zap.NewNop()
+	}
+
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			Driver: db.DriverSQLite,
+			DSN:    "file:" + filepath.Join(t.TempDir(), "nola.db") + "?_busy_timeout=5000&_journal_mode=WAL",
+		},
+	}
+
+	database, err := db.Connect(cfg)
+	if err != nil {
+		t.Fatalf("连接测试数据库失败: %v", err)
+	}
+
+	if _, err := migration.NewMigrator(database).Up(context.Background()); err != nil {
+		t.Fatalf("测试数据库迁移失败: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	return database
+}
@@ -0,0 +1,151 @@
+package testutil
+
+import (
+	"nola-go/internal/models"
+	"nola-go/internal/models/enum"
+	"nola-go/internal/util"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Fixture 测试数据加载器，插入失败时直接终止测试
+type Fixture struct {
+	t  testing.TB
+	db *gorm.DB
+	// base 起始时间
+	base int64
+	// seq 已分配的时间数量
+	seq int64
+}
+
+// NewFixture 创建测试数据加载器
+func NewFixture(t testing.TB, db *gorm.DB) *Fixture {
+	return &Fixture{t: t, db: db, base: time.Now().UnixMilli()}
+}
+
+// Now 获取递增的时间戳（每次调用增加 1 秒），保证按时间排序的结果稳定
+func (f *Fixture) Now() int64 {
+	f.seq++
+	return f.base + f.seq*1000
+}
+
+// Create 插入任意数据
+func (f *Fixture) Create(value any) {
+	f.t.Helper()
+	if err := f.db.Create(value).Error; err != nil {
+		f.t.Fatalf("插入测试数据失败: %v", err)
+	}
+}
+
+// Tag 插入标签，显示名和别名相同
+func (f *Fixture) Tag(slug string) *models.Tag {
+	f.t.Helper()
+	tag := &models.Tag{DisplayName: slug, Slug: slug}
+	f.Create(tag)
+	return tag
+}
+
+// Category 插入分类，显示名和别名相同
+func (f *Fixture) Category(slug string) *models.Category {
+	f.t.Helper()
+	category := &models.Category{DisplayName: slug, Slug: slug, Cover: util.StringPtr("")}
+	f.Create(category)
+	return category
+}
+
+// Post 插入已发布、可见的文章以及文章正文，标题和别名相同
+//   - slug: 文章别名
+//   - content: 文章正文
+//   - tags: 文章标签
+func (f *Fixture) Post(slug string, content string, tags ...*models.Tag) *models.Post {
+	f.t.Helper()
+	post := &models.Post{
+		Title:        slug,
+		Slug:         slug,
+		AllowComment: true,
+		Status:       enum.PostStatusPublished,
+		Visible:      enum.PostVisibleVisible,
+		CreateTime:   f.Now(),
+	}
+	f.Create(post)
+
+	f.Create(&models.PostContent{
+		PostId:  post.PostId,
+		Content: content,
+		HTML:    util.MarkdownToHtml(content),
+		Status:  enum.PostContentStatusPublished,
+	})
+
+	for _, tag := range tags {
+		f.Create(&models.PostTag{PostId: post.PostId, TagId: tag.TagId})
+	}
+	return post
+}
+
+// PostCategory 设置文章分类
+func (f *Fixture) PostCategory(post *models.Post, category *models.Category) {
+	f.t.Helper()
+	f.Create(&models.PostCategory{PostId: post.PostId, CategoryId: category.CategoryId})
+}
+
+// Draft 插入文章草稿
+func (f *Fixture) Draft(post *models.Post, draftName string, content string) *models.PostContent {
+	f.t.Helper()
+	draft := &models.PostContent{
+		PostId:    post.PostId,
+		Content:   content,
+		HTML:      util.MarkdownToHtml(content),
+		Status:    enum.PostContentStatusDraft,
+		DraftName: util.StringPtr(draftName),
+	}
+	f.Create(draft)
+	return draft
+}
+
+// Comment 插入评论
+//   - post: 评论所属文章
+//   - parent: 父评论，可以为 nil
+//   - email: 评论者邮箱
+//   - isPass: 是否通过审核
+func (f *Fixture) Comment(post *models.Post, parent *models.Comment, email string, isPass bool) *models.Comment {
+	f.t.Helper()
+	comment := &models.Comment{
+		PostId:      post.PostId,
+		Content:     "comment from " + email,
+		DisplayName: email,
+		Email:       email,
+		CreateTime:  f.Now(),
+		IsPass:      isPass,
+	}
+	if parent != nil {
+		comment.ParentCommentId = &parent.CommentId
+		comment.ReplyCommentId = &parent.CommentId
+		comment.ReplyDisplayName = &parent.DisplayName
+	}
+	f.Create(comment)
+	return comment
+}
+
+// FileGroup 插入文件组，显示名和路径相同
+func (f *Fixture) FileGroup(path string, storageMode enum.FileStorageMode) *models.FileGroup {
+	f.t.Helper()
+	group := &models.FileGroup{DisplayName: path, Path: path, StorageMode: storageMode}
+	f.Create(group)
+	return group
+}
+
+// File 插入文件
+//   - name: 文件名
+//   - group: 文件组，可以为 nil
+//   - storageMode: 文件存储方式
+func (f *Fixture) File(name string, group *models.FileGroup, storageMode enum.FileStorageMode) *models.File {
+	f.t.Helper()
+	file := &models.File{DisplayName: name, Size: 1024, StorageMode: storageMode, CreateTime: f.Now()}
+	if group != nil {
+		file.FileGroupId = &group.FileGroupId
+	}
+	f.Create(file)
+	return file
+}
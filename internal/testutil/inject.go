@@ -0,0 +1,74 @@
+package testutil
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// Op 数据库操作类型
+type Op string
+
+const (
+	// OpCreate 插入
+	OpCreate Op = "create"
+	// OpUpdate 修改
+	OpUpdate Op = "update"
+	// OpDelete 删除
+	OpDelete Op = "delete"
+)
+
+// ErrInjected 注入的错误
+var ErrInjected = errors.New("injected error")
+
+// injectSeq 回调名称序号
+var injectSeq atomic.Int64
+
+// InjectError 在指定表的指定操作执行前注入 ErrInjected，用于测试事务回滚。
+// 测试结束后自动移除
+//   - op: 操作类型
+//   - table: 表名
+//   - skip: 跳过前 skip 次匹配的操作，之后每次都返回错误
+func InjectError(t testing.TB, db *gorm.DB, op Op, table string, skip int) {
+	t.Helper()
+
+	name := fmt.Sprintf("testutil:inject_%d", injectSeq.Add(1))
+	var count atomic.Int64
+	fn := func(tx *gorm.DB) {
+		if tx.Statement.Table != table {
+			return
+		}
+		if count.Add(1) > int64(skip) {
+			_ = tx.AddError(ErrInjected)
+		}
+	}
+
+	var err error
+	switch op {
+	case OpCreate:
+		err = db.Callback().Create().Before("gorm:create").Register(name, fn)
+	case OpUpdate:
+		err = db.Callback().Update().Before("gorm:update").Register(name, fn)
+	case OpDelete:
+		err = db.Callback().Delete().Before("gorm:delete").Register(name, fn)
+	default:
+		t.Fatalf("不支持的操作类型 [%s]", op)
+	}
+	if err != nil {
+		t.Fatalf("注入错误失败: %v", err)
+	}
+
+	t.Cleanup(func() {
+		switch op {
+		case OpCreate:
+			_ = db.Callback().Create().Remove(name)
+		case OpUpdate:
+			_ = db.Callback().Update().Remove(name)
+		case OpDelete:
+			_ = db.Callback().Delete().Remove(name)
+		}
+	})
+}
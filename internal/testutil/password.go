@@ -0,0 +1,20 @@
+package testutil
+
+import (
+	"nola-go/internal/config"
+	"nola-go/internal/password"
+	"testing"
+)
+
+// NewHasher 创建使用低开销 Argon2id 参数的密码哈希器，避免测试耗时过长
+func NewHasher(t testing.TB) password.Hasher {
+	t.Helper()
+
+	hasher, err := password.New(config.PasswordConfig{
+		Argon2: config.Argon2Config{Memory: 1024, Iterations: 1, Parallelism: 1},
+	})
+	if err != nil {
+		t.Fatalf("创建密码哈希器失败: %v", err)
+	}
+	return hasher
+}
@@ -0,0 +1,24 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// ApiTokenPrefix API 令牌前缀，用于区分 API 令牌和登录访问令牌（JWT）
+const ApiTokenPrefix = "nola_"
+
+// GenerateApiToken 生成 256 位随机 API 令牌，格式为 nola_<64 位十六进制>
+func GenerateApiToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return ApiTokenPrefix + hex.EncodeToString(b), nil
+}
+
+// IsApiToken 判断令牌是否为 API 令牌
+func IsApiToken(token string) bool {
+	return strings.HasPrefix(token, ApiTokenPrefix)
+}
@@ -0,0 +1,21 @@
+package util
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strings"
+)
+
+// DefaultAvatarURL 默认头像地址（Gravatar），{hash} 会被替换为邮箱的 MD5
+const DefaultAvatarURL = "https://www.gravatar.com/avatar/{hash}?d=mp"
+
+// AvatarURL 根据邮箱生成 Gravatar 兼容的头像地址，邮箱去除首尾空白并转为小写后计算 MD5
+//   - pattern: 头像地址，{hash} 会被替换为邮箱的 MD5，为空时使用 DefaultAvatarURL
+//   - email: 邮箱
+func AvatarURL(pattern, email string) string {
+	if pattern == "" {
+		pattern = DefaultAvatarURL
+	}
+	hash := md5.Sum([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return strings.ReplaceAll(pattern, "{hash}", hex.EncodeToString(hash[:]))
+}
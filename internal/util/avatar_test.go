@@ -0,0 +1,20 @@
+package util
+
+import "testing"
+
+func TestAvatarURL(t *testing.T) {
+	// Gravatar 文档中的示例
+	email := " MyEmailAddress@example.com "
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"", "https://www.gravatar.com/avatar/0bc83cb571cd1c50ba6f3e8a78ef1346?d=mp"},
+		{"https://cravatar.cn/avatar/{hash}?s=80", "https://cravatar.cn/avatar/0bc83cb571cd1c50ba6f3e8a78ef1346?s=80"},
+	}
+	for _, tt := range tests {
+		if got := AvatarURL(tt.pattern, email); got != tt.want {
+			t.Errorf("AvatarURL(%q) = %s, want %s", tt.pattern, got, tt.want)
+		}
+	}
+}
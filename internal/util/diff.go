@@ -0,0 +1,200 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffOp 行差异类型
+type DiffOp string
+
+const (
+	// DiffOpEqual 两边相同的行
+	DiffOpEqual DiffOp = "EQUAL"
+	// DiffOpInsert 新增的行
+	DiffOpInsert DiffOp = "INSERT"
+	// DiffOpDelete 删除的行
+	DiffOpDelete DiffOp = "DELETE"
+)
+
+// DiffLine 行差异
+type DiffLine struct {
+	// Op 差异类型
+	Op DiffOp `json:"op"`
+	// OldLine 在旧文本中的行号（从 1 开始），新增的行为 0
+	OldLine int `json:"oldLine"`
+	// NewLine 在新文本中的行号（从 1 开始），删除的行为 0
+	NewLine int `json:"newLine"`
+	// Text 行内容
+	Text string `json:"text"`
+}
+
+// LineDiff 按行比较两段文本，返回最短的编辑序列（Myers 差异算法）
+//   - oldText: 旧文本
+//   - newText: 新文本
+func LineDiff(oldText, newText string) []*DiffLine {
+	a, b := splitLines(oldText), splitLines(newText)
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+
+	// trace[d] 保存第 d 步开始前 k ∈ [-d-1, d+1] 范围内的状态，用于回溯编辑路径
+	var trace [][]int
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// 从终点回溯编辑路径（逆序）
+	var reversed []*DiffLine
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d]
+		get := func(k int) int { return prev[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := get(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, &DiffLine{Op: DiffOpEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			reversed = append(reversed, &DiffLine{Op: DiffOpInsert, Text: b[prevY]})
+		} else {
+			reversed = append(reversed, &DiffLine{Op: DiffOpDelete, Text: a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		reversed = append(reversed, &DiffLine{Op: DiffOpEqual, Text: a[x-1]})
+		x--
+		y--
+	}
+
+	// 恢复顺序并计算行号
+	lines := make([]*DiffLine, len(reversed))
+	oldLine, newLine := 0, 0
+	for i := range reversed {
+		line := reversed[len(reversed)-1-i]
+		if line.Op != DiffOpInsert {
+			oldLine++
+			line.OldLine = oldLine
+		}
+		if line.Op != DiffOpDelete {
+			newLine++
+			line.NewLine = newLine
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+// UnifiedDiff 将行差异格式化为统一差异格式（unified diff），没有差异时返回空字符串
+//   - lines: LineDiff 返回的行差异
+//   - oldName: 旧文本名称
+//   - newName: 新文本名称
+//   - context: 每处修改前后保留的相同行数
+func UnifiedDiff(lines []*DiffLine, oldName, newName string, context int) string {
+	// 找出包含修改的区间，相邻区间重叠时合并
+	type hunk struct{ start, end int }
+	var hunks []hunk
+	for i, line := range lines {
+		if line.Op == DiffOpEqual {
+			continue
+		}
+		start, end := i-context, i+context+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].end {
+			hunks[len(hunks)-1].end = end
+		} else {
+			hunks = append(hunks, hunk{start, end})
+		}
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- " + oldName + "\n")
+	sb.WriteString("+++ " + newName + "\n")
+
+	// oldBefore、newBefore 为第 i 行之前两边已经出现的行数
+	oldBefore, newBefore := make([]int, len(lines)+1), make([]int, len(lines)+1)
+	for i, line := range lines {
+		oldBefore[i+1], newBefore[i+1] = oldBefore[i], newBefore[i]
+		if line.Op != DiffOpInsert {
+			oldBefore[i+1]++
+		}
+		if line.Op != DiffOpDelete {
+			newBefore[i+1]++
+		}
+	}
+
+	for _, h := range hunks {
+		oldCount := oldBefore[h.end] - oldBefore[h.start]
+		newCount := newBefore[h.end] - newBefore[h.start]
+		sb.WriteString(fmt.Sprintf("@@ -%s +%s @@\n",
+			hunkRange(oldBefore[h.start], oldCount), hunkRange(newBefore[h.start], newCount)))
+		for _, line := range lines[h.start:h.end] {
+			switch line.Op {
+			case DiffOpInsert:
+				sb.WriteString("+")
+			case DiffOpDelete:
+				sb.WriteString("-")
+			default:
+				sb.WriteString(" ")
+			}
+			sb.WriteString(line.Text + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// hunkRange 格式化差异块的起始行和行数，行数为 0 时起始行为前一行
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// splitLines 将文本按行拆分，忽略末尾的换行符
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{"相同", "a\nb\n", "a\nb", " a b"},
+		{"新增", "", "a\nb", "+a+b"},
+		{"删除", "a\nb", "", "-a-b"},
+		{"修改", "a\nb\nc", "a\nx\nc\nd", " a-b+x c+d"},
+		{"换行符", "a\r\nb", "a\nb", " a b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			oldLine, newLine := 0, 0
+			for _, line := range LineDiff(tt.old, tt.new) {
+				switch line.Op {
+				case DiffOpInsert:
+					sb.WriteString("+")
+				case DiffOpDelete:
+					sb.WriteString("-")
+				default:
+					sb.WriteString(" ")
+				}
+				sb.WriteString(line.Text)
+
+				if line.Op != DiffOpInsert {
+					oldLine++
+					if line.OldLine != oldLine {
+						t.Errorf("line %q OldLine = %d, want %d", line.Text, line.OldLine, oldLine)
+					}
+				}
+				if line.Op != DiffOpDelete {
+					newLine++
+					if line.NewLine != newLine {
+						t.Errorf("line %q NewLine = %d, want %d", line.Text, line.NewLine, newLine)
+					}
+				}
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("LineDiff = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10"
+	new := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11"
+
+	want := "--- a\n+++ b\n" +
+		"@@ -2,3 +2,3 @@\n 2\n-3\n+three\n 4\n" +
+		"@@ -10,1 +10,2 @@\n 10\n+11\n"
+	if got := UnifiedDiff(LineDiff(old, new), "a", "b", 1); got != want {
+		t.Errorf("UnifiedDiff =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := UnifiedDiff(LineDiff(old, old), "a", "b", 3); got != "" {
+		t.Errorf("UnifiedDiff(same) = %q, want empty", got)
+	}
+}
+
+func TestStringWordCount(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"hello, world", 2},
+		{"你好，世界", 4},
+		{"# Go 语言发布 v2", 6},
+	}
+	for _, tt := range tests {
+		if got := StringWordCount(tt.s); got != tt.want {
+			t.Errorf("StringWordCount(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
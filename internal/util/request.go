@@ -2,6 +2,9 @@ package util
 
 import (
 	"errors"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -43,3 +46,30 @@ func ShouldBindPager(c *gin.Context) (page, size int, err error) {
 
 	return *pager.Page, *pager.Size, nil
 }
+
+// IsNotModified 判断条件请求（GET / HEAD）的资源是否未修改，未修改时应返回 304
+// 请求带有 If-None-Match 时只比较 ETag（弱比较），否则比较 If-Modified-Since 和最后修改时间（精确到秒）
+//   - header: 请求头
+//   - etag: 资源当前的 ETag（带引号）
+//   - lastModified: 资源最后修改时间，零值表示未知
+func IsNotModified(header http.Header, etag string, lastModified time.Time) bool {
+	if match := header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	since := header.Get("If-Modified-Since")
+	if since == "" || lastModified.IsZero() {
+		return false
+	}
+	t, err := http.ParseTime(since)
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(t)
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestIsNotModified(t *testing.T) {
+	etag := `"abc"`
+	lastModified := time.Date(2026, 1, 2, 3, 4, 5, 600, time.UTC)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   bool
+	}{
+		{"没有条件", nil, false},
+		{"ETag 相同", map[string]string{"If-None-Match": `"abc"`}, true},
+		{"弱 ETag 相同", map[string]string{"If-None-Match": `"x", W/"abc"`}, true},
+		{"任意 ETag", map[string]string{"If-None-Match": `*`}, true},
+		{"ETag 不同时忽略修改时间", map[string]string{
+			"If-None-Match":     `"old"`,
+			"If-Modified-Since": lastModified.Add(time.Hour).Format(http.TimeFormat),
+		}, false},
+		{"修改时间相同（忽略毫秒）", map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, true},
+		{"之后又修改过", map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)}, false},
+		{"无效的时间", map[string]string{"If-Modified-Since": "yesterday"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.header {
+				header.Set(k, v)
+			}
+			if got := IsNotModified(header, etag, lastModified); got != tt.want {
+				t.Errorf("IsNotModified = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if IsNotModified(http.Header{"If-Modified-Since": {lastModified.Format(http.TimeFormat)}}, etag, time.Time{}) {
+		t.Error("unknown last modified time should not match")
+	}
+}
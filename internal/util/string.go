@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/mozillazg/go-pinyin"
 )
@@ -176,3 +177,25 @@ func StringToUint(s string) (uint, error) {
 
 	return 0, err
 }
+
+// StringWordCount 统计字数
+// 中日韩文字每个字计为一个字，其他连续的字母和数字计为一个单词
+func StringWordCount(s string) int {
+	count := 0
+	inWord := false
+	for _, r := range s {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			count++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				count++
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+	return count
+}
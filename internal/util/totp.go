@@ -0,0 +1,134 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// totpPeriod TOTP 时间步长（秒）
+	totpPeriod = 30
+	// totpDigits TOTP 验证码位数
+	totpDigits = 6
+	// totpSkew 允许前后偏差的时间步数，用于容忍客户端时钟误差
+	totpSkew = 1
+)
+
+// totpEncoding TOTP 密钥编码（Base32，无填充）
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTotpSecret 生成 160 位随机 TOTP 密钥（Base32 编码）
+func GenerateTotpSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TotpURI 生成身份验证器 App 使用的 otpauth 地址，可直接生成二维码
+//   - issuer: 签发者（显示在身份验证器中）
+//   - account: 账户名
+//   - secret: Base32 编码的密钥
+func TotpURI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// ValidateTotp 验证 TOTP 验证码（RFC 6238，HMAC-SHA1，6 位，30 秒）
+// 验证成功时返回验证码对应的时间步，调用方需要记录已使用的时间步，防止验证码被重复使用
+//   - secret: Base32 编码的密钥
+//   - code: 验证码
+//   - now: 当前时间
+func ValidateTotp(secret, code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return 0, false
+	}
+
+	step := now.Unix() / totpPeriod
+	for i := -totpSkew; i <= totpSkew; i++ {
+		candidate := step + int64(i)
+		expected := totpCode(key, candidate, totpDigits)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return candidate, true
+		}
+	}
+	return 0, false
+}
+
+// totpCode 计算指定时间步的验证码（RFC 4226 HOTP）
+func totpCode(key []byte, step int64, digits int) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
+
+// recoveryCodeAlphabet 恢复码字符集（去掉了容易混淆的 0/o、1/l/i）
+const recoveryCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+// GenerateRecoveryCodes 生成两步验证恢复码，格式为 xxxx-xxxx
+//   - count: 恢复码数量
+func GenerateRecoveryCodes(count int) ([]string, error) {
+	// 丢弃大于字符集整数倍的随机字节，避免取模带来的分布偏差
+	limit := byte(256 - 256%len(recoveryCodeAlphabet))
+
+	codes := make([]string, count)
+	buf := make([]byte, 1)
+	for i := range codes {
+		code := make([]byte, 0, 8)
+		for len(code) < 8 {
+			if _, err := rand.Read(buf); err != nil {
+				return nil, err
+			}
+			if buf[0] >= limit {
+				continue
+			}
+			code = append(code, recoveryCodeAlphabet[int(buf[0])%len(recoveryCodeAlphabet)])
+		}
+		codes[i] = string(code[:4]) + "-" + string(code[4:])
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode 规范化用户输入的恢复码（去掉空白和连字符并转为小写）
+func NormalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+}
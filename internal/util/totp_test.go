@@ -0,0 +1,99 @@
+package util
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTotpCode(t *testing.T) {
+	// RFC 6238 附录 B 测试向量（SHA1）
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/totpPeriod, 8); got != tt.want {
+			t.Errorf("totpCode(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTotp(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+	step := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name     string
+		code     string
+		wantStep int64
+		wantOk   bool
+	}{
+		{"当前时间步", "050471", step, true},
+		{"上一个时间步", totpCode([]byte("12345678901234567890"), step-1, 6), step - 1, true},
+		{"超出允许偏差", totpCode([]byte("12345678901234567890"), step+2, 6), 0, false},
+		{"位数错误", "50471", 0, false},
+		{"错误验证码", "000000", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := ValidateTotp(secret, tt.code, now)
+			if ok != tt.wantOk || gotStep != tt.wantStep {
+				t.Errorf("ValidateTotp = %d, %v, want %d, %v", gotStep, ok, tt.wantStep, tt.wantOk)
+			}
+		})
+	}
+
+	if _, ok := ValidateTotp("not base32!", "050471", now); ok {
+		t.Error("invalid secret should not validate")
+	}
+}
+
+func TestTotpURI(t *testing.T) {
+	secret, err := GenerateTotpSecret()
+	if err != nil || len(secret) != 32 {
+		t.Fatalf("GenerateTotpSecret = %q, %v", secret, err)
+	}
+
+	u, err := url.Parse(TotpURI("Nola Blog", "admin", secret))
+	if err != nil {
+		t.Fatalf("parse uri: %v", err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Nola Blog:admin" {
+		t.Errorf("uri = %s", u)
+	}
+	if q := u.Query(); q.Get("secret") != secret || q.Get("issuer") != "Nola Blog" || q.Get("digits") != "6" {
+		t.Errorf("query = %v", q)
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil || len(codes) != 10 {
+		t.Fatalf("GenerateRecoveryCodes = %v, %v", codes, err)
+	}
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 9 || code[4] != '-' {
+			t.Errorf("code %q has wrong format", code)
+		}
+		if NormalizeRecoveryCode(" "+strings.ToUpper(code)+" ") != strings.Replace(code, "-", "", 1) {
+			t.Errorf("NormalizeRecoveryCode(%q) mismatch", code)
+		}
+		seen[code] = true
+	}
+	if len(seen) != len(codes) {
+		t.Error("recovery codes should be unique")
+	}
+}